@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// Job IDs of common jobs, to be used with Capabilities.
+const (
+	AccountBalancesJob         = "HKSAL"
+	AccountTransactionsJob     = "HKKAZ"
+	CamtAccountTransactionsJob = "HKCAZ"
+	AccountInformationJob      = "HKKIF"
+	SepaTransferJob            = "HKCCS"
+)
+
+// updUsageRestricted is the UPD usage stating that jobs not listed within
+// the UPD are not allowed
+const updUsageRestricted = 0
+
+// JobNotAllowedError is returned if the UPD do not allow a job for an
+// account. The job is not sent in that case.
+type JobNotAllowedError struct {
+	Account domain.AccountConnection
+	Job     string
+}
+
+func (j *JobNotAllowedError) Error() string {
+	return fmt.Sprintf("job %s not allowed for account %s by the user parameter data", j.Job, j.Account.AccountID)
+}
+
+// Capabilities combines the jobs the UPD allow for an account with the job
+// versions the institute supports according to the BPD.
+type Capabilities struct {
+	// Account is the account as stated within the UPD
+	Account domain.AccountInformation
+	// restricted is true if jobs not listed within the UPD are not allowed
+	restricted bool
+	jobs       map[string]domain.BusinessTransaction
+	versions   map[string][]int
+}
+
+// Allowed returns true if the UPD allow job for the account.
+func (c *Capabilities) Allowed(job string) bool {
+	if _, ok := c.jobs[job]; ok {
+		return true
+	}
+	return !c.restricted
+}
+
+// Versions returns the versions of job the institute supports, sorted
+// ascending. It returns nil if the BPD do not state job.
+func (c *Capabilities) Versions(job string) []int {
+	return c.versions[job]
+}
+
+// Can returns true if job is allowed for the account and supported by the
+// institute, e.g. Can(CamtAccountTransactionsJob) tells whether transactions
+// can be fetched in camt format.
+func (c *Capabilities) Can(job string) bool {
+	return c.Allowed(job) && len(c.Versions(job)) > 0
+}
+
+// NeededSignatures returns the number of signatures needed for job, as stated
+// within the UPD.
+func (c *Capabilities) NeededSignatures(job string) int {
+	return c.jobs[job].NeededSignatures
+}
+
+// Limit returns the limit for job, e.g. the daily limit for transfers, or nil
+// if the UPD state none. The UPD only contain the granted limit, the amount
+// already used within the period of the limit is not known to the client.
+func (c *Capabilities) Limit(job string) *domain.AccountLimit {
+	return c.jobs[job].Limit
+}
+
+// AccountLimit returns the limit of the account, e.g. the overdraft, or nil
+// if the UPD state none.
+func (c *Capabilities) AccountLimit() *domain.AccountLimit {
+	return c.Account.Limit
+}
+
+// Jobs returns the IDs of all jobs the UPD list for the account, sorted
+// ascending.
+func (c *Capabilities) Jobs() []string {
+	jobs := make([]string, 0, len(c.jobs))
+	for job := range c.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Strings(jobs)
+	return jobs
+}
+
+// Capabilities returns what can be done with account according to the UPD
+// and BPD. It returns an error if the UPD do not contain account.
+func (c *Client) Capabilities(account domain.AccountConnection) (*Capabilities, error) {
+	return c.CapabilitiesContext(context.Background(), account)
+}
+
+// CapabilitiesContext is like Capabilities, but aborts when ctx is done.
+func (c *Client) CapabilitiesContext(ctx context.Context, account domain.AccountConnection) (*Capabilities, error) {
+	if err := c.init(ctx); err != nil {
+		return nil, err
+	}
+	if len(c.pinTanDialog.Accounts) == 0 {
+		if err := c.pinTanDialog.SyncUserParameterDataContext(ctx); err != nil {
+			return nil, fmt.Errorf("error getting accounts: %w", err)
+		}
+	}
+	capabilities, ok := c.capabilities(account)
+	if !ok {
+		return nil, fmt.Errorf("account %s not found within the user parameter data", account.AccountID)
+	}
+	return capabilities, nil
+}
+
+// capabilities returns the capabilities of account from the known UPD and
+// BPD, without contacting the institute.
+func (c *Client) capabilities(account domain.AccountConnection) (*Capabilities, bool) {
+	var info *domain.AccountInformation
+	for i, acc := range c.pinTanDialog.Accounts {
+		if acc.AccountConnection.AccountID == account.AccountID && acc.AccountConnection.BankID == account.BankID {
+			info = &c.pinTanDialog.Accounts[i]
+			break
+		}
+	}
+	if info == nil {
+		return nil, false
+	}
+	capabilities := &Capabilities{
+		Account: *info,
+		// without any listed jobs the UPD make no statement about the account
+		restricted: c.pinTanDialog.UserParameterData.Usage == updUsageRestricted && len(info.AllowedBusinessTransactions) > 0,
+		jobs:       make(map[string]domain.BusinessTransaction),
+		versions:   make(map[string][]int),
+	}
+	for _, job := range info.AllowedBusinessTransactions {
+		capabilities.jobs[job.ID] = job
+	}
+	for _, seg := range c.pinTanDialog.SupportedSegments() {
+		job, ok := jobID(seg.ID)
+		if !ok {
+			continue
+		}
+		capabilities.versions[job] = append(capabilities.versions[job], seg.Version)
+	}
+	for _, versions := range capabilities.versions {
+		sort.Ints(versions)
+	}
+	return capabilities, true
+}
+
+// checkAllowed returns a JobNotAllowedError if the known UPD do not allow job
+// for account. Accounts missing within the UPD are not checked.
+func (c *Client) checkAllowed(account domain.AccountConnection, job string) error {
+	capabilities, ok := c.capabilities(account)
+	if ok && !capabilities.Allowed(job) {
+		return &JobNotAllowedError{Account: account, Job: job}
+	}
+	return nil
+}
+
+// jobID returns the ID of the job a parameter segment of the BPD belongs to.
+// The parameters of a job HKXXX are named HIXXXS.
+func jobID(parameterID string) (string, bool) {
+	if len(parameterID) != 6 || parameterID[1] != 'I' || parameterID[5] != 'S' {
+		return "", false
+	}
+	return parameterID[:1] + "K" + parameterID[2:5], true
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+	https "github.com/mitch000001/go-hbci/transport/https"
+)
+
+func TestClientCapabilities(t *testing.T) {
+	transport := &https.MockHTTPTransport{}
+	defer setMockHTTPTransport(transport)()
+
+	c := newTestClient()
+
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+
+	syncResponse := encryptedTestMessage(
+		"abcde",
+		"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+		"HISYN:193:4:5+LRZYhZNbV2IBAAAd0?+VNqlkXrAQA'",
+		"HIBPA:2:2:+12+280:10000000+Bank Name+3+1+201:210:220+0'",
+		"HISALS:3:5:4+3+1'",
+		"HISALS:4:6:4+3+1'",
+		"HICAZS:5:1:4+1+1'",
+		"HIUPA:6:4:4+12345+3+0'",
+		"HIUPD:7:6:4+100000000::280:10000000++12345+1+EUR+Muster+Max+Girokonto+T:5000,:EUR:1+HKSAL:1+HKCCS:1:T:1000,:EUR:1'",
+	)
+	dialogEndResponseMessage := encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Der Auftrag wurde ausgeführt'")
+
+	transport.SetResponsePayloads([][]byte{
+		syncResponse,
+		dialogEndResponseMessage,
+	})
+
+	capabilities, err := c.Capabilities(account)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	if !capabilities.Can(AccountBalancesJob) {
+		t.Logf("Expected balances to be available\n")
+		t.Fail()
+	}
+	if versions := capabilities.Versions(AccountBalancesJob); !reflect.DeepEqual(versions, []int{5, 6}) {
+		t.Logf("Expected balance versions to equal [5 6], got %v\n", versions)
+		t.Fail()
+	}
+	if capabilities.Can(CamtAccountTransactionsJob) {
+		t.Logf("Expected camt transactions not to be allowed by the UPD\n")
+		t.Fail()
+	}
+	if capabilities.Allowed(AccountTransactionsJob) {
+		t.Logf("Expected transactions not to be allowed by the UPD\n")
+		t.Fail()
+	}
+
+	expectedTransferLimit := &domain.AccountLimit{Kind: "T", Amount: domain.Amount{Amount: 1000, Currency: "EUR"}, Days: 1}
+	if limit := capabilities.Limit(SepaTransferJob); !reflect.DeepEqual(limit, expectedTransferLimit) {
+		t.Logf("Expected transfer limit to equal %+v, got %+v\n", expectedTransferLimit, limit)
+		t.Fail()
+	}
+	expectedAccountLimit := &domain.AccountLimit{Kind: "T", Amount: domain.Amount{Amount: 5000, Currency: "EUR"}, Days: 1}
+	if limit := capabilities.AccountLimit(); !reflect.DeepEqual(limit, expectedAccountLimit) {
+		t.Logf("Expected account limit to equal %+v, got %+v\n", expectedAccountLimit, limit)
+		t.Fail()
+	}
+	if jobs := capabilities.Jobs(); !reflect.DeepEqual(jobs, []string{"HKCCS", "HKSAL"}) {
+		t.Logf("Expected jobs to equal [HKCCS HKSAL], got %v\n", jobs)
+		t.Fail()
+	}
+
+	_, err = c.AccountTransactions(account, domain.Timeframe{}, false, "")
+
+	var notAllowedErr *JobNotAllowedError
+	if !errors.As(err, &notAllowedErr) {
+		t.Logf("Expected error to be a JobNotAllowedError, got %T:%v\n", err, err)
+		t.Fail()
+	}
+	if transport.CallCount() != 2 {
+		t.Logf("Expected the job not to be sent, got %d requests\n", transport.CallCount())
+		t.Fail()
+	}
+}
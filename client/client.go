@@ -1,35 +1,59 @@
 package client
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/mitch000001/go-hbci/bankinfo"
 	"github.com/mitch000001/go-hbci/dialog"
 	"github.com/mitch000001/go-hbci/domain"
-	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/fints4"
 	"github.com/mitch000001/go-hbci/message"
 	"github.com/mitch000001/go-hbci/segment"
 	"github.com/mitch000001/go-hbci/swift"
 	"github.com/mitch000001/go-hbci/transport"
+	middleware "github.com/mitch000001/go-hbci/transport/middleware"
 )
 
 // Config defines the basic configuration needed for a Client to work.
 type Config struct {
-	BankID      string `json:"bank_id"`
-	AccountID   string `json:"account_id"`
-	PIN         string `json:"pin"`
-	URL         string `json:"url"`
-	HBCIVersion int    `json:"hbci_version"`
-	Transport   transport.Transport
+	BankID      string              `json:"bank_id"`
+	AccountID   string              `json:"account_id"`
+	PIN         string              `json:"pin"`
+	URL         string              `json:"url"`
+	HBCIVersion int                 `json:"hbci_version"`
+	Transport   transport.Transport `json:"-"`
+	// StateStore persists the client system ID, BPD and UPD between
+	// clients. Without a StateStore every new Client registers a new client
+	// system ID at the bank institute.
+	StateStore dialog.StateStore `json:"-"`
+	// Logger receives the log records of the client. If nil, the package
+	// wide loggers are used.
+	Logger *slog.Logger `json:"-"`
+	// LogRedaction configures which data are masked within logged
+	// messages. PINs and TANs are always masked.
+	LogRedaction middleware.Redaction `json:"log_redaction"`
+	// Observer gets notified about the course of the dialogs, e.g. to
+	// collect metrics.
+	Observer dialog.Observer `json:"-"`
+	// EnableCompression compresses messages, if the institute supports
+	// deflate. Compression is disabled by default.
+	EnableCompression bool `json:"enable_compression"`
+	// MaxPages limits the number of pages fetched for a single request. Zero
+	// means no limit. See Pager.
+	MaxPages int `json:"max_pages"`
 }
 
-func (c Config) hbciVersion() (segment.HBCIVersion, error) {
-	version, ok := segment.SupportedHBCIVersions[c.HBCIVersion]
+// segmentVersion returns the HBCIVersion building the segments for version.
+// FinTS 4.1 is handled by package fints4 and has no HBCIVersion.
+func segmentVersion(version int) (segment.HBCIVersion, error) {
+	hbciVersion, ok := segment.SupportedHBCIVersions[version]
 	if !ok {
-		return version, fmt.Errorf("Unsupported HBCI version. Supported versions are %v", domain.SupportedHBCIVersions)
+		return hbciVersion, fmt.Errorf("Unsupported HBCI version. Supported versions are %v and %d", domain.SupportedHBCIVersions, domain.FINTSVersion410)
 	}
-	return version, nil
+	return hbciVersion, nil
 }
 
 // New creates a new HBCI client. It returns an error if the provided
@@ -37,45 +61,49 @@ func (c Config) hbciVersion() (segment.HBCIVersion, error) {
 // bank institute database for the provided BankID.
 //
 // If the provided Config does not provide a URL or a HBCI-Version it will be
-// looked up in the bankinfo database.
+// looked up in the bankinfo database. For institutes speaking FinTS 4.1 the
+// client uses the XML message format of package fints4.
 func New(config Config) (*Client, error) {
 	bankID := domain.BankID{
 		CountryCode: 280,
 		ID:          config.BankID,
 	}
 	bankInfo := bankinfo.FindByBankID(config.BankID)
-	var (
-		url         string
-		hbciVersion segment.HBCIVersion
-	)
+	var url string
 	if config.URL != "" {
 		url = config.URL
 	} else {
 		url = bankInfo.URL
 	}
-	if config.HBCIVersion > 0 {
-		version, err := config.hbciVersion()
-		if err != nil {
-			return nil, err
-		}
-		hbciVersion = version
-	} else {
-		version, ok := segment.SupportedHBCIVersions[bankInfo.HbciVersion()]
-		if !ok {
-			return nil, fmt.Errorf("Unsupported HBCI version. Supported versions are %v", domain.SupportedHBCIVersions)
-		}
-		hbciVersion = version
+	version := config.HBCIVersion
+	if version <= 0 {
+		version = bankInfo.HbciVersion()
+	}
+	if version == domain.FINTSVersion410 {
+		return newFinTS4Client(config, bankID, url), nil
+	}
+	hbciVersion, err := segmentVersion(version)
+	if err != nil {
+		return nil, err
 	}
 	dcfg := dialog.Config{
-		BankID:      bankID,
-		HBCIURL:     url,
-		UserID:      config.AccountID,
-		HBCIVersion: hbciVersion,
-		Transport:   config.Transport,
+		BankID:            bankID,
+		HBCIURL:           url,
+		UserID:            config.AccountID,
+		HBCIVersion:       hbciVersion,
+		Transport:         config.Transport,
+		StateStore:        config.StateStore,
+		Logger:            config.Logger,
+		LogRedaction:      config.LogRedaction,
+		Observer:          config.Observer,
+		EnableCompression: config.EnableCompression,
 	}
 
 	d := dialog.NewPinTanDialog(dcfg)
 	d.SetPin(config.PIN)
+	if err := d.LoadState(); err != nil {
+		return nil, err
+	}
 	client := &Client{
 		config:       config,
 		hbciVersion:  hbciVersion,
@@ -92,13 +120,93 @@ type Client struct {
 	config       Config
 	hbciVersion  segment.HBCIVersion
 	pinTanDialog *dialog.PinTanDialog
+	session      *dialog.Session
+	// fints4Dialog is set instead of pinTanDialog if the institute speaks
+	// FinTS 4.1.
+	fints4Dialog *fints4.Dialog
+}
+
+// Batch runs fn with a Client whose requests all share one dialog. This saves
+// a dialog initialization and a dialog end for every request made within fn
+// and, depending on the institute, additional TANs.
+//
+// The Client passed to fn must not be used after fn returned. Nested calls to
+// Batch reuse the already opened dialog.
+func (c *Client) Batch(ctx context.Context, fn func(*Client) error) error {
+	if c.session != nil {
+		return fn(c)
+	}
+	if err := c.init(ctx); err != nil {
+		return err
+	}
+	session, err := c.pinTanDialog.OpenContext(ctx)
+	if err != nil {
+		return err
+	}
+	batchClient := *c
+	batchClient.session = session
+	err = fn(&batchClient)
+	closeErr := session.CloseContext(ctx)
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return fmt.Errorf("error ending dialog: %w", closeErr)
+	}
+	return nil
 }
 
-func (c *Client) init() error {
+// TANRequiredError is returned when the institute needs a TAN to execute the
+// jobs. Response holds the answer of the institute including the challenge
+// within the HITAN segment.
+type TANRequiredError struct {
+	Response message.BankMessage
+}
+
+func (t *TANRequiredError) Error() string {
+	return "TAN required to execute the jobs"
+}
+
+// Is reports whether target is domain.ErrTANRequired
+func (t *TANRequiredError) Is(target error) bool {
+	return target == domain.ErrTANRequired
+}
+
+// send sends the jobs within the dialog of the current batch or within a new
+// dialog if c is not part of a batch. If the institute needs a TAN, the
+// response is returned together with a *TANRequiredError.
+func (c *Client) send(ctx context.Context, jobs ...segment.ClientSegment) (message.BankMessage, error) {
+	if c.fints4Dialog != nil {
+		return nil, ErrNotSupportedByFinTS4
+	}
+	tanRequest, err := c.hbciVersion.NewTanProcess4Request(segment.IdentificationID)
+	if err != nil {
+		return nil, err
+	}
+	jobs = append([]segment.ClientSegment{tanRequest}, jobs...)
+	var response message.BankMessage
+	if c.session != nil {
+		response, err = c.session.SendContext(ctx, jobs...)
+	} else {
+		response, err = c.pinTanDialog.SendMessageContext(ctx, message.NewHBCIMessage(c.hbciVersion, jobs...))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if response.TANRequired() {
+		return response, &TANRequiredError{Response: response}
+	}
+	return response, nil
+}
+
+func (c *Client) init(ctx context.Context) error {
+	if c.fints4Dialog != nil {
+		return ErrNotSupportedByFinTS4
+	}
 	if c.pinTanDialog.BankParameterDataVersion() == 0 {
-		_, err := c.pinTanDialog.SyncClientSystemID()
+		_, err := c.pinTanDialog.SyncClientSystemIDContext(ctx)
 		if err != nil {
-			return fmt.Errorf("error while fetching accounts: %v", err)
+			return fmt.Errorf("error while fetching accounts: %w", err)
 		}
 	}
 	return nil
@@ -106,12 +214,17 @@ func (c *Client) init() error {
 
 // Accounts return the basic account information for the provided client config.
 func (c *Client) Accounts() ([]domain.AccountInformation, error) {
-	if err := c.init(); err != nil {
+	return c.AccountsContext(context.Background())
+}
+
+// AccountsContext is like Accounts, but aborts when ctx is done.
+func (c *Client) AccountsContext(ctx context.Context) ([]domain.AccountInformation, error) {
+	if err := c.init(ctx); err != nil {
 		return nil, err
 	}
-	err := c.pinTanDialog.SyncUserParameterData()
+	err := c.pinTanDialog.SyncUserParameterDataContext(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error getting accounts")
+		return nil, fmt.Errorf("error getting accounts: %w", err)
 	}
 	return c.pinTanDialog.Accounts, nil
 }
@@ -119,16 +232,28 @@ func (c *Client) Accounts() ([]domain.AccountInformation, error) {
 // AccountTransactions return all transactions for the provided timeframe.
 // If allAccouts is true, it will fetch all transactions associated with the
 // proviced account. For the initial request no continuationReference is
-// needed, as this method will be called recursivly if the server sends one.
+// needed, as all pages are fetched if the server sends one. See Pager.
 func (c *Client) AccountTransactions(account domain.AccountConnection, timeframe domain.Timeframe, allAccounts bool, continuationReference string) ([]domain.AccountTransaction, error) {
-	if err := c.init(); err != nil {
+	return c.AccountTransactionsContext(context.Background(), account, timeframe, allAccounts, continuationReference)
+}
+
+// AccountTransactionsContext is like AccountTransactions, but aborts when ctx
+// is done.
+func (c *Client) AccountTransactionsContext(ctx context.Context, account domain.AccountConnection, timeframe domain.Timeframe, allAccounts bool, continuationReference string) ([]domain.AccountTransaction, error) {
+	if c.fints4Dialog != nil {
+		return c.fints4Dialog.AccountTransactions(ctx, account, timeframe, allAccounts, continuationReference)
+	}
+	if err := c.init(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.checkAllowed(account, AccountTransactionsJob); err != nil {
 		return nil, err
 	}
 	requestBuilder := func() (segment.AccountTransactionRequest, error) {
 		builder := segment.NewBuilder(c.pinTanDialog.SupportedSegments())
 		return builder.AccountTransactionRequest(account, allAccounts)
 	}
-	bookedSwiftTransactions, err := c.accountTransactions(requestBuilder, timeframe, continuationReference)
+	bookedSwiftTransactions, err := c.accountTransactions(ctx, requestBuilder, timeframe, continuationReference)
 	if err != nil {
 		return nil, fmt.Errorf("error executing HBCI request: %w", err)
 	}
@@ -143,16 +268,28 @@ func (c *Client) AccountTransactions(account domain.AccountConnection, timeframe
 // SepaAccountTransactions return all transactions for the provided timeframe.
 // If allAccouts is true, it will fetch all transactions associated with the
 // provided account. For the initial request no continuationReference is
-// needed, as this method will be called recursivly if the server sends one.
+// needed, as all pages are fetched if the server sends one. See Pager.
 func (c *Client) SepaAccountTransactions(account domain.InternationalAccountConnection, timeframe domain.Timeframe, allAccounts bool, continuationReference string) ([]domain.AccountTransaction, error) {
-	if err := c.init(); err != nil {
+	return c.SepaAccountTransactionsContext(context.Background(), account, timeframe, allAccounts, continuationReference)
+}
+
+// SepaAccountTransactionsContext is like SepaAccountTransactions, but aborts
+// when ctx is done.
+func (c *Client) SepaAccountTransactionsContext(ctx context.Context, account domain.InternationalAccountConnection, timeframe domain.Timeframe, allAccounts bool, continuationReference string) ([]domain.AccountTransaction, error) {
+	if c.fints4Dialog != nil {
+		return c.fints4Dialog.AccountTransactions(ctx, account.ToAccountConnection(), timeframe, allAccounts, continuationReference)
+	}
+	if err := c.init(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.checkAllowed(account.ToAccountConnection(), AccountTransactionsJob); err != nil {
 		return nil, err
 	}
 	requestBuilder := func() (segment.AccountTransactionRequest, error) {
 		builder := segment.NewBuilder(c.pinTanDialog.SupportedSegments())
 		return builder.SepaAccountTransactionRequest(account, allAccounts)
 	}
-	bookedSwiftTransactions, err := c.accountTransactions(requestBuilder, timeframe, continuationReference)
+	bookedSwiftTransactions, err := c.accountTransactions(ctx, requestBuilder, timeframe, continuationReference)
 	if err != nil {
 		return nil, fmt.Errorf("error executing HBCI request: %w", err)
 	}
@@ -164,60 +301,57 @@ func (c *Client) SepaAccountTransactions(account domain.InternationalAccountConn
 	return tx, nil
 }
 
-func (c *Client) accountTransactions(requestBuilder func() (segment.AccountTransactionRequest, error), timeframe domain.Timeframe, continuationReference string) (*swift.MT940Messages, error) {
-	accountTransactionRequest, err := requestBuilder()
-	if err != nil {
-		return nil, fmt.Errorf("error building request: %w", err)
-	}
-	accountTransactionRequest.SetTransactionRange(timeframe)
-	if continuationReference != "" {
-		accountTransactionRequest.SetContinuationReference(continuationReference)
-	}
-	decryptedMessage, err := c.pinTanDialog.SendMessage(
-		message.NewHBCIMessage(c.hbciVersion, c.hbciVersion.TanProcess4Request(segment.IdentificationID), accountTransactionRequest),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("error sending hbci request: %w", err)
+func (c *Client) accountTransactions(ctx context.Context, requestBuilder func() (segment.AccountTransactionRequest, error), timeframe domain.Timeframe, continuationReference string) (*swift.MT940Messages, error) {
+	build := func() (PagedRequest, error) {
+		request, err := requestBuilder()
+		if err != nil {
+			return nil, err
+		}
+		request.SetTransactionRange(timeframe)
+		return request, nil
 	}
 	var bookedSwiftTransactions []*swift.MT940Messages
-	accountTransactionResponses := decryptedMessage.FindSegments("HIKAZ")
-	for _, unmarshaledSegment := range accountTransactionResponses {
-		seg, ok := unmarshaledSegment.(segment.AccountTransactionResponse)
-		if !ok {
-			return nil, fmt.Errorf("malformed segment found with ID `HIKAZ`")
+	// fetch all pages within one dialog
+	err := c.Batch(ctx, func(b *Client) error {
+		pager := b.Pager(ctx, build, continuationReference, b.config.MaxPages)
+		for pager.Next() {
+			for _, unmarshaledSegment := range pager.Page().FindSegments("HIKAZ") {
+				seg, ok := unmarshaledSegment.(segment.AccountTransactionResponse)
+				if !ok {
+					return fmt.Errorf("malformed segment found with ID `HIKAZ`")
+				}
+				bookedSwiftTransactions = append(bookedSwiftTransactions, seg.BookedSwiftTransactions())
+			}
 		}
-		bookedSwiftTransactions = append(bookedSwiftTransactions, seg.BookedSwiftTransactions())
-	}
-	var newContinuationReference string
-	acknowledgements := decryptedMessage.Acknowledgements()
-	for _, ack := range acknowledgements {
-		if ack.Code == element.AcknowledgementAdditionalInformation {
-			newContinuationReference = ack.Params[0]
-			break
+		if err := pager.Err(); err != nil {
+			return fmt.Errorf("error sending hbci request: %w", err)
 		}
-	}
-	tx := swift.MergeMT940Messages(bookedSwiftTransactions...)
-	if newContinuationReference == "" {
-		return tx, nil
-	}
-	msg, err := c.accountTransactions(requestBuilder, timeframe, newContinuationReference)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	return swift.MergeMT940Messages(msg, tx), err
+	return swift.MergeMT940Messages(bookedSwiftTransactions...), nil
 }
 
 // AccountInformation will print all information attached to the provided
 // account. If allAccounts is true it will fetch also the information
 // associated with the account.
 func (c *Client) AccountInformation(account domain.AccountConnection, allAccounts bool) error {
-	if err := c.init(); err != nil {
+	return c.AccountInformationContext(context.Background(), account, allAccounts)
+}
+
+// AccountInformationContext is like AccountInformation, but aborts when ctx is
+// done.
+func (c *Client) AccountInformationContext(ctx context.Context, account domain.AccountConnection, allAccounts bool) error {
+	if err := c.init(ctx); err != nil {
+		return err
+	}
+	if err := c.checkAllowed(account, AccountInformationJob); err != nil {
 		return err
 	}
 	accountInformationRequest := segment.NewAccountInformationRequestSegmentV1(account, allAccounts)
-	decryptedMessage, err := c.pinTanDialog.SendMessage(
-		message.NewHBCIMessage(c.hbciVersion, c.hbciVersion.TanProcess4Request(segment.IdentificationID), accountInformationRequest),
-	)
+	decryptedMessage, err := c.send(ctx, accountInformationRequest)
 	if err != nil {
 		return err
 	}
@@ -233,39 +367,46 @@ func (c *Client) AccountInformation(account domain.AccountConnection, allAccount
 // If allAccounts is true it will fetch also the balances for all accounts
 // associated with the account.
 func (c *Client) AccountBalances(account domain.AccountConnection, allAccounts bool) ([]domain.AccountBalance, error) {
-	if err := c.init(); err != nil {
-		return nil, err
+	return c.AccountBalancesContext(context.Background(), account, allAccounts)
+}
+
+// AccountBalancesContext is like AccountBalances, but aborts when ctx is done.
+func (c *Client) AccountBalancesContext(ctx context.Context, account domain.AccountConnection, allAccounts bool) ([]domain.AccountBalance, error) {
+	if c.fints4Dialog != nil {
+		return c.fints4Dialog.AccountBalances(ctx, account, allAccounts)
 	}
-	builder := segment.NewBuilder(c.pinTanDialog.SupportedSegments())
-	accountBalanceRequest, err := builder.AccountBalanceRequest(account, allAccounts)
-	if err != nil {
+	if err := c.init(ctx); err != nil {
 		return nil, err
 	}
-	decryptedMessage, err := c.pinTanDialog.SendMessage(
-		message.NewHBCIMessage(
-			c.hbciVersion,
-			c.hbciVersion.TanProcess4Request(segment.IdentificationID),
-			accountBalanceRequest,
-		),
-	)
-	if err != nil {
+	if err := c.checkAllowed(account, AccountBalancesJob); err != nil {
 		return nil, err
 	}
+	build := func() (PagedRequest, error) {
+		builder := segment.NewBuilder(c.pinTanDialog.SupportedSegments())
+		return builder.AccountBalanceRequest(account, allAccounts)
+	}
 	var balances []domain.AccountBalance
-	balanceResponses := decryptedMessage.FindMarshaledSegments("HISAL")
-	if balanceResponses != nil {
-		for _, marshaledSegment := range balanceResponses {
-			balanceSegment := &segment.AccountBalanceResponseSegment{}
-			err = balanceSegment.UnmarshalHBCI(marshaledSegment)
-			if err != nil {
-				return nil, fmt.Errorf("error while parsing account balance: %v", err)
+	err := c.Batch(ctx, func(b *Client) error {
+		pager := b.Pager(ctx, build, "", b.config.MaxPages)
+		for pager.Next() {
+			balanceResponses := pager.Page().FindMarshaledSegments("HISAL")
+			if balanceResponses == nil {
+				return fmt.Errorf("malformed response: expected HISAL segment")
+			}
+			for _, marshaledSegment := range balanceResponses {
+				balanceSegment := &segment.AccountBalanceResponseSegment{}
+				err := balanceSegment.UnmarshalHBCI(marshaledSegment)
+				if err != nil {
+					return fmt.Errorf("error while parsing account balance: %v", err)
+				}
+				balances = append(balances, balanceSegment.AccountBalance())
 			}
-			balances = append(balances, balanceSegment.AccountBalance())
 		}
-	} else {
-		return nil, fmt.Errorf("malformed response: expected HISAL segment")
+		return pager.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
-
 	return balances, nil
 }
 
@@ -273,26 +414,32 @@ func (c *Client) AccountBalances(account domain.AccountConnection, allAccounts b
 // If a continuationReference is present, the status information attached to it
 // will be fetched.
 func (c *Client) Status(from, to time.Time, maxEntries int, continuationReference string) ([]domain.StatusAcknowledgement, error) {
-	if err := c.init(); err != nil {
+	return c.StatusContext(context.Background(), from, to, maxEntries, continuationReference)
+}
+
+// StatusContext is like Status, but aborts when ctx is done.
+func (c *Client) StatusContext(ctx context.Context, from, to time.Time, maxEntries int, continuationReference string) ([]domain.StatusAcknowledgement, error) {
+	if err := c.init(ctx); err != nil {
 		return nil, err
 	}
-	builder := segment.NewBuilder(c.pinTanDialog.SupportedSegments())
-	statusRequest, err := builder.StatusProtocolRequest(from, to, maxEntries, continuationReference)
-	if err != nil {
-		return nil, err
+	build := func() (PagedRequest, error) {
+		builder := segment.NewBuilder(c.pinTanDialog.SupportedSegments())
+		return builder.StatusProtocolRequest(from, to, maxEntries, "")
 	}
-	bankMessage, err := c.pinTanDialog.SendMessage(
-		message.NewHBCIMessage(c.hbciVersion, c.hbciVersion.TanProcess4Request(segment.IdentificationID), statusRequest),
-	)
+	var statusAcknowledgements []domain.StatusAcknowledgement
+	err := c.Batch(ctx, func(b *Client) error {
+		pager := b.Pager(ctx, build, continuationReference, b.config.MaxPages)
+		for pager.Next() {
+			for _, seg := range pager.Page().FindSegments("HIPRO") {
+				statusResponse := seg.(segment.StatusProtocolResponse)
+				statusAcknowledgements = append(statusAcknowledgements, statusResponse.Status())
+			}
+		}
+		return pager.Err()
+	})
 	if err != nil {
 		return nil, err
 	}
-	var statusAcknowledgements []domain.StatusAcknowledgement
-	statusResponses := bankMessage.FindSegments("HIPRO")
-	for _, seg := range statusResponses {
-		statusResponse := seg.(segment.StatusProtocolResponse)
-		statusAcknowledgements = append(statusAcknowledgements, statusResponse.Status())
-	}
 	return statusAcknowledgements, nil
 }
 
@@ -305,10 +452,35 @@ type AnonymousClient struct {
 // CommunicationAccess returns data used to make calls to a given institute.
 // Not yet properly implemented, therefore only the raw data are returned.
 func (a *AnonymousClient) CommunicationAccess(from, to domain.BankID, maxEntries int) ([]byte, error) {
-	commRequest := segment.NewCommunicationAccessRequestSegment(from, to, maxEntries, "")
-	decryptedMessage, err := a.pinTanDialog.SendAnonymousMessage(message.NewHBCIMessage(a.hbciVersion, commRequest))
+	return a.CommunicationAccessContext(context.Background(), from, to, maxEntries)
+}
+
+// CommunicationAccessContext is like CommunicationAccess, but aborts when ctx
+// is done.
+func (a *AnonymousClient) CommunicationAccessContext(ctx context.Context, from, to domain.BankID, maxEntries int) ([]byte, error) {
+	if a.fints4Dialog != nil {
+		return nil, ErrNotSupportedByFinTS4
+	}
+	build := func() (PagedRequest, error) {
+		return segment.NewCommunicationAccessRequestSegment(from, to, maxEntries, ""), nil
+	}
+	// continuation references are only valid within the dialog they were
+	// issued in, so all pages are fetched within one dialog
+	session, err := a.pinTanDialog.OpenAnonymousContext(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return []byte(fmt.Sprintf("%+#v", decryptedMessage)), nil
+	var data []byte
+	pager := newPager(ctx, session.SendContext, build, "", a.config.MaxPages)
+	for pager.Next() {
+		data = append(data, fmt.Sprintf("%+#v", pager.Page())...)
+	}
+	closeErr := session.CloseContext(ctx)
+	if err := pager.Err(); err != nil {
+		return nil, err
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("error ending dialog: %w", closeErr)
+	}
+	return data, nil
 }
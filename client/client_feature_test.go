@@ -144,8 +144,8 @@ func TestAnonymousClientCommunicationAccess(t *testing.T) {
 		Client: newClient(),
 	}
 
-	from := domain.BankID{280, "78050000"}
-	to := domain.BankID{280, "78050000"}
+	from := domain.BankID{CountryCode: 280, ID: "78050000"}
+	to := domain.BankID{CountryCode: 280, ID: "78050000"}
 
 	res, err := a.CommunicationAccess(from, to, 10)
 
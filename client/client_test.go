@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"net/http"
 	"reflect"
 	"testing"
@@ -93,6 +94,68 @@ func TestClientBalances(t *testing.T) {
 	}
 }
 
+func TestClientBatch(t *testing.T) {
+	transport := &https.MockHTTPTransport{}
+	defer setMockHTTPTransport(transport)()
+
+	c := newTestClient()
+
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+
+	syncResponse := encryptedTestMessage(
+		"abcde",
+		"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+		"HISYN:193:4:5+LRZYhZNbV2IBAAAd0?+VNqlkXrAQA'",
+		"HIBPA:2:2:+12+280:10000000+Bank Name+3+1+201:210:220+0'",
+		"HISALS:3:5:4+3+1'",
+	)
+	initResponse := encryptedTestMessage(
+		"abcde",
+		"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+	)
+	balanceResponse := encryptedTestMessage(
+		"abcde",
+		"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+		"HISAL:3:5:1+100000000::280:10000000+Sichteinlagen+EUR+C:1000,15:EUR:20150812+C:20,:EUR:20150812+500,:EUR+1499,85:EUR'",
+	)
+	dialogEndResponseMessage := encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Der Auftrag wurde ausgeführt'")
+
+	transport.SetResponsePayloads([][]byte{
+		syncResponse,
+		dialogEndResponseMessage,
+		initResponse,
+		balanceResponse,
+		balanceResponse,
+		dialogEndResponseMessage,
+	})
+
+	var balances []domain.AccountBalance
+	err := c.Batch(context.Background(), func(b *Client) error {
+		for i := 0; i < 2; i++ {
+			res, err := b.AccountBalances(account, false)
+			if err != nil {
+				return err
+			}
+			balances = append(balances, res...)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.Fail()
+	}
+
+	if len(balances) != 2 {
+		t.Logf("Expected 2 balances, got %d\n", len(balances))
+		t.Fail()
+	}
+
+	if transport.CallCount() != 6 {
+		t.Logf("Expected both jobs to share one dialog, got %d requests\n", transport.CallCount())
+		t.Fail()
+	}
+}
+
 func newTestClient() *Client {
 	config := Config{
 		URL:         "https://localhost",
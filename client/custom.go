@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// BuilderContext returns a segment.Builder for the segments supported by the
+// institute. Requests registered within segment.KnownRequests are built with
+// Builder.Request.
+func (c *Client) BuilderContext(ctx context.Context) (segment.Builder, error) {
+	if err := c.init(ctx); err != nil {
+		return nil, err
+	}
+	return segment.NewBuilder(c.pinTanDialog.SupportedSegments()), nil
+}
+
+// SendContext sends jobs not covered by the Client, e.g. bank specific jobs
+// defined outside of this library, and returns the response of the institute.
+// Segments of the response registered within segment.KnownSegments are
+// returned by FindSegments, all others by FindGenericSegments. Jobs answered
+// in several pages are sent with a Pager instead. If the institute needs a
+// TAN, the response is returned together with a *TANRequiredError.
+func (c *Client) SendContext(ctx context.Context, jobs ...segment.ClientSegment) (message.BankMessage, error) {
+	if err := c.init(ctx); err != nil {
+		return nil, err
+	}
+	return c.send(ctx, jobs...)
+}
@@ -0,0 +1,29 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/fints4"
+)
+
+// ErrNotSupportedByFinTS4 is returned by the methods of a Client which are
+// not yet implemented for institutes speaking FinTS 4.1
+var ErrNotSupportedByFinTS4 = errors.New("not supported with FinTS 4.1")
+
+// newFinTS4Client returns a Client which sends its requests as FinTS 4.1 XML
+// messages. It supports account balances and account transactions.
+func newFinTS4Client(config Config, bankID domain.BankID, url string) *Client {
+	d := fints4.NewDialog(fints4.Config{
+		BankID:    bankID,
+		URL:       url,
+		UserID:    config.AccountID,
+		PIN:       config.PIN,
+		Transport: config.Transport,
+		Logger:    config.Logger,
+	})
+	return &Client{
+		config:       config,
+		fints4Dialog: d,
+	}
+}
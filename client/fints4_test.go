@@ -0,0 +1,100 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/transport"
+)
+
+type xmlTestTransport struct {
+	responses [][]byte
+	requests  [][]byte
+}
+
+func (x *xmlTestTransport) Do(request *transport.Request) (*transport.Response, error) {
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return nil, err
+	}
+	x.requests = append(x.requests, body)
+	if len(x.responses) < len(x.requests) {
+		return nil, errors.New("no response left")
+	}
+	response := x.responses[len(x.requests)-1]
+	return &transport.Response{Request: request, Body: ioutil.NopCloser(bytes.NewReader(response))}, nil
+}
+
+func newFinTS4TestClient(t *testing.T, fixtures ...string) (*Client, *xmlTestTransport) {
+	xmlTransport := &xmlTestTransport{}
+	for _, fixture := range fixtures {
+		data, err := ioutil.ReadFile("../fints4/testdata/" + fixture)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		xmlTransport.responses = append(xmlTransport.responses, data)
+	}
+	c, err := New(Config{
+		URL:         "https://localhost",
+		AccountID:   "12345",
+		BankID:      "10000000",
+		PIN:         "abcde",
+		HBCIVersion: domain.FINTSVersion410,
+		Transport:   xmlTransport,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return c, xmlTransport
+}
+
+func TestNewFinTS4Client(t *testing.T) {
+	c, _ := newFinTS4TestClient(t)
+
+	if c.fints4Dialog == nil || c.pinTanDialog != nil {
+		t.Logf("Expected a FinTS 4.1 dialog to be used\n")
+		t.Fail()
+	}
+
+	_, err := c.Accounts()
+	if !errors.Is(err, ErrNotSupportedByFinTS4) {
+		t.Logf("Expected ErrNotSupportedByFinTS4, got %v\n", err)
+		t.Fail()
+	}
+
+	c = newTestClient()
+	if c.fints4Dialog != nil || c.pinTanDialog == nil {
+		t.Logf("Expected a FinTS 3.0 dialog to be used for HBCI 2.2\n")
+		t.Fail()
+	}
+
+	_, err = New(Config{URL: "https://localhost", AccountID: "12345", BankID: "10000000", HBCIVersion: 400})
+	if err == nil || !strings.Contains(err.Error(), "[220 300] and 410") {
+		t.Logf("Expected an error listing the supported versions, got %v\n", err)
+		t.Fail()
+	}
+}
+
+func TestFinTS4ClientBalances(t *testing.T) {
+	c, xmlTransport := newFinTS4TestClient(t, "init_response.xml", "balance_response.xml", "end_response.xml")
+	account := domain.AccountConnection{AccountID: "1000000000", CountryCode: 280, BankID: "10000000"}
+
+	balances, err := c.AccountBalancesContext(context.Background(), account, false)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(balances) != 1 || balances[0].BookedBalance.Amount.Amount != -1234.56 {
+		t.Logf("Expected a booked balance of -1234.56, got %v\n", balances)
+		t.Fail()
+	}
+	if len(xmlTransport.requests) != 3 || !bytes.Contains(xmlTransport.requests[1], []byte("<AcctBal_2_Req ")) {
+		t.Logf("Expected balance to be requested as XML, got %q\n", xmlTransport.requests)
+		t.Fail()
+	}
+}
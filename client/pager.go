@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/internal"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// ErrMaxPagesReached is returned by a Pager if the institute provides more
+// pages than allowed.
+var ErrMaxPagesReached = errors.New("maximum number of pages reached")
+
+// ErrPagerOutsideBatch is returned by a Pager which is not used within
+// Client.Batch.
+var ErrPagerOutsideBatch = errors.New("pager used outside of Client.Batch")
+
+// PagedRequest is a job the institute may answer in several pages. The
+// continuation reference returned along with a page is set on the request
+// to fetch the next one.
+type PagedRequest interface {
+	segment.ClientSegment
+	SetContinuationReference(continuationReference string)
+}
+
+// PagedRequestBuilder builds a new request for every page.
+type PagedRequestBuilder func() (PagedRequest, error)
+
+// Pager sends a PagedRequest until the institute provides no more pages. The
+// pages are fetched lazily by calling Next. Continuation references are only
+// valid within the dialog they were returned in, so a Pager must be used
+// within Client.Batch:
+//
+//	err := c.Batch(ctx, func(b *Client) error {
+//		pager := b.Pager(ctx, build, "", 0)
+//		for pager.Next() {
+//			page := pager.Page()
+//			...
+//		}
+//		return pager.Err()
+//	})
+//
+// Outside of Client.Batch the Pager fails with ErrPagerOutsideBatch.
+type Pager struct {
+	pages *internal.ContinuationIterator[message.BankMessage]
+}
+
+// Pager returns a Pager for the requests built by build, starting at
+// continuationReference. If maxPages is greater than zero, the Pager fails
+// with ErrMaxPagesReached instead of fetching more than maxPages pages.
+func (c *Client) Pager(ctx context.Context, build PagedRequestBuilder, continuationReference string, maxPages int) *Pager {
+	if c.session == nil {
+		send := func(context.Context, ...segment.ClientSegment) (message.BankMessage, error) {
+			return nil, ErrPagerOutsideBatch
+		}
+		return newPager(ctx, send, build, continuationReference, maxPages)
+	}
+	return newPager(ctx, c.send, build, continuationReference, maxPages)
+}
+
+func newPager(
+	ctx context.Context,
+	send func(context.Context, ...segment.ClientSegment) (message.BankMessage, error),
+	build PagedRequestBuilder,
+	continuationReference string,
+	maxPages int,
+) *Pager {
+	pages := 0
+	fetch := func(continuationReference string) (message.BankMessage, string, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, "", err
+		}
+		if maxPages > 0 && pages >= maxPages {
+			return nil, "", ErrMaxPagesReached
+		}
+		request, err := build()
+		if err != nil {
+			return nil, "", fmt.Errorf("error building request: %w", err)
+		}
+		if continuationReference != "" {
+			request.SetContinuationReference(continuationReference)
+		}
+		bankMessage, err := send(ctx, request)
+		if err != nil {
+			return nil, "", err
+		}
+		pages++
+		return bankMessage, continuationReferenceFrom(bankMessage), nil
+	}
+	return &Pager{pages: internal.NewContinuationIterator(continuationReference, fetch)}
+}
+
+// Next fetches the next page. It returns false when there are no more pages
+// or an error occurred, which is returned by Err.
+func (p *Pager) Next() bool {
+	return p.pages.Next()
+}
+
+// Page returns the response fetched by the last call to Next.
+func (p *Pager) Page() message.BankMessage {
+	return p.pages.Page()
+}
+
+// Err returns the error which stopped the Pager, if any.
+func (p *Pager) Err() error {
+	return p.pages.Err()
+}
+
+// ContinuationReference returns the reference to the next page. It can be
+// used to resume paging later, e.g. after ErrMaxPagesReached.
+func (p *Pager) ContinuationReference() string {
+	return p.pages.ContinuationReference()
+}
+
+// continuationReferenceFrom returns the continuation reference the institute
+// attached to bankMessage, if any.
+func continuationReferenceFrom(bankMessage message.BankMessage) string {
+	for _, ack := range bankMessage.Acknowledgements() {
+		if ack.Code == element.AcknowledgementAdditionalInformation && len(ack.Params) > 0 {
+			return ack.Params[0]
+		}
+	}
+	return ""
+}
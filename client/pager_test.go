@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+type pagedTestRequest struct {
+	segment.ClientSegment
+	continuationReference string
+}
+
+func (p *pagedTestRequest) SetContinuationReference(continuationReference string) {
+	p.continuationReference = continuationReference
+}
+
+type pagedTestTransport struct {
+	responses [][]byte
+	sent      []string
+}
+
+func (p *pagedTestTransport) send(_ context.Context, jobs ...segment.ClientSegment) (message.BankMessage, error) {
+	p.sent = append(p.sent, jobs[0].(*pagedTestRequest).continuationReference)
+	response := p.responses[0]
+	p.responses = p.responses[1:]
+	header := segment.NewMessageHeaderSegment(0, 220, "abcde", 1)
+	return message.NewDecryptedMessage(header, nil, response)
+}
+
+func newPagedTestTransport() *pagedTestTransport {
+	return &pagedTestTransport{
+		responses: [][]byte{
+			[]byte("HIRMS:2:2:3+3040::Es liegen weitere Informationen vor:ref1'"),
+			[]byte("HIRMS:2:2:3+3040::Es liegen weitere Informationen vor:ref2'"),
+			[]byte("HIRMS:2:2:3+0020::Auftrag ausgeführt'"),
+		},
+	}
+}
+
+func pagedTestRequestBuilder() (PagedRequest, error) {
+	request := &pagedTestRequest{}
+	request.ClientSegment = segment.NewAccountBalanceRequestV5(domain.AccountConnection{}, false)
+	return request, nil
+}
+
+func TestPager(t *testing.T) {
+	transport := newPagedTestTransport()
+
+	pager := newPager(context.Background(), transport.send, pagedTestRequestBuilder, "", 0)
+	pages := 0
+	for pager.Next() {
+		pages++
+	}
+
+	if err := pager.Err(); err != nil {
+		t.Logf("Expected no error, got %v\n", err)
+		t.Fail()
+	}
+	if pages != 3 {
+		t.Logf("Expected 3 pages, got %d\n", pages)
+		t.Fail()
+	}
+	expected := []string{"", "ref1", "ref2"}
+	if !reflect.DeepEqual(expected, transport.sent) {
+		t.Logf("Expected continuation references\n%q\n\tgot\n%q\n", expected, transport.sent)
+		t.Fail()
+	}
+	if ref := pager.ContinuationReference(); ref != "" {
+		t.Logf("Expected no continuation reference, got %q\n", ref)
+		t.Fail()
+	}
+}
+
+func TestPagerMaxPages(t *testing.T) {
+	transport := newPagedTestTransport()
+
+	pager := newPager(context.Background(), transport.send, pagedTestRequestBuilder, "", 2)
+	pages := 0
+	for pager.Next() {
+		pages++
+	}
+
+	if !errors.Is(pager.Err(), ErrMaxPagesReached) {
+		t.Logf("Expected error %q, got %v\n", ErrMaxPagesReached, pager.Err())
+		t.Fail()
+	}
+	if pages != 2 {
+		t.Logf("Expected 2 pages, got %d\n", pages)
+		t.Fail()
+	}
+	if ref := pager.ContinuationReference(); ref != "ref2" {
+		t.Logf("Expected continuation reference %q to resume from, got %q\n", "ref2", ref)
+		t.Fail()
+	}
+}
+
+func TestPagerContextCancelled(t *testing.T) {
+	transport := newPagedTestTransport()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pager := newPager(ctx, transport.send, pagedTestRequestBuilder, "", 0)
+	pages := 0
+	for pager.Next() {
+		pages++
+		cancel()
+	}
+
+	if !errors.Is(pager.Err(), context.Canceled) {
+		t.Logf("Expected error %q, got %v\n", context.Canceled, pager.Err())
+		t.Fail()
+	}
+	if pages != 1 || len(transport.sent) != 1 {
+		t.Logf("Expected paging to stop after the first page, got %d pages\n", pages)
+		t.Fail()
+	}
+}
+
+func TestClientPagerOutsideBatch(t *testing.T) {
+	c := &Client{}
+
+	pager := c.Pager(context.Background(), pagedTestRequestBuilder, "ref1", 0)
+
+	if pager.Next() {
+		t.Logf("Expected no page to be fetched outside of a batch\n")
+		t.Fail()
+	}
+	if !errors.Is(pager.Err(), ErrPagerOutsideBatch) {
+		t.Logf("Expected error %q, got %v\n", ErrPagerOutsideBatch, pager.Err())
+		t.Fail()
+	}
+	if ref := pager.ContinuationReference(); ref != "ref1" {
+		t.Logf("Expected continuation reference %q to be kept, got %q\n", "ref1", ref)
+		t.Fail()
+	}
+}
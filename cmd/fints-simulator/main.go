@@ -0,0 +1,31 @@
+// Command fints-simulator serves a simulated bank institute over HTTP.
+//
+// Without a fixture it uses fintstest.DefaultFixture:
+//
+//	fints-simulator -addr localhost:8080 -fixture bank.json
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/mitch000001/go-hbci/fintstest"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "the address to listen on")
+	fixturePath := flag.String("fixture", "", "the path to a JSON fixture")
+	flag.Parse()
+
+	fixture := fintstest.DefaultFixture()
+	if *fixturePath != "" {
+		var err error
+		fixture, err = fintstest.LoadFixture(*fixturePath)
+		if err != nil {
+			log.Fatalf("Cannot load fixture: %v", err)
+		}
+	}
+	log.Printf("Simulating bank %s at http://%s", fixture.BankID, *addr)
+	log.Fatal(http.ListenAndServe(*addr, fintstest.NewHandler(fixture)))
+}
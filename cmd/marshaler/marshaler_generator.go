@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"strings"
+
+	"github.com/mitch000001/go-hbci/generator"
+)
+
+var segmentNames string
+
+func init() {
+	flag.StringVar(&segmentNames, "segments", "", "'MyAwesomeSegment,MyOtherAwesomeSegment'")
+}
+
+func main() {
+	flag.Parse()
+	if segmentNames == "" {
+		fmt.Printf("You must provide the segments to generate the marshalers\n")
+		os.Exit(1)
+	}
+	filename := os.Getenv("GOFILE")
+	packageName := os.Getenv("GOPACKAGE")
+	fileSet := token.NewFileSet()
+	f, err := parser.ParseFile(fileSet, filename, nil, 0)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	var segments []generator.SegmentIdentifier
+	for _, name := range strings.Split(segmentNames, ",") {
+		segments = append(segments, generator.SegmentIdentifier{Name: strings.TrimSpace(name)})
+	}
+	segmentGenerator := generator.NewSegmentMarshaler(segments, packageName, fileSet, f)
+	generated, err := segmentGenerator.Generate()
+	if err != nil {
+		fmt.Printf("Error while generating Marshaler: %v\n", err)
+		os.Exit(1)
+	}
+	newFileName := strings.TrimSuffix(filename, ".go") + "_marshaler.go"
+	file, err := os.Create(newFileName)
+	if err != nil {
+		fmt.Printf("Error while creating file: %v\n", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+	fileSet = token.NewFileSet()
+	newAstFile, err := parser.ParseFile(fileSet, newFileName, generated, parser.ParseComments)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	err = printer.Fprint(file, fileSet, newAstFile)
+	if err != nil {
+		fmt.Println(err)
+	}
+}
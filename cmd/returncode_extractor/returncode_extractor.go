@@ -0,0 +1,152 @@
+// Command returncode_extractor extracts the return codes documented within
+// the PDF of "FinTS Rückmeldungscodes" and writes them one per line. It is
+// used to generate the fixture the return code catalog is checked against:
+//
+//	go run ./cmd/returncode_extractor -pdf doc/FinTS_Rueckmeldungscodes_2021-07-07_final_version.pdf
+//
+// The codes are taken from the text the content streams of the PDF draw
+// within the code column of the tables.
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// codeColumnBound is the horizontal position in points left of which the
+// code column of the return code tables is drawn. Codes within other
+// columns, e.g. within the change history, are ignored.
+const codeColumnBound = 200
+
+var (
+	streamStart = regexp.MustCompile(`stream\r?\n`)
+	textObject  = regexp.MustCompile(`(?s)BT(.*?)ET`)
+	textMatrix  = regexp.MustCompile(`([\d.]+) ([\d.]+) Tm`)
+	textString  = regexp.MustCompile(`\(((?:\\.|[^\\)])*)\)`)
+	digits      = regexp.MustCompile(`^\d+$`)
+	code        = regexp.MustCompile(`^\d{4}$`)
+)
+
+func main() {
+	pdfFile := flag.String("pdf", "", "the PDF of FinTS Rückmeldungscodes")
+	outFile := flag.String("out", "", "the file to write, defaults to stdout")
+	flag.Parse()
+	if *pdfFile == "" {
+		log.Fatal("No PDF provided. Exiting...")
+	}
+	pdf, err := os.ReadFile(*pdfFile)
+	if err != nil {
+		log.Fatalf("Cannot read file: %v", err)
+	}
+	codes, err := extractCodes(pdf)
+	if err != nil {
+		log.Fatalf("Error while extracting codes: %v", err)
+	}
+	out := io.Writer(os.Stdout)
+	if *outFile != "" {
+		file, err := os.Create(*outFile)
+		if err != nil {
+			log.Fatalf("Cannot create file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	fmt.Fprintf(out, "# Codes defined within doc/%s,\n", filepath.Base(*pdfFile))
+	fmt.Fprintf(out, "# section B. The ranges 0950-0999, 1950-1999 and 3960-3999 are institute\n")
+	fmt.Fprintf(out, "# specific and therefore only listed with their bounds.\n")
+	fmt.Fprintf(out, "# Generated by cmd/returncode_extractor; DO NOT EDIT.\n")
+	for _, c := range codes {
+		fmt.Fprintf(out, "%s\n", c)
+	}
+}
+
+// textFragment is the text drawn within one text object
+type textFragment struct {
+	x, y float64
+	text string
+}
+
+// extractCodes returns the sorted and distinct codes within the code column
+// of pdf
+func extractCodes(pdf []byte) ([]string, error) {
+	var fragments []textFragment
+	for _, loc := range streamStart.FindAllIndex(pdf, -1) {
+		end := bytes.Index(pdf[loc[1]:], []byte("endstream"))
+		if end < 0 {
+			continue
+		}
+		content, err := inflate(pdf[loc[1] : loc[1]+end])
+		if err != nil {
+			// not a compressed content stream, e.g. an image
+			continue
+		}
+		for _, object := range textObject.FindAllSubmatch(content, -1) {
+			fragment, ok := parseTextObject(object[1])
+			if !ok {
+				continue
+			}
+			// codes may be split into several text objects on the same line
+			if last := len(fragments) - 1; last >= 0 && fragments[last].y == fragment.y &&
+				digits.MatchString(fragments[last].text) && digits.MatchString(fragment.text) {
+				fragments[last].text += fragment.text
+				continue
+			}
+			fragments = append(fragments, fragment)
+		}
+	}
+	distinct := make(map[string]bool)
+	for _, fragment := range fragments {
+		if fragment.x < codeColumnBound && code.MatchString(fragment.text) {
+			distinct[fragment.text] = true
+		}
+	}
+	if len(distinct) == 0 {
+		return nil, fmt.Errorf("no codes found")
+	}
+	var codes []string
+	for c := range distinct {
+		codes = append(codes, c)
+	}
+	sort.Strings(codes)
+	return codes, nil
+}
+
+func inflate(stream []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(stream))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// parseTextObject returns the position and the text of a text object. Only
+// literal strings are considered, which suffices for the digits of the codes.
+func parseTextObject(object []byte) (textFragment, bool) {
+	matrix := textMatrix.FindSubmatch(object)
+	if matrix == nil {
+		return textFragment{}, false
+	}
+	x, err := strconv.ParseFloat(string(matrix[1]), 64)
+	if err != nil {
+		return textFragment{}, false
+	}
+	y, err := strconv.ParseFloat(string(matrix[2]), 64)
+	if err != nil {
+		return textFragment{}, false
+	}
+	var text []byte
+	for _, str := range textString.FindAllSubmatch(object, -1) {
+		text = append(text, str[1]...)
+	}
+	return textFragment{x: x, y: y, text: string(text)}, true
+}
@@ -8,6 +8,9 @@ import (
 type BankParameterData struct {
 	domain.BankParameterData   `yaml:",inline"`
 	SupportedSegmentParameters []SegmentParameter `yaml:"supportedSegments"`
+	// CompressionFunctions contains the codes of the compression functions
+	// supported by the institute
+	CompressionFunctions []string `yaml:"compressionFunctions,omitempty"`
 }
 
 type SegmentParameter struct {
@@ -3,12 +3,11 @@ package dialog
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
-	"log"
-	"net"
-	"strconv"
-	"strings"
+	"log/slog"
 	"time"
 
 	"github.com/mitch000001/go-hbci/domain"
@@ -21,8 +20,12 @@ import (
 
 // Dialog represents the common interface to use when talking to bank institutes
 type Dialog interface {
+	Open() (*Session, error)
+	OpenContext(context.Context) (*Session, error)
 	SyncClientSystemID() (string, error)
+	SyncClientSystemIDContext(context.Context) (string, error)
 	SendMessage(message.HBCIMessage) (message.BankMessage, error)
+	SendMessageContext(context.Context, message.HBCIMessage) (message.BankMessage, error)
 }
 
 const (
@@ -31,6 +34,9 @@ const (
 	initialBankParameterDataVersion = 0
 	initialUserParameterDataVersion = 0
 	anonymousClientID               = "9999999999"
+	// dialogEndTimeout bounds the time spent to close a dialog whose
+	// context is already done.
+	dialogEndTimeout = 30 * time.Second
 )
 
 func newDialog(
@@ -53,6 +59,8 @@ func newDialog(
 		cryptoProvider:    cryptoProvider,
 		dialogID:          initialDialogID,
 		hbciVersion:       hbciVersion,
+		logger:            internal.Logger(),
+		observer:          nopObserver{},
 	}
 }
 
@@ -71,9 +79,32 @@ type dialog struct {
 	securityFn        string
 	signatureProvider message.SignatureProvider
 	cryptoProvider    message.CryptoProvider
+	// signatureVerifier verifies the signatures of the institute, if set
+	signatureVerifier message.SignatureVerifier
+	// keyBased is true for key based security methods, which need no TANs
+	keyBased bool
+	// savedSignatureID is the signature ID last written to the StateStore
+	savedSignatureID  int
 	BankParameterData BankParameterData
 	hbciVersion       segment.HBCIVersion
 	supportedSegments []segment.VersionedSegment
+	tanMedium         string
+	stateStore        StateStore
+	stateLoaded       bool
+	logger            *slog.Logger
+	observer          Observer
+	// compression is the negotiated compression function, if enabled
+	compression       message.CompressionFunction
+	enableCompression bool
+	kind              dialogKind
+	// active reports whether the dialog is initialized and not yet ended
+	active   bool
+	openedAt time.Time
+	// session is the open Session of the dialog, if any
+	session *Session
+	// the marshaled BPD and UPD segments as received from the institute
+	rawBankParameterData [][]byte
+	rawUserParameterData [][]byte
 }
 
 func (d *dialog) UserParameterDataVersion() int {
@@ -100,12 +131,85 @@ func (d *dialog) SetSecurityFunction(securityFn string) {
 	d.cryptoProvider.SetSecurityFunction(d.securityFn)
 }
 
+// SetTanMedium sets the name of the TAN medium chosen by the user.
+func (d *dialog) SetTanMedium(tanMedium string) {
+	d.tanMedium = tanMedium
+}
+
+// TanMedium returns the name of the TAN medium chosen by the user.
+func (d *dialog) TanMedium() string {
+	return d.tanMedium
+}
+
+// LoadState restores the dialog from its StateStore. It only loads the state
+// once, subsequent calls are no-ops. Without a StateStore, LoadState does
+// nothing.
+func (d *dialog) LoadState() error {
+	if d.stateLoaded {
+		return nil
+	}
+	if err := d.loadState(); err != nil {
+		return err
+	}
+	d.stateLoaded = true
+	return nil
+}
+
+// SendMessage sends the clientMessage within a new dialog. It is a shorthand
+// for SendMessageContext with a background context.
 func (d *dialog) SendMessage(clientMessage message.HBCIMessage) (message.BankMessage, error) {
-	err := d.init()
+	return d.SendMessageContext(context.Background(), clientMessage)
+}
+
+// SendMessageContext initializes a dialog, sends the clientMessage and ends
+// the dialog afterwards. The context is checked between the single messages.
+// If it is done after the dialog got initialized, the dialog is still closed
+// with a dialog end message before the context error is returned.
+//
+// If the dialog could not be ended, the response is returned along with a
+// DialogEndError.
+func (d *dialog) SendMessageContext(ctx context.Context, clientMessage message.HBCIMessage) (bankMessage message.BankMessage, err error) {
+	session, err := d.OpenContext(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { logErr(d.end()) }()
+	defer func() { err = withDialogEnd(err, func() error { return session.CloseContext(ctx) }) }()
+	return d.send(ctx, clientMessage)
+}
+
+// send signs, encrypts and sends the clientMessage within the currently
+// initialized dialog. If the institute reports outdated parameter data along
+// with a successful response, the parameter data are refreshed. If it
+// rejected the jobs because of outdated parameter data, the dialog is
+// initialized again and the message is sent once more, unless it contains
+// jobs which must not be sent twice.
+func (d *dialog) send(ctx context.Context, clientMessage message.HBCIMessage) (message.BankMessage, error) {
+	bankMessage, err := d.sendOnce(ctx, clientMessage)
+	if err == nil {
+		d.refreshParameterData(bankMessage)
+		return bankMessage, nil
+	}
+	if errors.Is(err, domain.ErrParameterDataChanged) && d.active && !jobMetadata(clientMessage).NonRetryable {
+		if err := d.reinit(ctx); err != nil {
+			return nil, fmt.Errorf("error reinitializing dialog: %w", err)
+		}
+		bankMessage, err = d.sendOnce(ctx, clientMessage)
+	}
+	if err != nil {
+		d.recoverFrom(ctx, err)
+		return nil, err
+	}
+	d.refreshParameterData(bankMessage)
+	return bankMessage, nil
+}
+
+func (d *dialog) sendOnce(ctx context.Context, clientMessage message.HBCIMessage) (message.BankMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := d.validateJobs(clientMessage.HBCISegments()); err != nil {
+		return nil, err
+	}
 	requestMessage := d.newBasicMessage(clientMessage)
 	signedMessage, err := requestMessage.Sign(d.signatureProvider)
 	if err != nil {
@@ -115,227 +219,269 @@ func (d *dialog) SendMessage(clientMessage message.HBCIMessage) (message.BankMes
 	if err != nil {
 		return nil, err
 	}
-	decryptedMessage, err := d.request(encMessage)
+	decryptedMessage, err := d.request(ctx, encMessage, jobMetadata(clientMessage))
 	if err != nil {
 		return nil, err
 	}
-	var errors []string
-	acknowledgements := decryptedMessage.Acknowledgements()
-	for _, ack := range acknowledgements {
-		if ack.IsWarning() {
-			internal.Info.Printf("%v\n", ack)
-		}
-		if ack.IsError() {
-			errors = append(errors, ack.String())
-		}
-	}
-	if len(errors) > 0 {
-		return nil, fmt.Errorf("institute returned errors:\n%s", strings.Join(errors, "\n"))
+	if err := d.checkAcknowledgements(decryptedMessage.Acknowledgements()); err != nil {
+		return nil, err
 	}
 	return decryptedMessage, nil
 }
 
 func (d *dialog) SyncUserParameterData() error {
-	internal.Info.Printf("Initializing dialog")
-	err := d.init()
+	return d.SyncUserParameterDataContext(context.Background())
+}
+
+func (d *dialog) SyncUserParameterDataContext(ctx context.Context) error {
+	d.logger.Info("Initializing dialog", slog.String("user_id", d.UserID))
+	err := d.init(ctx)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		internal.Info.Printf("Ending dialog")
-		logErr(d.end())
-	}()
+	d.log().Info("Ending dialog")
+	if err := d.endContext(ctx); err != nil {
+		return &DialogEndError{Err: err}
+	}
 	return nil
 }
 
 func (d *dialog) SyncClientSystemID() (string, error) {
+	return d.SyncClientSystemIDContext(context.Background())
+}
+
+func (d *dialog) SyncClientSystemIDContext(ctx context.Context) (string, error) {
+	opened := d.startDialog(ctx, synchronizationDialog)
+	err := d.synchronize(ctx)
+	opened(err)
+	if err != nil {
+		return "", err
+	}
+
+	err = d.endContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	return d.ClientSystemID, nil
+}
+
+// synchronize sends the synchronization message and processes the response.
+func (d *dialog) synchronize(ctx context.Context) error {
 	syncMessage := message.NewSynchronisationMessage(d.hbciVersion)
 	syncMessage.Identification = segment.NewIdentificationSegment(d.BankID, d.clientID, initialClientSystemID, true)
 	syncMessage.ProcessingPreparation = segment.NewProcessingPreparationSegmentV3(
 		initialBankParameterDataVersion, initialUserParameterDataVersion, domain.German,
 	)
-	syncMessage.TanRequest = d.hbciVersion.TanProcess4Request(segment.IdentificationID)
-	syncMessage.Sync = d.hbciVersion.SynchronisationRequest(segment.SyncModeAquireClientID)
+	if !d.keyBased {
+		tanRequest, err := d.hbciVersion.NewTanProcess4Request(segment.IdentificationID)
+		if err != nil {
+			return err
+		}
+		syncMessage.TanRequest = tanRequest
+	}
+	sync, err := d.hbciVersion.NewSynchronisationRequest(segment.SyncModeAquireClientID)
+	if err != nil {
+		return err
+	}
+	syncMessage.Sync = sync
 	syncMessage.BasicMessage = d.newBasicMessage(syncMessage)
 	signedSyncMessage, err := syncMessage.Sign(d.signatureProvider)
 	if err != nil {
-		return "", err
+		return err
 	}
 	d.cryptoProvider.SetClientSystemID(initialClientSystemID)
 	encryptedSyncMessage, err := signedSyncMessage.Encrypt(d.cryptoProvider)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	decryptedMessage, err := d.request(encryptedSyncMessage)
+	decryptedMessage, err := d.request(ctx, encryptedSyncMessage, jobMetadata(syncMessage))
 	if err != nil {
-		return "", fmt.Errorf("error while extracting encrypted message: %v", err)
+		return fmt.Errorf("error while extracting encrypted message: %w", err)
 	}
 
 	messageHeader := decryptedMessage.MessageHeader()
 	if messageHeader == nil {
-		return "", fmt.Errorf("malformed response message: %q", decryptedMessage)
+		return fmt.Errorf("malformed response message: %q", decryptedMessage)
 	}
 	d.dialogID = messageHeader.DialogID.Val()
-	var errors []string
-	acknowledgements := decryptedMessage.Acknowledgements()
-	for _, ack := range acknowledgements {
-		if ack.IsSuccess() {
-			internal.Debug.Printf("%v\n", ack)
-		}
-		if ack.IsWarning() {
-			internal.Info.Printf("%v\n", ack)
-		}
-		if ack.IsError() {
-			errors = append(errors, ack.String())
-		}
-	}
-	if len(errors) > 0 {
-		return "", fmt.Errorf("institute returned errors:\n%s", strings.Join(errors, "\n"))
+	if err := d.checkAcknowledgements(decryptedMessage.Acknowledgements()); err != nil {
+		return err
 	}
+	d.active = true
 	if err := d.updateSecurityFunctionIfNeeded(decryptedMessage); err != nil {
-		return "", fmt.Errorf("error updating security function: %w", err)
+		return fmt.Errorf("error updating security function: %w", err)
 	}
 
 	syncResponse := decryptedMessage.FindSegment("HISYN")
 	if syncResponse != nil {
 		syncSegment := syncResponse.(segment.SynchronisationResponse)
 		d.SetClientSystemID(syncSegment.ClientSystemID())
+		if signatureID := syncSegment.SignatureID(); signatureID > d.signatureID() {
+			d.setSignatureID(signatureID)
+		}
 	} else {
-		return "", fmt.Errorf("malformed message: missing unmarshaler for SynchronisationResponse")
+		return fmt.Errorf("malformed message: missing unmarshaler for SynchronisationResponse")
 	}
 
 	err = d.parseBankParameterData(decryptedMessage)
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	err = d.parseUserParameterData(decryptedMessage)
 	if err != nil {
-		return "", err
-	}
-
-	err = d.end()
-	if err != nil {
-		return "", err
+		return err
 	}
 
-	return d.ClientSystemID, nil
+	return d.saveState()
 }
 
 func (d *dialog) SendAnonymousMessage(clientMessage message.HBCIMessage) (message.BankMessage, error) {
-	err := d.anonymousInit()
+	return d.SendAnonymousMessageContext(context.Background(), clientMessage)
+}
+
+// SendAnonymousMessageContext initializes an anonymous dialog, sends the
+// clientMessage and ends the dialog afterwards. If the dialog could not be
+// ended, the response is returned along with a DialogEndError.
+func (d *dialog) SendAnonymousMessageContext(ctx context.Context, clientMessage message.HBCIMessage) (bankMessage message.BankMessage, err error) {
+	session, err := d.OpenAnonymousContext(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("Error while initating anonymous dialog: %v", err)
+		return nil, err
+	}
+	defer func() { err = withDialogEnd(err, func() error { return session.CloseContext(ctx) }) }()
+	return d.sendAnonymous(ctx, clientMessage)
+}
+
+// sendAnonymous sends the clientMessage unsigned and unencrypted within the
+// currently initialized anonymous dialog.
+func (d *dialog) sendAnonymous(ctx context.Context, clientMessage message.HBCIMessage) (message.BankMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := d.validateJobs(clientMessage.HBCISegments()); err != nil {
+		return nil, err
 	}
-	defer func() { logErr(d.anonymousEnd()) }()
 	// TODO: add checks if job needs signature or not
 	requestMessage := d.newBasicMessage(clientMessage)
 	requestMessage.SetSegmentPositions()
-	bankMessage, err := d.request(requestMessage)
+	bankMessage, err := d.request(ctx, requestMessage, jobMetadata(clientMessage))
+	if err == nil {
+		err = d.checkAcknowledgements(bankMessage.Acknowledgements())
+	}
 	if err != nil {
+		d.recoverFrom(ctx, err)
 		return nil, err
 	}
-	var errors []string
-	acknowledgements := bankMessage.Acknowledgements()
-	for _, ack := range acknowledgements {
-		if ack.IsWarning() {
-			fmt.Printf("%v\n", ack)
-		}
-		if ack.IsError() {
-			errors = append(errors, ack.String())
-		}
-	}
-	if len(errors) > 0 {
-		return nil, fmt.Errorf("Institute returned errors:\n%s", strings.Join(errors, "\n"))
-	}
 	return bankMessage, nil
 }
 
-func (d *dialog) anonymousInit() error {
+func (d *dialog) anonymousInit(ctx context.Context) error {
+	opened := d.startDialog(ctx, anonymousDialog)
+	err := d.sendAnonymousInit(ctx)
+	opened(err)
+	return err
+}
+
+func (d *dialog) sendAnonymousInit(ctx context.Context) error {
+	_, err := d.sendAnonymousInitMessage(ctx, message.NewDialogInitializationClientMessage(d.hbciVersion))
+	return err
+}
+
+// sendAnonymousInitMessage completes initMessage with the anonymous
+// identification, sends it and processes the response.
+func (d *dialog) sendAnonymousInitMessage(ctx context.Context, initMessage *message.DialogInitializationClientMessage) (message.BankMessage, error) {
 	d.dialogID = initialDialogID
 	d.messageCount = 0
-	initMessage := message.NewDialogInitializationClientMessage(d.hbciVersion)
 	initMessage.Identification = segment.NewIdentificationSegment(d.BankID, anonymousClientID, initialClientSystemID, false)
 	initMessage.ProcessingPreparation = segment.NewProcessingPreparationSegmentV3(
 		d.BankParameterDataVersion(), d.UserParameterDataVersion(), d.Language,
 	)
 	initMessage.BasicMessage = d.newBasicMessage(initMessage)
 	initMessage.SetSegmentPositions()
-	bankMessage, err := d.request(initMessage)
+	bankMessage, err := d.request(ctx, initMessage, dialogInitializationMetadata(initMessage))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	messageHeader := bankMessage.MessageHeader()
 	if messageHeader == nil {
-		return fmt.Errorf("malformed response message: %q", bankMessage)
+		return nil, fmt.Errorf("malformed response message: %q", bankMessage)
 	}
 	d.dialogID = messageHeader.DialogID.Val()
 
 	err = d.parseBankParameterData(bankMessage)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = d.parseUserParameterData(bankMessage)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	bankInfoMessage := bankMessage.FindSegment(segment.BankAnnouncementID)
 	if bankInfoMessage != nil {
 		bankInfoSegment := bankInfoMessage.(*segment.BankAnnouncementSegment)
-		internal.Info.Printf("INFO:\n%s\n%s\n", bankInfoSegment.Subject.Val(), bankInfoSegment.Body.Val())
+		d.log().Info(
+			"Bank information",
+			slog.String("subject", bankInfoSegment.Subject.Val()),
+			slog.String("body", bankInfoSegment.Body.Val()),
+		)
 	}
 
-	errors := make([]string, 0)
-	acknowledgements := bankMessage.Acknowledgements()
-	for _, ack := range acknowledgements {
-		if ack.IsWarning() {
-			fmt.Printf("%v\n", ack)
-		}
-		if ack.IsError() {
-			errors = append(errors, ack.String())
-		}
-	}
-	if len(errors) > 0 {
-		return fmt.Errorf("DialogEnd: Institute returned errors:\n%s", strings.Join(errors, "\n"))
+	if err := d.checkAcknowledgements(bankMessage.Acknowledgements()); err != nil {
+		return nil, err
 	}
-	return nil
+	d.active = true
+	return bankMessage, nil
 }
 
-func (d *dialog) anonymousEnd() error {
+func (d *dialog) anonymousEnd(ctx context.Context) (err error) {
+	if !d.active {
+		return nil
+	}
+	d.active = false
+	defer func() { d.observeDialogClosed(ctx, err) }()
 	dialogEnd := message.NewDialogFinishingMessage(d.hbciVersion, d.dialogID)
 	dialogEnd.BasicMessage = d.newBasicMessage(dialogEnd)
 	dialogEnd.SetSegmentPositions()
 
-	decryptedMessage, err := d.request(dialogEnd)
+	decryptedMessage, err := d.request(ctx, dialogEnd, jobMetadata(dialogEnd))
 	if err != nil {
-		return fmt.Errorf("Error while ending dialog: %v", err)
+		return fmt.Errorf("Error while ending dialog: %w", err)
 	}
 
-	errors := make([]string, 0)
-	acknowledgements := decryptedMessage.Acknowledgements()
-	for _, ack := range acknowledgements {
-		if ack.IsError() {
-			errors = append(errors, ack.String())
-		}
-	}
-	if len(errors) > 0 {
-		return fmt.Errorf("DialogEnd: Institute returned errors:\n%s", strings.Join(errors, "\n"))
+	if err := d.checkAcknowledgements(decryptedMessage.Acknowledgements()); err != nil {
+		return err
 	}
 
 	return nil
 }
 
-func (d *dialog) init() error {
+func (d *dialog) init(ctx context.Context) error {
+	if err := d.LoadState(); err != nil {
+		return err
+	}
 	if d.ClientSystemID == initialClientSystemID {
-		id, err := d.SyncClientSystemID()
+		id, err := d.SyncClientSystemIDContext(ctx)
 		if err != nil {
 			return err
 		}
 		d.ClientSystemID = id
 	}
+	opened := d.startDialog(ctx, personalDialog)
+	err := d.sendInit(ctx)
+	opened(err)
+	return err
+}
+
+// sendInit sends the dialog initialization message and processes the
+// response.
+func (d *dialog) sendInit(ctx context.Context) error {
 	d.dialogID = initialDialogID
 	d.messageCount = 0
 	initMessage := message.NewDialogInitializationClientMessage(d.hbciVersion)
@@ -343,7 +489,13 @@ func (d *dialog) init() error {
 	initMessage.ProcessingPreparation = segment.NewProcessingPreparationSegmentV3(
 		d.BankParameterDataVersion(), d.UserParameterDataVersion(), d.Language,
 	)
-	initMessage.TanRequest = d.hbciVersion.TanProcess4Request(segment.IdentificationID)
+	if !d.keyBased {
+		tanRequest, err := d.hbciVersion.NewTanProcess4Request(segment.IdentificationID)
+		if err != nil {
+			return err
+		}
+		initMessage.TanRequest = tanRequest
+	}
 	initMessage.BasicMessage = d.newBasicMessage(initMessage)
 	signedInitMessage, err := initMessage.Sign(d.signatureProvider)
 	if err != nil {
@@ -354,9 +506,9 @@ func (d *dialog) init() error {
 		return err
 	}
 
-	decryptedMessage, err := d.request(encryptedInitMessage)
+	decryptedMessage, err := d.request(ctx, encryptedInitMessage, dialogInitializationMetadata(initMessage))
 	if err != nil {
-		return fmt.Errorf("error while initializing dialog: %v", err)
+		return fmt.Errorf("error while initializing dialog: %w", err)
 	}
 	messageHeader := decryptedMessage.MessageHeader()
 	if messageHeader == nil {
@@ -377,34 +529,41 @@ func (d *dialog) init() error {
 	bankInfoMessage := decryptedMessage.FindSegment(segment.BankAnnouncementID)
 	if bankInfoMessage != nil {
 		bankInfoSegment := bankInfoMessage.(*segment.BankAnnouncementSegment)
-		internal.Info.Printf("INFO:\n%s\n%s\n", bankInfoSegment.Subject.Val(), bankInfoSegment.Body.Val())
+		d.log().Info(
+			"Bank information",
+			slog.String("subject", bankInfoSegment.Subject.Val()),
+			slog.String("body", bankInfoSegment.Body.Val()),
+		)
 	}
 
-	var errors []string
-	acknowledgements := decryptedMessage.Acknowledgements()
-	for _, ack := range acknowledgements {
-		if ack.IsSuccess() {
-			internal.Debug.Printf("%v\n", ack)
-		}
-		if ack.IsWarning() {
-			internal.Info.Printf("%v\n", ack)
-		}
-		if ack.IsError() {
-			errors = append(errors, ack.String())
-		}
-	}
-	if len(errors) > 0 {
-		return fmt.Errorf("DialogEnd: Institute returned errors:\n%s", strings.Join(errors, "\n"))
+	if err := d.checkAcknowledgements(decryptedMessage.Acknowledgements()); err != nil {
+		return err
 	}
 
 	if err := d.updateSecurityFunctionIfNeeded(decryptedMessage); err != nil {
 		return fmt.Errorf("error updating security function: %w", err)
 	}
+	d.active = true
 
-	return nil
+	return d.saveState()
+}
+
+// endContext ends the dialog. If ctx is already done, the dialog end message
+// is sent with a fresh context bound by dialogEndTimeout, so that the
+// institute can release the dialog nonetheless. A dialog already cancelled or
+// terminated by the institute is not ended again.
+func (d *dialog) endContext(ctx context.Context) error {
+	endCtx, cancel := dialogEndContext(ctx)
+	defer cancel()
+	return d.end(endCtx)
 }
 
-func (d *dialog) end() error {
+func (d *dialog) end(ctx context.Context) (err error) {
+	if !d.active {
+		return nil
+	}
+	d.active = false
+	defer func() { d.observeDialogClosed(ctx, err) }()
 	dialogEnd := message.NewDialogFinishingMessage(d.hbciVersion, d.dialogID)
 	dialogEnd.BasicMessage = d.newBasicMessage(dialogEnd)
 	signedDialogEnd, err := dialogEnd.Sign(d.signatureProvider)
@@ -416,20 +575,13 @@ func (d *dialog) end() error {
 		return err
 	}
 
-	decryptedMessage, err := d.request(encryptedDialogEnd)
+	decryptedMessage, err := d.request(ctx, encryptedDialogEnd, jobMetadata(dialogEnd))
 	if err != nil {
-		return fmt.Errorf("Error while ending dialog: %v", err)
+		return fmt.Errorf("Error while ending dialog: %w", err)
 	}
 
-	errors := make([]string, 0)
-	acknowledgements := decryptedMessage.Acknowledgements()
-	for _, ack := range acknowledgements {
-		if ack.IsError() {
-			errors = append(errors, ack.String())
-		}
-	}
-	if len(errors) > 0 {
-		return fmt.Errorf("DialogEnd: Institute returned errors:\n%s", strings.Join(errors, "\n"))
+	if err := d.checkAcknowledgements(decryptedMessage.Acknowledgements()); err != nil {
+		return err
 	}
 
 	return nil
@@ -442,11 +594,22 @@ func (d *dialog) newClientMessage(hbciMessage message.HBCIMessage) message.Clien
 func (d *dialog) newBasicMessage(hbciMessage message.HBCIMessage) *message.BasicMessage {
 	messageNum := d.nextMessageNumber()
 	clientMessage := message.NewBasicMessage(hbciMessage)
-	clientMessage.Header = segment.NewMessageHeaderSegment(-1, d.hbciVersion.Version(), d.dialogID, messageNum)
+	clientMessage.Header = segment.NewMessageHeaderSegment(0, d.hbciVersion.Version(), d.dialogID, messageNum)
 	clientMessage.End = segment.NewMessageEndSegment(-1, messageNum)
+	clientMessage.SetCompression(d.compression)
 	return clientMessage
 }
 
+// negotiateCompression chooses the compression function for the following
+// messages from the ones supported by the institute.
+func (d *dialog) negotiateCompression() {
+	if !d.enableCompression {
+		d.compression = message.CompressionNone
+		return
+	}
+	d.compression = message.NegotiateCompression(d.BankParameterData.CompressionFunctions)
+}
+
 func (d *dialog) updateSecurityFunctionIfNeeded(message message.BankMessage) error {
 	if !d.hasSupportedSecurityAcknowledgement(message) {
 		return nil
@@ -465,8 +628,10 @@ func (d *dialog) updateSecurityFunctionIfNeeded(message message.BankMessage) err
 		break
 	}
 	if oldSecurityFn != newSecurityFn {
-		internal.Info.Printf(
-			"New supported security function found. Setting new security function %q (%s).", newSecurityFnName, newSecurityFn,
+		d.log().Info(
+			"New supported security function found",
+			slog.String("security_function", newSecurityFn),
+			slog.String("name", newSecurityFnName),
 		)
 		d.SetSecurityFunction(newSecurityFn)
 
@@ -545,14 +710,36 @@ func (d *dialog) parseBankParameterData(bankMessage message.BankMessage) error {
 		d.BankParameterData.PinTanBusinessTransactions = pinTransactions
 	}
 	for i, s := range d.supportedSegments {
-		param := SegmentParameter{
+		d.BankParameterData.SupportedSegmentParameters[i] = SegmentParameter{
 			VersionedSegment: s,
+			Parameters:       findParameterSegment(bankMessage, s),
+		}
+	}
+	if compressionMethods, ok := bankMessage.FindSegment(segment.CompressionMethodID).(*segment.CompressionMethodSegment); ok {
+		d.BankParameterData.CompressionFunctions = compressionMethods.CompressionMethods()
+	}
+	d.negotiateCompression()
+	d.rawBankParameterData = bankParameterDataSegments(bankMessage)
+	return nil
+}
+
+// findParameterSegment returns the parameter segment with the given ID and
+// version. Segments without a specific unmarshaler are unmarshaled as
+// segment.BusinessTransactionParamsSegment.
+func findParameterSegment(bankMessage message.BankMessage, versionedSegment segment.VersionedSegment) segment.Segment {
+	for _, parameterData := range bankMessage.FindSegments(versionedSegment.ID) {
+		if parameterData.Header().Version.Val() == versionedSegment.Version {
+			return parameterData
+		}
+	}
+	for _, marshaledSegment := range bankMessage.FindMarshaledSegments(versionedSegment.ID) {
+		parameterData := &segment.BusinessTransactionParamsSegment{}
+		if err := parameterData.UnmarshalHBCI(marshaledSegment); err != nil {
+			continue
 		}
-		parameterData := bankMessage.FindSegment(s.ID)
-		if parameterData != nil && parameterData.Header().Version.Val() == s.Version {
-			param.Parameters = parameterData
+		if parameterData.Version() == versionedSegment.Version {
+			return parameterData
 		}
-		d.BankParameterData.SupportedSegmentParameters[i] = param
 	}
 	return nil
 }
@@ -563,67 +750,133 @@ func (d *dialog) parseUserParameterData(bankMessage message.BankMessage) error {
 		paramSegment := userParamData.(segment.CommonUserParameterData)
 		d.UserParameterData = paramSegment.UserParameterData()
 		d.clientID = d.UserParameterData.UserID
+		d.rawUserParameterData = userParameterDataSegments(bankMessage)
 	}
 
 	accountData := bankMessage.FindSegments(segment.AccountInformationID)
+	if len(accountData) == 0 {
+		return nil
+	}
+	// the institute always sends the complete UPD, so replace the known accounts
+	accounts := make([]domain.AccountInformation, 0, len(accountData))
 	for _, acc := range accountData {
 		infoSegment := acc.(segment.AccountInformation)
-		d.Accounts = append(d.Accounts, infoSegment.Account())
+		accounts = append(accounts, infoSegment.Account())
 	}
+	d.Accounts = accounts
 	return nil
 }
 
-func (d *dialog) request(clientMessage message.ClientMessage) (message.BankMessage, error) {
+func (d *dialog) request(ctx context.Context, clientMessage message.ClientMessage, metadata transport.RequestMetadata) (bankMessage message.BankMessage, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	marshaledMessage, err := clientMessage.MarshalHBCI()
 	if err != nil {
 		return nil, err
 	}
-	internal.Debug.Printf("Request:\n")
-	for _, seg := range bytes.Split(marshaledMessage, []byte("'")) {
-		internal.Debug.Printf("%q\n", seg)
+	// a signature ID must never be reused, so persist it before sending
+	if err := d.saveSignatureID(); err != nil {
+		return nil, err
 	}
+	messageNumber := d.messageCount
+	d.log().Debug("Sending message", slog.Int("message_number", messageNumber), slog.Any("jobs", metadata.Jobs))
+	start := time.Now()
+	d.observer.MessageSent(ctx, MessageSentEvent{
+		DialogInfo:    d.dialogInfo(),
+		MessageNumber: messageNumber,
+		Jobs:          metadata.Jobs,
+		Time:          start,
+	})
+	defer func() {
+		event := ResponseReceivedEvent{
+			DialogInfo:    d.dialogInfo(),
+			MessageNumber: messageNumber,
+			Jobs:          metadata.Jobs,
+			Start:         start,
+			Duration:      time.Since(start),
+			Err:           err,
+		}
+		if bankMessage != nil {
+			event.Acknowledgements = bankMessage.Acknowledgements()
+		}
+		d.observer.ResponseReceived(ctx, event)
+		if bankMessage != nil {
+			d.observeTANChallenge(ctx, messageNumber, metadata.Jobs, bankMessage)
+		}
+	}()
 
 	reqBody := bytes.NewReader(marshaledMessage)
 
 	request := &transport.Request{
-		URL:  d.hbciURL,
-		Body: ioutil.NopCloser(reqBody),
+		URL:      d.hbciURL,
+		Body:     ioutil.NopCloser(reqBody),
+		Metadata: metadata,
 	}
 
-	response, err := d.transport.Do(request)
+	response, err := transport.DoContext(ctx, d.transport, request)
 	if err != nil {
-		return nil, fmt.Errorf("error executing Transport request: %v", err)
+		return nil, fmt.Errorf("error executing Transport request: %w", err)
 	}
 	response, err = transport.ReadResponse(bufio.NewReader(response.Body), response.Request)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response from Transport: %v", err)
+		return nil, &ProtocolError{fmt.Errorf("error reading response from Transport: %v", err)}
 	}
 
-	var bankMessage message.BankMessage
 	if response.IsEncrypted() {
 		encMessage, err := d.extractEncryptedMessage(response)
 		if err != nil {
-			return nil, err
+			return nil, &ProtocolError{err}
 		}
 
 		decryptedMessage, err := encMessage.Decrypt(d.cryptoProvider)
 		if err != nil {
-			return nil, fmt.Errorf("error while decrypting message: %v", err)
+			return nil, &ProtocolError{fmt.Errorf("error while decrypting message: %v", err)}
 		}
-		internal.Debug.Printf("Response:\n %s\n", decryptedMessage.MessageHeader())
+		if err := d.verifySignature(decryptedMessage); err != nil {
+			return nil, &ProtocolError{err}
+		}
+		d.logResponse(decryptedMessage.MessageHeader())
 		bankMessage = decryptedMessage
 	} else {
+		// key based dialogs encrypt every personal message, a plaintext
+		// response could be forged or downgraded by an attacker
+		if d.keyBased && d.kind == personalDialog {
+			return nil, &ProtocolError{fmt.Errorf(
+				"error verifying signature of the institute: %w: unencrypted response within a key based dialog",
+				message.ErrInvalidSignature,
+			)}
+		}
 		decryptedMessage, err := extractUnencryptedMessage(response)
 		if err != nil {
-			return nil, err
+			return nil, &ProtocolError{err}
 		}
-		internal.Debug.Printf("Response:\n %s\n", decryptedMessage.MessageHeader())
+		if err := d.verifySignature(decryptedMessage); err != nil {
+			return nil, &ProtocolError{err}
+		}
+		d.logResponse(decryptedMessage.MessageHeader())
 		bankMessage = decryptedMessage
 	}
 
 	return bankMessage, err
 }
 
+// verifySignature verifies the signature of the institute, if the dialog
+// has a SignatureVerifier. The institute does not sign the messages of
+// anonymous dialogs, so they are only verified if they carry a signature.
+func (d *dialog) verifySignature(bankMessage message.BankMessage) error {
+	if d.signatureVerifier == nil {
+		return nil
+	}
+	if d.kind == anonymousDialog && bankMessage.FindMarshaledSegment("HNSHK") == nil {
+		return nil
+	}
+	if err := d.signatureVerifier.Verify(bankMessage); err != nil {
+		return fmt.Errorf("error verifying signature of the institute: %w", err)
+	}
+	return nil
+}
+
 func (d *dialog) extractEncryptedMessage(response *transport.Response) (*message.EncryptedMessage, error) {
 	messageHeader := response.FindSegment(segment.MessageHeaderID)
 	if messageHeader == nil {
@@ -635,9 +888,20 @@ func (d *dialog) extractEncryptedMessage(response *transport.Response) (*message
 		return nil, fmt.Errorf("error while unmarshaling message header: %v", err)
 	}
 	// TODO: parse messageEnd
-	// TODO: parse encryptionHeader
 
 	encMessage := message.NewEncryptedMessage(header, nil, d.hbciVersion)
+	if encryptionHeader := response.FindSegment(segment.EncryptionHeaderSegmentID); encryptionHeader != nil {
+		compression, err := message.ExtractCompression(encryptionHeader)
+		if err != nil {
+			return nil, fmt.Errorf("error while unmarshaling encryption header: %v", err)
+		}
+		encMessage.Compression = compression
+		messageKey, err := message.ExtractMessageKey(encryptionHeader)
+		if err != nil {
+			return nil, fmt.Errorf("error while unmarshaling encryption header: %v", err)
+		}
+		encMessage.MessageKey = messageKey
+	}
 
 	encryptedData := response.FindSegment("HNVSD")
 	if encryptedData != nil {
@@ -676,45 +940,66 @@ func (d *dialog) nextMessageNumber() int {
 	return d.messageCount
 }
 
-func (d *dialog) dial(message []byte) ([]byte, error) {
-	conn, err := net.Dial("tcp4", d.hbciURL)
-	if err != nil {
-		return nil, err
+// dialogEndContext returns ctx if it is not yet done, or a new context bound
+// by dialogEndTimeout otherwise.
+func dialogEndContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx.Err() == nil {
+		return ctx, func() {}
 	}
-	defer func() { logErr(conn.Close()) }()
-	err = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
-	if err != nil {
-		return nil, err
-	}
-	fmt.Fprintf(conn, "%s\r\n\r\n", string(message))
-	buf := bufio.NewReader(conn)
-	// read answer header
-	header, err := buf.ReadString('\'')
-	if err != nil {
-		return nil, err
-	}
-	headerItems := strings.Split(header, "+")
-	if len(headerItems) < 2 {
-		return nil, fmt.Errorf("response header too short")
+	return context.WithTimeout(context.Background(), dialogEndTimeout)
+}
+
+// checkAcknowledgements logs the acknowledgements and returns a
+// domain.AcknowledgementError if the institute returned errors. Responses
+// requiring a TAN are no errors, callers detect them by
+// message.BankMessage.TANRequired. Message errors reset the dialog, as the
+// institute terminates it.
+func (d *dialog) checkAcknowledgements(acknowledgements []domain.Acknowledgement) error {
+	d.resetIfTerminated(acknowledgements)
+	failed := false
+	for _, ack := range acknowledgements {
+		switch {
+		case ack.IsError():
+			failed = true
+		case ack.IsWarning():
+			d.logAcknowledgement(slog.LevelInfo, ack)
+		case ack.IsSuccess():
+			d.logAcknowledgement(slog.LevelDebug, ack)
+		}
 	}
-	sizeString := headerItems[1]
-	size, err := strconv.Atoi(sizeString)
-	if err != nil {
-		return nil, fmt.Errorf("error while parsing message size: %T:%v", err, err)
+	if failed {
+		return domain.NewAcknowledgementError(acknowledgements)
 	}
-	messageBuf := make([]byte, size)
-	_, err = buf.Read(messageBuf)
+	return nil
+}
+
+func (d *dialog) logErr(err error) {
 	if err != nil {
-		return nil, fmt.Errorf("error while reading message: %T:%v", err, err)
+		d.log().Error(err.Error())
 	}
-	var retBuf bytes.Buffer
-	retBuf.WriteString(header)
-	retBuf.Write(messageBuf)
-	return retBuf.Bytes(), err
 }
 
-func logErr(err error) {
-	if err != nil {
-		log.Println(err)
+// log returns the logger of the dialog with the dialog ID attached.
+func (d *dialog) log() *slog.Logger {
+	return d.logger.With(slog.String("dialog_id", d.dialogID))
+}
+
+func (d *dialog) logResponse(header *segment.MessageHeaderSegment) {
+	if header == nil || header.Number == nil {
+		d.log().Debug("Received response")
+		return
 	}
+	d.log().Debug("Received response", slog.Int("message_number", header.Number.Val()))
+}
+
+func (d *dialog) logAcknowledgement(level slog.Level, ack domain.Acknowledgement) {
+	d.log().Log(
+		context.Background(),
+		level,
+		"Acknowledgement",
+		slog.Int("code", ack.Code),
+		slog.String("text", ack.Text),
+		slog.Int("segment_number", ack.ReferencingSegmentNumber),
+		slog.Any("params", ack.Params),
+	)
 }
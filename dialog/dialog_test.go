@@ -2,15 +2,21 @@ package dialog
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"log/slog"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/mitch000001/go-hbci/charset"
 	"github.com/mitch000001/go-hbci/domain"
 	"github.com/mitch000001/go-hbci/message"
 	"github.com/mitch000001/go-hbci/segment"
+	"github.com/mitch000001/go-hbci/transport"
 )
 
 func TestPinTanDialogSendMessage(t *testing.T) {
@@ -61,6 +67,106 @@ func TestPinTanDialogSendMessage(t *testing.T) {
 	}
 }
 
+func TestPinTanDialogSendMessageContextCancelled(t *testing.T) {
+	mock := &mockHTTPSTransport{}
+
+	d := newTestPinTanDialog(mock)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d.transport = transport.Func(func(req *transport.Request) (*transport.Response, error) {
+		// cancel while the dialog initialization is on the wire
+		cancel()
+		return mock.Do(req)
+	})
+
+	initResponse := encryptedTestMessage(
+		"abcde",
+		"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+	)
+	dialogEndResponseMessage := encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Der Auftrag wurde ausgeführt'")
+	mock.SetResponseMessages([][]byte{
+		initResponse,
+		dialogEndResponseMessage,
+	})
+
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	accountBalanceRequest := segment.NewAccountBalanceRequestV5(account, false)
+
+	res, err := d.SendMessageContext(ctx, message.NewHBCIMessage(d.hbciVersion, accountBalanceRequest))
+
+	if !errors.Is(err, context.Canceled) {
+		t.Logf("Expected error to be %v, got %T:%v\n", context.Canceled, err, err)
+		t.Fail()
+	}
+
+	if res != nil {
+		t.Logf("Expected result to be nil, got %v\n", res)
+		t.Fail()
+	}
+
+	if mock.CallCount() != 2 {
+		t.Logf("Expected init and dialog end to be sent, got %d requests\n", mock.CallCount())
+		t.Fail()
+	}
+
+	// a context done beforehand must not reach the transport at all
+	mock.Reset()
+
+	_, err = d.SendMessageContext(ctx, message.NewHBCIMessage(d.hbciVersion, accountBalanceRequest))
+
+	if !errors.Is(err, context.Canceled) {
+		t.Logf("Expected error to be %v, got %T:%v\n", context.Canceled, err, err)
+		t.Fail()
+	}
+
+	if mock.CallCount() != 0 {
+		t.Logf("Expected no requests, got %d\n", mock.CallCount())
+		t.Fail()
+	}
+}
+
+func TestPinTanDialogSendMessageRequestMetadata(t *testing.T) {
+	mock := &mockHTTPSTransport{}
+
+	d := newTestPinTanDialog(mock)
+	var metadata []transport.RequestMetadata
+	d.transport = transport.Func(func(req *transport.Request) (*transport.Response, error) {
+		metadata = append(metadata, req.Metadata)
+		return mock.Do(req)
+	})
+
+	mock.SetResponseMessages([][]byte{
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Auftrag entgegengenommen'"),
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Auftrag entgegengenommen'"),
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Der Auftrag wurde ausgeführt'"),
+	})
+
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	accountBalanceRequest := segment.NewAccountBalanceRequestV5(account, false)
+
+	_, err := d.SendMessage(message.NewHBCIMessage(d.hbciVersion, accountBalanceRequest))
+	if err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.Fail()
+	}
+
+	expected := []transport.RequestMetadata{
+		{Jobs: []string{"HKIDN", "HKVVB", "HKTAN"}, DialogInitialization: true},
+		{Jobs: []string{"HKSAL"}},
+		{Jobs: []string{"HKEND"}},
+	}
+	if !reflect.DeepEqual(expected, metadata) {
+		t.Logf("Expected request metadata to equal\n%+v\n\tgot\n%+v\n", expected, metadata)
+		t.Fail()
+	}
+
+	syncMetadata := jobMetadata(message.NewHBCIMessage(d.hbciVersion, segment.NewSynchronisationSegmentV2(segment.SyncModeAquireClientID)))
+	if !syncMetadata.NonRetryable {
+		t.Logf("Expected synchronisation to be non retryable\n")
+		t.Fail()
+	}
+}
+
 func TestPinTanDialogSyncClientSystemID(t *testing.T) {
 	transport := &mockHTTPSTransport{}
 
@@ -203,7 +309,7 @@ func TestPinTanDialogSyncClientSystemID(t *testing.T) {
 
 	if err != nil {
 		errMessage := err.Error()
-		expectedMessage := "institute returned errors:\nMessageAcknowledgement for message 0 (), segment 1: Code: 9000, Position: none, Text: 'Nachricht enthält Fehler'"
+		expectedMessage := "institute returned errors:\nMessageAcknowledgement for message 0 (), segment 1: Code: 9000 (Status indeterminate), Position: none, Text: 'Nachricht enthält Fehler'"
 		if expectedMessage != errMessage {
 			t.Logf("Expected error to equal\n%q\n\tgot\n%q\n", expectedMessage, errMessage)
 			t.Fail()
@@ -229,7 +335,7 @@ func TestPinTanDialogInit(t *testing.T) {
 	)
 	transport.SetResponseMessage(initResponse)
 
-	err := d.init()
+	err := d.init(context.Background())
 
 	if err != nil {
 		t.Logf("Expected no error, got %T:%v\n", err, err)
@@ -243,6 +349,171 @@ func TestPinTanDialogInit(t *testing.T) {
 	}
 }
 
+func TestPinTanDialogInitAcknowledgementError(t *testing.T) {
+	tests := []struct {
+		response string
+		category error
+	}{
+		{"HIRMG:2:2:1+9942::PIN falsch'", domain.ErrWrongPIN},
+		{"HIRMG:2:2:1+3938::Zugang vorläufig gesperrt+9800::Dialog abgebrochen'", domain.ErrAccessLocked},
+	}
+	for _, test := range tests {
+		transport := &mockHTTPSTransport{}
+		d := newTestPinTanDialog(transport)
+		transport.SetResponseMessage(encryptedTestMessage("newDialogID", test.response))
+
+		err := d.init(context.Background())
+
+		if !errors.Is(err, test.category) {
+			t.Logf("Expected error to be %q, got %T:%v\n", test.category, err, err)
+			t.Fail()
+		}
+		var ackErr *domain.AcknowledgementError
+		if !errors.As(err, &ackErr) {
+			t.Logf("Expected error to be an AcknowledgementError, got %T\n", err)
+			t.Fail()
+		}
+	}
+}
+
+func TestNewPinTanDialogChannel(t *testing.T) {
+	for _, channel := range []Channel{ChannelHTTPS, ChannelTCP} {
+		var body []byte
+		cfg := Config{
+			BankID:      domain.BankID{CountryCode: 280, ID: "10000000"},
+			HBCIURL:     "localhost:3000",
+			UserID:      "12345",
+			HBCIVersion: segment.HBCI220,
+			Channel:     channel,
+			Transport: transport.Func(func(req *transport.Request) (*transport.Response, error) {
+				body, _ = ioutil.ReadAll(req.Body)
+				return nil, fmt.Errorf("not connected")
+			}),
+		}
+
+		d := NewPinTanDialog(cfg)
+		d.SetPin("abcde")
+		d.Open()
+
+		plain := bytes.HasPrefix(body, []byte("HNHBK:1:3+"))
+		if expected := channel == ChannelTCP; expected != plain {
+			t.Logf("Expected channel %d to send plain messages: %t, got body\n%q\n", channel, expected, body)
+			t.Fail()
+		}
+	}
+}
+
+func TestNewPinTanDialogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := Config{
+		BankID:      domain.BankID{CountryCode: 280, ID: "10000000"},
+		HBCIURL:     "localhost:3000",
+		UserID:      "12345",
+		HBCIVersion: segment.HBCI220,
+		Channel:     ChannelTCP,
+		Transport: transport.Func(func(req *transport.Request) (*transport.Response, error) {
+			return nil, fmt.Errorf("not connected")
+		}),
+		Logger: slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+
+	d := NewPinTanDialog(cfg)
+	d.SetPin("geheim")
+	d.Open()
+
+	if !strings.Contains(buf.String(), "segment_id=HNSHA") {
+		t.Logf("Expected the configured logger to receive the request segments, got\n%s\n", buf.String())
+		t.Fail()
+	}
+	if strings.Contains(buf.String(), "geheim") {
+		t.Logf("Expected the PIN to be masked, got\n%s\n", buf.String())
+		t.Fail()
+	}
+}
+
+type recordingObserver struct {
+	events []string
+	opened []DialogOpenedEvent
+	tans   []TANChallengedEvent
+}
+
+func (r *recordingObserver) DialogOpened(_ context.Context, event DialogOpenedEvent) {
+	r.events = append(r.events, "DialogOpened")
+	r.opened = append(r.opened, event)
+}
+
+func (r *recordingObserver) MessageSent(_ context.Context, event MessageSentEvent) {
+	r.events = append(r.events, fmt.Sprintf("MessageSent:%d", event.MessageNumber))
+}
+
+func (r *recordingObserver) ResponseReceived(_ context.Context, event ResponseReceivedEvent) {
+	r.events = append(r.events, fmt.Sprintf("ResponseReceived:%d:%d", event.MessageNumber, len(event.Acknowledgements)))
+}
+
+func (r *recordingObserver) TANChallenged(_ context.Context, event TANChallengedEvent) {
+	r.events = append(r.events, "TANChallenged")
+	r.tans = append(r.tans, event)
+}
+
+func (r *recordingObserver) DialogClosed(_ context.Context, event DialogClosedEvent) {
+	r.events = append(r.events, "DialogClosed")
+}
+
+func TestPinTanDialogObserver(t *testing.T) {
+	mock := &mockHTTPSTransport{}
+	d := newTestPinTanDialog(mock)
+	observer := &recordingObserver{}
+	d.observer = observer
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	mock.SetResponseMessages([][]byte{
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Auftrag entgegengenommen'"),
+		encryptedTestMessage(
+			"abcde",
+			"HIRMG:2:2:1+3060::Bitte beachten Sie die enthaltenen Warnungen'",
+			"HIRMS:3:2:4+0030::Auftrag empfangen - Sicherheitsfreigabe erforderlich'",
+			"HITAN:4:6:4+4++4711+Bitte TAN eingeben'",
+		),
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0100::Dialog beendet'"),
+	})
+
+	response, err := d.SendMessage(message.NewHBCIMessage(d.hbciVersion, segment.NewAccountBalanceRequestV5(account, false)))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !response.TANRequired() {
+		t.Logf("Expected response to require a TAN\n")
+		t.Fail()
+	}
+	if response.FindSegment("HITAN") == nil {
+		t.Logf("Expected response to contain the HITAN segment\n")
+		t.Fail()
+	}
+	expected := []string{
+		"MessageSent:1",
+		"ResponseReceived:1:1",
+		"DialogOpened",
+		"MessageSent:2",
+		"ResponseReceived:2:2",
+		"TANChallenged",
+		"MessageSent:3",
+		"ResponseReceived:3:1",
+		"DialogClosed",
+	}
+	if !reflect.DeepEqual(expected, observer.events) {
+		t.Logf("Expected events\n%v\n\tgot\n%v\n", expected, observer.events)
+		t.Fail()
+	}
+	if len(observer.opened) == 1 && (observer.opened[0].DialogID != "abcde" || observer.opened[0].Err != nil) {
+		t.Logf("Expected dialog to be opened successfully with ID %q, got %+v\n", "abcde", observer.opened[0])
+		t.Fail()
+	}
+	if len(observer.tans) == 1 && (observer.tans[0].JobReference != "4711" || observer.tans[0].Challenge != "Bitte TAN eingeben") {
+		t.Logf("Expected TAN challenge to be taken from HITAN, got %+v\n", observer.tans[0])
+		t.Fail()
+	}
+}
+
 func newTestPinTanDialog(transport *mockHTTPSTransport) *PinTanDialog {
 	cfg := Config{
 		BankID:      domain.BankID{CountryCode: 280, ID: "10000000"},
@@ -258,6 +529,98 @@ func newTestPinTanDialog(transport *mockHTTPSTransport) *PinTanDialog {
 	return d
 }
 
+func TestPinTanDialogCompression(t *testing.T) {
+	mock := &mockHTTPSTransport{}
+	d := newTestPinTanDialog(mock)
+	d.enableCompression = true
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	mock.SetResponseMessages([][]byte{
+		encryptedTestMessage(
+			"abcde",
+			"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+			"HIBPA:3:2:4+12+280:10000000+Testbank+0+1+220'",
+			"HIKPV:4:1:4+1+6'",
+		),
+		compressedTestMessage(
+			"abcde",
+			"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+			"HISAL:3:5:1+100000000::280:10000000+Sichteinlagen+EUR+C:1000,15:EUR:20150812+C:20,:EUR:20150812+500,:EUR+1499,85:EUR'",
+		),
+		compressedTestMessage("abcde", "HIRMG:2:2:1+0100::Dialog beendet'"),
+	})
+
+	res, err := d.SendMessage(message.NewHBCIMessage(d.hbciVersion, segment.NewAccountBalanceRequestV5(account, false)))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if res.FindSegment("HISAL") == nil {
+		t.Logf("Expected compressed response to be decompressed\n")
+		t.Fail()
+	}
+	if d.compression != message.CompressionDeflate {
+		t.Logf("Expected compression %q to be negotiated, got %q\n", message.CompressionDeflate, d.compression)
+		t.Fail()
+	}
+	initRequest, _ := ioutil.ReadAll(mock.requests[0].Body)
+	if !bytes.Contains(initRequest, []byte("HKIDN")) {
+		t.Logf("Expected dialog initialization to be sent uncompressed, got %q\n", initRequest)
+		t.Fail()
+	}
+	for i, req := range mock.requests[1:] {
+		body, _ := ioutil.ReadAll(req.Body)
+		if bytes.Contains(body, []byte("HKSAL")) || bytes.Contains(body, []byte("HKEND")) {
+			t.Logf("Expected request %d to be compressed, got %q\n", i+2, body)
+			t.Fail()
+		}
+	}
+}
+
+func TestPinTanDialogCompressionDisabledByDefault(t *testing.T) {
+	mock := &mockHTTPSTransport{}
+	d := newTestPinTanDialog(mock)
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	mock.SetResponseMessages([][]byte{
+		encryptedTestMessage(
+			"abcde",
+			"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+			"HIBPA:3:2:4+12+280:10000000+Testbank+0+1+220'",
+			"HIKPV:4:1:4+1+6'",
+		),
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Auftrag entgegengenommen'"),
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0100::Dialog beendet'"),
+	})
+
+	_, err := d.SendMessage(message.NewHBCIMessage(d.hbciVersion, segment.NewAccountBalanceRequestV5(account, false)))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if d.compression != message.CompressionNone {
+		t.Logf("Expected no compression to be negotiated, got %q\n", d.compression)
+		t.Fail()
+	}
+	body, _ := ioutil.ReadAll(mock.requests[1].Body)
+	if !bytes.Contains(body, []byte("HKSAL")) {
+		t.Logf("Expected request to be sent uncompressed, got %q\n", body)
+		t.Fail()
+	}
+}
+
+// compressedTestMessage is like encryptedTestMessage, but compresses the data
+// with deflate.
+func compressedTestMessage(dialogID string, encryptedData ...string) []byte {
+	data, err := message.Compress(message.CompressionDeflate, charset.ToISO8859_1(strings.Join(encryptedData, "")))
+	if err != nil {
+		panic(err)
+	}
+	encryptionHeader := "HNVSK:998:2:+998+1+1::0+1:20150713:173634+2:2:13:@8@\x00\x00\x00\x00\x00\x00\x00\x00:5:1:+280:10000000:12345:V:0:0+6+'"
+	encryptionData := fmt.Sprintf("HNVSD:999:1:+@%d@%s'", len(data), data)
+	messageEnd := fmt.Sprintf("HNHBS:%d:1:+1'", len(encryptedData)+1)
+	messageHeader := fmt.Sprintf("HNHBK:1:3+%012d+220+%s+1+'", 31+len(dialogID)+len(encryptionHeader)+len(encryptionData)+len(messageEnd), dialogID)
+	return []byte(messageHeader + encryptionHeader + encryptionData + messageEnd)
+}
+
 func encryptedTestMessage(dialogID string, encryptedData ...string) []byte {
 	encryptionHeader := "HNVSK:998:2:+998+1+1::0+1:20150713:173634+2:2:13:@8@\x00\x00\x00\x00\x00\x00\x00\x00:5:1:+280:10000000:12345:V:0:0+0+'"
 	encryptionData := fmt.Sprintf("HNVSD:999:1:+@%d@%s'", len(charset.ToISO8859_1(strings.Join(encryptedData, ""))), charset.ToISO8859_1(strings.Join(encryptedData, "")))
@@ -271,3 +634,296 @@ func encryptedTestMessage(dialogID string, encryptedData ...string) []byte {
 	}
 	return bytes.Join(encryptedMessage, []byte(""))
 }
+
+func TestPinTanDialogCancellation(t *testing.T) {
+	mock := &mockHTTPSTransport{}
+	d := newTestPinTanDialog(mock)
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	// the encrypted data is missing
+	brokenResponse := "HNHBK:1:3+000000000100+220+abcde+2+'" +
+		"HNVSK:998:2:+998+1+1::0+1:20150713:173634+2:2:13:@8@\x00\x00\x00\x00\x00\x00\x00\x00:5:1:+280:10000000:12345:V:0:0+0+'" +
+		"HNHBS:3:1:+2'"
+	mock.SetResponseMessages([][]byte{
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Auftrag entgegengenommen'"),
+		charset.ToISO8859_1(brokenResponse),
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0100::Dialog beendet'"),
+	})
+
+	_, err := d.SendMessage(message.NewHBCIMessage(d.hbciVersion, segment.NewAccountBalanceRequestV5(account, false)))
+
+	var protocolErr *ProtocolError
+	if !errors.As(err, &protocolErr) {
+		t.Logf("Expected error to be a ProtocolError, got %T:%v\n", err, err)
+		t.Fail()
+	}
+	if mock.CallCount() != 3 {
+		t.Fatalf("Expected 3 requests, got %d", mock.CallCount())
+	}
+	cancellation, _ := ioutil.ReadAll(mock.requests[2].Body)
+	if !bytes.Contains(cancellation, []byte("HIRMG")) || !bytes.Contains(cancellation, []byte("9800")) {
+		t.Logf("Expected dialog cancellation to be sent, got %q\n", cancellation)
+		t.Fail()
+	}
+	if bytes.Contains(cancellation, []byte("HKEND")) {
+		t.Logf("Expected no dialog end to be sent, got %q\n", cancellation)
+		t.Fail()
+	}
+	if d.dialogID != initialDialogID || d.messageCount != 0 {
+		t.Logf("Expected dialog to be reset, got dialog ID %q and message number %d\n", d.dialogID, d.messageCount)
+		t.Fail()
+	}
+}
+
+func TestPinTanDialogMessageErrorResetsDialog(t *testing.T) {
+	mock := &mockHTTPSTransport{}
+	d := newTestPinTanDialog(mock)
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	mock.SetResponseMessages([][]byte{
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Auftrag entgegengenommen'"),
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+9800::Dialog abgebrochen'"),
+	})
+
+	_, err := d.SendMessage(message.NewHBCIMessage(d.hbciVersion, segment.NewAccountBalanceRequestV5(account, false)))
+
+	var ackErr *domain.AcknowledgementError
+	if !errors.As(err, &ackErr) {
+		t.Logf("Expected error to be an AcknowledgementError, got %T:%v\n", err, err)
+		t.Fail()
+	}
+	if mock.CallCount() != 2 {
+		t.Logf("Expected no dialog end to be sent, got %d requests\n", mock.CallCount())
+		t.Fail()
+	}
+	if d.dialogID != initialDialogID || d.messageCount != 0 {
+		t.Logf("Expected dialog to be reset, got dialog ID %q and message number %d\n", d.dialogID, d.messageCount)
+		t.Fail()
+	}
+}
+
+func TestPinTanDialogSendMessageDialogEndError(t *testing.T) {
+	mock := &mockHTTPSTransport{}
+	d := newTestPinTanDialog(mock)
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	mock.SetResponseMessages([][]byte{
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Auftrag entgegengenommen'"),
+		encryptedTestMessage(
+			"abcde",
+			"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+			"HISAL:3:5:1+100000000::280:10000000+Sichteinlagen+EUR+C:1000,15:EUR:20150812+C:20,:EUR:20150812+500,:EUR+1499,85:EUR'",
+		),
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+9050::Die Nachricht enthält Fehler'"),
+	})
+
+	res, err := d.SendMessage(message.NewHBCIMessage(d.hbciVersion, segment.NewAccountBalanceRequestV5(account, false)))
+
+	var endErr *DialogEndError
+	if !errors.As(err, &endErr) {
+		t.Logf("Expected error to be a DialogEndError, got %T:%v\n", err, err)
+		t.Fail()
+	}
+	if res == nil || res.FindSegment("HISAL") == nil {
+		t.Logf("Expected the response to be returned along with the error, got %v\n", res)
+		t.Fail()
+	}
+}
+
+func TestPinTanDialogParameterDataChanged(t *testing.T) {
+	mock := &mockHTTPSTransport{}
+	d := newTestPinTanDialog(mock)
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	mock.SetResponseMessages([][]byte{
+		encryptedTestMessage(
+			"abcde",
+			"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+			"HIBPA:3:2:4+12+280:10000000+Testbank+0+1+220'",
+		),
+		encryptedTestMessage(
+			"abcde",
+			"HIRMG:2:2:1+3060::Bitte beachten Sie die enthaltenen Warnungen'",
+			"HIRMS:3:2:3+3081::Aktualisierte Parameterdaten beachten+9120::Auftrag abgelehnt'",
+		),
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0100::Dialog beendet'"),
+		encryptedTestMessage(
+			"fghij",
+			"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+			"HIBPA:3:2:4+13+280:10000000+Testbank+0+1+220'",
+		),
+		encryptedTestMessage(
+			"fghij",
+			"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+			"HISAL:3:5:1+100000000::280:10000000+Sichteinlagen+EUR+C:1000,15:EUR:20150812+C:20,:EUR:20150812+500,:EUR+1499,85:EUR'",
+		),
+		encryptedTestMessage("fghij", "HIRMG:2:2:1+0100::Dialog beendet'"),
+	})
+
+	res, err := d.SendMessage(message.NewHBCIMessage(d.hbciVersion, segment.NewAccountBalanceRequestV5(account, false)))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v", err, err)
+	}
+	if res.FindSegment("HISAL") == nil {
+		t.Logf("Expected the job to be retried within a new dialog\n")
+		t.Fail()
+	}
+	if mock.CallCount() != 6 {
+		t.Logf("Expected 6 requests, got %d\n", mock.CallCount())
+		t.Fail()
+	}
+	if d.BankParameterDataVersion() != 13 {
+		t.Logf("Expected BPD version 13, got %d\n", d.BankParameterDataVersion())
+		t.Fail()
+	}
+	reinit, _ := ioutil.ReadAll(mock.requests[3].Body)
+	if !bytes.Contains(reinit, []byte("HKVVB:4:3+0+0+")) {
+		t.Logf("Expected the dialog to be reinitialized with outdated parameter data, got %q\n", reinit)
+		t.Fail()
+	}
+}
+
+func TestPinTanDialogParameterDataChangedWithinSuccessfulResponse(t *testing.T) {
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	balance := "HISAL:4:5:3+100000000::280:10000000+Sichteinlagen+EUR+C:1000,15:EUR:20150812+C:20,:EUR:20150812+500,:EUR+1499,85:EUR'"
+	tests := []struct {
+		description        string
+		jobResponse        []string
+		expectedBPDVersion int
+		expectedUPDVersion int
+	}{
+		{
+			"parameter data not enclosed",
+			[]string{
+				"HIRMG:2:2:1+3060::Bitte beachten Sie die enthaltenen Warnungen'",
+				"HIRMS:3:2:3+0020::Auftrag ausgeführt+3081::Aktualisierte Parameterdaten beachten'",
+				balance,
+			},
+			0, 0,
+		},
+		{
+			"BPD enclosed",
+			[]string{
+				"HIRMG:2:2:1+1040::BPD nicht mehr aktuell, aktuelle Version enthalten'",
+				"HIRMS:3:2:3+0020::Auftrag ausgeführt'",
+				balance,
+				"HIBPA:5:2:3+13+280:10000000+Testbank+0+1+220'",
+			},
+			13, 4,
+		},
+	}
+
+	for _, test := range tests {
+		mock := &mockHTTPSTransport{}
+		d := newTestPinTanDialog(mock)
+		mock.SetResponseMessages([][]byte{
+			encryptedTestMessage(
+				"abcde",
+				"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+				"HIBPA:3:2:4+12+280:10000000+Testbank+0+1+220'",
+				"HIUPA:4:2:4+12345+4+0'",
+			),
+			encryptedTestMessage("abcde", test.jobResponse...),
+			encryptedTestMessage("abcde", "HIRMG:2:2:1+0100::Dialog beendet'"),
+		})
+
+		res, err := d.SendMessage(message.NewHBCIMessage(d.hbciVersion, segment.NewAccountBalanceRequestV5(account, false)))
+
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %T:%v", test.description, err, err)
+		}
+		if res.FindSegment("HISAL") == nil {
+			t.Logf("%s: Expected the response of the job\n", test.description)
+			t.Fail()
+		}
+		if mock.CallCount() != 3 {
+			t.Logf("%s: Expected the message not to be sent again, got %d requests\n", test.description, mock.CallCount())
+			t.Fail()
+		}
+		if d.BankParameterDataVersion() != test.expectedBPDVersion || d.UserParameterDataVersion() != test.expectedUPDVersion {
+			t.Logf(
+				"%s: Expected BPD version %d and UPD version %d, got %d and %d\n", test.description,
+				test.expectedBPDVersion, test.expectedUPDVersion, d.BankParameterDataVersion(), d.UserParameterDataVersion(),
+			)
+			t.Fail()
+		}
+	}
+}
+
+func TestPinTanDialogParameterDataChangedNonRetryable(t *testing.T) {
+	mock := &mockHTTPSTransport{}
+	d := newTestPinTanDialog(mock)
+	keyName := domain.KeyName{BankID: domain.BankID{CountryCode: 280, ID: "10000000"}, UserID: "12345", KeyType: domain.KeyTypeSigning}
+	mock.SetResponseMessages([][]byte{
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Auftrag entgegengenommen'"),
+		encryptedTestMessage(
+			"abcde",
+			"HIRMG:2:2:1+3060::Bitte beachten Sie die enthaltenen Warnungen'",
+			"HIRMS:3:2:3+3081::Aktualisierte Parameterdaten beachten+9120::Auftrag abgelehnt'",
+		),
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0100::Dialog beendet'"),
+	})
+
+	_, err := d.SendMessage(message.NewHBCIMessage(
+		d.hbciVersion, segment.NewPublicKeyRevocationSegment(-1, keyName, segment.KeyRevocationMisc),
+	))
+
+	if !errors.Is(err, domain.ErrParameterDataChanged) {
+		t.Logf("Expected the rejection to be returned, got %T:%v\n", err, err)
+		t.Fail()
+	}
+	if mock.CallCount() != 3 {
+		t.Logf("Expected the message not to be sent again, got %d requests\n", mock.CallCount())
+		t.Fail()
+	}
+}
+
+func TestPinTanDialogValidateJobs(t *testing.T) {
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	parameters := &segment.AccountTransactionParameterSegment{}
+	if err := parameters.UnmarshalHBCI([]byte("HIKAZS:4:5:4+1+1+90:N:N'")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	transactionRequest := func(allAccounts bool, startDate time.Time, maxEntries int) segment.ClientSegment {
+		request := segment.NewAccountTransactionRequestSegmentV5(account, allAccounts)
+		request.SetTransactionRange(domain.Timeframe{StartDate: domain.NewShortDate(startDate)})
+		if maxEntries > 0 {
+			request.SetMaxEntries(maxEntries)
+		}
+		return request
+	}
+	recently := time.Now().AddDate(0, 0, -10)
+	tests := []struct {
+		description string
+		jobs        []segment.ClientSegment
+		valid       bool
+	}{
+		{"valid request", []segment.ClientSegment{transactionRequest(false, recently, 0)}, true},
+		{"too many jobs", []segment.ClientSegment{transactionRequest(false, recently, 0), transactionRequest(false, recently, 0)}, false},
+		{"all accounts", []segment.ClientSegment{transactionRequest(true, recently, 0)}, false},
+		{"max entries", []segment.ClientSegment{transactionRequest(false, recently, 10)}, false},
+		{"start date too old", []segment.ClientSegment{transactionRequest(false, time.Now().AddDate(0, 0, -100), 0)}, false},
+		{"TAN without HKTAN", []segment.ClientSegment{segment.NewAccountBalanceRequestV5(account, false)}, false},
+		{
+			"TAN with HKTAN",
+			[]segment.ClientSegment{segment.NewTanProcess4RequestSegmentV6(segment.IdentificationID), segment.NewAccountBalanceRequestV5(account, false)},
+			true,
+		},
+	}
+	for _, test := range tests {
+		d := newTestPinTanDialog(&mockHTTPSTransport{})
+		d.BankParameterData.SupportedSegmentParameters = []SegmentParameter{
+			{VersionedSegment: segment.VersionedSegment{ID: "HIKAZS", Version: 5}, Parameters: parameters},
+		}
+		d.BankParameterData.PinTanBusinessTransactions = map[string]bool{"HKSAL": true}
+
+		err := d.validateJobs(test.jobs)
+
+		var validationErr *JobValidationError
+		if test.valid && err != nil {
+			t.Logf("%s: Expected no error, got %v\n", test.description, err)
+			t.Fail()
+		}
+		if !test.valid && !errors.As(err, &validationErr) {
+			t.Logf("%s: Expected a JobValidationError, got %T:%v\n", test.description, err, err)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package dialog
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/message"
+	"golang.org/x/crypto/argon2"
+)
+
+// ErrKeyFileDecryption is returned if a key file could not be decrypted,
+// i.e. the password is wrong or the file got corrupted.
+var ErrKeyFileDecryption = errors.New("dialog: invalid password or corrupted key file")
+
+const (
+	keyFileFormat  = "go-hbci-keyfile/1"
+	keyFileKDF     = "argon2id"
+	keyFileCipher  = "AES-256-GCM"
+	keyFileKeySize = 32
+)
+
+// The Argon2id parameters follow the recommendation of RFC 9106 for
+// memory constrained environments
+var defaultKeyFileKDF = keyFileKDFParams{
+	Name:    keyFileKDF,
+	Time:    3,
+	Memory:  64 * 1024,
+	Threads: 4,
+}
+
+// NewKeyFile returns an empty KeyFile stored at path and encrypted with a key
+// derived from password. The file is created by the first call to Write.
+func NewKeyFile(path string, password []byte) (*KeyFile, error) {
+	kdf := defaultKeyFileKDF
+	kdf.Salt = make([]byte, 16)
+	if _, err := rand.Read(kdf.Salt); err != nil {
+		return nil, fmt.Errorf("error generating salt: %w", err)
+	}
+	return &KeyFile{path: path, kdf: kdf, key: kdf.deriveKey(password)}, nil
+}
+
+// OpenKeyFile reads and decrypts the KeyFile at path. It returns
+// ErrKeyFileDecryption if password does not match.
+func OpenKeyFile(path string, password []byte) (*KeyFile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading key file: %w", err)
+	}
+	var envelope keyFileEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("error unmarshaling key file: %w", err)
+	}
+	if envelope.Format != keyFileFormat || envelope.Cipher != keyFileCipher || envelope.KDF.Name != keyFileKDF {
+		return nil, fmt.Errorf("unsupported key file format %q (%s, %s)", envelope.Format, envelope.KDF.Name, envelope.Cipher)
+	}
+	k := &KeyFile{path: path, kdf: envelope.KDF, key: envelope.KDF.deriveKey(password)}
+	aead, err := k.aead()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, envelope.Nonce, envelope.Data, envelope.additionalData())
+	if err != nil {
+		return nil, ErrKeyFileDecryption
+	}
+	var content keyFileContent
+	if err := json.Unmarshal(plaintext, &content); err != nil {
+		return nil, fmt.Errorf("error unmarshaling key file content: %w", err)
+	}
+	if err := k.setContent(content); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// KeyFile contains the RSA keys of a user, the public keys of the institute
+// and the client state needed for key based security. It is stored
+// encrypted with AES-256-GCM, using a key derived from a password by
+// Argon2id.
+//
+// A KeyFile implements StateStore for the client system ID and the
+// signature ID. Every save writes the file, so that a signature ID is never
+// reused. BPD and UPD are not stored.
+type KeyFile struct {
+	BankID  domain.BankID
+	UserID  string
+	Profile message.SecurityProfile
+	// SigningKey and EncryptionKey are the keys of the user, including
+	// their private keys
+	SigningKey    *domain.RSAKey
+	EncryptionKey *domain.RSAKey
+	// BankSigningKey and BankEncryptionKey are the public keys of the
+	// institute
+	BankSigningKey    *domain.RSAKey
+	BankEncryptionKey *domain.RSAKey
+	// SignatureID is the last signature ID used
+	SignatureID    int
+	ClientSystemID string
+
+	mu   sync.Mutex
+	path string
+	kdf  keyFileKDFParams
+	key  []byte
+}
+
+// Write encrypts the KeyFile and replaces the file atomically.
+func (k *KeyFile) Write() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.write()
+}
+
+// Load returns the client system ID and signature ID. As a KeyFile belongs
+// to a single user, key is ignored.
+func (k *KeyFile) Load(key string) (*State, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return &State{ClientSystemID: k.ClientSystemID, SignatureID: k.SignatureID}, nil
+}
+
+// Save stores the client system ID and signature ID of state and writes the
+// file.
+func (k *KeyFile) Save(key string, state *State) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.ClientSystemID = state.ClientSystemID
+	k.SignatureID = state.SignatureID
+	return k.write()
+}
+
+func (k *KeyFile) write() error {
+	content, err := k.content()
+	if err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("error marshaling key file content: %w", err)
+	}
+	aead, err := k.aead()
+	if err != nil {
+		return err
+	}
+	envelope := keyFileEnvelope{
+		Format: keyFileFormat,
+		KDF:    k.kdf,
+		Cipher: keyFileCipher,
+		Nonce:  make([]byte, aead.NonceSize()),
+	}
+	if _, err := rand.Read(envelope.Nonce); err != nil {
+		return fmt.Errorf("error generating nonce: %w", err)
+	}
+	envelope.Data = aead.Seal(nil, envelope.Nonce, plaintext, envelope.additionalData())
+	data, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling key file: %w", err)
+	}
+	return writeFileAtomic(k.path, data, 0600)
+}
+
+func (k *KeyFile) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(k.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (k *KeyFile) content() (keyFileContent, error) {
+	content := keyFileContent{
+		BankID:         k.BankID,
+		UserID:         k.UserID,
+		Profile:        k.Profile,
+		SignatureID:    k.SignatureID,
+		ClientSystemID: k.ClientSystemID,
+	}
+	var err error
+	if content.SigningKey, err = newKeyFileKey(k.SigningKey); err != nil {
+		return content, err
+	}
+	if content.EncryptionKey, err = newKeyFileKey(k.EncryptionKey); err != nil {
+		return content, err
+	}
+	if content.BankSigningKey, err = newKeyFileKey(k.BankSigningKey); err != nil {
+		return content, err
+	}
+	if content.BankEncryptionKey, err = newKeyFileKey(k.BankEncryptionKey); err != nil {
+		return content, err
+	}
+	return content, nil
+}
+
+func (k *KeyFile) setContent(content keyFileContent) error {
+	k.BankID = content.BankID
+	k.UserID = content.UserID
+	k.Profile = content.Profile
+	k.SignatureID = content.SignatureID
+	k.ClientSystemID = content.ClientSystemID
+	var err error
+	if k.SigningKey, err = content.SigningKey.rsaKey(); err != nil {
+		return err
+	}
+	if k.EncryptionKey, err = content.EncryptionKey.rsaKey(); err != nil {
+		return err
+	}
+	if k.BankSigningKey, err = content.BankSigningKey.rsaKey(); err != nil {
+		return err
+	}
+	if k.BankEncryptionKey, err = content.BankEncryptionKey.rsaKey(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// apply fills the unset fields of config from the KeyFile
+func (k *KeyFile) apply(config RDHConfig) RDHConfig {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if config.BankID == (domain.BankID{}) {
+		config.BankID = k.BankID
+	}
+	if config.UserID == "" {
+		config.UserID = k.UserID
+	}
+	if config.Profile == (message.SecurityProfile{}) {
+		config.Profile = k.Profile
+	}
+	if config.SigningKey == nil {
+		config.SigningKey = k.SigningKey
+	}
+	if config.EncryptionKey == nil {
+		config.EncryptionKey = k.EncryptionKey
+	}
+	if config.BankSigningKey == nil {
+		config.BankSigningKey = k.BankSigningKey
+	}
+	if config.BankEncryptionKey == nil {
+		config.BankEncryptionKey = k.BankEncryptionKey
+	}
+	if config.StateStore == nil {
+		config.StateStore = k
+	}
+	return config
+}
+
+type keyFileEnvelope struct {
+	Format string           `json:"format"`
+	KDF    keyFileKDFParams `json:"kdf"`
+	Cipher string           `json:"cipher"`
+	Nonce  []byte           `json:"nonce"`
+	Data   []byte           `json:"data"`
+}
+
+// additionalData binds the format and KDF parameters to the encrypted data
+func (e keyFileEnvelope) additionalData() []byte {
+	data, _ := json.Marshal(keyFileEnvelope{Format: e.Format, KDF: e.KDF, Cipher: e.Cipher})
+	return data
+}
+
+type keyFileKDFParams struct {
+	Name    string `json:"name"`
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+func (p keyFileKDFParams) deriveKey(password []byte) []byte {
+	return argon2.IDKey(password, p.Salt, p.Time, p.Memory, p.Threads, keyFileKeySize)
+}
+
+type keyFileContent struct {
+	BankID            domain.BankID           `json:"bankID"`
+	UserID            string                  `json:"userID"`
+	Profile           message.SecurityProfile `json:"profile"`
+	SigningKey        *keyFileKey             `json:"signingKey,omitempty"`
+	EncryptionKey     *keyFileKey             `json:"encryptionKey,omitempty"`
+	BankSigningKey    *keyFileKey             `json:"bankSigningKey,omitempty"`
+	BankEncryptionKey *keyFileKey             `json:"bankEncryptionKey,omitempty"`
+	SignatureID       int                     `json:"signatureID"`
+	ClientSystemID    string                  `json:"clientSystemID"`
+}
+
+// keyFileKey contains a private key in PKCS #8 or a public key in PKIX
+// form, along with its key name
+type keyFileKey struct {
+	KeyName    domain.KeyName `json:"keyName"`
+	PrivateKey []byte         `json:"privateKey,omitempty"`
+	PublicKey  []byte         `json:"publicKey,omitempty"`
+}
+
+func newKeyFileKey(key *domain.RSAKey) (*keyFileKey, error) {
+	if key == nil {
+		return nil, nil
+	}
+	k := &keyFileKey{KeyName: key.KeyName()}
+	var err error
+	if privateKey := key.SigningKey(); privateKey != nil {
+		k.PrivateKey, err = x509.MarshalPKCS8PrivateKey(privateKey)
+	} else {
+		k.PublicKey, err = x509.MarshalPKIXPublicKey(key.RSAPublicKey())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling key %v: %w", k.KeyName, err)
+	}
+	return k, nil
+}
+
+func (k *keyFileKey) rsaKey() (*domain.RSAKey, error) {
+	if k == nil {
+		return nil, nil
+	}
+	if k.PrivateKey != nil {
+		return parseRSAKey(&pem.Block{Type: "PRIVATE KEY", Bytes: k.PrivateKey}, k.KeyName)
+	}
+	return parseRSAKey(&pem.Block{Type: "PUBLIC KEY", Bytes: k.PublicKey}, k.KeyName)
+}
+
+// ImportPEMKey imports a RSA key from PEM encoded data, e.g. as exported by
+// OpenSSL. Private keys in PKCS #1 ("RSA PRIVATE KEY") or PKCS #8 ("PRIVATE
+// KEY") form and public keys in PKCS #1 ("RSA PUBLIC KEY") or PKIX ("PUBLIC
+// KEY") form are supported. Encrypted PEM blocks are not.
+//
+// PEM is the only supported import format. The key files of other HBCI
+// clients, like RDH-2 files or HBCI4Java passports, are not readable; their
+// keys have to be exported to PEM first.
+func ImportPEMKey(data []byte, keyName domain.KeyName) (*domain.RSAKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+	if _, encrypted := block.Headers["Proc-Type"]; encrypted {
+		return nil, fmt.Errorf("encrypted PEM block %q is not supported", block.Type)
+	}
+	return parseRSAKey(block, keyName)
+}
+
+func parseRSAKey(block *pem.Block, keyName domain.KeyName) (*domain.RSAKey, error) {
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing key %v: %w", keyName, err)
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return domain.NewRSAKey(domain.NewPrivateKey(keyName.KeyType, k), &keyName), nil
+	case *rsa.PublicKey:
+		return domain.NewRSAKey(domain.NewPublicKey(keyName.KeyType, k), &keyName), nil
+	default:
+		return nil, fmt.Errorf("key %v is no RSA key but %T", keyName, key)
+	}
+}
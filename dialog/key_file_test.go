@@ -0,0 +1,194 @@
+package dialog
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+func newTestKeyFile(t *testing.T, keys rdhTestKeys, path string) *KeyFile {
+	keyFile, err := NewKeyFile(path, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	keyFile.BankID = domain.BankID{CountryCode: 280, ID: "10000000"}
+	keyFile.UserID = "12345"
+	keyFile.Profile = message.RAH10
+	keyFile.SigningKey = keys.userSigning
+	keyFile.EncryptionKey = keys.userEncryption
+	keyFile.BankSigningKey = public(keys.bankSigning)
+	keyFile.BankEncryptionKey = public(keys.bankEncryption)
+	keyFile.ClientSystemID = "xyz"
+	keyFile.SignatureID = 41
+	if err := keyFile.Write(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return keyFile
+}
+
+func TestKeyFile(t *testing.T) {
+	keys := generateRDHTestKeys(t)
+	path := filepath.Join(t.TempDir(), "keys.json")
+	newTestKeyFile(t, keys, path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Logf("Expected file mode 0600, got %v\n", info.Mode().Perm())
+		t.Fail()
+	}
+	data, _ := ioutil.ReadFile(path)
+	if bytes.Contains(data, []byte("12345")) {
+		t.Logf("Expected content to be encrypted, got %s\n", data)
+		t.Fail()
+	}
+
+	_, err = OpenKeyFile(path, []byte("wrong"))
+	if !errors.Is(err, ErrKeyFileDecryption) {
+		t.Logf("Expected ErrKeyFileDecryption for a wrong password, got %v\n", err)
+		t.Fail()
+	}
+
+	keyFile, err := OpenKeyFile(path, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if keyFile.UserID != "12345" || keyFile.Profile != message.RAH10 ||
+		keyFile.ClientSystemID != "xyz" || keyFile.SignatureID != 41 {
+		t.Logf("Expected fields to be restored, got %+v\n", keyFile)
+		t.Fail()
+	}
+	if keyFile.SigningKey.SigningKey() == nil || !keyFile.SigningKey.SigningKey().Equal(keys.userSigning.SigningKey()) {
+		t.Logf("Expected private signing key to be restored\n")
+		t.Fail()
+	}
+	if keyFile.BankEncryptionKey.SigningKey() != nil ||
+		!keyFile.BankEncryptionKey.RSAPublicKey().Equal(keys.bankEncryption.RSAPublicKey()) {
+		t.Logf("Expected public bank encryption key to be restored\n")
+		t.Fail()
+	}
+	if keyFile.EncryptionKey.KeyName() != keys.userEncryption.KeyName() {
+		t.Logf("Expected key name %v, got %v\n", keys.userEncryption.KeyName(), keyFile.EncryptionKey.KeyName())
+		t.Fail()
+	}
+
+	if err := keyFile.Save("ignored", &State{ClientSystemID: "abc", SignatureID: 42}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	reopened, err := OpenKeyFile(path, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	state, _ := reopened.Load("ignored")
+	if state.ClientSystemID != "abc" || state.SignatureID != 42 {
+		t.Logf("Expected saved state to be written, got %+v\n", state)
+		t.Fail()
+	}
+}
+
+func TestNewRDHDialogFromKeyFile(t *testing.T) {
+	keys := generateRDHTestKeys(t)
+	path := filepath.Join(t.TempDir(), "keys.json")
+	keyFile := newTestKeyFile(t, keys, path)
+	profile := message.RAH10
+	mock := &mockHTTPSTransport{}
+	mock.SetResponseMessages([][]byte{
+		rdhTestMessage(t, profile, keys, nil, "abcde", "HIRMG:3:2+0010::Nachricht entgegengenommen'"),
+		rdhTestMessage(t, profile, keys, nil, "abcde", "HIRMG:3:2+0010::Nachricht entgegengenommen'"),
+		rdhTestMessage(t, profile, keys, nil, "abcde", "HIRMG:3:2+0100::Dialog beendet'"),
+	})
+
+	d, err := NewRDHDialog(RDHConfig{
+		Config: Config{
+			HBCIURL:     "http://localhost",
+			HBCIVersion: segment.FINTS300,
+		},
+		RequireBankSignature: true,
+		KeyFile:              keyFile,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	d.transport = mock
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	_, err = d.SendMessage(message.NewHBCIMessage(d.hbciVersion, segment.NewAccountBalanceRequestV5(account, false)))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	body, _ := ioutil.ReadAll(mock.requests[0].Body)
+	decryptRDHRequest(t, profile, keys, body)
+	reopened, err := OpenKeyFile(path, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if reopened.SignatureID != 44 {
+		t.Logf("Expected signature ID 44 to be persisted, got %d\n", reopened.SignatureID)
+		t.Fail()
+	}
+}
+
+func TestImportPEMKey(t *testing.T) {
+	keys := generateRDHTestKeys(t)
+	keyName := keys.userSigning.KeyName()
+	privatePEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(keys.userSigning.SigningKey()),
+	})
+	publicDER, err := x509.MarshalPKIXPublicKey(keys.bankSigning.RSAPublicKey())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+	privateKey, err := ImportPEMKey(privatePEM, keyName)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !privateKey.CanSign() || privateKey.KeyName() != keyName {
+		t.Logf("Expected private key named %v, got %v\n", keyName, privateKey.KeyName())
+		t.Fail()
+	}
+	publicKey, err := ImportPEMKey(publicPEM, keys.bankSigning.KeyName())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if publicKey.CanSign() || !publicKey.RSAPublicKey().Equal(keys.bankSigning.RSAPublicKey()) {
+		t.Logf("Expected public key of the institute\n")
+		t.Fail()
+	}
+
+	_, err = ImportPEMKey([]byte("no key"), keyName)
+	if err == nil {
+		t.Logf("Expected an error for missing PEM data\n")
+		t.Fail()
+	}
+
+	unsupported := map[string]*pem.Block{
+		"encrypted PEM block": {
+			Type:    "RSA PRIVATE KEY",
+			Headers: map[string]string{"Proc-Type": "4,ENCRYPTED", "DEK-Info": "AES-256-CBC,00000000000000000000000000000000"},
+			Bytes:   []byte("encrypted"),
+		},
+		"encrypted PKCS #8": {Type: "ENCRYPTED PRIVATE KEY", Bytes: []byte("encrypted")},
+		"certificate":       {Type: "CERTIFICATE", Bytes: publicDER},
+	}
+	for name, block := range unsupported {
+		_, err = ImportPEMKey(pem.EncodeToMemory(block), keyName)
+		if err == nil {
+			t.Logf("%s: Expected an error, got nil\n", name)
+			t.Fail()
+		}
+	}
+}
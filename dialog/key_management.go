@@ -0,0 +1,356 @@
+package dialog
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// ErrBankKeyHashMismatch is returned if the hash of the keys sent by the
+// institute does not match the hash supplied by the user.
+var ErrBankKeyHashMismatch = errors.New("dialog: hash of the bank key does not match")
+
+// FetchBankKeys requests the public keys of the institute within an
+// anonymous dialog. The hash of the signing key, or of the encryption key if
+// the institute does not sign, must match expectedHash, as published by the
+// institute. Both SHA-256 and RIPEMD-160 hashes are accepted in hex notation,
+// separators are ignored. If the institute signs, the response must be
+// signed with the fetched signing key, which authenticates the encryption
+// key. On success the keys are used for the following dialogs and written
+// into the KeyFile, if any.
+func (d *RDHDialog) FetchBankKeys(ctx context.Context, expectedHash string) (err error) {
+	// the keys in use are replaced, the response is verified with the
+	// fetched signing key instead
+	d.signatureVerifier = nil
+	defer d.useKeys()
+	initMessage := message.NewDialogInitializationClientMessage(d.hbciVersion)
+	initMessage.PublicSigningKeyRequest = segment.NewPublicKeyRequestSegment(-1, d.bankKeyName(domain.KeyTypeSigning))
+	initMessage.PublicEncryptionKeyRequest = segment.NewPublicKeyRequestSegment(-1, d.bankKeyName(domain.KeyTypeEncryption))
+	opened := d.startDialog(ctx, anonymousDialog)
+	bankMessage, err := d.sendAnonymousInitMessage(ctx, initMessage)
+	opened(err)
+	if err != nil {
+		return fmt.Errorf("error while requesting the keys of the institute: %w", err)
+	}
+	defer func() {
+		endCtx, cancel := dialogEndContext(ctx)
+		defer cancel()
+		err = withDialogEnd(err, func() error { return d.anonymousEnd(endCtx) })
+	}()
+
+	var signingKey, encryptionKey *domain.RSAKey
+	for _, seg := range bankMessage.FindSegments("HIISA") {
+		keySegment, ok := seg.(*segment.PublicKeyTransmissionSegment)
+		if !ok {
+			continue
+		}
+		key := keySegment.Key()
+		switch key.KeyName().KeyType {
+		case domain.KeyTypeSigning:
+			signingKey = key
+		case domain.KeyTypeEncryption:
+			encryptionKey = key
+		}
+	}
+	if encryptionKey == nil {
+		return &ProtocolError{fmt.Errorf("missing encryption key of the institute")}
+	}
+	hashedKey := signingKey
+	if hashedKey == nil {
+		hashedKey = encryptionKey
+	}
+	if !matchesKeyHash(hashedKey.PublicKey, expectedHash) {
+		return ErrBankKeyHashMismatch
+	}
+	if signingKey != nil {
+		verifier := message.NewRDHSignatureVerifier(d.profile, signingKey, true)
+		if err := verifier.Verify(bankMessage); err != nil {
+			return &ProtocolError{fmt.Errorf("error verifying the keys of the institute: %w", err)}
+		}
+	}
+	d.bankSigningKey = signingKey
+	d.bankEncryptionKey = encryptionKey
+	d.useKeys()
+	return d.saveKeys()
+}
+
+// BankKeys returns the public keys of the institute in use. The signing key
+// is nil if the institute does not sign its messages.
+func (d *RDHDialog) BankKeys() (signingKey, encryptionKey *domain.RSAKey) {
+	return d.bankSigningKey, d.bankEncryptionKey
+}
+
+// SubmitUserKeys submits the public keys of the user to the institute
+// (HKSAK). The institute activates them after receiving the INI letter.
+func (d *RDHDialog) SubmitUserKeys(ctx context.Context) error {
+	if d.bankEncryptionKey == nil {
+		return fmt.Errorf("missing encryption key of the institute, fetch the bank keys first")
+	}
+	return d.keyManagement(ctx,
+		segment.NewPublicKeyRenewalSegment(-1, d.signingKey.KeyName(), d.signingKey.PublicKey),
+		segment.NewPublicKeyRenewalSegment(-1, d.encryptionKey.KeyName(), d.encryptionKey.PublicKey),
+	)
+}
+
+// ChangeUserKeys replaces the keys of the user by signingKey and
+// encryptionKey (HKSAK). The message is signed with the current key, the new
+// keys are used afterwards.
+func (d *RDHDialog) ChangeUserKeys(ctx context.Context, signingKey, encryptionKey *domain.RSAKey) error {
+	if signingKey == nil || signingKey.SigningKey() == nil {
+		return fmt.Errorf("missing private signing key of the user")
+	}
+	if encryptionKey == nil || encryptionKey.SigningKey() == nil {
+		return fmt.Errorf("missing private encryption key of the user")
+	}
+	err := d.keyManagement(ctx,
+		segment.NewPublicKeyRenewalSegment(-1, signingKey.KeyName(), signingKey.PublicKey),
+		segment.NewPublicKeyRenewalSegment(-1, encryptionKey.KeyName(), encryptionKey.PublicKey),
+	)
+	if err != nil {
+		return err
+	}
+	d.signingKey = signingKey
+	d.encryptionKey = encryptionKey
+	d.useKeys()
+	return d.saveKeys()
+}
+
+// RevokeUserKeys revokes the keys of the user (HKSSP). reason must be one of
+// segment.KeyCompromitted, segment.KeyMaybeCompromitted or
+// segment.KeyRevocationMisc.
+func (d *RDHDialog) RevokeUserKeys(ctx context.Context, reason string) error {
+	switch reason {
+	case segment.KeyCompromitted, segment.KeyMaybeCompromitted, segment.KeyRevocationMisc:
+	default:
+		return fmt.Errorf("invalid revocation reason %q", reason)
+	}
+	return d.keyManagement(ctx,
+		segment.NewPublicKeyRevocationSegment(-1, d.signingKey.KeyName(), reason),
+		segment.NewPublicKeyRevocationSegment(-1, d.encryptionKey.KeyName(), reason),
+	)
+}
+
+// keyManagement sends segments within a signed and encrypted dialog
+// initialization and ends the dialog afterwards.
+func (d *RDHDialog) keyManagement(ctx context.Context, segments ...segment.ClientSegment) (err error) {
+	opened := d.startDialog(ctx, personalDialog)
+	err = d.sendKeyManagementInit(ctx, segments)
+	opened(err)
+	if err != nil {
+		return fmt.Errorf("error while initializing key management dialog: %w", err)
+	}
+	return withDialogEnd(nil, func() error { return d.endContext(ctx) })
+}
+
+func (d *RDHDialog) sendKeyManagementInit(ctx context.Context, segments []segment.ClientSegment) error {
+	d.dialogID = initialDialogID
+	d.messageCount = 0
+	segments = append([]segment.ClientSegment{
+		segment.NewIdentificationSegment(d.BankID, d.clientID, d.ClientSystemID, true),
+		segment.NewProcessingPreparationSegmentV3(
+			d.BankParameterDataVersion(), d.UserParameterDataVersion(), d.Language,
+		),
+	}, segments...)
+	initMessage := message.NewHBCIMessage(d.hbciVersion, segments...)
+	signedMessage, err := d.newBasicMessage(initMessage).Sign(d.signatureProvider)
+	if err != nil {
+		return err
+	}
+	encryptedMessage, err := signedMessage.Encrypt(d.cryptoProvider)
+	if err != nil {
+		return err
+	}
+	bankMessage, err := d.request(ctx, encryptedMessage, dialogInitializationMetadata(initMessage))
+	if err != nil {
+		return err
+	}
+	messageHeader := bankMessage.MessageHeader()
+	if messageHeader == nil {
+		return fmt.Errorf("malformed response message: %q", bankMessage)
+	}
+	d.dialogID = messageHeader.DialogID.Val()
+	if err := d.checkAcknowledgements(bankMessage.Acknowledgements()); err != nil {
+		return err
+	}
+	d.active = true
+	return nil
+}
+
+func (d *RDHDialog) bankKeyName(keyType domain.KeyType) domain.KeyName {
+	return *domain.NewInitialKeyName(d.BankID.CountryCode, d.BankID.ID, d.UserID, keyType)
+}
+
+func matchesKeyHash(key *domain.PublicKey, expectedHash string) bool {
+	normalized := strings.Map(func(r rune) rune {
+		if strings.ContainsRune("0123456789abcdef", r) {
+			return r
+		}
+		return -1
+	}, strings.ToLower(expectedHash))
+	return normalized != "" &&
+		(normalized == hex.EncodeToString(key.HashSHA256()) ||
+			normalized == hex.EncodeToString(key.HashRIPEMD160()))
+}
+
+// IniLetter returns the INI letter for the keys of the user. It has to be
+// signed and sent to the institute to activate the keys.
+func (d *RDHDialog) IniLetter() *IniLetter {
+	letter := &IniLetter{
+		BankID:   d.BankID,
+		UserID:   d.UserID,
+		Profile:  d.profile.String(),
+		Date:     time.Now(),
+		BankName: d.BankParameterData.BankName,
+	}
+	for _, key := range []*domain.RSAKey{d.signingKey, d.encryptionKey} {
+		letter.Keys = append(letter.Keys, newIniLetterKey(key))
+	}
+	return letter
+}
+
+// IniLetter contains the data of the INI letter
+type IniLetter struct {
+	BankID   domain.BankID
+	BankName string
+	UserID   string
+	Profile  string
+	Date     time.Time
+	Keys     []IniLetterKey
+}
+
+// IniLetterKey contains the data of a key printed on the INI letter. All
+// binary values are hex encoded.
+type IniLetterKey struct {
+	KeyName   domain.KeyName
+	Exponent  string
+	Modulus   string
+	RIPEMD160 string
+	SHA256    string
+}
+
+func newIniLetterKey(key *domain.RSAKey) IniLetterKey {
+	return IniLetterKey{
+		KeyName:   key.KeyName(),
+		Exponent:  formatHex(key.Exponent),
+		Modulus:   formatHex(key.Modulus),
+		RIPEMD160: formatHex(key.HashRIPEMD160()),
+		SHA256:    formatHex(key.HashSHA256()),
+	}
+}
+
+// KeyType returns a description of the key type
+func (i IniLetterKey) KeyType() string {
+	if i.KeyName.KeyType == domain.KeyTypeEncryption {
+		return "Chiffrierschlüssel"
+	}
+	return "Signierschlüssel"
+}
+
+// formatHex returns the upper case hex representation of value in groups of
+// two characters, 16 groups per line
+func formatHex(value []byte) string {
+	var lines []string
+	for len(value) > 0 {
+		n := 16
+		if len(value) < n {
+			n = len(value)
+		}
+		groups := make([]string, n)
+		for i, b := range value[:n] {
+			groups[i] = fmt.Sprintf("%02X", b)
+		}
+		lines = append(lines, strings.Join(groups, " "))
+		value = value[n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+const iniLetterText = `INI-Brief {{.Profile}}
+
+Kreditinstitut: {{if .BankName}}{{.BankName}} {{end}}({{.BankID.ID}})
+Benutzerkennung: {{.UserID}}
+Datum: {{.Date.Format "02.01.2006 15:04"}}
+{{range .Keys}}
+{{.KeyType}} (Nummer {{.KeyName.KeyNumber}}, Version {{.KeyName.KeyVersion}})
+
+Exponent:
+{{.Exponent}}
+
+Modulus:
+{{.Modulus}}
+
+Hashwert RIPEMD-160:
+{{.RIPEMD160}}
+
+Hashwert SHA-256:
+{{.SHA256}}
+{{end}}
+Ich bestätige hiermit, dass die oben genannten Schlüssel meine öffentlichen
+Schlüssel sind.
+
+
+______________________________          ______________________________
+Ort, Datum                              Unterschrift
+`
+
+const iniLetterHTML = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>INI-Brief {{.Profile}}</title></head>
+<body>
+<h1>INI-Brief {{.Profile}}</h1>
+<table>
+<tr><th>Kreditinstitut</th><td>{{if .BankName}}{{.BankName}} {{end}}({{.BankID.ID}})</td></tr>
+<tr><th>Benutzerkennung</th><td>{{.UserID}}</td></tr>
+<tr><th>Datum</th><td>{{.Date.Format "02.01.2006 15:04"}}</td></tr>
+</table>
+{{range .Keys}}
+<h2>{{.KeyType}} (Nummer {{.KeyName.KeyNumber}}, Version {{.KeyName.KeyVersion}})</h2>
+<h3>Exponent</h3>
+<pre>{{.Exponent}}</pre>
+<h3>Modulus</h3>
+<pre>{{.Modulus}}</pre>
+<h3>Hashwert RIPEMD-160</h3>
+<pre>{{.RIPEMD160}}</pre>
+<h3>Hashwert SHA-256</h3>
+<pre>{{.SHA256}}</pre>
+{{end}}
+<p>Ich bestätige hiermit, dass die oben genannten Schlüssel meine öffentlichen Schlüssel sind.</p>
+<table style="width:100%;margin-top:4em">
+<tr><td style="border-top:1px solid">Ort, Datum</td><td style="width:2em"></td><td style="border-top:1px solid">Unterschrift</td></tr>
+</table>
+</body>
+</html>
+`
+
+var (
+	iniLetterTextTemplate = template.Must(template.New("ini").Parse(iniLetterText))
+	iniLetterHTMLTemplate = htmltemplate.Must(htmltemplate.New("ini").Parse(iniLetterHTML))
+)
+
+// Text renders the INI letter as plain text
+func (i *IniLetter) Text() (string, error) {
+	var buf bytes.Buffer
+	if err := iniLetterTextTemplate.Execute(&buf, i); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// HTML renders the INI letter as HTML page, ready to print
+func (i *IniLetter) HTML() (string, error) {
+	var buf bytes.Buffer
+	if err := iniLetterHTMLTemplate.Execute(&buf, i); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
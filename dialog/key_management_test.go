@@ -0,0 +1,307 @@
+package dialog
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// plainTestMessage returns an unencrypted response as sent within anonymous
+// dialogs
+func plainTestMessage(dialogID string, segments ...[]byte) []byte {
+	body := bytes.Join(segments, nil)
+	messageEnd := fmt.Sprintf("HNHBS:%d:1+1'", len(segments)+2)
+	messageHeader := fmt.Sprintf("HNHBK:1:3+%012d+300+%s+1+'", 31+len(dialogID)+len(body)+len(messageEnd), dialogID)
+	return bytes.Join([][]byte{[]byte(messageHeader), body, []byte(messageEnd)}, nil)
+}
+
+// signedTestSegments returns segments enclosed by a signature of the
+// institute, starting at position 2
+func signedTestSegments(t *testing.T, profile message.SecurityProfile, bankSigningKey *domain.RSAKey, segments ...[]byte) [][]byte {
+	signatureHeader := fmt.Sprintf(
+		"HNSHK:2:4+%s:%d+1+ctrlref+1+1+1::xyz+1+1:20150713:173634+1:6:1+6:10:19+280:10000000:10000000:S:1:1'",
+		profile.Method, profile.Version,
+	)
+	signedData := append([]byte(signatureHeader), bytes.Join(segments, nil)...)
+	signature, err := message.NewRDHSignatureProvider(profile, bankSigningKey, 0).Sign(signedData)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	signatureEnd := fmt.Sprintf("HNSHA:%d:2+ctrlref+@%d@%s'", len(segments)+3, len(signature), signature)
+	return append(append([][]byte{[]byte(signatureHeader)}, segments...), []byte(signatureEnd))
+}
+
+func bankKeyTestSegment(t *testing.T, position int, key *domain.RSAKey) []byte {
+	keyName, err := element.NewKeyName(key.KeyName()).MarshalHBCI()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	publicKey, err := element.NewPublicKey(public(key).PublicKey).MarshalHBCI()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	header := fmt.Sprintf("HIISA:%d:2:%d+1+abcde+1+224+", position, position)
+	return bytes.Join([][]byte{[]byte(header), keyName, []byte("+"), publicKey, []byte("'")}, nil)
+}
+
+func TestRDHDialogFetchBankKeys(t *testing.T) {
+	keys := generateRDHTestKeys(t)
+	hash := strings.ToUpper(hex.EncodeToString(keys.bankSigning.HashSHA256()))
+	ripemdHash := formatHex(keys.bankSigning.HashRIPEMD160())
+
+	tests := []struct {
+		description  string
+		expectedHash string
+		expectedErr  error
+	}{
+		{"SHA-256 hash", hash, nil},
+		{"RIPEMD-160 hash with separators", ripemdHash, nil},
+		{"hash of another key", hex.EncodeToString(keys.userSigning.HashSHA256()), ErrBankKeyHashMismatch},
+		{"empty hash", "", ErrBankKeyHashMismatch},
+	}
+
+	for _, test := range tests {
+		mock := &mockHTTPSTransport{}
+		d := newTestRDHDialog(t, message.RDH10, keys, mock, nil)
+		d.bankSigningKey, d.bankEncryptionKey = nil, nil
+		d.useKeys()
+		mock.SetResponseMessages([][]byte{
+			plainTestMessage("abcde", signedTestSegments(t, message.RDH10, keys.bankSigning,
+				[]byte("HIRMG:3:2+0010::Nachricht entgegengenommen'"),
+				bankKeyTestSegment(t, 4, keys.bankSigning),
+				bankKeyTestSegment(t, 5, keys.bankEncryption),
+			)...),
+			plainTestMessage("abcde", []byte("HIRMG:2:2+0100::Dialog beendet'")),
+		})
+
+		err := d.FetchBankKeys(context.Background(), test.expectedHash)
+
+		if !errors.Is(err, test.expectedErr) {
+			t.Logf("%s: Expected error %v, got %v\n", test.description, test.expectedErr, err)
+			t.Fail()
+		}
+		if mock.CallCount() != 2 {
+			t.Logf("%s: Expected 2 requests, got %d\n", test.description, mock.CallCount())
+			t.Fail()
+		}
+		body, _ := ioutil.ReadAll(mock.requests[0].Body)
+		for _, expected := range []string{"HKISA:4:2+2+124+280:10000000:12345:S:999:999'", "HKISA:5:2+2+124+280:10000000:12345:V:999:999'"} {
+			if !bytes.Contains(body, []byte(expected)) {
+				t.Logf("%s: Expected request to contain %q, got %q\n", test.description, expected, body)
+				t.Fail()
+			}
+		}
+		if bytes.Contains(body, []byte("HNSHK")) || bytes.Contains(body, []byte("HNVSK")) {
+			t.Logf("%s: Expected request to be neither signed nor encrypted, got %q\n", test.description, body)
+			t.Fail()
+		}
+
+		signingKey, encryptionKey := d.BankKeys()
+		if test.expectedErr != nil {
+			if signingKey != nil || encryptionKey != nil {
+				t.Logf("%s: Expected bank keys not to be used\n", test.description)
+				t.Fail()
+			}
+			continue
+		}
+		if signingKey == nil || signingKey.RSAPublicKey().N.Cmp(keys.bankSigning.RSAPublicKey().N) != 0 {
+			t.Logf("%s: Expected bank signing key to be set, got %v\n", test.description, signingKey)
+			t.Fail()
+		}
+		if encryptionKey == nil || encryptionKey.RSAPublicKey().E != keys.bankEncryption.RSAPublicKey().E ||
+			encryptionKey.RSAPublicKey().N.Cmp(keys.bankEncryption.RSAPublicKey().N) != 0 {
+			t.Logf("%s: Expected bank encryption key to be set, got %v\n", test.description, encryptionKey)
+			t.Fail()
+		}
+		if d.signatureVerifier == nil {
+			t.Logf("%s: Expected signatures of the institute to be verified\n", test.description)
+			t.Fail()
+		}
+	}
+}
+
+func TestRDHDialogFetchBankKeysSubstitutedEncryptionKey(t *testing.T) {
+	keys := generateRDHTestKeys(t)
+	attacker := generateRDHTestKeys(t)
+	hash := hex.EncodeToString(keys.bankSigning.HashSHA256())
+	bankKeys := func(encryptionKey *domain.RSAKey, start int) [][]byte {
+		return [][]byte{
+			[]byte(fmt.Sprintf("HIRMG:%d:2+0010::Nachricht entgegengenommen'", start)),
+			bankKeyTestSegment(t, start+1, keys.bankSigning),
+			bankKeyTestSegment(t, start+2, encryptionKey),
+		}
+	}
+
+	tests := map[string][]byte{
+		"unsigned response": plainTestMessage("abcde", bankKeys(attacker.bankEncryption, 2)...),
+		"signed by another key": plainTestMessage("abcde",
+			signedTestSegments(t, message.RDH10, attacker.bankSigning, bankKeys(attacker.bankEncryption, 3)...)...,
+		),
+	}
+
+	for description, response := range tests {
+		mock := &mockHTTPSTransport{}
+		d := newTestRDHDialog(t, message.RDH10, keys, mock, nil)
+		d.bankSigningKey, d.bankEncryptionKey = nil, nil
+		d.useKeys()
+		mock.SetResponseMessages([][]byte{
+			response,
+			plainTestMessage("abcde", []byte("HIRMG:2:2+0100::Dialog beendet'")),
+		})
+
+		err := d.FetchBankKeys(context.Background(), hash)
+
+		if !errors.Is(err, message.ErrInvalidSignature) {
+			t.Logf("%s: Expected ErrInvalidSignature, got %v\n", description, err)
+			t.Fail()
+		}
+		if signingKey, encryptionKey := d.BankKeys(); signingKey != nil || encryptionKey != nil {
+			t.Logf("%s: Expected bank keys not to be used\n", description)
+			t.Fail()
+		}
+	}
+}
+
+func TestRDHDialogKeyManagement(t *testing.T) {
+	keys := generateRDHTestKeys(t)
+	newKeys := generateRDHTestKeys(t)
+	newKeys.userSigning.SetKeyVersion(2)
+	newKeys.userEncryption.SetKeyVersion(2)
+	profile := message.RAH10
+
+	tests := []struct {
+		description string
+		run         func(*RDHDialog) error
+		expected    []string
+	}{
+		{
+			"submit user keys",
+			func(d *RDHDialog) error { return d.SubmitUserKeys(context.Background()) },
+			[]string{"HKSAK:5:2+2+112+280:10000000:12345:S:1:1+6:19:10:", "HKSAK:6:2+2+112+280:10000000:12345:V:1:1+5:18:10:"},
+		},
+		{
+			"change user keys",
+			func(d *RDHDialog) error {
+				return d.ChangeUserKeys(context.Background(), newKeys.userSigning, newKeys.userEncryption)
+			},
+			[]string{"HKSAK:5:2+2+112+280:10000000:12345:S:1:2+6:19:10:", "HKSAK:6:2+2+112+280:10000000:12345:V:1:2+5:18:10:"},
+		},
+		{
+			"revoke user keys",
+			func(d *RDHDialog) error { return d.RevokeUserKeys(context.Background(), segment.KeyCompromitted) },
+			[]string{"HKSSP:5:2+2+130+280:10000000:12345:S:1:1+1+", "HKSSP:6:2+2+130+280:10000000:12345:V:1:1+1+"},
+		},
+	}
+
+	for _, test := range tests {
+		mock := &mockHTTPSTransport{}
+		d := newTestRDHDialog(t, profile, keys, mock, nil)
+		mock.SetResponseMessages([][]byte{
+			rdhTestMessage(t, profile, keys, nil, "abcde", "HIRMG:3:2+0010::Nachricht entgegengenommen'"),
+			rdhTestMessage(t, profile, keys, nil, "abcde", "HIRMG:3:2+0100::Dialog beendet'"),
+		})
+
+		err := test.run(d)
+
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", test.description, err)
+		}
+		if mock.CallCount() != 2 {
+			t.Fatalf("%s: Expected 2 requests, got %d", test.description, mock.CallCount())
+		}
+		body, _ := ioutil.ReadAll(mock.requests[0].Body)
+		plaintext := decryptRDHRequest(t, profile, keys, body)
+		for _, expected := range test.expected {
+			if !bytes.Contains(plaintext, []byte(expected)) {
+				t.Logf("%s: Expected request to contain %q, got %q\n", test.description, expected, plaintext)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestRDHDialogChangeUserKeysUsesNewKeys(t *testing.T) {
+	keys := generateRDHTestKeys(t)
+	newKeys := generateRDHTestKeys(t)
+	newKeys.bankSigning, newKeys.bankEncryption = keys.bankSigning, keys.bankEncryption
+	profile := message.RDH10
+	mock := &mockHTTPSTransport{}
+	d := newTestRDHDialog(t, profile, keys, mock, nil)
+	mock.SetResponseMessages([][]byte{
+		rdhTestMessage(t, profile, keys, nil, "abcde", "HIRMG:3:2+0010::Nachricht entgegengenommen'"),
+		rdhTestMessage(t, profile, keys, nil, "abcde", "HIRMG:3:2+0100::Dialog beendet'"),
+	})
+
+	if err := d.ChangeUserKeys(context.Background(), newKeys.userSigning, newKeys.userEncryption); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	mock = &mockHTTPSTransport{}
+	d.transport = mock
+	mock.SetResponseMessages([][]byte{
+		rdhTestMessage(t, profile, newKeys, nil, "abcde", "HIRMG:3:2+0010::Nachricht entgegengenommen'"),
+		rdhTestMessage(t, profile, newKeys, nil, "abcde", "HIRMG:3:2+0100::Dialog beendet'"),
+	})
+	if err := d.RevokeUserKeys(context.Background(), segment.KeyRevocationMisc); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	body, _ := ioutil.ReadAll(mock.requests[0].Body)
+	decryptRDHRequest(t, profile, newKeys, body)
+	if d.SignatureID() != 4 {
+		t.Logf("Expected signature ID to continue with the new keys, got %d\n", d.SignatureID())
+		t.Fail()
+	}
+}
+
+func TestRDHDialogIniLetter(t *testing.T) {
+	keys := generateRDHTestKeys(t)
+	d := newTestRDHDialog(t, message.RDH10, keys, &mockHTTPSTransport{}, nil)
+	d.BankParameterData.BankName = "Bank <Test>"
+
+	letter := d.IniLetter()
+
+	if len(letter.Keys) != 2 {
+		t.Fatalf("Expected 2 keys, got %d", len(letter.Keys))
+	}
+	text, err := letter.Text()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	html, err := letter.HTML()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []string{
+		"RDH-10",
+		"12345",
+		formatHex(keys.userSigning.HashSHA256()),
+		formatHex(keys.userSigning.HashRIPEMD160()),
+		formatHex(keys.userEncryption.HashSHA256()),
+		formatHex(keys.userEncryption.HashRIPEMD160()),
+		"Unterschrift",
+	}
+	for _, e := range expected {
+		if !strings.Contains(text, e) {
+			t.Logf("Expected text to contain %q, got\n%s\n", e, text)
+			t.Fail()
+		}
+		if !strings.Contains(html, e) {
+			t.Logf("Expected HTML to contain %q, got\n%s\n", e, html)
+			t.Fail()
+		}
+	}
+	if !strings.Contains(text, "Bank <Test>") || !strings.Contains(html, "Bank &lt;Test&gt;") {
+		t.Logf("Expected bank name to be escaped within HTML only\n")
+		t.Fail()
+	}
+}
@@ -0,0 +1,66 @@
+package dialog
+
+import (
+	"reflect"
+
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+	"github.com/mitch000001/go-hbci/transport"
+)
+
+// readOnlyJobs contains the IDs of jobs which only fetch data from the
+// institute. Messages consisting only of these jobs can be sent twice without
+// harm.
+var readOnlyJobs = map[string]bool{
+	segment.IdentificationID:        true,
+	segment.ProcessingPreparationID: true,
+	"HKEND":                         true,
+	"HKSAL":                         true,
+	"HKKAZ":                         true,
+	"HKCAZ":                         true,
+	"HKKIF":                         true,
+	"HKSPA":                         true,
+	"HKPRO":                         true,
+	"HKTAB":                         true,
+	"HKKOM":                         true,
+	"HKISA":                         true,
+}
+
+// tanJobID identifies the TAN process segment. It is read only when sent along
+// with other read only jobs, but may submit a TAN if sent on its own.
+const tanJobID = "HKTAN"
+
+// jobMetadata returns the transport metadata describing the jobs within
+// hbciMessage. The message is marked as NonRetryable if it contains a job not
+// known to be read only.
+func jobMetadata(hbciMessage message.HBCIMessage) transport.RequestMetadata {
+	var metadata transport.RequestMetadata
+	for _, seg := range hbciMessage.HBCISegments() {
+		if seg == nil || reflect.ValueOf(seg).IsNil() {
+			continue
+		}
+		metadata.Jobs = append(metadata.Jobs, seg.Header().ID.Val())
+	}
+	otherJobs := 0
+	for _, id := range metadata.Jobs {
+		if id == tanJobID {
+			continue
+		}
+		otherJobs++
+		if !readOnlyJobs[id] {
+			metadata.NonRetryable = true
+		}
+	}
+	if otherJobs == 0 && len(metadata.Jobs) != 0 {
+		metadata.NonRetryable = true
+	}
+	return metadata
+}
+
+// dialogInitializationMetadata returns the transport metadata for a dialog
+// initialization message.
+func dialogInitializationMetadata(hbciMessage message.HBCIMessage) transport.RequestMetadata {
+	metadata := jobMetadata(hbciMessage)
+	metadata.DialogInitialization = true
+	return metadata
+}
@@ -0,0 +1,203 @@
+package dialog
+
+import (
+	"context"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// An Observer gets notified about the course of dialogs, e.g. to collect
+// metrics or traces. The methods are called synchronously and should return
+// quickly.
+type Observer interface {
+	// DialogOpened is called after a dialog initialization completed,
+	// successfully or not.
+	DialogOpened(context.Context, DialogOpenedEvent)
+	// MessageSent is called before a message is handed to the transport.
+	MessageSent(context.Context, MessageSentEvent)
+	// ResponseReceived is called after the transport returned, successfully
+	// or not.
+	ResponseReceived(context.Context, ResponseReceivedEvent)
+	// TANChallenged is called if the institute requests a TAN.
+	TANChallenged(context.Context, TANChallengedEvent)
+	// DialogClosed is called after the dialog end message got answered,
+	// successfully or not.
+	DialogClosed(context.Context, DialogClosedEvent)
+}
+
+// DialogInfo identifies the dialog an event belongs to.
+type DialogInfo struct {
+	BankID   domain.BankID
+	DialogID string
+	// Anonymous is true for dialogs without user identification.
+	Anonymous bool
+	// Synchronization is true for dialogs acquiring a client system ID.
+	Synchronization bool
+}
+
+// DialogOpenedEvent describes a dialog initialization.
+type DialogOpenedEvent struct {
+	DialogInfo
+	Start    time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// MessageSentEvent describes a message sent to the institute.
+type MessageSentEvent struct {
+	DialogInfo
+	MessageNumber int
+	Jobs          []string
+	Time          time.Time
+}
+
+// ResponseReceivedEvent describes the answer to a message. Duration is the
+// time between sending the message and receiving the response.
+type ResponseReceivedEvent struct {
+	DialogInfo
+	MessageNumber    int
+	Jobs             []string
+	Acknowledgements []domain.Acknowledgement
+	Start            time.Time
+	Duration         time.Duration
+	Err              error
+}
+
+// TANChallengedEvent describes a TAN request of the institute.
+type TANChallengedEvent struct {
+	DialogInfo
+	MessageNumber int
+	Jobs          []string
+	// JobReference and Challenge are taken from the TAN response segment if
+	// the institute sent one.
+	JobReference string
+	Challenge    string
+}
+
+// DialogClosedEvent describes the end of a dialog. Duration is the time
+// between the start of the initialization and the end of the dialog.
+type DialogClosedEvent struct {
+	DialogInfo
+	Start    time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// MultiObserver notifies all of its observers in order.
+type MultiObserver []Observer
+
+// DialogOpened implements Observer
+func (m MultiObserver) DialogOpened(ctx context.Context, event DialogOpenedEvent) {
+	for _, o := range m {
+		o.DialogOpened(ctx, event)
+	}
+}
+
+// MessageSent implements Observer
+func (m MultiObserver) MessageSent(ctx context.Context, event MessageSentEvent) {
+	for _, o := range m {
+		o.MessageSent(ctx, event)
+	}
+}
+
+// ResponseReceived implements Observer
+func (m MultiObserver) ResponseReceived(ctx context.Context, event ResponseReceivedEvent) {
+	for _, o := range m {
+		o.ResponseReceived(ctx, event)
+	}
+}
+
+// TANChallenged implements Observer
+func (m MultiObserver) TANChallenged(ctx context.Context, event TANChallengedEvent) {
+	for _, o := range m {
+		o.TANChallenged(ctx, event)
+	}
+}
+
+// DialogClosed implements Observer
+func (m MultiObserver) DialogClosed(ctx context.Context, event DialogClosedEvent) {
+	for _, o := range m {
+		o.DialogClosed(ctx, event)
+	}
+}
+
+type nopObserver struct{}
+
+func (nopObserver) DialogOpened(context.Context, DialogOpenedEvent)         {}
+func (nopObserver) MessageSent(context.Context, MessageSentEvent)           {}
+func (nopObserver) ResponseReceived(context.Context, ResponseReceivedEvent) {}
+func (nopObserver) TANChallenged(context.Context, TANChallengedEvent)       {}
+func (nopObserver) DialogClosed(context.Context, DialogClosedEvent)         {}
+
+// dialogKind marks the kind of the dialog currently running.
+type dialogKind int
+
+const (
+	personalDialog dialogKind = iota
+	anonymousDialog
+	synchronizationDialog
+)
+
+func (d *dialog) dialogInfo() DialogInfo {
+	return DialogInfo{
+		BankID:          d.BankID,
+		DialogID:        d.dialogID,
+		Anonymous:       d.kind == anonymousDialog,
+		Synchronization: d.kind == synchronizationDialog,
+	}
+}
+
+// startDialog marks the start of a dialog initialization. The returned
+// function reports the result to the observer.
+func (d *dialog) startDialog(ctx context.Context, kind dialogKind) func(error) {
+	d.kind = kind
+	d.openedAt = time.Now()
+	return func(err error) {
+		d.observer.DialogOpened(ctx, DialogOpenedEvent{
+			DialogInfo: d.dialogInfo(),
+			Start:      d.openedAt,
+			Duration:   time.Since(d.openedAt),
+			Err:        err,
+		})
+	}
+}
+
+func (d *dialog) observeDialogClosed(ctx context.Context, err error) {
+	d.observer.DialogClosed(ctx, DialogClosedEvent{
+		DialogInfo: d.dialogInfo(),
+		Start:      d.openedAt,
+		Duration:   time.Since(d.openedAt),
+		Err:        err,
+	})
+}
+
+// observeTANChallenge notifies the observer if bankMessage requests a TAN.
+func (d *dialog) observeTANChallenge(ctx context.Context, messageNumber int, jobs []string, bankMessage message.BankMessage) {
+	challenged := false
+	for _, ack := range bankMessage.Acknowledgements() {
+		if ack.Code == element.AcknowledgementTanRequired {
+			challenged = true
+		}
+	}
+	if !challenged {
+		return
+	}
+	event := TANChallengedEvent{
+		DialogInfo:    d.dialogInfo(),
+		MessageNumber: messageNumber,
+		Jobs:          jobs,
+	}
+	if tanResponse, ok := bankMessage.FindSegment("HITAN").(*segment.TanResponseSegmentV6); ok {
+		if tanResponse.JobReference != nil {
+			event.JobReference = tanResponse.JobReference.Val()
+		}
+		if tanResponse.Challenge != nil {
+			event.Challenge = tanResponse.Challenge.Val()
+		}
+	}
+	d.observer.TANChallenged(ctx, event)
+}
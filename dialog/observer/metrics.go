@@ -0,0 +1,124 @@
+// Package observer provides implementations of dialog.Observer reporting to
+// metrics and tracing systems.
+//
+// The adapters do not depend on a specific library and hold no global state.
+// They report to the Registry or Tracer they are created with, which are
+// small interfaces easily implemented on top of Prometheus or OpenTelemetry.
+package observer
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/mitch000001/go-hbci/dialog"
+	"github.com/mitch000001/go-hbci/returncode"
+)
+
+// CounterVec is a counter partitioned by labels, like a prometheus.CounterVec.
+type CounterVec interface {
+	Inc(labelValues ...string)
+}
+
+// HistogramVec is a histogram partitioned by labels, like a
+// prometheus.HistogramVec.
+type HistogramVec interface {
+	Observe(value float64, labelValues ...string)
+}
+
+// Registry creates and registers metrics, like a prometheus.Registerer.
+type Registry interface {
+	CounterVec(name, help string, labelNames ...string) CounterVec
+	HistogramVec(name, help string, labelNames ...string) HistogramVec
+}
+
+// The names of the metrics registered by NewMetrics.
+const (
+	DialogInitDurationMetric = "hbci_dialog_init_duration_seconds"
+	DialogDurationMetric     = "hbci_dialog_duration_seconds"
+	MessagesSentMetric       = "hbci_messages_sent_total"
+	ResponseDurationMetric   = "hbci_response_duration_seconds"
+	ReturnCodesMetric        = "hbci_return_codes_total"
+	TANChallengesMetric      = "hbci_tan_challenges_total"
+)
+
+// Metrics is a dialog.Observer recording metrics about dialogs, messages,
+// return codes and TAN challenges. All metrics are labeled with the bank ID.
+type Metrics struct {
+	dialogInitDuration HistogramVec
+	dialogDuration     HistogramVec
+	messagesSent       CounterVec
+	responseDuration   HistogramVec
+	returnCodes        CounterVec
+	tanChallenges      CounterVec
+}
+
+// NewMetrics registers the metrics within registry and returns an observer
+// recording them.
+func NewMetrics(registry Registry) *Metrics {
+	return &Metrics{
+		dialogInitDuration: registry.HistogramVec(
+			DialogInitDurationMetric, "Duration of dialog initializations.", "bank_id", "kind", "result",
+		),
+		dialogDuration: registry.HistogramVec(
+			DialogDurationMetric, "Duration of dialogs from initialization to end.", "bank_id", "kind", "result",
+		),
+		messagesSent: registry.CounterVec(
+			MessagesSentMetric, "Number of messages sent.", "bank_id",
+		),
+		responseDuration: registry.HistogramVec(
+			ResponseDurationMetric, "Duration between sending a message and receiving the response.", "bank_id", "result",
+		),
+		returnCodes: registry.CounterVec(
+			ReturnCodesMetric, "Number of return codes received.", "bank_id", "code", "severity",
+		),
+		tanChallenges: registry.CounterVec(
+			TANChallengesMetric, "Number of TAN challenges received.", "bank_id",
+		),
+	}
+}
+
+// DialogOpened implements dialog.Observer
+func (m *Metrics) DialogOpened(_ context.Context, event dialog.DialogOpenedEvent) {
+	m.dialogInitDuration.Observe(event.Duration.Seconds(), event.BankID.ID, kind(event.DialogInfo), result(event.Err))
+}
+
+// MessageSent implements dialog.Observer
+func (m *Metrics) MessageSent(_ context.Context, event dialog.MessageSentEvent) {
+	m.messagesSent.Inc(event.BankID.ID)
+}
+
+// ResponseReceived implements dialog.Observer
+func (m *Metrics) ResponseReceived(_ context.Context, event dialog.ResponseReceivedEvent) {
+	m.responseDuration.Observe(event.Duration.Seconds(), event.BankID.ID, result(event.Err))
+	for _, ack := range event.Acknowledgements {
+		m.returnCodes.Inc(event.BankID.ID, strconv.Itoa(ack.Code), returncode.SeverityOf(ack.Code).String())
+	}
+}
+
+// TANChallenged implements dialog.Observer
+func (m *Metrics) TANChallenged(_ context.Context, event dialog.TANChallengedEvent) {
+	m.tanChallenges.Inc(event.BankID.ID)
+}
+
+// DialogClosed implements dialog.Observer
+func (m *Metrics) DialogClosed(_ context.Context, event dialog.DialogClosedEvent) {
+	m.dialogDuration.Observe(event.Duration.Seconds(), event.BankID.ID, kind(event.DialogInfo), result(event.Err))
+}
+
+func kind(info dialog.DialogInfo) string {
+	switch {
+	case info.Anonymous:
+		return "anonymous"
+	case info.Synchronization:
+		return "synchronization"
+	default:
+		return "personal"
+	}
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
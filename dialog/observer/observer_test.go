@@ -0,0 +1,143 @@
+package observer
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/dialog"
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+type mockRegistry struct {
+	values map[string]float64
+}
+
+func (m *mockRegistry) CounterVec(name, help string, labelNames ...string) CounterVec {
+	return &mockMetric{name: name, registry: m}
+}
+
+func (m *mockRegistry) HistogramVec(name, help string, labelNames ...string) HistogramVec {
+	return &mockMetric{name: name, registry: m}
+}
+
+type mockMetric struct {
+	name     string
+	registry *mockRegistry
+}
+
+func (m *mockMetric) Inc(labelValues ...string) {
+	m.Observe(1, labelValues...)
+}
+
+func (m *mockMetric) Observe(value float64, labelValues ...string) {
+	m.registry.values[fmt.Sprintf("%s{%s}", m.name, strings.Join(labelValues, ","))] += value
+}
+
+func TestMetrics(t *testing.T) {
+	registry := &mockRegistry{values: make(map[string]float64)}
+	metrics := NewMetrics(registry)
+	info := dialog.DialogInfo{BankID: domain.BankID{CountryCode: 280, ID: "10000000"}, DialogID: "abcde"}
+	ctx := context.Background()
+
+	metrics.DialogOpened(ctx, dialog.DialogOpenedEvent{DialogInfo: info, Duration: 2 * time.Second})
+	metrics.MessageSent(ctx, dialog.MessageSentEvent{DialogInfo: info})
+	metrics.MessageSent(ctx, dialog.MessageSentEvent{DialogInfo: info})
+	metrics.ResponseReceived(ctx, dialog.ResponseReceivedEvent{
+		DialogInfo: info,
+		Duration:   time.Second,
+		Acknowledgements: []domain.Acknowledgement{
+			{Code: 30}, {Code: 3060}, {Code: 30},
+		},
+	})
+	metrics.TANChallenged(ctx, dialog.TANChallengedEvent{DialogInfo: info})
+	metrics.DialogClosed(ctx, dialog.DialogClosedEvent{DialogInfo: info, Duration: 3 * time.Second, Err: fmt.Errorf("error")})
+
+	expected := map[string]float64{
+		"hbci_dialog_init_duration_seconds{10000000,personal,success}": 2,
+		"hbci_messages_sent_total{10000000}":                           2,
+		"hbci_response_duration_seconds{10000000,success}":             1,
+		"hbci_return_codes_total{10000000,30,success}":                 2,
+		"hbci_return_codes_total{10000000,3060,warning}":               1,
+		"hbci_tan_challenges_total{10000000}":                          1,
+		"hbci_dialog_duration_seconds{10000000,personal,error}":        3,
+	}
+	if !reflect.DeepEqual(expected, registry.values) {
+		t.Logf("Expected metrics\n%v\n\tgot\n%v\n", expected, registry.values)
+		t.Fail()
+	}
+}
+
+type mockTracer struct {
+	spans []*mockSpan
+}
+
+func (m *mockTracer) Start(ctx context.Context, name string, start time.Time, attributes ...Attribute) Span {
+	span := &mockSpan{name: name, start: start, attributes: attributes}
+	m.spans = append(m.spans, span)
+	return span
+}
+
+type mockSpan struct {
+	name       string
+	start      time.Time
+	end        time.Time
+	attributes []Attribute
+	events     []string
+	err        error
+}
+
+func (m *mockSpan) AddEvent(name string, attributes ...Attribute) {
+	m.events = append(m.events, name)
+}
+
+func (m *mockSpan) RecordError(err error) {
+	m.err = err
+}
+
+func (m *mockSpan) End(end time.Time) {
+	m.end = end
+}
+
+func TestTracing(t *testing.T) {
+	tracer := &mockTracer{}
+	tracing := NewTracing(tracer)
+	info := dialog.DialogInfo{BankID: domain.BankID{CountryCode: 280, ID: "10000000"}, DialogID: "abcde", Anonymous: true}
+	start := time.Date(2021, 7, 7, 10, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	tracing.ResponseReceived(ctx, dialog.ResponseReceivedEvent{
+		DialogInfo:       info,
+		MessageNumber:    2,
+		Start:            start,
+		Duration:         time.Second,
+		Acknowledgements: []domain.Acknowledgement{{Code: 20}, {Code: 3060}},
+	})
+	tracing.DialogClosed(ctx, dialog.DialogClosedEvent{
+		DialogInfo: info,
+		Start:      start,
+		Duration:   time.Minute,
+		Err:        fmt.Errorf("error"),
+	})
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(tracer.spans))
+	}
+	message := tracer.spans[0]
+	if message.name != MessageSpan || !message.end.Equal(start.Add(time.Second)) || len(message.events) != 2 {
+		t.Logf("Expected message span with 2 acknowledgements, got %+v\n", message)
+		t.Fail()
+	}
+	if !reflect.DeepEqual(Attribute{"hbci.dialog_kind", "anonymous"}, message.attributes[2]) {
+		t.Logf("Expected dialog kind attribute, got %+v\n", message.attributes)
+		t.Fail()
+	}
+	closed := tracer.spans[1]
+	if closed.name != DialogSpan || !closed.end.Equal(start.Add(time.Minute)) || closed.err == nil {
+		t.Logf("Expected failed dialog span, got %+v\n", closed)
+		t.Fail()
+	}
+}
@@ -0,0 +1,108 @@
+package observer
+
+import (
+	"context"
+	"time"
+
+	"github.com/mitch000001/go-hbci/dialog"
+)
+
+// Attribute is a key value pair attached to spans and span events.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Span is a single operation within a trace, like an OpenTelemetry
+// trace.Span.
+type Span interface {
+	AddEvent(name string, attributes ...Attribute)
+	RecordError(err error)
+	End(end time.Time)
+}
+
+// Tracer starts spans, like an OpenTelemetry trace.Tracer. The span should be
+// a child of the span within ctx, if any.
+type Tracer interface {
+	Start(ctx context.Context, name string, start time.Time, attributes ...Attribute) Span
+}
+
+// The names of the spans created by Tracing.
+const (
+	DialogSpan       = "hbci.dialog"
+	DialogInitSpan   = "hbci.dialog.init"
+	MessageSpan      = "hbci.message"
+	TANChallengeSpan = "hbci.tan_challenge"
+)
+
+// Tracing is a dialog.Observer creating spans for dialogs, their
+// initialization and every message. As the events carry their timing, the
+// spans are created once an operation is completed.
+type Tracing struct {
+	tracer Tracer
+}
+
+// NewTracing returns an observer creating spans with tracer.
+func NewTracing(tracer Tracer) *Tracing {
+	return &Tracing{tracer: tracer}
+}
+
+// DialogOpened implements dialog.Observer
+func (t *Tracing) DialogOpened(ctx context.Context, event dialog.DialogOpenedEvent) {
+	span := t.tracer.Start(ctx, DialogInitSpan, event.Start, dialogAttributes(event.DialogInfo)...)
+	t.end(span, event.Start.Add(event.Duration), event.Err)
+}
+
+// MessageSent implements dialog.Observer. The message is traced once the
+// response is received.
+func (t *Tracing) MessageSent(context.Context, dialog.MessageSentEvent) {}
+
+// ResponseReceived implements dialog.Observer
+func (t *Tracing) ResponseReceived(ctx context.Context, event dialog.ResponseReceivedEvent) {
+	attributes := append(
+		dialogAttributes(event.DialogInfo),
+		Attribute{"hbci.message_number", event.MessageNumber},
+		Attribute{"hbci.jobs", event.Jobs},
+	)
+	span := t.tracer.Start(ctx, MessageSpan, event.Start, attributes...)
+	for _, ack := range event.Acknowledgements {
+		span.AddEvent(
+			"acknowledgement",
+			Attribute{"hbci.return_code", ack.Code},
+			Attribute{"hbci.segment_number", ack.ReferencingSegmentNumber},
+		)
+	}
+	t.end(span, event.Start.Add(event.Duration), event.Err)
+}
+
+// TANChallenged implements dialog.Observer
+func (t *Tracing) TANChallenged(ctx context.Context, event dialog.TANChallengedEvent) {
+	now := time.Now()
+	attributes := append(
+		dialogAttributes(event.DialogInfo),
+		Attribute{"hbci.message_number", event.MessageNumber},
+		Attribute{"hbci.jobs", event.Jobs},
+	)
+	t.tracer.Start(ctx, TANChallengeSpan, now, attributes...).End(now)
+}
+
+// DialogClosed implements dialog.Observer
+func (t *Tracing) DialogClosed(ctx context.Context, event dialog.DialogClosedEvent) {
+	span := t.tracer.Start(ctx, DialogSpan, event.Start, dialogAttributes(event.DialogInfo)...)
+	t.end(span, event.Start.Add(event.Duration), event.Err)
+}
+
+func (t *Tracing) end(span Span, end time.Time, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End(end)
+}
+
+func dialogAttributes(info dialog.DialogInfo) []Attribute {
+	return []Attribute{
+		{"hbci.bank_id", info.BankID.ID},
+		{"hbci.dialog_id", info.DialogID},
+		{"hbci.dialog_kind", kind(info)},
+	}
+}
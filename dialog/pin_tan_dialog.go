@@ -2,14 +2,27 @@ package dialog
 
 import (
 	"encoding/base64"
+	"log/slog"
 
 	"github.com/mitch000001/go-hbci/domain"
-	"github.com/mitch000001/go-hbci/internal"
 	"github.com/mitch000001/go-hbci/message"
 	"github.com/mitch000001/go-hbci/segment"
 	"github.com/mitch000001/go-hbci/transport"
 	https "github.com/mitch000001/go-hbci/transport/https"
 	middleware "github.com/mitch000001/go-hbci/transport/middleware"
+	tcp "github.com/mitch000001/go-hbci/transport/tcp"
+)
+
+// Channel defines the communication channel used to reach the institute
+type Channel int
+
+const (
+	// ChannelHTTPS sends Base64 encoded messages over HTTPS, as used by
+	// pin/tan servers. It is the default.
+	ChannelHTTPS Channel = iota
+	// ChannelTCP sends plain messages over the classic HBCI TCP channel.
+	// The HBCIURL is expected to be in the form host:port.
+	ChannelTCP
 )
 
 // Config contains the configuration of a PinTanDialog
@@ -19,6 +32,25 @@ type Config struct {
 	UserID      string
 	HBCIVersion segment.HBCIVersion
 	Transport   transport.Transport
+	// Channel selects the default Transport and the encoding of messages.
+	// It is also respected when a Transport is given.
+	Channel Channel
+	// StateStore is used to persist the client system ID, BPD and UPD
+	// between dialogs. If nil, the state is kept in memory only.
+	StateStore StateStore
+	// Logger receives the log records of the dialog and the messages sent
+	// and received. If nil, the package wide loggers are used.
+	Logger *slog.Logger
+	// LogRedaction configures which data are masked within logged messages.
+	// PINs and TANs are always masked.
+	LogRedaction middleware.Redaction
+	// Observer gets notified about the course of the dialogs. Use a
+	// MultiObserver to notify several observers.
+	Observer Observer
+	// EnableCompression compresses the messages following the dialog
+	// initialization, if the institute supports deflate. Compression is
+	// disabled by default.
+	EnableCompression bool
 }
 
 // NewPinTanDialog creates a new dialog to use for pin/tan transport
@@ -37,17 +69,34 @@ func NewPinTanDialog(config Config) *PinTanDialog {
 			cryptoProvider,
 		),
 	}
+	d.configure(config)
+	return d
+}
 
-	var dialogTransport transport.Transport
-	if config.Transport == nil {
-		dialogTransport = https.New()
-	} else {
-		dialogTransport = config.Transport
+// configure sets up the transport and the options of config. Logged messages
+// are decrypted with the current crypto provider of the dialog.
+func (d *dialog) configure(config Config) {
+	dialogTransport := config.Transport
+	if dialogTransport == nil {
+		if config.Channel == ChannelTCP {
+			dialogTransport = tcp.New()
+		} else {
+			dialogTransport = https.New()
+		}
 	}
-	dialogTransport = middleware.Base64Encoding(base64.StdEncoding)(dialogTransport)
-	dialogTransport = middleware.Logging(internal.Debug, cryptoProvider)(dialogTransport)
+	if config.Channel != ChannelTCP {
+		dialogTransport = middleware.Base64Encoding(base64.StdEncoding)(dialogTransport)
+	}
+	if config.Logger != nil {
+		d.logger = config.Logger
+	}
+	if config.Observer != nil {
+		d.observer = config.Observer
+	}
+	dialogTransport = middleware.StructuredLoggingFunc(d.logger, func() message.CryptoProvider { return d.cryptoProvider }, config.LogRedaction)(dialogTransport)
+	d.enableCompression = config.EnableCompression
 	d.transport = dialogTransport
-	return d
+	d.stateStore = config.StateStore
 }
 
 // PinTanDialog represents a dialog to use in pin/tan flow with HTTPS transport
@@ -61,4 +110,7 @@ func (d *PinTanDialog) SetPin(pin string) {
 	d.signatureProvider = message.NewPinTanSignatureProvider(pinKey, d.ClientSystemID)
 	pinKey = domain.NewPinKey(pin, domain.NewPinTanKeyName(d.BankID, d.UserID, domain.KeyTypeEncryption))
 	d.cryptoProvider = message.NewPinTanCryptoProvider(pinKey, d.ClientSystemID)
+	if d.securityFn != "" {
+		d.SetSecurityFunction(d.securityFn)
+	}
 }
@@ -1,25 +1,123 @@
 package dialog
 
 import (
+	"fmt"
+
 	"github.com/mitch000001/go-hbci/domain"
 	"github.com/mitch000001/go-hbci/message"
-	"github.com/mitch000001/go-hbci/segment"
 )
 
-// NewRDHDialog creates a dialog to use with cardreader flow
-func NewRDHDialog(bankID domain.BankID, hbciURL string, clientID string, hbciVersion segment.HBCIVersion) Dialog {
-	key, err := domain.GenerateSigningKey()
-	if err != nil {
-		panic(err)
+// RDHConfig contains the configuration of a RDHDialog
+type RDHConfig struct {
+	Config
+	// Profile is the security profile to use, message.RDH10 or
+	// message.RAH10
+	Profile message.SecurityProfile
+	// SigningKey and EncryptionKey are the keys of the user, including
+	// their private keys
+	SigningKey    *domain.RSAKey
+	EncryptionKey *domain.RSAKey
+	// BankSigningKey and BankEncryptionKey are the public keys of the
+	// institute. Without BankSigningKey, responses are not verified.
+	BankSigningKey    *domain.RSAKey
+	BankEncryptionKey *domain.RSAKey
+	// RequireBankSignature rejects responses not signed by the institute.
+	// Otherwise only present signatures are verified, as institutes do not
+	// sign every message. Personal dialogs reject unencrypted responses
+	// regardless, anonymous dialogs are not signed by the institute.
+	RequireBankSignature bool
+	// KeyFile provides the keys, profile, bank ID and user ID not set
+	// explicitly. Unless another StateStore is given, it stores the client
+	// system ID and signature ID as well. Keys fetched or changed by the
+	// dialog are written back into it.
+	KeyFile *KeyFile
+}
+
+// NewRDHDialog creates a dialog secured by the RSA keys of the user and the
+// institute. The signature ID is persisted within the StateStore, if any.
+func NewRDHDialog(config RDHConfig) (*RDHDialog, error) {
+	if config.KeyFile != nil {
+		config = config.KeyFile.apply(config)
+	}
+	if err := config.Profile.Validate(); err != nil {
+		return nil, err
 	}
-	signingKey := domain.NewRSAKey(key, domain.NewInitialKeyName(bankID.CountryCode, bankID.ID, clientID, "S"))
-	provider := message.NewRDHSignatureProvider(signingKey, 12345)
-	d := &rdhDialog{
-		dialog: newDialog(bankID, hbciURL, clientID, hbciVersion, provider, nil),
+	if config.SigningKey == nil || config.SigningKey.SigningKey() == nil {
+		return nil, fmt.Errorf("missing private signing key of the user")
 	}
-	return d
+	if config.EncryptionKey == nil || config.EncryptionKey.SigningKey() == nil {
+		return nil, fmt.Errorf("missing private encryption key of the user")
+	}
+	d := &RDHDialog{
+		profile:              config.Profile,
+		signingKey:           config.SigningKey,
+		encryptionKey:        config.EncryptionKey,
+		bankSigningKey:       config.BankSigningKey,
+		bankEncryptionKey:    config.BankEncryptionKey,
+		requireBankSignature: config.RequireBankSignature,
+		keyFile:              config.KeyFile,
+	}
+	d.dialog = newDialog(
+		config.BankID,
+		config.HBCIURL,
+		config.UserID,
+		config.HBCIVersion,
+		message.NewRDHSignatureProvider(config.Profile, config.SigningKey, 0),
+		nil,
+	)
+	d.keyBased = true
+	d.useKeys()
+	d.configure(config.Config)
+	return d, nil
 }
 
-type rdhDialog struct {
+// RDHDialog represents a dialog secured by RSA keys, i.e. RDH or RAH
+type RDHDialog struct {
 	*dialog
+	profile              message.SecurityProfile
+	signingKey           *domain.RSAKey
+	encryptionKey        *domain.RSAKey
+	bankSigningKey       *domain.RSAKey
+	bankEncryptionKey    *domain.RSAKey
+	requireBankSignature bool
+	keyFile              *KeyFile
+}
+
+// SignatureID returns the last signature ID used.
+func (d *RDHDialog) SignatureID() int {
+	return d.signatureID()
+}
+
+// useKeys sets up the providers for the current keys, keeping the
+// signature ID.
+func (d *RDHDialog) useKeys() {
+	d.signatureProvider = message.NewRDHSignatureProvider(d.profile, d.signingKey, d.signatureID())
+	d.signatureProvider.SetClientSystemID(d.ClientSystemID)
+	d.cryptoProvider = message.NewRDHCryptoProvider(
+		d.profile, d.bankEncryptionKey, d.encryptionKey, d.ClientSystemID,
+	)
+	d.signatureVerifier = nil
+	if d.bankSigningKey != nil {
+		d.signatureVerifier = message.NewRDHSignatureVerifier(
+			d.profile, d.bankSigningKey, d.requireBankSignature,
+		)
+	}
+}
+
+// saveKeys writes the keys in use into the KeyFile, if any.
+func (d *RDHDialog) saveKeys() error {
+	if d.keyFile == nil {
+		return nil
+	}
+	d.keyFile.mu.Lock()
+	defer d.keyFile.mu.Unlock()
+	d.keyFile.Profile = d.profile
+	d.keyFile.SigningKey = d.signingKey
+	d.keyFile.EncryptionKey = d.encryptionKey
+	d.keyFile.BankSigningKey = d.bankSigningKey
+	d.keyFile.BankEncryptionKey = d.bankEncryptionKey
+	if err := d.keyFile.write(); err != nil {
+		return fmt.Errorf("error saving keys: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,279 @@
+package dialog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// rdhTestKeySize keeps the key generation fast, real keys have 2048 bits
+const rdhTestKeySize = 1024
+
+type rdhTestKeys struct {
+	userSigning    *domain.RSAKey
+	userEncryption *domain.RSAKey
+	bankSigning    *domain.RSAKey
+	bankEncryption *domain.RSAKey
+}
+
+func generateRDHTestKeys(t *testing.T) rdhTestKeys {
+	generate := func(userID string, keyType domain.KeyType) *domain.RSAKey {
+		key, err := domain.GenerateKey(keyType, rdhTestKeySize)
+		if err != nil {
+			t.Fatalf("Expected no error generating key, got %v", err)
+		}
+		return domain.NewRSAKey(key, &domain.KeyName{
+			BankID:     domain.BankID{CountryCode: 280, ID: "10000000"},
+			UserID:     userID,
+			KeyType:    keyType,
+			KeyNumber:  1,
+			KeyVersion: 1,
+		})
+	}
+	return rdhTestKeys{
+		userSigning:    generate("12345", domain.KeyTypeSigning),
+		userEncryption: generate("12345", domain.KeyTypeEncryption),
+		bankSigning:    generate("10000000", domain.KeyTypeSigning),
+		bankEncryption: generate("10000000", domain.KeyTypeEncryption),
+	}
+}
+
+// public returns a copy of key without its private key
+func public(key *domain.RSAKey) *domain.RSAKey {
+	keyName := key.KeyName()
+	return domain.NewRSAKey(domain.NewPublicKey(keyName.KeyType, key.RSAPublicKey()), &keyName)
+}
+
+func newTestRDHDialog(t *testing.T, profile message.SecurityProfile, keys rdhTestKeys, transport *mockHTTPSTransport, store StateStore) *RDHDialog {
+	d, err := NewRDHDialog(RDHConfig{
+		Config: Config{
+			BankID:      domain.BankID{CountryCode: 280, ID: "10000000"},
+			HBCIURL:     "http://localhost",
+			UserID:      "12345",
+			HBCIVersion: segment.FINTS300,
+			StateStore:  store,
+		},
+		Profile:              profile,
+		SigningKey:           keys.userSigning,
+		EncryptionKey:        keys.userEncryption,
+		BankSigningKey:       public(keys.bankSigning),
+		BankEncryptionKey:    public(keys.bankEncryption),
+		RequireBankSignature: true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	d.SetClientSystemID("xyz")
+	d.transport = transport
+	return d
+}
+
+// rdhTestMessage returns a response signed with the signing key of the
+// institute and encrypted for the user. tamper modifies the message after
+// signing.
+func rdhTestMessage(t *testing.T, profile message.SecurityProfile, keys rdhTestKeys, tamper func([]byte) []byte, dialogID string, segments ...string) []byte {
+	signer := message.NewRDHSignatureProvider(profile, keys.bankSigning, 0)
+	signatureHeader := fmt.Sprintf(
+		"HNSHK:2:4+%s:%d+1+ctrlref+1+1+1::xyz+1+1:20150713:173634+1:6:1+6:10:19+280:10000000:10000000:S:1:1'",
+		profile.Method, profile.Version,
+	)
+	signedData := signatureHeader + strings.Join(segments, "")
+	signature, err := signer.Sign([]byte(signedData))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	plaintext := []byte(signedData + fmt.Sprintf("HNSHA:%d:2+ctrlref+@%d@%s'", len(segments)+3, len(signature), signature))
+	if tamper != nil {
+		plaintext = tamper(plaintext)
+	}
+
+	crypto := message.NewRDHCryptoProvider(profile, public(keys.userEncryption), nil, "xyz")
+	encrypted, err := crypto.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	header := segment.FINTS300.PinTanEncryptionHeader("xyz", domain.KeyName{})
+	crypto.WriteEncryptionHeader(header)
+	header.SetPosition(func() int { return 998 })
+	encryptionHeader, err := header.MarshalHBCI()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	encryptedData := append([]byte(fmt.Sprintf("HNVSD:999:1+@%d@", len(encrypted))), encrypted...)
+	encryptedData = append(encryptedData, '\'')
+	messageEnd := "HNHBS:4:1+1'"
+	messageHeader := fmt.Sprintf(
+		"HNHBK:1:3+%012d+300+%s+1+'",
+		31+len(dialogID)+len(encryptionHeader)+len(encryptedData)+len(messageEnd), dialogID,
+	)
+	return bytes.Join([][]byte{[]byte(messageHeader), encryptionHeader, encryptedData, []byte(messageEnd)}, nil)
+}
+
+// decryptRDHRequest decrypts a request as the institute would do and
+// verifies the signature of the user.
+func decryptRDHRequest(t *testing.T, profile message.SecurityProfile, keys rdhTestKeys, request []byte) []byte {
+	extractor := message.NewSegmentExtractor(request)
+	if _, err := extractor.Extract(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	messageKey, err := message.ExtractMessageKey(extractor.FindSegment("HNVSK"))
+	if err != nil || messageKey == nil {
+		t.Fatalf("Expected request to contain a message key, got %q (%v)", messageKey, err)
+	}
+	encryptedData := &segment.EncryptedDataSegment{}
+	if err := encryptedData.UnmarshalHBCI(extractor.FindSegment("HNVSD")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	crypto := message.NewRDHCryptoProvider(profile, nil, keys.bankEncryption, "")
+	plaintext, err := crypto.(message.MessageKeyDecrypter).DecryptWithMessageKey(messageKey, encryptedData.Data.Val())
+	if err != nil {
+		t.Fatalf("Expected no error decrypting request, got %v", err)
+	}
+	requestMessage, err := message.NewDecryptedMessage(segment.NewMessageHeaderSegment(1, 300, "0", 1), nil, plaintext)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	verifier := message.NewRDHSignatureVerifier(profile, public(keys.userSigning), true)
+	if err := verifier.Verify(requestMessage); err != nil {
+		t.Logf("Expected signature of the user to verify, got %v\n", err)
+		t.Fail()
+	}
+	return plaintext
+}
+
+func TestRDHDialogSendMessage(t *testing.T) {
+	keys := generateRDHTestKeys(t)
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+
+	for _, profile := range []message.SecurityProfile{message.RDH10, message.RAH10} {
+		mock := &mockHTTPSTransport{}
+		store := NewMemoryStateStore()
+		d := newTestRDHDialog(t, profile, keys, mock, store)
+		mock.SetResponseMessages([][]byte{
+			rdhTestMessage(t, profile, keys, nil, "abcde", "HIRMG:3:2+0010::Nachricht entgegengenommen'"),
+			rdhTestMessage(t, profile, keys, nil, "abcde", "HIRMG:3:2+0010::Nachricht entgegengenommen'"),
+			rdhTestMessage(t, profile, keys, nil, "abcde", "HIRMG:3:2+0100::Dialog beendet'"),
+		})
+
+		_, err := d.SendMessage(message.NewHBCIMessage(d.hbciVersion, segment.NewAccountBalanceRequestV5(account, false)))
+
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", profile, err)
+		}
+		if mock.CallCount() != 3 {
+			t.Fatalf("%s: Expected 3 requests, got %d", profile, mock.CallCount())
+		}
+		for i, request := range mock.requests {
+			body, _ := ioutil.ReadAll(request.Body)
+			plaintext := decryptRDHRequest(t, profile, keys, body)
+			expectedProfile := fmt.Sprintf("+%s:%d+", profile.Method, profile.Version)
+			if !bytes.Contains(plaintext, []byte(expectedProfile)) {
+				t.Logf("%s: Expected request %d to name the security profile, got %q\n", profile, i, plaintext)
+				t.Fail()
+			}
+			if bytes.Contains(plaintext, []byte("HKTAN")) {
+				t.Logf("%s: Expected request %d to contain no TAN segment, got %q\n", profile, i, plaintext)
+				t.Fail()
+			}
+			expectedSignatureID := fmt.Sprintf("+%d+1:", i+1)
+			if !bytes.Contains(plaintext, []byte(expectedSignatureID)) {
+				t.Logf("%s: Expected request %d to use signature ID %d, got %q\n", profile, i, i+1, plaintext)
+				t.Fail()
+			}
+		}
+
+		if d.SignatureID() != 3 {
+			t.Logf("%s: Expected signature ID 3, got %d\n", profile, d.SignatureID())
+			t.Fail()
+		}
+		state, err := store.Load(d.stateKey())
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", profile, err)
+		}
+		if state.SignatureID != 3 {
+			t.Logf("%s: Expected persisted signature ID 3, got %d\n", profile, state.SignatureID)
+			t.Fail()
+		}
+
+		restored := newTestRDHDialog(t, profile, keys, &mockHTTPSTransport{}, store)
+		if err := restored.LoadState(); err != nil {
+			t.Fatalf("%s: Expected no error, got %v", profile, err)
+		}
+		if restored.SignatureID() != 3 {
+			t.Logf("%s: Expected restored signature ID 3, got %d\n", profile, restored.SignatureID())
+			t.Fail()
+		}
+	}
+}
+
+func TestRDHDialogInvalidBankSignature(t *testing.T) {
+	keys := generateRDHTestKeys(t)
+	profile := message.RAH10
+	mock := &mockHTTPSTransport{}
+	d := newTestRDHDialog(t, profile, keys, mock, nil)
+	tamper := func(plaintext []byte) []byte {
+		return bytes.Replace(plaintext, []byte("0010"), []byte("0020"), 1)
+	}
+	mock.SetResponseMessages([][]byte{
+		rdhTestMessage(t, profile, keys, tamper, "abcde", "HIRMG:3:2+0010::Nachricht entgegengenommen'"),
+		rdhTestMessage(t, profile, keys, nil, "abcde", "HIRMG:3:2+0100::Dialog beendet'"),
+		rdhTestMessage(t, profile, keys, nil, "abcde", "HIRMG:3:2+0100::Dialog beendet'"),
+	})
+
+	_, err := d.Open()
+
+	var protocolErr *ProtocolError
+	if !errors.As(err, &protocolErr) || !errors.Is(err, message.ErrInvalidSignature) {
+		t.Logf("Expected a ProtocolError caused by an invalid signature, got %T:%v\n", err, err)
+		t.Fail()
+	}
+}
+
+func TestRDHDialogUnencryptedResponse(t *testing.T) {
+	keys := generateRDHTestKeys(t)
+	profile := message.RDH10
+	mock := &mockHTTPSTransport{}
+	d := newTestRDHDialog(t, profile, keys, mock, nil)
+	mock.SetResponseMessages([][]byte{
+		[]byte("HNHBK:1:3+000000000061+300+abcde+1+'HIRMG:2:2+0010::Nachricht entgegengenommen'HNHBS:3:1+1'"),
+		rdhTestMessage(t, profile, keys, nil, "abcde", "HIRMG:3:2+0100::Dialog beendet'"),
+	})
+
+	_, err := d.Open()
+
+	var protocolErr *ProtocolError
+	if !errors.As(err, &protocolErr) || !errors.Is(err, message.ErrInvalidSignature) {
+		t.Logf("Expected a ProtocolError caused by an invalid signature, got %T:%v\n", err, err)
+		t.Fail()
+	}
+}
+
+func TestNewRDHDialogMissingKeys(t *testing.T) {
+	keys := generateRDHTestKeys(t)
+	config := RDHConfig{
+		Profile:       message.RDH10,
+		SigningKey:    public(keys.userSigning),
+		EncryptionKey: keys.userEncryption,
+	}
+
+	_, err := NewRDHDialog(config)
+	if err == nil {
+		t.Logf("Expected an error for a signing key without private key\n")
+		t.Fail()
+	}
+
+	config.SigningKey = keys.userSigning
+	config.Profile = message.SecurityProfile{Method: "RDH", Version: 2}
+	_, err = NewRDHDialog(config)
+	if err == nil {
+		t.Logf("Expected an error for an unsupported profile\n")
+		t.Fail()
+	}
+}
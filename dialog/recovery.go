@@ -0,0 +1,168 @@
+package dialog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// ProtocolError is returned when the response of the institute could not be
+// read, decrypted or parsed. The dialog is cancelled in that case, as its
+// state can no longer be relied upon.
+type ProtocolError struct {
+	Err error
+}
+
+func (p *ProtocolError) Error() string {
+	return fmt.Sprintf("protocol error: %v", p.Err)
+}
+
+// Unwrap returns the underlying error
+func (p *ProtocolError) Unwrap() error {
+	return p.Err
+}
+
+// DialogEndError is returned when a job got executed, but the dialog could
+// not be ended properly afterwards. The response of the job is returned along
+// with it.
+type DialogEndError struct {
+	Err error
+}
+
+func (d *DialogEndError) Error() string {
+	return fmt.Sprintf("error ending dialog: %v", d.Err)
+}
+
+// Unwrap returns the underlying error
+func (d *DialogEndError) Unwrap() error {
+	return d.Err
+}
+
+// dialogCancelledCode is the return code sent within a dialog cancellation
+const dialogCancelledCode = 9800
+
+// The return codes reporting that only the BPD or only the UPD changed
+const (
+	bankParameterDataChangedCode = 1040
+	userParameterDataChangedCode = 1050
+)
+
+// withDialogEnd joins err with the error returned by end, if any.
+func withDialogEnd(err error, end func() error) error {
+	if endErr := end(); endErr != nil {
+		return errors.Join(err, &DialogEndError{Err: endErr})
+	}
+	return err
+}
+
+// recoverFrom handles the error returned while sending a message within an
+// initialized dialog. Protocol errors cancel the dialog.
+func (d *dialog) recoverFrom(ctx context.Context, err error) {
+	var protocolErr *ProtocolError
+	if !errors.As(err, &protocolErr) || !d.active {
+		return
+	}
+	endCtx, cancel := dialogEndContext(ctx)
+	defer cancel()
+	d.logErr(d.cancel(endCtx))
+}
+
+// cancel sends a dialog cancellation to the institute and resets the dialog.
+// The response is not evaluated, as the dialog is finished anyway.
+func (d *dialog) cancel(ctx context.Context) (err error) {
+	defer d.resetDialog()
+	defer func() { d.observeDialogClosed(ctx, err) }()
+	d.log().Info("Cancelling dialog")
+	acknowledgement := segment.NewMessageAcknowledgement(
+		domain.NewMessageAcknowledgement(dialogCancelledCode, "", "Dialog abgebrochen", nil),
+	)
+	cancellation := message.NewDialogCancellationMessage(d.hbciVersion, acknowledgement)
+	cancellation.BasicMessage = d.newBasicMessage(cancellation)
+	var clientMessage message.ClientMessage = cancellation
+	if d.kind == anonymousDialog {
+		cancellation.SetSegmentPositions()
+	} else {
+		signedCancellation, err := cancellation.Sign(d.signatureProvider)
+		if err != nil {
+			return err
+		}
+		clientMessage, err = signedCancellation.Encrypt(d.cryptoProvider)
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := d.request(ctx, clientMessage, jobMetadata(cancellation)); err != nil {
+		return fmt.Errorf("error while cancelling dialog: %w", err)
+	}
+	return nil
+}
+
+// resetDialog drops the dialog ID and message number, so that the next
+// message has to initialize a new dialog.
+func (d *dialog) resetDialog() {
+	d.dialogID = initialDialogID
+	d.messageCount = 0
+	d.active = false
+}
+
+// resetIfTerminated resets the dialog if the institute rejected the whole
+// message, as it terminates the dialog with 9xxx message errors.
+func (d *dialog) resetIfTerminated(acknowledgements []domain.Acknowledgement) {
+	for _, ack := range acknowledgements {
+		if ack.IsMessageAcknowledgement() && ack.Code >= 9000 {
+			d.log().Info("Dialog terminated by institute", slog.Int("code", ack.Code))
+			d.resetDialog()
+			return
+		}
+	}
+}
+
+// reinit ends the dialog and initializes a new one with fresh parameter data.
+func (d *dialog) reinit(ctx context.Context) error {
+	d.log().Info("Parameter data changed, reinitializing dialog")
+	d.logErr(d.endContext(ctx))
+	d.BankParameterData.Version = initialBankParameterDataVersion
+	d.UserParameterData.Version = initialUserParameterDataVersion
+	opened := d.startDialog(ctx, personalDialog)
+	err := d.sendInit(ctx)
+	opened(err)
+	return err
+}
+
+// refreshParameterData processes the BPD and UPD enclosed in bankMessage, if
+// the institute reports them as changed. Parameter data not enclosed are
+// marked as outdated, so that the next dialog initialization fetches them.
+func (d *dialog) refreshParameterData(bankMessage message.BankMessage) {
+	var bpdChanged, updChanged bool
+	for _, ack := range bankMessage.Acknowledgements() {
+		if !ack.InCategory(domain.ErrParameterDataChanged) {
+			continue
+		}
+		d.log().Info("Parameter data changed", slog.Int("code", ack.Code))
+		bpdChanged = bpdChanged || ack.Code != userParameterDataChangedCode
+		updChanged = updChanged || ack.Code != bankParameterDataChangedCode
+	}
+	if !bpdChanged && !updChanged {
+		return
+	}
+	if bpdChanged {
+		if bankMessage.FindSegment(segment.CommonBankParameterID) != nil {
+			d.logErr(d.parseBankParameterData(bankMessage))
+		} else {
+			d.BankParameterData.Version = initialBankParameterDataVersion
+		}
+	}
+	if updChanged {
+		if bankMessage.FindSegment(segment.CommonUserParameterDataID) != nil {
+			d.logErr(d.parseUserParameterData(bankMessage))
+		} else {
+			d.UserParameterData.Version = initialUserParameterDataVersion
+		}
+	}
+	d.logErr(d.saveState())
+}
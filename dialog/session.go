@@ -0,0 +1,156 @@
+package dialog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// A Session represents an initialized dialog. All jobs sent through a Session
+// share the dialog ID and the message counter, so that several jobs only cost
+// one dialog initialization and one dialog end.
+//
+// A dialog can only hold one open Session at a time. A Session is not safe for
+// concurrent use.
+type Session struct {
+	dialog *dialog
+	closed bool
+}
+
+// Open initializes a new dialog and returns it as Session. It is a shorthand
+// for OpenContext with a background context.
+func (d *dialog) Open() (*Session, error) {
+	return d.OpenContext(context.Background())
+}
+
+// OpenContext initializes a new dialog and returns it as Session. The caller
+// is responsible to call Close on the Session when done. It returns an error
+// if the dialog still holds an open Session.
+func (d *dialog) OpenContext(ctx context.Context) (*Session, error) {
+	if d.session != nil {
+		return nil, fmt.Errorf("dialog already holds an open session")
+	}
+	if err := d.init(ctx); err != nil {
+		return nil, err
+	}
+	d.session = &Session{dialog: d}
+	return d.session, nil
+}
+
+// OpenAnonymous initializes a new anonymous dialog and returns it as Session.
+// It is a shorthand for OpenAnonymousContext with a background context.
+func (d *dialog) OpenAnonymous() (*Session, error) {
+	return d.OpenAnonymousContext(context.Background())
+}
+
+// OpenAnonymousContext is like OpenContext, but initializes an anonymous
+// dialog. The jobs sent through the Session are neither signed nor
+// encrypted.
+func (d *dialog) OpenAnonymousContext(ctx context.Context) (*Session, error) {
+	if d.session != nil {
+		return nil, fmt.Errorf("dialog already holds an open session")
+	}
+	if err := d.anonymousInit(ctx); err != nil {
+		return nil, fmt.Errorf("Error while initating anonymous dialog: %w", err)
+	}
+	d.session = &Session{dialog: d}
+	return d.session, nil
+}
+
+// DialogID returns the dialog ID assigned by the institute.
+func (s *Session) DialogID() string {
+	return s.dialog.dialogID
+}
+
+// MaxJobsPerMessage returns the maximum number of jobs the institute accepts
+// within one message as stated in the BPD. A value of zero means there is no
+// restriction.
+func (s *Session) MaxJobsPerMessage() int {
+	return s.dialog.BankParameterData.MaxTransactionsPerMessage
+}
+
+// Send sends all jobs within one message. It is a shorthand for SendContext
+// with a background context.
+func (s *Session) Send(jobs ...segment.ClientSegment) (message.BankMessage, error) {
+	return s.SendContext(context.Background(), jobs...)
+}
+
+// SendContext sends all jobs within one message and returns the response of
+// the institute. It returns an error if the BPD do not allow that many jobs
+// within one message. Use SendBatchContext to split the jobs automatically.
+//
+// Once the dialog got terminated by the institute or cancelled, SendContext
+// returns an error and the Session must be closed.
+func (s *Session) SendContext(ctx context.Context, jobs ...segment.ClientSegment) (message.BankMessage, error) {
+	if s.closed {
+		return nil, fmt.Errorf("session already closed")
+	}
+	if !s.dialog.active {
+		return nil, fmt.Errorf("dialog is no longer active")
+	}
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("no jobs to send")
+	}
+	if max := s.MaxJobsPerMessage(); max > 0 && len(jobs) > max {
+		return nil, fmt.Errorf("institute accepts at most %d jobs per message, got %d", max, len(jobs))
+	}
+	clientMessage := message.NewHBCIMessage(s.dialog.hbciVersion, jobs...)
+	if s.dialog.kind == anonymousDialog {
+		return s.dialog.sendAnonymous(ctx, clientMessage)
+	}
+	return s.dialog.send(ctx, clientMessage)
+}
+
+// SendBatch sends the jobs within as few messages as possible. It is a
+// shorthand for SendBatchContext with a background context.
+func (s *Session) SendBatch(jobs ...segment.ClientSegment) ([]message.BankMessage, error) {
+	return s.SendBatchContext(context.Background(), jobs...)
+}
+
+// SendBatchContext sends the jobs within as few messages as the BPD allow and
+// returns the responses in the order the jobs were sent. It stops at the first
+// error and returns the responses received so far.
+func (s *Session) SendBatchContext(ctx context.Context, jobs ...segment.ClientSegment) ([]message.BankMessage, error) {
+	chunkSize := s.MaxJobsPerMessage()
+	if chunkSize <= 0 {
+		chunkSize = len(jobs)
+	}
+	var responses []message.BankMessage
+	for len(jobs) > 0 {
+		if chunkSize > len(jobs) {
+			chunkSize = len(jobs)
+		}
+		response, err := s.SendContext(ctx, jobs[:chunkSize]...)
+		if err != nil {
+			return responses, err
+		}
+		responses = append(responses, response)
+		jobs = jobs[chunkSize:]
+	}
+	return responses, nil
+}
+
+// Close ends the dialog. It is a shorthand for CloseContext with a background
+// context.
+func (s *Session) Close() error {
+	return s.CloseContext(context.Background())
+}
+
+// CloseContext ends the dialog by sending a dialog end message. If ctx is
+// already done, the dialog end is sent nonetheless. Calling CloseContext on a
+// closed Session is a no-op.
+func (s *Session) CloseContext(ctx context.Context) error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.dialog.session = nil
+	if s.dialog.kind == anonymousDialog {
+		endCtx, cancel := dialogEndContext(ctx)
+		defer cancel()
+		return s.dialog.anonymousEnd(endCtx)
+	}
+	return s.dialog.endContext(ctx)
+}
@@ -0,0 +1,280 @@
+package dialog
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+func TestSessionSend(t *testing.T) {
+	transport := &mockHTTPSTransport{}
+
+	d := newTestPinTanDialog(transport)
+
+	initResponse := encryptedTestMessage(
+		"abcde",
+		"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+	)
+	balanceResponse := encryptedTestMessage(
+		"abcde",
+		"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+		"HISAL:3:5:1+100000000::280:10000000+Sichteinlagen+EUR+C:1000,15:EUR:20150812+C:20,:EUR:20150812+500,:EUR+1499,85:EUR'",
+	)
+	dialogEndResponseMessage := encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Der Auftrag wurde ausgeführt'")
+	transport.SetResponseMessages([][]byte{
+		initResponse,
+		balanceResponse,
+		balanceResponse,
+		dialogEndResponseMessage,
+	})
+
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+
+	session, err := d.Open()
+	if err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.FailNow()
+	}
+
+	for i := 0; i < 2; i++ {
+		res, err := session.Send(segment.NewAccountBalanceRequestV5(account, false))
+		if err != nil {
+			t.Logf("Expected no error, got %T:%v\n", err, err)
+			t.Fail()
+		}
+		if res == nil {
+			t.Logf("Expected result not to be nil\n")
+			t.Fail()
+		}
+	}
+
+	err = session.Close()
+	if err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.Fail()
+	}
+
+	requests := transport.Requests()
+	if len(requests) != 4 {
+		t.Logf("Expected 4 requests, got %d\n", len(requests))
+		t.FailNow()
+	}
+
+	for i, request := range requests[1:] {
+		body, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			panic(err)
+		}
+		expected := "+220+abcde+" + string(rune('2'+i)) + "'"
+		if !strings.Contains(string(body), expected) {
+			t.Logf("Expected message %d to contain dialog ID and message number %q, got\n%q\n", i+2, expected, body)
+			t.Fail()
+		}
+	}
+
+	_, err = session.Send(segment.NewAccountBalanceRequestV5(account, false))
+	if err == nil {
+		t.Logf("Expected error sending on closed session, got nil\n")
+		t.Fail()
+	}
+}
+
+func TestSessionSendBatch(t *testing.T) {
+	transport := &mockHTTPSTransport{}
+
+	d := newTestPinTanDialog(transport)
+	d.BankParameterData.MaxTransactionsPerMessage = 2
+
+	initResponse := encryptedTestMessage(
+		"abcde",
+		"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+	)
+	balanceResponse := encryptedTestMessage(
+		"abcde",
+		"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+	)
+	dialogEndResponseMessage := encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Der Auftrag wurde ausgeführt'")
+	transport.SetResponseMessages([][]byte{
+		initResponse,
+		balanceResponse,
+		balanceResponse,
+		dialogEndResponseMessage,
+	})
+
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	jobs := []segment.ClientSegment{
+		segment.NewAccountBalanceRequestV5(account, false),
+		segment.NewAccountBalanceRequestV5(account, false),
+		segment.NewAccountBalanceRequestV5(account, false),
+	}
+
+	session, err := d.Open()
+	if err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.FailNow()
+	}
+	defer session.Close()
+
+	_, err = session.Send(jobs...)
+	if err == nil {
+		t.Logf("Expected error when exceeding max jobs per message, got nil\n")
+		t.Fail()
+	}
+
+	responses, err := session.SendBatch(jobs...)
+	if err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.Fail()
+	}
+
+	if len(responses) != 2 {
+		t.Logf("Expected 2 responses, got %d\n", len(responses))
+		t.Fail()
+	}
+
+	requests := transport.Requests()
+	if len(requests) != 3 {
+		t.Logf("Expected 3 requests, got %d\n", len(requests))
+		t.FailNow()
+	}
+
+	body, err := ioutil.ReadAll(requests[1].Body)
+	if err != nil {
+		panic(err)
+	}
+	if count := strings.Count(string(body), "HKSAL"); count != 2 {
+		t.Logf("Expected first message to contain 2 jobs, got %d\n", count)
+		t.Fail()
+	}
+}
+
+func TestSessionSendAfterTermination(t *testing.T) {
+	transport := &mockHTTPSTransport{}
+
+	d := newTestPinTanDialog(transport)
+
+	transport.SetResponseMessages([][]byte{
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Auftrag entgegengenommen'"),
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+9800::Dialog abgebrochen'"),
+	})
+
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+
+	session, err := d.Open()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	_, err = session.Send(segment.NewAccountBalanceRequestV5(account, false))
+	if err == nil {
+		t.Logf("Expected error for terminated dialog, got nil\n")
+		t.Fail()
+	}
+
+	_, err = session.Send(segment.NewAccountBalanceRequestV5(account, false))
+	if err == nil {
+		t.Logf("Expected error sending within terminated dialog, got nil\n")
+		t.Fail()
+	}
+	if requests := transport.Requests(); len(requests) != 2 {
+		t.Logf("Expected 2 requests, got %d\n", len(requests))
+		t.Fail()
+	}
+
+	if err := session.Close(); err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.Fail()
+	}
+}
+
+func TestDialogOpenWithOpenSession(t *testing.T) {
+	transport := &mockHTTPSTransport{}
+
+	d := newTestPinTanDialog(transport)
+
+	transport.SetResponseMessages([][]byte{
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Auftrag entgegengenommen'"),
+		encryptedTestMessage("abcde", "HIRMG:2:2:1+0020::Der Auftrag wurde ausgeführt'"),
+		encryptedTestMessage("fghij", "HIRMG:2:2:1+0020::Auftrag entgegengenommen'"),
+	})
+
+	session, err := d.Open()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	_, err = d.Open()
+	if err == nil {
+		t.Logf("Expected error opening a second session, got nil\n")
+		t.Fail()
+	}
+
+	if err := session.Close(); err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	session, err = d.Open()
+	if err != nil {
+		t.Fatalf("Expected no error after closing the session, got %T:%v\n", err, err)
+	}
+	if session.DialogID() != "fghij" {
+		t.Logf("Expected dialog ID %q, got %q\n", "fghij", session.DialogID())
+		t.Fail()
+	}
+}
+
+func TestSessionSendAnonymous(t *testing.T) {
+	transport := &mockHTTPSTransport{}
+
+	d := newTestPinTanDialog(transport)
+
+	transport.SetResponseMessages([][]byte{
+		plainTestMessage("abcde", []byte("HIRMG:2:2+0010::Nachricht entgegengenommen'")),
+		plainTestMessage("abcde", []byte("HIRMG:2:2+3040::Es liegen weitere Informationen vor:ref1'")),
+		plainTestMessage("abcde", []byte("HIRMG:2:2+0020::Auftrag ausgeführt'")),
+		plainTestMessage("abcde", []byte("HIRMG:2:2+0100::Dialog beendet'")),
+	})
+
+	from := domain.BankID{CountryCode: 280, ID: "10000000"}
+
+	session, err := d.OpenAnonymous()
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	for _, continuationReference := range []string{"", "ref1"} {
+		_, err := session.Send(segment.NewCommunicationAccessRequestSegment(from, from, 10, continuationReference))
+		if err != nil {
+			t.Logf("Expected no error, got %T:%v\n", err, err)
+			t.Fail()
+		}
+	}
+
+	if err := session.Close(); err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.Fail()
+	}
+
+	requests := transport.Requests()
+	if len(requests) != 4 {
+		t.Fatalf("Expected 4 requests, got %d\n", len(requests))
+	}
+	for i, request := range requests[1:] {
+		body, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			panic(err)
+		}
+		expected := "+abcde+" + string(rune('2'+i)) + "'"
+		if !strings.Contains(string(body), expected) {
+			t.Logf("Expected message %d to contain dialog ID and message number %q, got\n%q\n", i+2, expected, body)
+			t.Fail()
+		}
+		if strings.Contains(string(body), "HNVSK") {
+			t.Logf("Expected message %d not to be encrypted, got\n%q\n", i+2, body)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package dialog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// ErrStateNotFound is returned by a StateStore if there is no State stored
+// for a given key.
+var ErrStateNotFound = errors.New("dialog: state not found")
+
+// State represents the parts of a dialog which should outlive a single
+// process. Restoring a State avoids registering a new client system ID and
+// downloading BPD and UPD on every start.
+//
+// BankParameterData and UserParameterData hold the marshaled segments as
+// sent by the institute.
+type State struct {
+	ClientSystemID           string   `json:"clientSystemID"`
+	BankParameterDataVersion int      `json:"bpdVersion"`
+	BankParameterData        [][]byte `json:"bpd,omitempty"`
+	UserParameterDataVersion int      `json:"updVersion"`
+	UserParameterData        [][]byte `json:"upd,omitempty"`
+	SecurityFunction         string   `json:"securityFunction,omitempty"`
+	TanMedium                string   `json:"tanMedium,omitempty"`
+	// SignatureID is the last signature ID used with key based security
+	SignatureID int `json:"signatureID,omitempty"`
+}
+
+// A StateStore loads and saves dialog States. Keys identify a user at a bank
+// institute, so that one store can hold the States of several users.
+type StateStore interface {
+	// Load returns the State stored for key. It returns ErrStateNotFound if
+	// there is none.
+	Load(key string) (*State, error)
+	// Save stores state for key, replacing any previously stored State.
+	Save(key string, state *State) error
+}
+
+// NewMemoryStateStore returns a StateStore which keeps all States in memory.
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{states: make(map[string]State)}
+}
+
+// MemoryStateStore implements StateStore in memory. It is safe for
+// concurrent use.
+type MemoryStateStore struct {
+	mu     sync.Mutex
+	states map[string]State
+}
+
+// Load returns a copy of the State stored for key.
+func (m *MemoryStateStore) Load(key string) (*State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.states[key]
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+	return &state, nil
+}
+
+// Save stores a copy of state for key.
+func (m *MemoryStateStore) Save(key string, state *State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[key] = *state
+	return nil
+}
+
+// NewFileStateStore returns a StateStore which persists all States as JSON
+// into the file at path. The file is created on first save.
+func NewFileStateStore(path string) *FileStateStore {
+	return &FileStateStore{path: path}
+}
+
+// FileStateStore implements StateStore backed by a single JSON file. Writes
+// replace the file atomically. As the file contains the client system ID, it
+// is only readable by its owner.
+type FileStateStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// Load returns the State stored for key.
+func (f *FileStateStore) Load(key string) (*State, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	states, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+	state, ok := states[key]
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+	return &state, nil
+}
+
+// Save stores state for key and writes the file.
+func (f *FileStateStore) Save(key string, state *State) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	states, err := f.read()
+	if err != nil {
+		return err
+	}
+	states[key] = *state
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling state: %w", err)
+	}
+	return writeFileAtomic(f.path, data, 0600)
+}
+
+func (f *FileStateStore) read() (map[string]State, error) {
+	states := make(map[string]State)
+	data, err := ioutil.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading state file: %w", err)
+	}
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("error unmarshaling state file: %w", err)
+	}
+	return states, nil
+}
+
+// writeFileAtomic writes data into a temporary file within the directory of
+// path and renames it to path afterwards.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// stateKey returns the key used to identify the dialog state within a
+// StateStore.
+func (d *dialog) stateKey() string {
+	return fmt.Sprintf("%d:%s:%s", d.BankID.CountryCode, d.BankID.ID, d.UserID)
+}
+
+// State returns the current State of the dialog.
+func (d *dialog) State() *State {
+	return &State{
+		ClientSystemID:           d.ClientSystemID,
+		BankParameterDataVersion: d.BankParameterDataVersion(),
+		BankParameterData:        d.rawBankParameterData,
+		UserParameterDataVersion: d.UserParameterDataVersion(),
+		UserParameterData:        d.rawUserParameterData,
+		SecurityFunction:         d.securityFn,
+		TanMedium:                d.tanMedium,
+		SignatureID:              d.signatureID(),
+	}
+}
+
+// RestoreState sets the client system ID, security function, TAN medium and
+// signature ID from state and parses the contained BPD and UPD.
+func (d *dialog) RestoreState(state *State) error {
+	if state.ClientSystemID != "" {
+		d.SetClientSystemID(state.ClientSystemID)
+	}
+	if state.SecurityFunction != "" {
+		d.SetSecurityFunction(state.SecurityFunction)
+	}
+	d.tanMedium = state.TanMedium
+	if state.SignatureID > d.signatureID() {
+		d.setSignatureID(state.SignatureID)
+	}
+	if len(state.BankParameterData) != 0 {
+		bankMessage, err := d.stateMessage(state.BankParameterData)
+		if err != nil {
+			return fmt.Errorf("error restoring bank parameter data: %w", err)
+		}
+		if err := d.parseBankParameterData(bankMessage); err != nil {
+			return fmt.Errorf("error restoring bank parameter data: %w", err)
+		}
+	}
+	if len(state.UserParameterData) != 0 {
+		bankMessage, err := d.stateMessage(state.UserParameterData)
+		if err != nil {
+			return fmt.Errorf("error restoring user parameter data: %w", err)
+		}
+		if err := d.parseUserParameterData(bankMessage); err != nil {
+			return fmt.Errorf("error restoring user parameter data: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadState restores the dialog from the StateStore, if any. A missing State
+// is not considered an error.
+func (d *dialog) loadState() error {
+	if d.stateStore == nil {
+		return nil
+	}
+	state, err := d.stateStore.Load(d.stateKey())
+	if errors.Is(err, ErrStateNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error loading dialog state: %w", err)
+	}
+	return d.RestoreState(state)
+}
+
+// saveState writes the current State into the StateStore, if any.
+func (d *dialog) saveState() error {
+	if d.stateStore == nil {
+		return nil
+	}
+	if err := d.stateStore.Save(d.stateKey(), d.State()); err != nil {
+		return fmt.Errorf("error saving dialog state: %w", err)
+	}
+	return nil
+}
+
+// signatureIDProvider is implemented by SignatureProviders tracking
+// signature IDs, as needed for key based security
+type signatureIDProvider interface {
+	SignatureID() int
+	SetSignatureID(signatureID int)
+}
+
+// signatureID returns the last signature ID used, or zero if the
+// SignatureProvider does not use signature IDs.
+func (d *dialog) signatureID() int {
+	if provider, ok := d.signatureProvider.(signatureIDProvider); ok {
+		return provider.SignatureID()
+	}
+	return 0
+}
+
+func (d *dialog) setSignatureID(signatureID int) {
+	if provider, ok := d.signatureProvider.(signatureIDProvider); ok {
+		provider.SetSignatureID(signatureID)
+	}
+}
+
+// saveSignatureID saves the State if a new signature ID got used since the
+// last save.
+func (d *dialog) saveSignatureID() error {
+	signatureID := d.signatureID()
+	if signatureID == d.savedSignatureID {
+		return nil
+	}
+	if err := d.saveState(); err != nil {
+		return err
+	}
+	d.savedSignatureID = signatureID
+	return nil
+}
+
+// stateMessage wraps the marshaled segments into a BankMessage, so that they
+// can be parsed like a response from the institute.
+func (d *dialog) stateMessage(segments [][]byte) (message.BankMessage, error) {
+	header := segment.NewMessageHeaderSegment(0, d.hbciVersion.Version(), initialDialogID, 0)
+	return message.NewDecryptedMessage(header, nil, bytes.Join(segments, nil))
+}
+
+// bankParameterDataSegments returns the marshaled segments of bankMessage
+// which belong to the BPD.
+func bankParameterDataSegments(bankMessage message.BankMessage) [][]byte {
+	ids := []string{segment.CommonBankParameterID, "HIKOM", "HISHV", "HIKPV"}
+	for _, s := range bankMessage.SupportedSegments() {
+		ids = append(ids, s.ID)
+	}
+	return findMarshaledSegments(bankMessage, ids)
+}
+
+// userParameterDataSegments returns the marshaled segments of bankMessage
+// which belong to the UPD.
+func userParameterDataSegments(bankMessage message.BankMessage) [][]byte {
+	return findMarshaledSegments(bankMessage, []string{segment.CommonUserParameterDataID, segment.AccountInformationID})
+}
+
+func findMarshaledSegments(bankMessage message.BankMessage, ids []string) [][]byte {
+	var segments [][]byte
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		segments = append(segments, bankMessage.FindMarshaledSegments(id)...)
+	}
+	return segments
+}
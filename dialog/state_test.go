@@ -0,0 +1,149 @@
+package dialog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFileStateStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-hbci")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "state.json")
+
+	store := NewFileStateStore(path)
+
+	_, err = store.Load("foo")
+	if err != ErrStateNotFound {
+		t.Logf("Expected error to equal %v, got %v\n", ErrStateNotFound, err)
+		t.Fail()
+	}
+
+	state := &State{
+		ClientSystemID:           "abc",
+		BankParameterDataVersion: 12,
+		BankParameterData:        [][]byte{[]byte("HIBPA:3:2:+12+280:10000000+Bank Name+3+1+201:210:220+0'")},
+		UserParameterDataVersion: 4,
+		SecurityFunction:         "942",
+		TanMedium:                "Handy",
+	}
+
+	err = store.Save("foo", state)
+	if err != nil {
+		t.Logf("Expected no error, got %v\n", err)
+		t.FailNow()
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Logf("Expected state file to exist, got %v\n", err)
+		t.FailNow()
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Logf("Expected file mode to equal %v, got %v\n", os.FileMode(0600), info.Mode().Perm())
+		t.Fail()
+	}
+
+	actual, err := NewFileStateStore(path).Load("foo")
+	if err != nil {
+		t.Logf("Expected no error, got %v\n", err)
+		t.FailNow()
+	}
+
+	if !reflect.DeepEqual(state, actual) {
+		t.Logf("Expected state to equal\n%+#v\n\tgot\n%+#v\n", state, actual)
+		t.Fail()
+	}
+}
+
+func TestPinTanDialogStateStore(t *testing.T) {
+	transport := &mockHTTPSTransport{}
+	store := NewMemoryStateStore()
+
+	d := newTestPinTanDialog(transport)
+	d.stateStore = store
+
+	syncResponseMessage := encryptedTestMessage(
+		"newDialogID",
+		"HIRMG:2:2:1+0100::Dialog beendet'",
+		"HIBPA:3:2:+12+280:10000000+Bank Name+3+1+201:210:220+0'",
+		"HIPINS:4:1:+1+1+0+5:38:6:USERID:CUSTID:HKSAL:N:HKUEB:J'",
+		"HISYN:5:3:8+newClientSystemID'",
+		"HIUPA:6:2:7+12345+4+0'",
+		"HIUPD:7:4:8+12345::280:1000000+54321+EUR+Muster+Max+++HKTAN:1+HKKAZ:1'",
+	)
+	dialogEndResponseMessage := encryptedTestMessage("newDialogID", "HIRMG:2:2:1+0020::Auftrag entgegengenommen'")
+
+	transport.SetResponseMessages([][]byte{
+		syncResponseMessage,
+		dialogEndResponseMessage,
+	})
+
+	_, err := d.SyncClientSystemID()
+	if err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.FailNow()
+	}
+
+	restored := newTestPinTanDialog(transport)
+	restored.SetClientSystemID(initialClientSystemID)
+	restored.stateStore = store
+
+	err = restored.LoadState()
+	if err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.FailNow()
+	}
+
+	if restored.ClientSystemID != "newClientSystemID" {
+		t.Logf("Expected ClientSystemID to equal %q, got %q\n", "newClientSystemID", restored.ClientSystemID)
+		t.Fail()
+	}
+
+	if restored.BankParameterDataVersion() != 12 {
+		t.Logf("Expected BPD version to equal 12, got %d\n", restored.BankParameterDataVersion())
+		t.Fail()
+	}
+
+	if !reflect.DeepEqual(d.BankParameterData.PinTanBusinessTransactions, restored.BankParameterData.PinTanBusinessTransactions) {
+		t.Logf("Expected PinTanBusinessTransactions to equal\n%+#v\n\tgot\n%+#v\n", d.BankParameterData.PinTanBusinessTransactions, restored.BankParameterData.PinTanBusinessTransactions)
+		t.Fail()
+	}
+
+	if restored.UserParameterDataVersion() != 4 {
+		t.Logf("Expected UPD version to equal 4, got %d\n", restored.UserParameterDataVersion())
+		t.Fail()
+	}
+
+	if !reflect.DeepEqual(d.Accounts, restored.Accounts) {
+		t.Logf("Expected accounts to equal\n%+#v\n\tgot\n%+#v\n", d.Accounts, restored.Accounts)
+		t.Fail()
+	}
+
+	// the restored dialog must not sync again and has to send the stored versions
+	transport.Reset()
+	initResponse := encryptedTestMessage("newDialogID", "HIRMG:2:2:1+0020::Auftrag entgegengenommen'")
+	transport.SetResponseMessages([][]byte{initResponse, dialogEndResponseMessage})
+
+	session, err := restored.Open()
+	if err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.FailNow()
+	}
+	session.Close()
+
+	body, err := ioutil.ReadAll(transport.Request(0).Body)
+	if err != nil {
+		panic(err)
+	}
+	if !strings.Contains(string(body), "HKVVB:4:3+12+4+") {
+		t.Logf("Expected init message to contain stored BPD and UPD versions, got\n%q\n", body)
+		t.Fail()
+	}
+}
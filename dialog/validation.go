@@ -0,0 +1,111 @@
+package dialog
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// JobValidationError is returned if a job violates the parameters the
+// institute stated within the BPD. The job is not sent in that case.
+type JobValidationError struct {
+	Job    segment.VersionedSegment
+	Reason string
+}
+
+func (j *JobValidationError) Error() string {
+	return fmt.Sprintf("job %s not allowed by the institute: %s", j.Job, j.Reason)
+}
+
+// accountTransactionJob is implemented by jobs requesting account
+// transactions
+type accountTransactionJob interface {
+	Timeframe() domain.Timeframe
+	ForAllAccounts() bool
+	EntryCount() int
+}
+
+// validateJobs checks the jobs of one message against their parameter
+// segments within the BPD and the PIN/TAN parameters. Jobs without known
+// parameters are not checked.
+func (d *dialog) validateJobs(messageSegments []segment.ClientSegment) error {
+	var jobs []segment.ClientSegment
+	for _, seg := range messageSegments {
+		if seg != nil && !reflect.ValueOf(seg).IsNil() {
+			jobs = append(jobs, seg)
+		}
+	}
+	jobCount := make(map[segment.VersionedSegment]int)
+	hasTanSegment := false
+	for _, job := range jobs {
+		jobCount[versionedSegment(job)]++
+		if job.Header().ID.Val() == "HKTAN" {
+			hasTanSegment = true
+		}
+	}
+	for _, job := range jobs {
+		versionedJob := versionedSegment(job)
+		invalid := func(format string, args ...interface{}) error {
+			return &JobValidationError{Job: versionedJob, Reason: fmt.Sprintf(format, args...)}
+		}
+		if !d.keyBased && d.BankParameterData.PinTanBusinessTransactions[versionedJob.ID] && !hasTanSegment {
+			return invalid("job needs a TAN, but the message contains no TAN segment")
+		}
+		parameters := d.jobParameters(versionedJob)
+		if parameters == nil {
+			continue
+		}
+		if max := parameters.MaxJobsPerMessage(); max > 0 && jobCount[versionedJob] > max {
+			return invalid("at most %d jobs per message allowed, got %d", max, jobCount[versionedJob])
+		}
+		transactionParameters, ok := parameters.(*segment.AccountTransactionParameterSegment)
+		request, isTransactionRequest := job.(accountTransactionJob)
+		if !ok || !isTransactionRequest {
+			continue
+		}
+		if request.ForAllAccounts() && !transactionParameters.AllAccountsRequestable() {
+			return invalid("requesting all accounts is not allowed")
+		}
+		if request.EntryCount() > 0 && !transactionParameters.EntryCountRestrictable() {
+			return invalid("restricting the number of entries is not allowed")
+		}
+		if maxDays := transactionParameters.MaxDaysBack(); maxDays > 0 {
+			earliest := domain.NewShortDate(time.Now().AddDate(0, 0, -maxDays))
+			startDate := request.Timeframe().StartDate
+			if !startDate.IsZero() && startDate.Before(earliest.Time) {
+				return invalid(
+					"transactions are kept for %d days, start date %s is before %s",
+					maxDays, startDate.Format("2006-01-02"), earliest.Format("2006-01-02"),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// jobParameters returns the parameter segment of job within the BPD, if any.
+// The parameter segment of a job HKXXX is named HIXXXS.
+func (d *dialog) jobParameters(job segment.VersionedSegment) segment.BusinessTransactionParameters {
+	if len(job.ID) != 5 {
+		return nil
+	}
+	parameterID := "HI" + job.ID[2:] + "S"
+	for _, param := range d.BankParameterData.SupportedSegmentParameters {
+		if param.ID != parameterID || param.Version != job.Version {
+			continue
+		}
+		parameters, ok := param.Parameters.(segment.BusinessTransactionParameters)
+		if ok {
+			return parameters
+		}
+	}
+	return nil
+}
+
+func versionedSegment(seg segment.Segment) segment.VersionedSegment {
+	header := seg.Header()
+	return segment.VersionedSegment{ID: header.ID.Val(), Version: header.Version.Val()}
+}
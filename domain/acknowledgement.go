@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/mitch000001/go-hbci/returncode"
 )
 
 // NewMessageAcknowledgement creactes a new message acknowledgement
@@ -55,7 +57,7 @@ func (a Acknowledgement) String() string {
 	} else {
 		fmt.Fprintf(&buf, ": ")
 	}
-	fmt.Fprintf(&buf, "Code: %d, ", a.Code)
+	fmt.Fprintf(&buf, "Code: %d (%s), ", a.Code, a.ReturnCode().Format(false, a.Params...))
 	if a.ReferenceDataElement != "" {
 		fmt.Fprintf(&buf, "Position: %s, ", a.ReferenceDataElement)
 	} else {
@@ -68,6 +70,12 @@ func (a Acknowledgement) String() string {
 	return buf.String()
 }
 
+// ReturnCode returns the catalog entry for the code of a. For undocumented
+// codes it returns a generic entry.
+func (a Acknowledgement) ReturnCode() returncode.ReturnCode {
+	return returncode.Describe(a.Code)
+}
+
 // IsMessageAcknowledgement returns true if the type is MessageAcknowledgement, false otherwise
 func (a Acknowledgement) IsMessageAcknowledgement() bool {
 	return a.Type == MessageAcknowledgement
@@ -108,7 +116,7 @@ func (s StatusAcknowledgement) String() string {
 		fmt.Fprintf(&buf, ": ")
 	}
 	fmt.Fprintf(&buf, "Transmitted at: %s, ", s.TransmittedAt)
-	fmt.Fprintf(&buf, "Code: %d, ", s.Code)
+	fmt.Fprintf(&buf, "Code: %d (%s), ", s.Code, s.ReturnCode().Format(false, s.Params...))
 	if s.ReferenceDataElement != "" {
 		fmt.Fprintf(&buf, "Position: %s, ", s.ReferenceDataElement)
 	} else {
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Categories of acknowledgements to use with errors.Is on an
+// AcknowledgementError.
+var (
+	// ErrWrongPIN indicates that the institute rejected the PIN (9931, 9942).
+	ErrWrongPIN = errors.New("wrong PIN")
+	// ErrAccessLocked indicates that the access is locked temporarily (3938).
+	ErrAccessLocked = errors.New("access locked")
+	// ErrTANRequired indicates that the institute needs a TAN to execute the
+	// jobs (0030). As 0030 does not reject a message, an AcknowledgementError
+	// never falls into this category. It is matched by the errors returned
+	// along with the response waiting for the TAN instead.
+	ErrTANRequired = errors.New("TAN required")
+	// ErrParameterDataChanged indicates that the BPD or UPD of the client are
+	// outdated (1040, 1050, 3081).
+	ErrParameterDataChanged = errors.New("parameter data changed")
+)
+
+var acknowledgementCategories = map[error][]int{
+	ErrWrongPIN:             {9931, 9942},
+	ErrAccessLocked:         {3938},
+	ErrTANRequired:          {30},
+	ErrParameterDataChanged: {1040, 1050, 3081},
+}
+
+// InCategory reports whether the code of a falls into category, e.g.
+// ErrParameterDataChanged.
+func (a Acknowledgement) InCategory(category error) bool {
+	for _, code := range acknowledgementCategories[category] {
+		if a.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// NewAcknowledgementError returns an AcknowledgementError containing all
+// acknowledgements of a response.
+func NewAcknowledgementError(acknowledgements []Acknowledgement) *AcknowledgementError {
+	return &AcknowledgementError{Acknowledgements: acknowledgements}
+}
+
+// AcknowledgementError is returned when the institute rejects a message. It
+// carries all acknowledgements of the response, not only the errors, so that
+// callers can inspect the referenced segments and parameters.
+type AcknowledgementError struct {
+	Acknowledgements []Acknowledgement
+}
+
+// Errors returns the acknowledgements representing errors.
+func (a *AcknowledgementError) Errors() []Acknowledgement {
+	var errs []Acknowledgement
+	for _, ack := range a.Acknowledgements {
+		if ack.IsError() {
+			errs = append(errs, ack)
+		}
+	}
+	return errs
+}
+
+// HasCode returns true if one of the acknowledgements has the given code.
+func (a *AcknowledgementError) HasCode(code int) bool {
+	for _, ack := range a.Acknowledgements {
+		if ack.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Is reports whether the acknowledgements which caused the rejection fall
+// into the category target, e.g. ErrWrongPIN. These are the errors and the
+// warnings and notices sharing their scope, e.g. 3938 sent along with a
+// message error. Successes like 0030 never cause a rejection.
+func (a *AcknowledgementError) Is(target error) bool {
+	for _, ack := range a.causes() {
+		if ack.InCategory(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// causes returns the acknowledgements which caused the rejection. A message
+// error covers the whole message, a segment error only its segment.
+func (a *AcknowledgementError) causes() []Acknowledgement {
+	messageRejected := false
+	rejectedSegments := make(map[int]bool)
+	for _, ack := range a.Errors() {
+		if ack.IsMessageAcknowledgement() {
+			messageRejected = true
+		} else {
+			rejectedSegments[ack.ReferencingSegmentNumber] = true
+		}
+	}
+	var causes []Acknowledgement
+	for _, ack := range a.Acknowledgements {
+		if ack.IsSuccess() {
+			continue
+		}
+		if ack.IsError() || messageRejected ||
+			(ack.IsSegmentAcknowledgement() && rejectedSegments[ack.ReferencingSegmentNumber]) {
+			causes = append(causes, ack)
+		}
+	}
+	return causes
+}
+
+func (a *AcknowledgementError) Error() string {
+	acknowledgements := a.Errors()
+	if len(acknowledgements) == 0 {
+		acknowledgements = a.Acknowledgements
+	}
+	messages := make([]string, len(acknowledgements))
+	for i, ack := range acknowledgements {
+		messages[i] = ack.String()
+	}
+	return fmt.Sprintf("institute returned errors:\n%s", strings.Join(messages, "\n"))
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestAcknowledgementErrorIs(t *testing.T) {
+	tests := []struct {
+		code     int
+		category error
+	}{
+		{9931, ErrWrongPIN},
+		{9942, ErrWrongPIN},
+		{3938, ErrAccessLocked},
+		{30, nil},
+		{1040, ErrParameterDataChanged},
+		{3050, nil},
+	}
+	categories := []error{ErrWrongPIN, ErrAccessLocked, ErrTANRequired, ErrParameterDataChanged}
+	for _, test := range tests {
+		err := fmt.Errorf("wrapped: %w", NewAcknowledgementError([]Acknowledgement{
+			{Type: MessageAcknowledgement, Code: 9050, Text: "Die Nachricht enthält Fehler."},
+			{Type: SegmentAcknowledgement, Code: test.code, ReferencingSegmentNumber: 3},
+		}))
+
+		for _, category := range categories {
+			expected := category == test.category
+			if actual := errors.Is(err, category); actual != expected {
+				t.Logf("%04d: Expected errors.Is(err, %q) to be %t, got %t\n", test.code, category, expected, actual)
+				t.Fail()
+			}
+		}
+
+		var ackErr *AcknowledgementError
+		if !errors.As(err, &ackErr) {
+			t.Logf("%04d: Expected error to be an AcknowledgementError\n", test.code)
+			t.Fail()
+			continue
+		}
+		if len(ackErr.Acknowledgements) != 2 {
+			t.Logf("%04d: Expected 2 acknowledgements, got %d\n", test.code, len(ackErr.Acknowledgements))
+			t.Fail()
+		}
+	}
+}
+
+func TestAcknowledgementErrorIsOnlyMatchesCauses(t *testing.T) {
+	err := NewAcknowledgementError([]Acknowledgement{
+		{Type: MessageAcknowledgement, Code: 3060, Text: "Teilweise liegen Warnungen/Hinweise vor."},
+		{Type: SegmentAcknowledgement, Code: 30, ReferencingSegmentNumber: 3},
+		{Type: SegmentAcknowledgement, Code: 3938, ReferencingSegmentNumber: 3},
+		{Type: SegmentAcknowledgement, Code: 9942, ReferencingSegmentNumber: 4},
+	})
+
+	if errors.Is(err, ErrTANRequired) || errors.Is(err, ErrAccessLocked) {
+		t.Logf("Expected acknowledgements of successful segments not to match\n")
+		t.Fail()
+	}
+	if !errors.Is(err, ErrWrongPIN) {
+		t.Logf("Expected the segment error to match ErrWrongPIN\n")
+		t.Fail()
+	}
+
+	err.Acknowledgements = append(err.Acknowledgements, Acknowledgement{Type: SegmentAcknowledgement, Code: 9010, ReferencingSegmentNumber: 3})
+
+	if !errors.Is(err, ErrAccessLocked) {
+		t.Logf("Expected the warning of a rejected segment to match ErrAccessLocked\n")
+		t.Fail()
+	}
+	if errors.Is(err, ErrTANRequired) {
+		t.Logf("Expected 0030 never to match\n")
+		t.Fail()
+	}
+}
+
+func TestAcknowledgementErrorError(t *testing.T) {
+	err := NewAcknowledgementError([]Acknowledgement{
+		{Type: MessageAcknowledgement, Code: 3060, Text: "Warnung"},
+		{Type: SegmentAcknowledgement, Code: 9010, Text: "Fehler", ReferencingSegmentNumber: 3},
+	})
+
+	expected := "institute returned errors:\nSegmentAcknowledgement for message 0 (), segment 3: Code: 9010 (Processing not possible), Position: none, Text: 'Fehler'"
+	if err.Error() != expected {
+		t.Logf("Expected error to equal\n%q\n\tgot\n%q\n", expected, err.Error())
+		t.Fail()
+	}
+}
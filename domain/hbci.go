@@ -5,9 +5,14 @@ const (
 	HBCIVersion220 = 220
 	// FINTSVersion300 represents version 3.0.0 of FINTS protocol
 	FINTSVersion300 = 300
+	// FINTSVersion410 represents version 4.1 of FINTS protocol, which uses
+	// XML messages. It is not part of SupportedHBCIVersions as its messages
+	// are not built from segments.
+	FINTSVersion410 = 410
 )
 
-// SupportedHBCIVersions provides a list of supported versions
+// SupportedHBCIVersions provides a list of supported versions using the
+// segment based message format
 var SupportedHBCIVersions = []int{
 	HBCIVersion220,
 	FINTSVersion300,
@@ -3,7 +3,10 @@ package domain
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"math/big"
+
+	"golang.org/x/crypto/ripemd160"
 )
 
 // Key provides an interface to an encryption/signing key
@@ -145,13 +148,29 @@ func GenerateSigningKey() (*PublicKey, error) {
 	if err != nil {
 		return nil, err
 	}
-	p := PublicKey{
-		Type:          "S",
-		Modulus:       rsaKey.N.Bytes(),
-		Exponent:      big.NewInt(int64(rsaKey.E)).Bytes(),
-		rsaPrivateKey: rsaKey,
+	return NewPrivateKey(KeyTypeSigning, rsaKey), nil
+}
+
+// GenerateKey generates a new RSA key of keyType with the given number of
+// bits
+func GenerateKey(keyType KeyType, bits int) (*PublicKey, error) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrivateKey(keyType, rsaKey), nil
+}
+
+// NewPrivateKey returns a key of keyType embedding the private RSA key. The
+// key can be used for the operations of the public key as well.
+func NewPrivateKey(keyType KeyType, key *rsa.PrivateKey) *PublicKey {
+	return &PublicKey{
+		Type:          string(keyType),
+		Modulus:       key.N.Bytes(),
+		Exponent:      big.NewInt(int64(key.E)).Bytes(),
+		rsaPrivateKey: key,
+		rsaPublicKey:  &key.PublicKey,
 	}
-	return &p, nil
 }
 
 // NewRSAKey returns a new RSA key
@@ -190,13 +209,24 @@ func (r *RSAKey) CanEncrypt() bool {
 	return r.PublicKey.rsaPublicKey != nil
 }
 
+// NewPublicKey returns a key of keyType embedding the public RSA key, e.g.
+// one of the keys of the institute
+func NewPublicKey(keyType KeyType, key *rsa.PublicKey) *PublicKey {
+	return &PublicKey{
+		Type:         string(keyType),
+		Modulus:      key.N.Bytes(),
+		Exponent:     big.NewInt(int64(key.E)).Bytes(),
+		rsaPublicKey: key,
+	}
+}
+
 // NewEncryptionKey creates a new RSA encryption key
 func NewEncryptionKey(modulus, exponent []byte) *PublicKey {
 	p := &PublicKey{
-		Type: "V",
+		Type:     "V",
+		Modulus:  append([]byte(nil), modulus...),
+		Exponent: append([]byte(nil), exponent...),
 	}
-	copy(p.Modulus, modulus)
-	copy(p.Exponent, exponent)
 	mod := new(big.Int).SetBytes(modulus)
 	exp := new(big.Int).SetBytes(exponent)
 	pubKey := rsa.PublicKey{
@@ -221,6 +251,35 @@ func (p *PublicKey) SigningKey() *rsa.PrivateKey {
 	return p.rsaPrivateKey
 }
 
+// RSAPublicKey returns the RSA public key, or nil when not set
+func (p *PublicKey) RSAPublicKey() *rsa.PublicKey {
+	return p.rsaPublicKey
+}
+
+// HashRIPEMD160 returns the RIPEMD-160 hash of the key as printed on the INI
+// letter
+func (p *PublicKey) HashRIPEMD160() []byte {
+	h := ripemd160.New()
+	h.Write(p.hashInput())
+	return h.Sum(nil)
+}
+
+// HashSHA256 returns the SHA-256 hash of the key as printed on the INI letter
+func (p *PublicKey) HashSHA256() []byte {
+	sum := sha256.Sum256(p.hashInput())
+	return sum[:]
+}
+
+// hashInput concatenates exponent and modulus, both left padded with zeros to
+// the length of the modulus
+func (p *PublicKey) hashInput() []byte {
+	size := len(p.Modulus)
+	input := make([]byte, 2*size)
+	copy(input[size-len(p.Exponent):size], p.Exponent)
+	copy(input[2*size-len(p.Modulus):], p.Modulus)
+	return input
+}
+
 // Sign signs message with the private key
 func (p *PublicKey) Sign(message []byte) ([]byte, error) {
 	return rsa.SignPKCS1v15(rand.Reader, p.rsaPrivateKey, 0, message)
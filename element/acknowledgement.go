@@ -11,6 +11,7 @@ import (
 // These represent HBCI acknowledgement codes. Codes starting with 3 are meant
 // to be warnings.
 const (
+	AcknowledgementTanRequired               = 30
 	AcknowledgementAdditionalInformation     = 3040
 	AcknowledgementSupportedSecurityFunction = 3920
 )
@@ -21,7 +22,7 @@ func NewAcknowledgement(acknowledgement domain.Acknowledgement) *Acknowledgement
 		Code:                 NewDigit(acknowledgement.Code, 4),
 		ReferenceDataElement: NewAlphaNumeric(acknowledgement.ReferenceDataElement, 7),
 		Text:                 NewAlphaNumeric(acknowledgement.Text, 80),
-		Params:               NewParams(10, 10, acknowledgement.Params...),
+		Params:               NewParams(0, 10, acknowledgement.Params...),
 	}
 	a.DataElement = NewDataElementGroup(acknowledgementDEG, 4, a)
 	return a
@@ -158,6 +159,6 @@ func (p *ParamsDataElement) UnmarshalHBCI(value []byte) error {
 	for i, elem := range elements {
 		dataElements[i] = NewAlphaNumeric(charset.ToUTF8(elem), 35)
 	}
-	p.arrayElementGroup = newArrayElementGroup(acknowlegdementParamsGDEG, 10, 10, dataElements)
+	p.arrayElementGroup = newArrayElementGroup(acknowlegdementParamsGDEG, 0, 10, dataElements)
 	return nil
 }
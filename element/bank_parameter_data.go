@@ -180,12 +180,58 @@ func (s *SupportedLanguagesDataElement) UnmarshalHBCI(value []byte) error {
 	return nil
 }
 
+// NewSupportedCompressionMethods returns a new
+// SupportedCompressionMethodsDataElement for the given compression function
+// codes
+func NewSupportedCompressionMethods(methods ...string) *SupportedCompressionMethodsDataElement {
+	methodDEs := make([]DataElement, len(methods))
+	for i, method := range methods {
+		methodDEs[i] = NewAlphaNumeric(method, 3)
+	}
+	s := &SupportedCompressionMethodsDataElement{}
+	s.arrayElementGroup = newArrayElementGroup(supportedCompressionMethodsDEG, 1, 99, methodDEs)
+	return s
+}
+
 // SupportedCompressionMethodsDataElement represents the compression methods
 // supported by the bank institute
 type SupportedCompressionMethodsDataElement struct {
 	*arrayElementGroup
 }
 
+// Methods returns the codes of the supported compression functions
+func (s *SupportedCompressionMethodsDataElement) Methods() []string {
+	methods := make([]string, len(s.arrayElementGroup.array))
+	for i, method := range s.arrayElementGroup.array {
+		methods[i] = method.(*AlphaNumericDataElement).Val()
+	}
+	return methods
+}
+
+// UnmarshalHBCI unmarshals the value into the
+// SupportedCompressionMethodsDataElement. As institutes separate the codes
+// either as data elements or as group data elements, both are accepted.
+func (s *SupportedCompressionMethodsDataElement) UnmarshalHBCI(value []byte) error {
+	var methods []DataElement
+	for _, group := range bytes.Split(value, []byte("+")) {
+		elements, err := ExtractElements(group)
+		if err != nil {
+			return err
+		}
+		for _, elem := range elements {
+			if len(elem) == 0 {
+				continue
+			}
+			methods = append(methods, NewAlphaNumeric(charset.ToUTF8(elem), 3))
+		}
+	}
+	if len(methods) == 0 || len(methods) > 99 {
+		return fmt.Errorf("Malformed marshaled value")
+	}
+	s.arrayElementGroup = newArrayElementGroup(supportedCompressionMethodsDEG, 1, 99, methods)
+	return nil
+}
+
 // A BusinessTransactionParameter defines parameters for a specific business
 // transaction.
 type BusinessTransactionParameter struct {
@@ -293,3 +339,49 @@ func (p *PinTanBusinessTransactionParameter) UnmarshalHBCI(value []byte) error {
 	p.DataElement = NewGroupDataElementGroup(pinTanBusinessTransactionParameterGDEG, 2, p)
 	return nil
 }
+
+// AccountTransactionParameter represents the parameters of the account
+// transaction request (HKKAZ) within the BPD
+type AccountTransactionParameter struct {
+	DataElement
+	MaxDays            *NumberDataElement
+	EntryCountAllowed  *BooleanDataElement
+	AllAccountsAllowed *BooleanDataElement
+}
+
+// GroupDataElements returns the grouped DataElements
+func (a *AccountTransactionParameter) GroupDataElements() []DataElement {
+	return []DataElement{
+		a.MaxDays,
+		a.EntryCountAllowed,
+		a.AllAccountsAllowed,
+	}
+}
+
+// UnmarshalHBCI unmarshals value into a. AllAccountsAllowed is missing in
+// version 4.
+func (a *AccountTransactionParameter) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) < 2 {
+		return fmt.Errorf("Malformed marshaled value")
+	}
+	a.MaxDays = &NumberDataElement{}
+	if err := a.MaxDays.UnmarshalHBCI(elements[0]); err != nil {
+		return fmt.Errorf("Malformed max days: %v", err)
+	}
+	a.EntryCountAllowed = &BooleanDataElement{}
+	if err := a.EntryCountAllowed.UnmarshalHBCI(elements[1]); err != nil {
+		return fmt.Errorf("Malformed entry count allowed: %v", err)
+	}
+	if len(elements) > 2 && len(elements[2]) > 0 {
+		a.AllAccountsAllowed = &BooleanDataElement{}
+		if err := a.AllAccountsAllowed.UnmarshalHBCI(elements[2]); err != nil {
+			return fmt.Errorf("Malformed all accounts allowed: %v", err)
+		}
+	}
+	a.DataElement = NewDataElementGroup(accountTransactionParameterDEG, 3, a)
+	return nil
+}
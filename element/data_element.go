@@ -151,9 +151,12 @@ func (g *elementGroup) MarshalHBCI() ([]byte, error) {
 			elementBytes[i] = marshaled
 		}
 	}
-	marshaled := bytes.Join(elementBytes, []byte(":"))
-	marshaled = bytes.TrimRight(marshaled, ":")
-	return marshaled, nil
+	// omit trailing empty elements, trimming the separators would also cut
+	// binary data ending with a colon
+	for len(elementBytes) > 0 && len(elementBytes[len(elementBytes)-1]) == 0 {
+		elementBytes = elementBytes[:len(elementBytes)-1]
+	}
+	return bytes.Join(elementBytes, []byte(":")), nil
 }
 
 func (g *elementGroup) UnmarshalHBCI(value []byte) error {
@@ -204,6 +207,12 @@ type arrayElementGroup struct {
 	array     []DataElement
 }
 
+// occurrence returns the number of elements of a and the allowed minimum and
+// maximum
+func (a *arrayElementGroup) occurrence() (int, int, int) {
+	return len(a.array), a.minLength, a.maxLength
+}
+
 func (a *arrayElementGroup) IsValid() bool {
 	if len(a.array) < a.minLength || len(a.array) > a.maxLength {
 		return false
@@ -245,7 +254,7 @@ func (a *AlphaNumericDataElement) Val() string { return a.val.(string) }
 
 // IsValid returns false if a contains '\n' and '\r', true otherwise
 func (a *AlphaNumericDataElement) IsValid() bool {
-	if strings.ContainsAny(a.Val(), "\n & \r") {
+	if strings.ContainsAny(a.Val(), "\n\r") {
 		return false
 	}
 	return a.basicDataElement.IsValid()
@@ -540,7 +549,8 @@ func (c *CodeDataElement) Type() DataElementType {
 // IsValid returns true if the value is in the valid set and the underlying
 // AlphaNumericDataElement is valid, false otherwise.
 func (c *CodeDataElement) IsValid() bool {
-	if sort.SearchStrings(c.validSet, c.Val()) >= len(c.validSet) {
+	i := sort.SearchStrings(c.validSet, c.Val())
+	if i >= len(c.validSet) || c.validSet[i] != c.Val() {
 		return false
 	}
 	return c.AlphaNumericDataElement.IsValid()
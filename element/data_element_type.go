@@ -54,6 +54,7 @@ const (
 	certificateDEG
 	publicKeyDEG
 	supportedLanguagesDEG
+	supportedCompressionMethodsDEG
 	supportedHBCIVersionDEG
 	communicationParameterDEG
 	supportedSecurityMethodDEG
@@ -65,6 +66,7 @@ const (
 	tan2StepSubmissionParameterDEG
 	tan2StepSubmissionProcessParameterDEG
 	pinTanSpecificParamDataElementDEG
+	accountTransactionParameterDEG
 )
 
 var typeName = map[DataElementType]string{
@@ -118,6 +120,7 @@ var typeName = map[DataElementType]string{
 	tan2StepSubmissionParameterDEG:        "Parameter Zwei-Schritt-TAN-Einreichung",
 	tan2StepSubmissionProcessParameterDEG: "Verfahrensparameter Zwei-Schritt-Verfahren",
 	pinTanSpecificParamDataElementDEG:     "Parameter PIN/TAN-spezifische Informationen",
+	accountTransactionParameterDEG:        "Parameter Kontoumsätze/Zeitraum",
 }
 
 func (d DataElementType) String() string {
@@ -1,7 +1,6 @@
 package element
 
 import (
-	"bytes"
 	"fmt"
 
 	"github.com/mitch000001/go-hbci/token"
@@ -16,16 +15,28 @@ func ExtractElements(dataElementGroup []byte) ([][]byte, error) {
 // newElementExtractor creates a new GroupExtractor ready to use
 func newElementExtractor(dataElementGroup []byte) *groupExtractor {
 	// TODO: workaround to get the lexer work properly for us. Maybe we should adopt the lexer?
-	if bytes.LastIndexFunc(dataElementGroup, func(r rune) bool {
-		return r == '+' || r == '\''
-	}) == -1 {
-		dataElementGroup = append(dataElementGroup, '+')
+	// Only the end is checked, as binary data may contain separators.
+	if !endsWithTerminator(dataElementGroup) {
+		dataElementGroup = append(dataElementGroup[:len(dataElementGroup):len(dataElementGroup)], '+')
 	}
 	return &groupExtractor{
 		rawDataElementGroup: dataElementGroup,
 	}
 }
 
+// endsWithTerminator returns true if data ends with an unescaped data
+// element separator or segment end marker
+func endsWithTerminator(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+	last := data[len(data)-1]
+	if last != '+' && last != '\'' {
+		return false
+	}
+	return len(data) < 2 || data[len(data)-2] != '?'
+}
+
 // An groupExtractor extracts DataElements from DataElementGroups
 type groupExtractor struct {
 	rawDataElementGroup []byte
@@ -61,6 +61,18 @@ func Test_ExtractElements(t *testing.T) {
 			},
 			nil,
 		},
+		{
+			"2:2:13:@4@a+'b:6:1",
+			[]string{
+				"2",
+				"2",
+				"13",
+				"@4@a+'b",
+				"6",
+				"1",
+			},
+			nil,
+		},
 	}
 
 	for _, test := range tests {
@@ -32,20 +32,38 @@ func NewRDHEncryptionAlgorithm(pubKey []byte) *EncryptionAlgorithmDataElement {
 	return e
 }
 
+// NewHybridEncryptionAlgorithm returns an EncryptionAlgorithmDataElement for
+// messages encrypted with the symmetric algorithm, whose message key is
+// transported encrypted with the public key of the receiver, as used by
+// RDH-10 and RAH-10.
+func NewHybridEncryptionAlgorithm(algorithm string, encryptedMessageKey []byte) *EncryptionAlgorithmDataElement {
+	e := &EncryptionAlgorithmDataElement{
+		Usage:                      NewAlphaNumeric("2", 3),
+		OperationMode:              NewAlphaNumeric("2", 3),
+		Algorithm:                  NewAlphaNumeric(algorithm, 3),
+		Key:                        NewBinary(encryptedMessageKey, 512),
+		KeyParamID:                 NewAlphaNumeric("6", 3),
+		InitializationValueParamID: NewAlphaNumeric("1", 3),
+	}
+	e.DataElement = NewDataElementGroup(encryptionAlgorithmDEG, 7, e)
+	return e
+}
+
 // EncryptionAlgorithmDataElement represents an encryption algorithm
 type EncryptionAlgorithmDataElement struct {
 	DataElement
 	// "2" for OSY, Owner Symmetric
-	Usage *AlphaNumericDataElement
+	Usage *AlphaNumericDataElement `hbci:"required"`
 	// "2" for CBC, Cipher Block Chaining.
-	OperationMode *AlphaNumericDataElement
+	OperationMode *AlphaNumericDataElement `hbci:"required"`
 	// "13" for 2-Key-Triple-DES
-	Algorithm *AlphaNumericDataElement
-	Key       *BinaryDataElement
+	// "14" for AES-256
+	Algorithm *AlphaNumericDataElement `hbci:"required"`
+	Key       *BinaryDataElement       `hbci:"required"`
 	// "5" for KYE, Symmetric key, en-/decryption with a symmetric key (DDV)
 	// "6" for KYP, Symmetric key, encryption with a public key (RDH).
-	KeyParamID                 *AlphaNumericDataElement
-	InitializationValueParamID *AlphaNumericDataElement
+	KeyParamID                 *AlphaNumericDataElement `hbci:"required"`
+	InitializationValueParamID *AlphaNumericDataElement `hbci:"required"`
 	InitializationValue        *BinaryDataElement
 }
 
@@ -64,15 +82,15 @@ func (e *EncryptionAlgorithmDataElement) GroupDataElements() []DataElement {
 
 // UnmarshalHBCI unmarshals value into the DataElement
 func (e *EncryptionAlgorithmDataElement) UnmarshalHBCI(value []byte) error {
-	e = &EncryptionAlgorithmDataElement{}
 	elements, err := ExtractElements(value)
 	if err != nil {
 		return err
 	}
-	if len(elements) < 7 {
+	// trailing optional elements may be omitted
+	if len(elements) < 2 {
 		return fmt.Errorf("Malformed marshaled value")
 	}
-	e.DataElement = NewDataElementGroup(encryptionAlgorithmDEG, 5, e)
+	e.DataElement = NewDataElementGroup(encryptionAlgorithmDEG, 7, e)
 	e.Usage = &AlphaNumericDataElement{}
 	err = e.Usage.UnmarshalHBCI(elements[0])
 	if err != nil {
@@ -1,6 +1,7 @@
 package element
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"strconv"
@@ -23,8 +24,8 @@ func NewAmount(value float64, currency string) *AmountDataElement {
 // An AmountDataElement represents a value with a currency
 type AmountDataElement struct {
 	DataElement
-	Amount   *ValueDataElement
-	Currency *CurrencyDataElement
+	Amount   *ValueDataElement    `hbci:"required"`
+	Currency *CurrencyDataElement `hbci:"required"`
 }
 
 // Elements returns the child elements of the group
@@ -79,8 +80,8 @@ func NewBankIdentification(bankID domain.BankID) *BankIdentificationDataElement
 // A BankIdentificationDataElement represents the identification for a bank institute
 type BankIdentificationDataElement struct {
 	DataElement
-	CountryCode *CountryCodeDataElement
-	BankID      *AlphaNumericDataElement
+	CountryCode *CountryCodeDataElement  `hbci:"required"`
+	BankID      *AlphaNumericDataElement `hbci:"required"`
 }
 
 // Val returns the value of b as domain.BankID
@@ -115,6 +116,7 @@ func (b *BankIdentificationDataElement) UnmarshalHBCI(value []byte) error {
 	}
 	b.CountryCode = countryCode
 	b.BankID = NewAlphaNumeric(charset.ToUTF8(elements[1]), 30)
+	b.DataElement = NewGroupDataElementGroup(bankIdentificationGDEG, 2, b)
 	return nil
 }
 
@@ -133,10 +135,10 @@ func NewAccountConnection(conn domain.AccountConnection) *AccountConnectionDataE
 // AccountConnectionDataElement represents a bank account
 type AccountConnectionDataElement struct {
 	DataElement
-	AccountID                 *IdentificationDataElement
+	AccountID                 *IdentificationDataElement `hbci:"required"`
 	SubAccountCharacteristics *IdentificationDataElement
-	CountryCode               *CountryCodeDataElement
-	BankID                    *AlphaNumericDataElement
+	CountryCode               *CountryCodeDataElement  `hbci:"required"`
+	BankID                    *AlphaNumericDataElement `hbci:"required"`
 }
 
 // Elements returns all child elements of a
@@ -256,7 +258,8 @@ func (i *InternationalAccountConnectionDataElement) UnmarshalHBCI(value []byte)
 	}
 	if len(elements) > 4 && len(elements[4]) > 0 {
 		i.BankID = &BankIdentificationDataElement{}
-		err = i.BankID.UnmarshalHBCI(elements[4])
+		// the bank ID is a group of the country code and the bank ID
+		err = i.BankID.UnmarshalHBCI(bytes.Join(elements[4:], []byte(":")))
 		if err != nil {
 			return err
 		}
@@ -33,3 +33,30 @@ func TestAccountConnectionUnmarshalHBCI(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestInternationalAccountConnectionUnmarshalHBCI(t *testing.T) {
+	test := "DE12345678901234567890:BICXXXX:abc:subacc:280:12345678"
+
+	acc := &InternationalAccountConnectionDataElement{}
+
+	err := acc.UnmarshalHBCI([]byte(test))
+
+	if err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.Fail()
+	}
+
+	expected := domain.InternationalAccountConnection{
+		IBAN:                      "DE12345678901234567890",
+		BIC:                       "BICXXXX",
+		AccountID:                 "abc",
+		SubAccountCharacteristics: "subacc",
+		BankID:                    domain.BankID{CountryCode: 280, ID: "12345678"},
+	}
+	actual := acc.Val()
+
+	if expected != actual {
+		t.Logf("Expected unmarshaled value to equal\n%+v\n\tgot\n%+v\n", expected, actual)
+		t.Fail()
+	}
+}
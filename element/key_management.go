@@ -1,20 +1,23 @@
 package element
 
 import (
+	"crypto/rsa"
 	"fmt"
-	"reflect"
+	"math/big"
 
 	"github.com/mitch000001/go-hbci/domain"
 )
 
-// NewPublicKey creates a new PublicKeyElement from pubKey
+// NewPublicKey creates a new PublicKeyElement from pubKey. The operation
+// mode is chosen by the key type as used by RDH-10 and RAH-10.
 func NewPublicKey(pubKey *domain.PublicKey) *PublicKeyDataElement {
-	if !reflect.DeepEqual(pubKey.Exponent, []byte("65537")) {
-		panic(fmt.Errorf("Exponent must equal 65537 (% X)", "65537"))
+	usage, operationMode := "6", "19"
+	if pubKey.Type == string(domain.KeyTypeEncryption) {
+		usage, operationMode = "5", "18"
 	}
 	p := &PublicKeyDataElement{
-		Usage:         NewAlphaNumeric(pubKey.Type, 3),
-		OperationMode: NewAlphaNumeric("16", 3),
+		Usage:         NewAlphaNumeric(usage, 3),
+		OperationMode: NewAlphaNumeric(operationMode, 3),
 		Cipher:        NewAlphaNumeric("10", 3),
 		Modulus:       NewBinary(pubKey.Modulus, 512),
 		ModulusID:     NewAlphaNumeric("12", 3),
@@ -30,18 +33,20 @@ type PublicKeyDataElement struct {
 	DataElement
 	// "5" for OCF, Owner Ciphering (Encryption key)
 	// "6" for OSG, Owner Signing (Signing key)
-	Usage *AlphaNumericDataElement
+	Usage *AlphaNumericDataElement `hbci:"required"`
 	// "16" for DSMR (ISO 9796)
-	OperationMode *AlphaNumericDataElement
+	// "18" for RSAES-PKCS#1 v1.5 (encryption key of RDH-10, RAH-10)
+	// "19" for RSASSA-PSS (signing key of RDH-10, RAH-10)
+	OperationMode *AlphaNumericDataElement `hbci:"required"`
 	// "10" for RSA
-	Cipher  *AlphaNumericDataElement
-	Modulus *BinaryDataElement
+	Cipher  *AlphaNumericDataElement `hbci:"required"`
+	Modulus *BinaryDataElement       `hbci:"required"`
 	// "12" for MOD, Modulus
-	ModulusID *AlphaNumericDataElement
-	// "65537"
-	Exponent *BinaryDataElement
+	ModulusID *AlphaNumericDataElement `hbci:"required"`
+	// usually 65537
+	Exponent *BinaryDataElement `hbci:"required"`
 	// "13" for EXP, Exponent
-	ExponentID *AlphaNumericDataElement
+	ExponentID *AlphaNumericDataElement `hbci:"required"`
 }
 
 // GroupDataElements returns the grouped DataElements
@@ -59,9 +64,55 @@ func (p *PublicKeyDataElement) GroupDataElements() []DataElement {
 
 // Val returns the public key
 func (p *PublicKeyDataElement) Val() *domain.PublicKey {
-	return &domain.PublicKey{
-		Type:     p.Usage.Val(),
-		Modulus:  p.Modulus.Val(),
-		Exponent: p.Exponent.Val(),
+	keyType := domain.KeyTypeSigning
+	if p.Usage != nil && p.Usage.Val() == "5" {
+		keyType = domain.KeyTypeEncryption
 	}
+	return domain.NewPublicKey(keyType, &rsa.PublicKey{
+		N: new(big.Int).SetBytes(p.Modulus.Val()),
+		E: int(new(big.Int).SetBytes(p.Exponent.Val()).Int64()),
+	})
+}
+
+// UnmarshalHBCI unmarshals value into the DataElement
+func (p *PublicKeyDataElement) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) < 6 {
+		return fmt.Errorf("Malformed marshaled value")
+	}
+	p.DataElement = NewDataElementGroup(publicKeyDEG, 7, p)
+	p.Usage = &AlphaNumericDataElement{}
+	if err := p.Usage.UnmarshalHBCI(elements[0]); err != nil {
+		return err
+	}
+	p.OperationMode = &AlphaNumericDataElement{}
+	if err := p.OperationMode.UnmarshalHBCI(elements[1]); err != nil {
+		return err
+	}
+	p.Cipher = &AlphaNumericDataElement{}
+	if err := p.Cipher.UnmarshalHBCI(elements[2]); err != nil {
+		return err
+	}
+	p.Modulus = &BinaryDataElement{}
+	if err := p.Modulus.UnmarshalHBCI(elements[3]); err != nil {
+		return err
+	}
+	p.ModulusID = &AlphaNumericDataElement{}
+	if err := p.ModulusID.UnmarshalHBCI(elements[4]); err != nil {
+		return err
+	}
+	p.Exponent = &BinaryDataElement{}
+	if err := p.Exponent.UnmarshalHBCI(elements[5]); err != nil {
+		return err
+	}
+	if len(elements) > 6 && len(elements[6]) > 0 {
+		p.ExponentID = &AlphaNumericDataElement{}
+		if err := p.ExponentID.UnmarshalHBCI(elements[6]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
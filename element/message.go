@@ -23,8 +23,8 @@ func NewReferencingMessage(dialogID string, messageNumber int) *ReferencingMessa
 // a given dialog
 type ReferencingMessageDataElement struct {
 	DataElement
-	DialogID      *IdentificationDataElement
-	MessageNumber *NumberDataElement
+	DialogID      *IdentificationDataElement `hbci:"required"`
+	MessageNumber *NumberDataElement         `hbci:"required"`
 }
 
 // Val returns the value of r as domain.ReferencingMessage
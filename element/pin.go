@@ -46,7 +46,7 @@ func NewPinTan(pin, tan string) *PinTanDataElement {
 // TAN for a transaction
 type PinTanDataElement struct {
 	DataElement
-	PIN *AlphaNumericDataElement
+	PIN *AlphaNumericDataElement `hbci:"required"`
 	TAN *AlphaNumericDataElement
 }
 
@@ -58,6 +58,48 @@ func (p *PinTanDataElement) GroupDataElements() []DataElement {
 	}
 }
 
+// UnmarshalHBCI unmarshals value into p
+func (p *PinTanDataElement) UnmarshalHBCI(value []byte) error {
+	pin, tan, err := unmarshalPinTan(value)
+	if err != nil {
+		return fmt.Errorf("%T: %v", p, err)
+	}
+	*p = *NewPinTan(pin, tan)
+	return nil
+}
+
+// UnmarshalHBCI unmarshals value into c
+func (c *CustomSignatureDataElement) UnmarshalHBCI(value []byte) error {
+	pin, tan, err := unmarshalPinTan(value)
+	if err != nil {
+		return fmt.Errorf("%T: %v", c, err)
+	}
+	*c = *NewCustomSignature(pin, tan)
+	return nil
+}
+
+func unmarshalPinTan(value []byte) (string, string, error) {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return "", "", err
+	}
+	if len(elements) == 0 || len(elements) > 2 {
+		return "", "", fmt.Errorf("Malformed marshaled value")
+	}
+	pin := &AlphaNumericDataElement{}
+	if err := pin.UnmarshalHBCI(elements[0]); err != nil {
+		return "", "", fmt.Errorf("Malformed PIN: %v", err)
+	}
+	if len(elements) == 1 {
+		return pin.Val(), "", nil
+	}
+	tan := &AlphaNumericDataElement{}
+	if err := tan.UnmarshalHBCI(elements[1]); err != nil {
+		return "", "", fmt.Errorf("Malformed TAN: %v", err)
+	}
+	return pin.Val(), tan.Val(), nil
+}
+
 type PinTanSpecificParamDataElement struct {
 	DataElement
 	PinMinLength                 *NumberDataElement                   `yaml:"PinMinLength"`
@@ -0,0 +1,40 @@
+package element
+
+import "testing"
+
+func TestCustomSignatureDataElementUnmarshalHBCI(t *testing.T) {
+	tests := []struct {
+		marshaled string
+		pin       string
+		tan       string
+	}{
+		{"12345", "12345", ""},
+		{"12345:123456", "12345", "123456"},
+		{"pin?:with?+separators:tan", "pin:with+separators", "tan"},
+	}
+
+	for _, test := range tests {
+		element := &CustomSignatureDataElement{}
+
+		err := element.UnmarshalHBCI([]byte(test.marshaled))
+
+		if err != nil {
+			t.Logf("%q: Expected no error, got %T:%v\n", test.marshaled, err, err)
+			t.Fail()
+			continue
+		}
+		if element.PIN.Val() != test.pin {
+			t.Logf("%q: Expected PIN %q, got %q\n", test.marshaled, test.pin, element.PIN.Val())
+			t.Fail()
+		}
+		if (test.tan == "") != (element.TAN == nil) || (element.TAN != nil && element.TAN.Val() != test.tan) {
+			t.Logf("%q: Expected TAN %q, got %v\n", test.marshaled, test.tan, element.TAN)
+			t.Fail()
+		}
+		marshaled, err := element.MarshalHBCI()
+		if err != nil || string(marshaled) != test.marshaled {
+			t.Logf("%q: Expected unmarshaled value to marshal to the input, got %q (%v)\n", test.marshaled, marshaled, err)
+			t.Fail()
+		}
+	}
+}
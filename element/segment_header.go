@@ -24,9 +24,9 @@ func NewSegmentHeader(id string, position, version int) *SegmentHeader {
 // A SegmentHeader represents the metadata of a given segment such as ID or version
 type SegmentHeader struct {
 	DataElement
-	ID       *AlphaNumericDataElement
-	Position *NumberDataElement
-	Version  *NumberDataElement
+	ID       *AlphaNumericDataElement `hbci:"required"`
+	Position *NumberDataElement       `hbci:"required"`
+	Version  *NumberDataElement       `hbci:"required"`
 	Ref      *NumberDataElement
 }
 
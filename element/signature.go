@@ -1,6 +1,7 @@
 package element
 
 import (
+	"bytes"
 	"fmt"
 	"time"
 
@@ -36,7 +37,7 @@ func NewRDHSecurityIdentification(securityHolder, clientSystemID string) *Securi
 type SecurityIdentificationDataElement struct {
 	DataElement
 	// Bezeichner für Sicherheitspartei
-	SecurityHolder *AlphaNumericDataElement
+	SecurityHolder *AlphaNumericDataElement `hbci:"required"`
 	CID            *BinaryDataElement
 	ClientSystemID *IdentificationDataElement
 }
@@ -113,11 +114,40 @@ func NewSecurityDate(dateID string, date time.Time) *SecurityDateDataElement {
 // SecurityDateDataElement represents a date with a context type
 type SecurityDateDataElement struct {
 	DataElement
-	DateIdentifier *AlphaNumericDataElement
+	DateIdentifier *AlphaNumericDataElement `hbci:"required"`
 	Date           *DateDataElement
 	Time           *TimeDataElement
 }
 
+// UnmarshalHBCI unmarshals value into s
+func (s *SecurityDateDataElement) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) < 1 {
+		return fmt.Errorf("Malformed marshaled value")
+	}
+	s.DataElement = NewDataElementGroup(securityDateDEG, 3, s)
+	s.DateIdentifier = &AlphaNumericDataElement{}
+	if err := s.DateIdentifier.UnmarshalHBCI(elements[0]); err != nil {
+		return err
+	}
+	if len(elements) > 1 && len(elements[1]) > 0 {
+		s.Date = &DateDataElement{}
+		if err := s.Date.UnmarshalHBCI(elements[1]); err != nil {
+			return err
+		}
+	}
+	if len(elements) > 2 && len(elements[2]) > 0 {
+		s.Time = &TimeDataElement{}
+		if err := s.Time.UnmarshalHBCI(elements[2]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GroupDataElements returns the grouped DataElements
 func (s *SecurityDateDataElement) GroupDataElements() []DataElement {
 	return []DataElement{
@@ -139,15 +169,28 @@ func NewDefaultHashAlgorithm() *HashAlgorithmDataElement {
 	return h
 }
 
+// NewHashAlgorithm creates a HashAlgorithmDataElement for the hash algorithm
+// with the given code
+func NewHashAlgorithm(algorithm string) *HashAlgorithmDataElement {
+	h := &HashAlgorithmDataElement{
+		Usage:            NewAlphaNumeric("1", 3),
+		Algorithm:        NewAlphaNumeric(algorithm, 3),
+		AlgorithmParamID: NewAlphaNumeric("1", 3),
+	}
+	h.DataElement = NewDataElementGroup(hashAlgorithmDEG, 4, h)
+	return h
+}
+
 // HashAlgorithmDataElement defines a hash algorithm
 type HashAlgorithmDataElement struct {
 	DataElement
 	// "1" for OHA, Owner Hashing
-	Usage *AlphaNumericDataElement
+	Usage *AlphaNumericDataElement `hbci:"required"`
+	// "3" for SHA-256
 	// "999" for ZZZ (RIPEMD-160)
-	Algorithm *AlphaNumericDataElement
+	Algorithm *AlphaNumericDataElement `hbci:"required"`
 	// "1" for IVC, Initialization value, clear text
-	AlgorithmParamID *AlphaNumericDataElement
+	AlgorithmParamID *AlphaNumericDataElement `hbci:"required"`
 	// may not be used in versions 2.20 and below
 	AlgorithmParamValue *BinaryDataElement
 }
@@ -173,17 +216,30 @@ func NewRDHSignatureAlgorithm() *SignatureAlgorithmDataElement {
 	return s
 }
 
+// NewRSASignatureAlgorithm creates a SignatureAlgorithm for RSA signatures
+// with the given operation mode
+func NewRSASignatureAlgorithm(operationMode string) *SignatureAlgorithmDataElement {
+	s := &SignatureAlgorithmDataElement{
+		Usage:         NewAlphaNumeric("6", 3),
+		Algorithm:     NewAlphaNumeric("10", 3),
+		OperationMode: NewAlphaNumeric(operationMode, 3),
+	}
+	s.DataElement = NewDataElementGroup(signatureAlgorithmDEG, 3, s)
+	return s
+}
+
 // A SignatureAlgorithmDataElement represents a signature algorithm
 type SignatureAlgorithmDataElement struct {
 	DataElement
 	// "1" for OSG, Owner Signing
-	Usage *AlphaNumericDataElement
+	Usage *AlphaNumericDataElement `hbci:"required"`
 	// "1" for DES (DDV)
 	// "10" for RSA (RDH)
-	Algorithm *AlphaNumericDataElement
+	Algorithm *AlphaNumericDataElement `hbci:"required"`
 	// "16" for DSMR, Digital Signature Scheme giving Message Recovery: ISO 9796 (RDH)
+	// "19" for RSASSA-PSS (RDH-10, RAH-10)
 	// "999" for ZZZ (DDV)
-	OperationMode *AlphaNumericDataElement
+	OperationMode *AlphaNumericDataElement `hbci:"required"`
 }
 
 // GroupDataElements returns the grouped DataElements
@@ -211,13 +267,13 @@ func NewKeyName(keyName domain.KeyName) *KeyNameDataElement {
 // KeyNameDataElement represents metadata for keys
 type KeyNameDataElement struct {
 	DataElement
-	Bank   *BankIdentificationDataElement
-	UserID *IdentificationDataElement
+	Bank   *BankIdentificationDataElement `hbci:"required"`
+	UserID *IdentificationDataElement     `hbci:"required"`
 	// "S" for Signing key
 	// "V" for Encryption key
-	KeyType    *AlphaNumericDataElement
-	KeyNumber  *NumberDataElement
-	KeyVersion *NumberDataElement
+	KeyType    *AlphaNumericDataElement `hbci:"required"`
+	KeyNumber  *NumberDataElement       `hbci:"required"`
+	KeyVersion *NumberDataElement       `hbci:"required"`
 }
 
 // Val returns the KeyName as domain.KeyName
@@ -233,6 +289,36 @@ func (k *KeyNameDataElement) Val() domain.KeyName {
 	}
 }
 
+// UnmarshalHBCI unmarshals value into the DataElement
+func (k *KeyNameDataElement) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) < 6 {
+		return fmt.Errorf("Malformed marshaled value")
+	}
+	k.DataElement = NewDataElementGroup(keyNameDEG, 5, k)
+	k.Bank = &BankIdentificationDataElement{}
+	if err := k.Bank.UnmarshalHBCI(bytes.Join(elements[0:2], []byte(":"))); err != nil {
+		return err
+	}
+	k.UserID = &IdentificationDataElement{}
+	if err := k.UserID.UnmarshalHBCI(elements[2]); err != nil {
+		return err
+	}
+	k.KeyType = &AlphaNumericDataElement{}
+	if err := k.KeyType.UnmarshalHBCI(elements[3]); err != nil {
+		return err
+	}
+	k.KeyNumber = &NumberDataElement{}
+	if err := k.KeyNumber.UnmarshalHBCI(elements[4]); err != nil {
+		return err
+	}
+	k.KeyVersion = &NumberDataElement{}
+	return k.KeyVersion.UnmarshalHBCI(elements[5])
+}
+
 // GroupDataElements returns the grouped DataElements
 func (k *KeyNameDataElement) GroupDataElements() []DataElement {
 	return []DataElement{
@@ -260,8 +346,26 @@ type CertificateDataElement struct {
 	// "1" for ZKA
 	// "2" for UN/EDIFACT
 	// "3" for X.509
-	CertificateType *NumberDataElement
-	Content         *BinaryDataElement
+	CertificateType *NumberDataElement `hbci:"required"`
+	Content         *BinaryDataElement `hbci:"required"`
+}
+
+// UnmarshalHBCI unmarshals value into the DataElement
+func (c *CertificateDataElement) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) < 2 {
+		return fmt.Errorf("Malformed marshaled value")
+	}
+	c.DataElement = NewDataElementGroup(certificateDEG, 2, c)
+	c.CertificateType = &NumberDataElement{}
+	if err := c.CertificateType.UnmarshalHBCI(elements[0]); err != nil {
+		return err
+	}
+	c.Content = &BinaryDataElement{}
+	return c.Content.UnmarshalHBCI(elements[1])
 }
 
 // GroupDataElements returns the grouped DataElements
@@ -13,16 +13,26 @@ func NewPinTanSecurityProfile(securityMethod int) *SecurityProfileDataElement {
 	return s
 }
 
+// NewSecurityProfile returns a new SecurityProfile for the provided
+// securityMethod, e.g. "RDH" or "RAH", and its version
+func NewSecurityProfile(securityMethod string, version int) *SecurityProfileDataElement {
+	s := &SecurityProfileDataElement{
+		SecurityMethod:        NewAlphaNumeric(securityMethod, 3),
+		SecurityMethodVersion: NewNumber(version, 3),
+	}
+	s.DataElement = NewDataElementGroup(securityProfileDEG, 2, s)
+	return s
+}
+
 // SecurityProfileDataElement defines a security method for the dialog flow
 type SecurityProfileDataElement struct {
 	DataElement
-	SecurityMethod        *AlphaNumericDataElement
-	SecurityMethodVersion *NumberDataElement
+	SecurityMethod        *AlphaNumericDataElement `hbci:"required"`
+	SecurityMethodVersion *NumberDataElement       `hbci:"required"`
 }
 
 // UnmarshalHBCI unmarshals value into the DataElement
 func (s *SecurityProfileDataElement) UnmarshalHBCI(value []byte) error {
-	s = &SecurityProfileDataElement{}
 	elements, err := ExtractElements(value)
 	if err != nil {
 		return err
@@ -30,7 +40,7 @@ func (s *SecurityProfileDataElement) UnmarshalHBCI(value []byte) error {
 	if len(elements) < 2 {
 		return fmt.Errorf("Malformed marshaled value")
 	}
-	s.DataElement = NewDataElementGroup(securityProfileDEG, 5, s)
+	s.DataElement = NewDataElementGroup(securityProfileDEG, 2, s)
 	s.SecurityMethod = &AlphaNumericDataElement{}
 	err = s.SecurityMethod.UnmarshalHBCI(elements[0])
 	if err != nil {
@@ -3,6 +3,7 @@ package element
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/mitch000001/go-hbci/domain"
 )
@@ -33,13 +34,46 @@ type AccountLimitDataElement struct {
 	Days   *NumberDataElement
 }
 
+// UnmarshalHBCI unmarshals value into a
+func (a *AccountLimitDataElement) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) < 3 {
+		return fmt.Errorf("Malformed marshaled value")
+	}
+	a.DataElement = NewDataElementGroup(accountLimitDEG, 3, a)
+	a.Kind = &AlphaNumericDataElement{}
+	err = a.Kind.UnmarshalHBCI(elements[0])
+	if err != nil {
+		return err
+	}
+	a.Amount = &AmountDataElement{}
+	err = a.Amount.UnmarshalHBCI(bytes.Join(elements[1:3], []byte(":")))
+	if err != nil {
+		return err
+	}
+	if len(elements) > 3 && len(elements[3]) > 0 {
+		a.Days = &NumberDataElement{}
+		err = a.Days.UnmarshalHBCI(elements[3])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Val returns the limit as domain.AccountLimit
 func (a *AccountLimitDataElement) Val() domain.AccountLimit {
-	return domain.AccountLimit{
+	limit := domain.AccountLimit{
 		Kind:   a.Kind.Val(),
 		Amount: a.Amount.Val(),
-		Days:   a.Days.Val(),
 	}
+	if a.Days != nil {
+		limit.Days = a.Days.Val()
+	}
+	return limit
 }
 
 // GroupDataElements returns the grouped DataElements
@@ -88,6 +122,28 @@ func (a *AllowedBusinessTransactionsDataElement) UnmarshalHBCI(value []byte) err
 	return nil
 }
 
+// MarshalHBCI marshals the transactions separated by '+', as they are
+// repeated on segment level.
+func (a *AllowedBusinessTransactionsDataElement) MarshalHBCI() ([]byte, error) {
+	marshaled := make([][]byte, len(a.array))
+	for i, de := range a.array {
+		transaction, err := de.MarshalHBCI()
+		if err != nil {
+			return nil, err
+		}
+		marshaled[i] = transaction
+	}
+	return bytes.Join(marshaled, []byte("+")), nil
+}
+
+func (a *AllowedBusinessTransactionsDataElement) String() string {
+	transactions := make([]string, len(a.array))
+	for i, de := range a.array {
+		transactions[i] = de.String()
+	}
+	return strings.Join(transactions, "+")
+}
+
 // AllowedBusinessTransactions returns all allowed business transactions
 func (a *AllowedBusinessTransactionsDataElement) AllowedBusinessTransactions() []domain.BusinessTransaction {
 	businessTransactions := make([]domain.BusinessTransaction, len(a.array))
@@ -157,16 +213,17 @@ func (a *AllowedBusinessTransactionDataElement) UnmarshalHBCI(value []byte) erro
 			return err
 		}
 	}
-	if len(elements) > 3 && len(elements[3]) > 0 {
+	// the amount is a group of value and currency
+	if len(elements) > 4 && len(elements[3]) > 0 {
 		a.Amount = &AmountDataElement{}
-		err = a.Amount.UnmarshalHBCI(elements[3])
+		err = a.Amount.UnmarshalHBCI(bytes.Join(elements[3:5], []byte(":")))
 		if err != nil {
 			return err
 		}
 	}
-	if len(elements) > 4 && len(elements[4]) > 0 {
+	if len(elements) > 5 && len(elements[5]) > 0 {
 		a.Days = &NumberDataElement{}
-		err = a.Days.UnmarshalHBCI(elements[4])
+		err = a.Days.UnmarshalHBCI(elements[5])
 		if err != nil {
 			return err
 		}
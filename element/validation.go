@@ -0,0 +1,373 @@
+package element
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A Rule names a format rule of HBCI data elements
+type Rule string
+
+const (
+	// RuleRequired is violated by a missing required data element
+	RuleRequired Rule = "required"
+	// RuleMaxLength is violated by values exceeding the maximum length
+	RuleMaxLength Rule = "max length"
+	// RuleCharacterSet is violated by characters outside of ISO 8859-1 and
+	// by control characters. Only text elements may contain CR and LF.
+	RuleCharacterSet Rule = "character set"
+	// RuleNumberFormat is violated by negative numbers and digits
+	RuleNumberFormat Rule = "number format"
+	// RuleAmountFormat is violated by negative or infinite amounts
+	RuleAmountFormat Rule = "amount format"
+	// RuleDateFormat is violated by unset dates and times
+	RuleDateFormat Rule = "date format"
+	// RuleCurrency is violated by currencies not in ISO 4217 alpha-3 format
+	RuleCurrency Rule = "currency"
+	// RuleCode is violated by codes not within the valid set
+	RuleCode Rule = "code"
+	// RuleOccurrence is violated by repeated elements occurring too few or too
+	// many times
+	RuleOccurrence Rule = "occurrence"
+)
+
+// A ValidationError describes a data element violating a format rule
+type ValidationError struct {
+	// Path names the data element by the fields leading to it, e.g.
+	// Account.AccountID. It is empty if the invalid element was validated
+	// directly.
+	Path string
+	// Position is the position of the data element, starting at 1.
+	// Positions within groups are separated by colons, e.g. 3:1.
+	Position string
+	Rule     Rule
+	// Message describes the violation in detail
+	Message string
+}
+
+func (v *ValidationError) Error() string {
+	var location []string
+	if v.Position != "" {
+		location = append(location, v.Position)
+	}
+	if v.Path != "" {
+		location = append(location, v.Path)
+	}
+	if len(location) == 0 {
+		return fmt.Sprintf("invalid data element: %s: %s", v.Rule, v.Message)
+	}
+	return fmt.Sprintf("invalid data element %s: %s: %s", strings.Join(location, " "), v.Rule, v.Message)
+}
+
+// Validate checks elem and all its grouped elements against the format rules
+// of their types. It returns a *ValidationError for the first violation
+// found.
+func Validate(elem DataElement) error {
+	if err := validate(elem, "", ""); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidateGroup validates elements as the elements of group, which is the
+// struct declaring them as fields. The field names are used as path within
+// errors and fields tagged with `hbci:"required"` must not be nil. The
+// positions of the elements start after offset.
+func ValidateGroup(group interface{}, elements []DataElement, offset int) error {
+	if err := validateGroup(group, elements, "", "", offset); err != nil {
+		return err
+	}
+	return nil
+}
+
+func validate(elem DataElement, path, position string) *ValidationError {
+	if isNil(elem) {
+		return nil
+	}
+	violation := func(rule Rule, format string, args ...interface{}) *ValidationError {
+		return &ValidationError{Path: path, Position: position, Rule: rule, Message: fmt.Sprintf(format, args...)}
+	}
+	switch e := elem.(type) {
+	case *CodeDataElement:
+		if err := validate(e.AlphaNumericDataElement, path, position); err != nil {
+			return err
+		}
+		if len(e.validSet) != 0 {
+			i := sort.SearchStrings(e.validSet, e.Val())
+			if i >= len(e.validSet) || e.validSet[i] != e.Val() {
+				return violation(RuleCode, "%q is not one of %v", e.Val(), e.validSet)
+			}
+		}
+	case *CurrencyDataElement:
+		if len(e.Val()) != 3 || strings.ToUpper(e.Val()) != e.Val() || !isLetters(e.Val()) {
+			return violation(RuleCurrency, "%q is no ISO 4217 currency code", e.Val())
+		}
+	case *IdentificationDataElement:
+		return validate(e.AlphaNumericDataElement, path, position)
+	case *AlphaNumericDataElement:
+		if r, ok := invalidRune(e.Val(), false); ok {
+			return violation(RuleCharacterSet, "character %q is not allowed", r)
+		}
+		return validateLength(e.basicDataElement, len([]rune(e.Val())), violation)
+	case *TextDataElement:
+		if r, ok := invalidRune(e.Val(), true); ok {
+			return violation(RuleCharacterSet, "character %q is not allowed", r)
+		}
+		return validateLength(e.basicDataElement, len([]rune(e.Val())), violation)
+	case *CountryCodeDataElement:
+		return validate(e.DigitDataElement, path, position)
+	case *DigitDataElement:
+		if e.Val() < 0 {
+			return violation(RuleNumberFormat, "%d is negative", e.Val())
+		}
+		return validateLength(e.basicDataElement, len(strconv.Itoa(e.Val())), violation)
+	case *VirtualDateDataElement:
+		return validate(e.NumberDataElement, path, position)
+	case *NumberDataElement:
+		if e.Val() < 0 {
+			return violation(RuleNumberFormat, "%d is negative", e.Val())
+		}
+		return validateLength(e.basicDataElement, len(strconv.Itoa(e.Val())), violation)
+	case *ValueDataElement:
+		return validate(e.FloatDataElement, path, position)
+	case *FloatDataElement:
+		if math.IsNaN(e.Val()) || math.IsInf(e.Val(), 0) || e.Val() < 0 {
+			return violation(RuleAmountFormat, "%v is no positive finite amount", e.Val())
+		}
+		return validateLength(e.basicDataElement, len(e.String()), violation)
+	case *DtausCharsetDataElement:
+		return validate(e.BinaryDataElement, path, position)
+	case *BinaryDataElement:
+		return validateLength(e.basicDataElement, len(e.Val()), violation)
+	case *DateDataElement:
+		if e.Val().IsZero() {
+			return violation(RuleDateFormat, "date is not set")
+		}
+	case *TimeDataElement:
+		if e.Val().IsZero() {
+			return violation(RuleDateFormat, "time is not set")
+		}
+	case *BooleanDataElement:
+	default:
+		return validateComposite(elem, path, position)
+	}
+	return nil
+}
+
+// validateComposite validates groups and types wrapping another DataElement
+func validateComposite(elem DataElement, path, position string) *ValidationError {
+	if occurrence, ok := elem.(interface{ occurrence() (int, int, int) }); ok {
+		count, min, max := occurrence.occurrence()
+		if count < min || count > max {
+			return &ValidationError{
+				Path: path, Position: position, Rule: RuleOccurrence,
+				Message: fmt.Sprintf("%d elements are not within %d and %d", count, min, max),
+			}
+		}
+	}
+	switch group := elem.(type) {
+	case DataElementGroup:
+		return validateGroup(group, group.GroupDataElements(), path, position, 0)
+	case GroupDataElementGroup:
+		return validateGroup(group, group.Elements(), path, position, 0)
+	case *elementGroup:
+		return validateGroup(group, group.elements(), path, position, 0)
+	}
+	if wrapped, ok := embeddedDataElement(elem); ok {
+		return validate(wrapped, path, position)
+	}
+	return nil
+}
+
+func validateGroup(group interface{}, elements []DataElement, path, position string, offset int) *ValidationError {
+	fields := dataElementFields(group)
+	names := make(map[DataElement]string)
+	for _, field := range fields {
+		if isPtr(field.elem) {
+			names[field.elem] = field.name
+		}
+	}
+	for _, field := range fields {
+		if !field.required || !isNil(field.elem) {
+			continue
+		}
+		fieldPosition := ""
+		if len(fields) == len(elements) {
+			fieldPosition = joinPosition(position, strconv.Itoa(offset+field.index+1))
+		}
+		return &ValidationError{
+			Path:     joinPath(path, field.name),
+			Position: fieldPosition,
+			Rule:     RuleRequired,
+			Message:  "element is missing",
+		}
+	}
+	for i, elem := range elements {
+		if isNil(elem) {
+			continue
+		}
+		name := fmt.Sprintf("[%d]", i)
+		if isPtr(elem) {
+			if fieldName, ok := names[elem]; ok {
+				name = fieldName
+			}
+		}
+		if err := validate(elem, joinPath(path, name), joinPosition(position, strconv.Itoa(offset+i+1))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type dataElementField struct {
+	name     string
+	index    int
+	elem     DataElement
+	required bool
+}
+
+var dataElementType = reflect.TypeOf((*DataElement)(nil)).Elem()
+
+// structInfo describes the DataElement fields of a struct type
+type structInfo struct {
+	fields []fieldInfo
+	// embedded is the index of the first embedded DataElement field, or -1
+	embedded int
+}
+
+type fieldInfo struct {
+	name     string
+	index    int
+	required bool
+}
+
+// structInfos caches the structInfo per struct type, so the fields and tags
+// of a type are inspected only once
+var structInfos sync.Map
+
+func structInfoOf(typ reflect.Type) *structInfo {
+	if info, ok := structInfos.Load(typ); ok {
+		return info.(*structInfo)
+	}
+	info := &structInfo{embedded: -1}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.Type.Implements(dataElementType) {
+			continue
+		}
+		if field.Anonymous {
+			if info.embedded < 0 {
+				info.embedded = i
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		info.fields = append(info.fields, fieldInfo{
+			name:     field.Name,
+			index:    i,
+			required: field.Tag.Get("hbci") == "required",
+		})
+	}
+	cached, _ := structInfos.LoadOrStore(typ, info)
+	return cached.(*structInfo)
+}
+
+// dataElementFields returns the named DataElement fields of the struct v
+// points to
+func dataElementFields(v interface{}) []dataElementField {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	val = val.Elem()
+	info := structInfoOf(val.Type())
+	fields := make([]dataElementField, len(info.fields))
+	for i, field := range info.fields {
+		elem, _ := val.Field(field.index).Interface().(DataElement)
+		fields[i] = dataElementField{
+			name:     field.name,
+			index:    i,
+			elem:     elem,
+			required: field.required,
+		}
+	}
+	return fields
+}
+
+// embeddedDataElement returns the DataElement embedded by elem, if any
+func embeddedDataElement(elem DataElement) (DataElement, bool) {
+	val := reflect.ValueOf(elem)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
+		return nil, false
+	}
+	val = val.Elem()
+	info := structInfoOf(val.Type())
+	if info.embedded < 0 {
+		return nil, false
+	}
+	wrapped, ok := val.Field(info.embedded).Interface().(DataElement)
+	return wrapped, ok && !isNil(wrapped)
+}
+
+func validateLength(basic *basicDataElement, length int, violation func(Rule, string, ...interface{}) *ValidationError) *ValidationError {
+	if basic.maxLength > 0 && length > basic.maxLength {
+		return violation(RuleMaxLength, "length %d exceeds the maximum of %d", length, basic.maxLength)
+	}
+	return nil
+}
+
+// invalidRune returns the first rune of value which is not allowed within
+// alphanumeric elements, or text elements if text is true
+func invalidRune(value string, text bool) (rune, bool) {
+	for _, r := range value {
+		if text && (r == '\r' || r == '\n') {
+			continue
+		}
+		if r > 0xFF || r < 0x20 || (r >= 0x7F && r <= 0x9F) {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+func isLetters(value string) bool {
+	for _, r := range value {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isNil(elem DataElement) bool {
+	if elem == nil {
+		return true
+	}
+	val := reflect.ValueOf(elem)
+	return val.Kind() == reflect.Ptr && val.IsNil()
+}
+
+func isPtr(elem DataElement) bool {
+	return !isNil(elem) && reflect.ValueOf(elem).Kind() == reflect.Ptr
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" || strings.HasPrefix(name, "[") {
+		return parent + name
+	}
+	return parent + "." + name
+}
+
+func joinPosition(parent, position string) string {
+	if parent == "" {
+		return position
+	}
+	return parent + ":" + position
+}
@@ -0,0 +1,64 @@
+package element
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		elem     DataElement
+		rule     Rule
+		path     string
+		position string
+	}{
+		{"valid alphanumeric", NewAlphaNumeric("Miete März", 35), "", "", ""},
+		{"too long alphanumeric", NewAlphaNumeric(strings.Repeat("a", 36), 35), RuleMaxLength, "", ""},
+		{"alphanumeric with newline", NewAlphaNumeric("a\nb", 35), RuleCharacterSet, "", ""},
+		{"alphanumeric outside ISO 8859-1", NewAlphaNumeric("€", 35), RuleCharacterSet, "", ""},
+		{"text with newline", NewText("a\r\nb", 35), "", "", ""},
+		{"negative number", NewNumber(-1, 4), RuleNumberFormat, "", ""},
+		{"too long digit", NewDigit(12345, 4), RuleMaxLength, "", ""},
+		{"infinite value", NewValue(math.Inf(1)), RuleAmountFormat, "", ""},
+		{"lower case currency", NewCurrency("eur"), RuleCurrency, "", ""},
+		{"unknown code", NewCode("3", 1, []string{"1", "2"}), RuleCode, "", ""},
+		{"negative amount", NewAmount(-5, "EUR"), RuleAmountFormat, "Amount", "1"},
+		{
+			"too long account ID",
+			NewAccountConnection(domain.AccountConnection{AccountID: strings.Repeat("1", 31), CountryCode: 280, BankID: "10000000"}),
+			RuleMaxLength, "AccountID", "1",
+		},
+		{
+			"missing bank ID",
+			&AccountConnectionDataElement{AccountID: NewIdentification("12345"), CountryCode: NewCountryCode(280)},
+			RuleRequired, "BankID", "4",
+		},
+		{"too many params", NewParams(0, 1, "a", "b"), RuleOccurrence, "", ""},
+	}
+	for _, test := range tests {
+		err := Validate(test.elem)
+
+		if test.rule == "" {
+			if err != nil {
+				t.Logf("%s: Expected no error, got %v\n", test.name, err)
+				t.Fail()
+			}
+			continue
+		}
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Logf("%s: Expected a ValidationError, got %T:%v\n", test.name, err, err)
+			t.Fail()
+			continue
+		}
+		if validationErr.Rule != test.rule || validationErr.Path != test.path || validationErr.Position != test.position {
+			t.Logf("%s: Expected rule %q at %q %q, got %q at %q %q\n", test.name, test.rule, test.position, test.path, validationErr.Rule, validationErr.Position, validationErr.Path)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package fints4
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	hbci "github.com/mitch000001/go-hbci"
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/internal"
+	"github.com/mitch000001/go-hbci/swift"
+	"github.com/mitch000001/go-hbci/transport"
+	https "github.com/mitch000001/go-hbci/transport/https"
+)
+
+// ErrDialogNotInitialized is returned when jobs are sent before the dialog got
+// initialized
+var ErrDialogNotInitialized = errors.New("fints4: dialog not initialized")
+
+// ErrInsecureURL is returned when messages should be sent to a URL not using
+// HTTPS. PIN/TAN relies on the transport to protect the PIN.
+var ErrInsecureURL = errors.New("fints4: PIN/TAN requires an HTTPS URL")
+
+const (
+	productName    = "5A624F86A785F4024DD914404"
+	productVersion = hbci.Version
+	// userTextRef is the reference of the user sent within every message
+	userTextRef = "go-hbci"
+	// languageGerman is the dialog language requested from the institute
+	languageGerman = "de"
+)
+
+// Config contains the configuration of a Dialog
+type Config struct {
+	BankID domain.BankID
+	// URL is the HTTPS URL of the institute
+	URL    string
+	UserID string
+	PIN    string
+	// Transport sends the messages to the institute. If nil, the messages are
+	// sent over HTTPS.
+	Transport transport.Transport
+	// Signer signs the messages. If nil, the one step PIN/TAN procedure is
+	// used with PIN.
+	Signer Signer
+	// Logger receives the log records of the dialog. If nil, the package
+	// wide loggers are used.
+	Logger *slog.Logger
+}
+
+// NewDialog creates a new FinTS 4.1 dialog
+func NewDialog(config Config) *Dialog {
+	d := &Dialog{
+		BankID:    config.BankID,
+		UserID:    config.UserID,
+		url:       config.URL,
+		transport: config.Transport,
+		signer:    config.Signer,
+		logger:    config.Logger,
+	}
+	if d.transport == nil {
+		d.transport = https.New()
+	}
+	if d.signer == nil {
+		d.signer = NewPinTanSigner(config.BankID, config.UserID, config.PIN)
+	}
+	if d.logger == nil {
+		d.logger = internal.Logger()
+	}
+	return d
+}
+
+// A Dialog represents a FinTS 4.1 dialog with an institute. Jobs can be sent
+// after the dialog got initialized with Init, until it is ended with End.
+type Dialog struct {
+	BankID domain.BankID
+	UserID string
+	// BankParameterDataVersion is the version of the BPD sent by the
+	// institute during the last dialog initialization.
+	BankParameterDataVersion int
+	// BankName is the name of the institute sent within the BPD.
+	BankName      string
+	url           string
+	transport     transport.Transport
+	signer        Signer
+	logger        *slog.Logger
+	userRef       string
+	bankRef       string
+	messageNumber int
+}
+
+// DialogID returns the reference the institute assigned to the current
+// dialog. It is empty if the dialog is not initialized.
+func (d *Dialog) DialogID() string {
+	return d.bankRef
+}
+
+// Init initializes the dialog
+func (d *Dialog) Init(ctx context.Context) error {
+	userRef, err := newUserRef()
+	if err != nil {
+		return fmt.Errorf("error creating user reference: %w", err)
+	}
+	d.userRef = userRef
+	d.bankRef = ""
+	d.messageNumber = 1
+	initRequest := &StandardReq{
+		InitReq: &InitReq{
+			Identification: PersonalizedIdentification{
+				BankID: NewBank(d.BankID),
+				CustID: d.UserID,
+			},
+			ProcPreparation: ProcPreparation{
+				BpdVersion:     d.BankParameterDataVersion,
+				SessionLang:    languageGerman,
+				ProductName:    productName,
+				ProductVersion: productVersion,
+			},
+		},
+	}
+	respMsg, err := d.request(ctx, initRequest, true)
+	if err != nil {
+		return err
+	}
+	if respMsg.Header.BankRef == "" {
+		return fmt.Errorf("malformed response: missing bank reference")
+	}
+	d.bankRef = respMsg.Header.BankRef
+	if initResp := respMsg.Body.StandardResp.InitResp; initResp != nil && initResp.BankParamData != nil {
+		d.BankParameterDataVersion = initResp.BankParamData.BpdVersion
+		d.BankName = initResp.BankParamData.BankName
+	}
+	return nil
+}
+
+// Send sends orders within the initialized dialog. It returns a
+// domain.AcknowledgementError if the institute reported errors.
+func (d *Dialog) Send(ctx context.Context, orders ...Order) (*RespMsg, error) {
+	if d.bankRef == "" {
+		return nil, ErrDialogNotInitialized
+	}
+	return d.request(ctx, &StandardReq{RequestList: &RequestList{Orders: Orders{Orders: orders}}}, false)
+}
+
+// End ends the dialog
+func (d *Dialog) End(ctx context.Context) error {
+	if d.bankRef == "" {
+		return ErrDialogNotInitialized
+	}
+	_, err := d.request(ctx, &StandardReq{TermSession: &Flag{}}, false)
+	d.bankRef = ""
+	return err
+}
+
+func (d *Dialog) request(ctx context.Context, standardReq *StandardReq, dialogInitialization bool) (*RespMsg, error) {
+	if err := checkURL(d.url); err != nil {
+		return nil, err
+	}
+	reqMsg := NewReqMsg(d.BankID, d.userRef, d.bankRef, d.messageNumber, standardReq)
+	if err := d.signer.Sign(reqMsg); err != nil {
+		return nil, fmt.Errorf("error signing message: %w", err)
+	}
+	marshaledMessage, err := reqMsg.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling message: %w", err)
+	}
+	request := &transport.Request{
+		URL:  d.url,
+		Body: ioutil.NopCloser(bytes.NewReader(marshaledMessage)),
+		Metadata: transport.RequestMetadata{
+			Jobs:                 reqMsg.Jobs(),
+			DialogInitialization: dialogInitialization,
+		},
+	}
+	response, err := transport.DoContext(ctx, d.transport, request)
+	if err != nil {
+		return nil, fmt.Errorf("error executing Transport request: %w", err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response from Transport: %w", err)
+	}
+	if response.StatusCode != 0 && response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", response.StatusCode, body)
+	}
+	d.messageNumber++
+	respMsg, err := ParseRespMsg(body)
+	if err != nil {
+		return nil, err
+	}
+	d.logger.Debug("Received response", slog.String("dialog_id", respMsg.Header.BankRef), slog.Int("message_number", respMsg.Header.MsgNo))
+	if err := d.checkAcknowledgements(respMsg.Acknowledgements()); err != nil {
+		return nil, err
+	}
+	return respMsg, nil
+}
+
+// checkURL returns ErrInsecureURL if rawURL does not use HTTPS
+func checkURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("malformed URL: %w", err)
+	}
+	if parsed.Scheme != "https" {
+		return ErrInsecureURL
+	}
+	return nil
+}
+
+// newUserRef returns a random reference identifying a dialog
+func newUserRef() (string, error) {
+	reference := make([]byte, 12)
+	if _, err := rand.Read(reference); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(reference), nil
+}
+
+// checkAcknowledgements logs the acknowledgements and returns a
+// domain.AcknowledgementError if the institute returned errors
+func (d *Dialog) checkAcknowledgements(acknowledgements []domain.Acknowledgement) error {
+	failed := false
+	for _, ack := range acknowledgements {
+		switch {
+		case ack.IsError():
+			failed = true
+		case ack.IsWarning():
+			d.logger.Info(ack.String())
+		case ack.IsSuccess():
+			d.logger.Debug(ack.String())
+		}
+	}
+	if failed {
+		return domain.NewAcknowledgementError(acknowledgements)
+	}
+	return nil
+}
+
+// run executes fn within a new dialog
+func (d *Dialog) run(ctx context.Context, fn func() error) error {
+	if err := d.Init(ctx); err != nil {
+		return fmt.Errorf("error initializing dialog: %w", err)
+	}
+	err := fn()
+	endErr := d.End(ctx)
+	if err != nil {
+		return err
+	}
+	if endErr != nil {
+		return fmt.Errorf("error ending dialog: %w", endErr)
+	}
+	return nil
+}
+
+// pagedRequest is a job whose response might be split into several pages
+type pagedRequest interface {
+	Order
+	SetContinuationReference(string)
+}
+
+// pages sends request within the dialog, starting at continuationReference,
+// until the institute sends no further continuation reference and passes
+// every response to handle.
+func (d *Dialog) pages(ctx context.Context, request pagedRequest, continuationReference string, handle func(*RespMsg) error) error {
+	pages := internal.NewContinuationIterator(continuationReference, func(reference string) (*RespMsg, string, error) {
+		request.SetContinuationReference(reference)
+		respMsg, err := d.Send(ctx, request)
+		if err != nil {
+			return nil, "", err
+		}
+		return respMsg, nextContinuationReference(respMsg), nil
+	})
+	for pages.Next() {
+		if err := handle(pages.Page()); err != nil {
+			return err
+		}
+	}
+	return pages.Err()
+}
+
+// nextContinuationReference returns the scroll reference to the next page
+// sent within respMsg, if any
+func nextContinuationReference(respMsg *RespMsg) string {
+	for _, ack := range respMsg.Acknowledgements() {
+		if ack.Code == element.AcknowledgementAdditionalInformation && len(ack.Params) > 0 {
+			return ack.Params[0]
+		}
+	}
+	return ""
+}
+
+// AccountBalances retrieves the balance for account within a new dialog. If
+// allAccounts is true it also fetches the balances of all other accounts of
+// the user.
+func (d *Dialog) AccountBalances(ctx context.Context, account domain.AccountConnection, allAccounts bool) ([]domain.AccountBalance, error) {
+	var balances []domain.AccountBalance
+	err := d.run(ctx, func() error {
+		return d.pages(ctx, NewAcctBalReq(account, allAccounts), "", func(respMsg *RespMsg) error {
+			found := false
+			for _, response := range respMsg.Responses() {
+				if response.AcctBal == nil {
+					continue
+				}
+				found = true
+				for _, record := range response.AcctBal.RespRecs {
+					balance, err := record.AccountBalance()
+					if err != nil {
+						return fmt.Errorf("error while parsing account balance: %w", err)
+					}
+					balances = append(balances, balance)
+				}
+			}
+			if !found {
+				return fmt.Errorf("malformed response: expected AcctBal_2_Resp")
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
+
+// AccountTransactions retrieves the booked transactions of account within
+// timeframe within a new dialog. If allAccounts is true it also fetches the
+// transactions of all other accounts of the user. All pages are fetched,
+// starting at continuationReference if it is not empty.
+func (d *Dialog) AccountTransactions(ctx context.Context, account domain.AccountConnection, timeframe domain.Timeframe, allAccounts bool, continuationReference string) ([]domain.AccountTransaction, error) {
+	var bookedSwiftTransactions []*swift.MT940Messages
+	err := d.run(ctx, func() error {
+		return d.pages(ctx, NewAcctMvmtsSpecifiedPeriodReq(account, timeframe, allAccounts), continuationReference, func(respMsg *RespMsg) error {
+			for _, response := range respMsg.Responses() {
+				if response.AcctMvmts == nil {
+					continue
+				}
+				for _, record := range response.AcctMvmts.RespRecs {
+					booked, err := record.BookedTransactions()
+					if err != nil {
+						return err
+					}
+					bookedSwiftTransactions = append(bookedSwiftTransactions, booked)
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	unmarshaler := swift.NewMT940MessagesUnmarshaler()
+	tx, err := unmarshaler.UnmarshalMT940(swift.MergeMT940Messages(bookedSwiftTransactions...).Data)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling SWIFT transactions: %w", err)
+	}
+	return tx, nil
+}
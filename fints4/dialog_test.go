@@ -0,0 +1,188 @@
+package fints4
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/transport"
+)
+
+type mockTransport struct {
+	requests  []*transport.Request
+	bodies    [][]byte
+	responses [][]byte
+}
+
+func (m *mockTransport) Do(request *transport.Request) (*transport.Response, error) {
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return nil, err
+	}
+	m.requests = append(m.requests, request)
+	m.bodies = append(m.bodies, body)
+	if len(m.responses) < len(m.requests) {
+		return nil, errors.New("no response left")
+	}
+	response := m.responses[len(m.requests)-1]
+	return &transport.Response{Request: request, Body: ioutil.NopCloser(bytes.NewReader(response)), StatusCode: 200}, nil
+}
+
+func newTestDialog(t *testing.T, fixtures ...string) (*Dialog, *mockTransport) {
+	mock := &mockTransport{}
+	for _, fixture := range fixtures {
+		mock.responses = append(mock.responses, readFixture(t, fixture))
+	}
+	d := NewDialog(Config{
+		BankID:    domain.BankID{CountryCode: 280, ID: "10000000"},
+		URL:       "https://bank.de/fints4",
+		UserID:    "12345",
+		PIN:       "secret",
+		Transport: mock,
+	})
+	return d, mock
+}
+
+func TestDialogAccountBalances(t *testing.T) {
+	d, mock := newTestDialog(t, "init_response.xml", "balance_response.xml", "end_response.xml")
+	account := domain.AccountConnection{AccountID: "1000000000", CountryCode: 280, BankID: "10000000"}
+
+	balances, err := d.AccountBalances(context.Background(), account, false)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(balances) != 1 || balances[0].Account != account || balances[0].ProductName != "Girokonto" {
+		t.Logf("Expected the balance of account %v, got %v\n", account, balances)
+		t.Fail()
+	}
+	if len(mock.requests) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(mock.requests))
+	}
+	expectedJobs := [][]string{{"InitReq"}, {"AcctBal_2_Req"}, {"TermSession"}}
+	for i, request := range mock.requests {
+		if !reflect.DeepEqual(request.Metadata.Jobs, expectedJobs[i]) {
+			t.Logf("Expected request %d to contain jobs %v, got %v\n", i, expectedJobs[i], request.Metadata.Jobs)
+			t.Fail()
+		}
+		if request.Metadata.DialogInitialization != (i == 0) {
+			t.Logf("Expected only the first request to initialize the dialog\n")
+			t.Fail()
+		}
+	}
+	expectedContent := [][]string{
+		{"<UserTextRef>go-hbci</UserTextRef><MsgNo>1</MsgNo></ReqMsgHeader>", "<CustID>12345</CustID></PersonalizedIdentification><ProcPreparation><SessionLang>de</SessionLang>", "<PIN>secret</PIN>"},
+		{"<BankRef>4711</BankRef><MsgNo>2</MsgNo>", "1000000000</AcctNo>", "<PIN>secret</PIN>"},
+		{"<BankRef>4711</BankRef><MsgNo>3</MsgNo>", "<StandardReq><TermSession></TermSession></StandardReq>"},
+	}
+	for i, expected := range expectedContent {
+		for _, e := range expected {
+			if !bytes.Contains(mock.bodies[i], []byte(e)) {
+				t.Logf("Expected request %d to contain %q, got\n%s\n", i, e, mock.bodies[i])
+				t.Fail()
+			}
+		}
+	}
+	userRef := regexp.MustCompile("<UserRef>([0-9a-f]+)</UserRef>")
+	for i, body := range mock.bodies {
+		if match := userRef.FindSubmatch(body); match == nil || string(match[1]) != d.userRef {
+			t.Logf("Expected request %d to reference the dialog by user reference %q, got\n%s\n", i, d.userRef, body)
+			t.Fail()
+		}
+	}
+	if d.BankParameterDataVersion != 12 || d.BankName != "Testbank & Co" {
+		t.Logf("Expected BPD of the institute to be kept, got version %d of %q\n", d.BankParameterDataVersion, d.BankName)
+		t.Fail()
+	}
+	if d.DialogID() != "" {
+		t.Logf("Expected dialog to be ended, got dialog ID %q\n", d.DialogID())
+		t.Fail()
+	}
+}
+
+func TestDialogAccountTransactions(t *testing.T) {
+	d, mock := newTestDialog(t, "init_response.xml", "statement_response_page1.xml", "statement_response_page2.xml", "end_response.xml")
+	account := domain.AccountConnection{AccountID: "1000000000", CountryCode: 280, BankID: "10000000"}
+	timeframe := domain.Timeframe{
+		StartDate: domain.NewShortDate(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:   domain.NewShortDate(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)),
+	}
+
+	transactions, err := d.AccountTransactions(context.Background(), account, timeframe, false, "")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(transactions) != 2 {
+		t.Fatalf("Expected 2 transactions, got %d", len(transactions))
+	}
+	if transactions[0].Amount.Amount != -25.5 || transactions[1].Amount.Amount != 1500 {
+		t.Logf("Expected transactions of -25.50 and 1500.00, got %v and %v\n", transactions[0].Amount, transactions[1].Amount)
+		t.Fail()
+	}
+	if len(mock.bodies) != 4 {
+		t.Fatalf("Expected 4 requests, got %d", len(mock.bodies))
+	}
+	if !bytes.Contains(mock.bodies[1], []byte("<StartDate>2024-03-01</StartDate><EndDate>2024-03-31</EndDate></AcctMvmtsSpecifiedPeriod_2_Req>")) {
+		t.Logf("Expected first page to be requested without continuation reference, got\n%s\n", mock.bodies[1])
+		t.Fail()
+	}
+	if !bytes.Contains(mock.bodies[2], []byte("<ScrollRef>cont-4711-1</ScrollRef>")) {
+		t.Logf("Expected second page to be requested with continuation reference, got\n%s\n", mock.bodies[2])
+		t.Fail()
+	}
+}
+
+func TestDialogInitError(t *testing.T) {
+	d, mock := newTestDialog(t, "pin_error_response.xml")
+	account := domain.AccountConnection{AccountID: "1000000000", CountryCode: 280, BankID: "10000000"}
+
+	_, err := d.AccountBalances(context.Background(), account, false)
+
+	var ackErr *domain.AcknowledgementError
+	if !errors.As(err, &ackErr) {
+		t.Fatalf("Expected an AcknowledgementError, got %v", err)
+	}
+	if len(ackErr.Acknowledgements) != 2 || ackErr.Acknowledgements[1].Code != 9931 {
+		t.Logf("Expected acknowledgement 9931, got %v\n", ackErr.Acknowledgements)
+		t.Fail()
+	}
+	if len(mock.requests) != 1 {
+		t.Logf("Expected no further requests after a failed initialization, got %d\n", len(mock.requests))
+		t.Fail()
+	}
+
+	_, err = d.Send(context.Background(), NewAcctBalReq(account, false))
+	if err != ErrDialogNotInitialized {
+		t.Logf("Expected ErrDialogNotInitialized, got %v\n", err)
+		t.Fail()
+	}
+}
+
+func TestDialogRefusesInsecureURL(t *testing.T) {
+	mock := &mockTransport{}
+	d := NewDialog(Config{
+		BankID:    domain.BankID{CountryCode: 280, ID: "10000000"},
+		URL:       "http://bank.de/fints4",
+		UserID:    "12345",
+		PIN:       "secret",
+		Transport: mock,
+	})
+
+	err := d.Init(context.Background())
+
+	if err != ErrInsecureURL {
+		t.Logf("Expected ErrInsecureURL, got %v\n", err)
+		t.Fail()
+	}
+	if len(mock.requests) != 0 {
+		t.Logf("Expected the PIN not to be sent, got %d requests\n", len(mock.requests))
+		t.Fail()
+	}
+}
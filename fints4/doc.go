@@ -0,0 +1,22 @@
+// Package fints4 implements the XML based message format of FinTS 4.1.
+//
+// FinTS 4.1 replaces the delimiter syntax of FinTS 3.0 with XML messages but
+// keeps the course of a dialog: the dialog gets initialized, jobs are sent
+// within it and it gets ended afterwards. The package provides the message
+// envelope, the PIN/TAN security layer, the dialog and the jobs to retrieve
+// account balances and account statements.
+//
+// The types follow the schemas of FinTS 4.1 (release 2014-01-20): the
+// envelope ReqMsg and RespMsg of messages/message.xsd, the data types of
+// types/patterns.xsd and the jobs AcctBal_2 and AcctMvmtsSpecifiedPeriod_2
+// of the transactions schemas. Only the elements needed for these jobs are
+// modelled. Elements sent by the institute which are not known to the
+// package are ignored.
+//
+// With PIN/TAN the PIN is sent within the messenger signature of every
+// message. As defined by "FinTS 4.1 Security - Sicherheitsverfahren PIN/TAN",
+// chapter II, PIN/TAN does not encrypt on the level of the FinTS protocol but
+// only on the transport level. The dialog therefore refuses to send messages
+// to URLs not using HTTPS. Messages encrypted with XML Encryption, which is
+// used by the HBCI security procedures, are not supported.
+package fints4
@@ -0,0 +1,298 @@
+package fints4
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/swift"
+)
+
+const (
+	dateFormat = "2006-01-02"
+	timeFormat = "15:04:05"
+)
+
+// Bank identifies an institute
+type Bank struct {
+	CountryCode int    `xml:"http://www.fints.org/spec/xmlschema/4.1/types CountryCode"`
+	BankCode    string `xml:"http://www.fints.org/spec/xmlschema/4.1/types BankCode,omitempty"`
+}
+
+// NewBank returns the Bank for bankID
+func NewBank(bankID domain.BankID) Bank {
+	return Bank{CountryCode: bankID.CountryCode, BankCode: bankID.ID}
+}
+
+// IntlAcct identifies an account either by IBAN and BIC or by the national
+// account number
+type IntlAcct struct {
+	IntlAcctInfo     *IntlAcctInfo     `xml:"http://www.fints.org/spec/xmlschema/4.1/types IntlAcctInfo,omitempty"`
+	NationalAcctInfo *NationalAcctInfo `xml:"http://www.fints.org/spec/xmlschema/4.1/types NationalAcctInfo,omitempty"`
+}
+
+// IntlAcctInfo identifies an account by IBAN and BIC
+type IntlAcctInfo struct {
+	IBAN string `xml:"http://www.fints.org/spec/xmlschema/4.1/types IBAN"`
+	BIC  string `xml:"http://www.fints.org/spec/xmlschema/4.1/types BIC"`
+}
+
+// NationalAcctInfo identifies an account by the national account number
+type NationalAcctInfo struct {
+	AcctNo                string `xml:"http://www.fints.org/spec/xmlschema/4.1/types AcctNo"`
+	SubAcctCharacteristic string `xml:"http://www.fints.org/spec/xmlschema/4.1/types SubAcctCharacteristic,omitempty"`
+	BankID                Bank   `xml:"http://www.fints.org/spec/xmlschema/4.1/types BankID"`
+}
+
+// NewIntlAcct returns the IntlAcct for account
+func NewIntlAcct(account domain.AccountConnection) IntlAcct {
+	return IntlAcct{
+		NationalAcctInfo: &NationalAcctInfo{
+			AcctNo:                account.AccountID,
+			SubAcctCharacteristic: account.SubAccountCharacteristics,
+			BankID:                Bank{CountryCode: account.CountryCode, BankCode: account.BankID},
+		},
+	}
+}
+
+// Val returns the account as domain.InternationalAccountConnection
+func (i IntlAcct) Val() domain.InternationalAccountConnection {
+	var account domain.InternationalAccountConnection
+	if i.IntlAcctInfo != nil {
+		account.IBAN = i.IntlAcctInfo.IBAN
+		account.BIC = i.IntlAcctInfo.BIC
+	}
+	if i.NationalAcctInfo != nil {
+		account.AccountID = i.NationalAcctInfo.AcctNo
+		account.SubAccountCharacteristics = i.NationalAcctInfo.SubAcctCharacteristic
+		account.BankID = domain.BankID{
+			CountryCode: i.NationalAcctInfo.BankID.CountryCode,
+			ID:          i.NationalAcctInfo.BankID.BankCode,
+		}
+	}
+	return account
+}
+
+// Amount is a value in a currency
+type Amount struct {
+	Value    float64 `xml:"http://www.fints.org/spec/xmlschema/4.1/types Value"`
+	Currency string  `xml:"http://www.fints.org/spec/xmlschema/4.1/types Currency"`
+}
+
+// Val returns the amount as domain.Amount
+func (a Amount) Val() domain.Amount {
+	return domain.Amount{Amount: a.Value, Currency: a.Currency}
+}
+
+// Timestamp is a date with an optional time
+type Timestamp struct {
+	Date string `xml:"http://www.fints.org/spec/xmlschema/4.1/types Date"`
+	Time string `xml:"http://www.fints.org/spec/xmlschema/4.1/types Time,omitempty"`
+}
+
+// NewTimestamp returns the Timestamp for t
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Date: t.Format(dateFormat), Time: t.Format(timeFormat)}
+}
+
+// Val returns the timestamp as time.Time
+func (t Timestamp) Val() (time.Time, error) {
+	if t.Time == "" {
+		return time.Parse(dateFormat, t.Date)
+	}
+	return time.Parse(dateFormat+"T"+timeFormat, t.Date+"T"+t.Time)
+}
+
+// Balance is the balance of an account at a date. DebitCreditFlag is either
+// C or D.
+type Balance struct {
+	DebitCreditFlag string `xml:"http://www.fints.org/spec/xmlschema/4.1/types DebitCreditFlag"`
+	Amount          Amount `xml:"http://www.fints.org/spec/xmlschema/4.1/types Amount"`
+	Date            string `xml:"http://www.fints.org/spec/xmlschema/4.1/types Date"`
+	Time            string `xml:"http://www.fints.org/spec/xmlschema/4.1/types Time,omitempty"`
+}
+
+// Val returns the balance as domain.Balance. Debit balances get a negative
+// amount.
+func (b Balance) Val() (domain.Balance, error) {
+	amount := b.Amount.Val()
+	switch b.DebitCreditFlag {
+	case "C":
+	case "D":
+		amount.Amount = -amount.Amount
+	default:
+		return domain.Balance{}, fmt.Errorf("malformed debit credit flag: %q", b.DebitCreditFlag)
+	}
+	date, err := time.Parse(dateFormat, b.Date)
+	if err != nil {
+		return domain.Balance{}, fmt.Errorf("malformed balance date: %w", err)
+	}
+	balance := domain.Balance{Amount: amount, TransmissionDate: date}
+	if b.Time != "" {
+		transmissionTime, err := Timestamp{Date: b.Date, Time: b.Time}.Val()
+		if err != nil {
+			return domain.Balance{}, fmt.Errorf("malformed balance time: %w", err)
+		}
+		balance.TransmissionTime = &transmissionTime
+	}
+	return balance, nil
+}
+
+// NewAcctBalReq returns a request for the balance of account. If allAccounts
+// is true the balances of all accounts of the user are requested.
+func NewAcctBalReq(account domain.AccountConnection, allAccounts bool) *AcctBalReq {
+	return &AcctBalReq{
+		OrderingCustIntlAcct: NewIntlAcct(account),
+		AllAcct:              allAccounts,
+	}
+}
+
+// AcctBalReq requests the balance of an account (AcctBal_2_Req)
+type AcctBalReq struct {
+	XMLName              xml.Name `xml:"http://www.fints.org/spec/xmlschema/4.1/transactions AcctBal_2_Req"`
+	OrderingCustIntlAcct IntlAcct `xml:"OrderingCustIntlAcct"`
+	AllAcct              bool     `xml:"All_Acct"`
+	MaxNoEntries         int      `xml:"MaxNo_Entries,omitempty"`
+	ScrollRef            string   `xml:"ScrollRef,omitempty"`
+}
+
+// OrderName implements Order
+func (a *AcctBalReq) OrderName() string {
+	return "AcctBal_2_Req"
+}
+
+// SetContinuationReference sets the reference to the next page of balances
+func (a *AcctBalReq) SetContinuationReference(continuationReference string) {
+	a.ScrollRef = continuationReference
+}
+
+// AcctBalResp contains the balances of accounts (AcctBal_2_Resp)
+type AcctBalResp struct {
+	RespRecs []AcctBalRespRec `xml:"RespRec"`
+}
+
+// AcctBalRespRec contains the balance of an account
+type AcctBalRespRec struct {
+	OrderingCustIntlAcct     IntlAcct   `xml:"OrderingCustIntlAcct"`
+	AcctName                 string     `xml:"AcctName"`
+	AcctCcy                  string     `xml:"AcctCcy"`
+	CurrentValutaBal         Balance    `xml:"Current_ValutaBal"`
+	IncludingPendingTransBal *Balance   `xml:"IncludingPendingTrans_Bal"`
+	OverdraftLim             *Amount    `xml:"OverdraftLim"`
+	AvailableFunds           *Amount    `xml:"AvailableFunds"`
+	AlreadyDrawnOnAmt        *Amount    `xml:"AlreadyDrawnOn_Amt"`
+	BookingTime              *Timestamp `xml:"Booking_Time"`
+	MaturityDate             string     `xml:"MaturityDate"`
+}
+
+// AccountBalance returns the balance as domain.AccountBalance
+func (r *AcctBalRespRec) AccountBalance() (domain.AccountBalance, error) {
+	bookedBalance, err := r.CurrentValutaBal.Val()
+	if err != nil {
+		return domain.AccountBalance{}, err
+	}
+	accountBalance := domain.AccountBalance{
+		Account:         r.OrderingCustIntlAcct.Val().ToAccountConnection(),
+		ProductName:     r.AcctName,
+		Currency:        r.AcctCcy,
+		BookedBalance:   bookedBalance,
+		CreditLimit:     amountVal(r.OverdraftLim),
+		AvailableAmount: amountVal(r.AvailableFunds),
+		UsedAmount:      amountVal(r.AlreadyDrawnOnAmt),
+	}
+	if r.IncludingPendingTransBal != nil {
+		pendingBalance, err := r.IncludingPendingTransBal.Val()
+		if err != nil {
+			return domain.AccountBalance{}, err
+		}
+		accountBalance.EarmarkedBalance = &pendingBalance
+	}
+	if r.BookingTime != nil {
+		bookingTime, err := r.BookingTime.Val()
+		if err != nil {
+			return domain.AccountBalance{}, fmt.Errorf("malformed booking time: %w", err)
+		}
+		accountBalance.BookingDate = &bookingTime
+	}
+	if r.MaturityDate != "" {
+		maturityDate, err := time.Parse(dateFormat, r.MaturityDate)
+		if err != nil {
+			return domain.AccountBalance{}, fmt.Errorf("malformed maturity date: %w", err)
+		}
+		accountBalance.DueDate = &maturityDate
+	}
+	return accountBalance, nil
+}
+
+// NewAcctMvmtsSpecifiedPeriodReq returns a request for the booked
+// transactions of account within timeframe. If allAccounts is true the
+// transactions of all accounts of the user are requested.
+func NewAcctMvmtsSpecifiedPeriodReq(account domain.AccountConnection, timeframe domain.Timeframe, allAccounts bool) *AcctMvmtsSpecifiedPeriodReq {
+	request := &AcctMvmtsSpecifiedPeriodReq{
+		OrderingCustIntlAcct: NewIntlAcct(account),
+		AllAcct:              allAccounts,
+	}
+	if !timeframe.StartDate.IsZero() {
+		request.StartDate = timeframe.StartDate.Format(dateFormat)
+	}
+	if !timeframe.EndDate.IsZero() {
+		request.EndDate = timeframe.EndDate.Format(dateFormat)
+	}
+	return request
+}
+
+// AcctMvmtsSpecifiedPeriodReq requests the transactions of an account within
+// a period (AcctMvmtsSpecifiedPeriod_2_Req)
+type AcctMvmtsSpecifiedPeriodReq struct {
+	XMLName              xml.Name `xml:"http://www.fints.org/spec/xmlschema/4.1/transactions AcctMvmtsSpecifiedPeriod_2_Req"`
+	OrderingCustIntlAcct IntlAcct `xml:"OrderingCustIntlAcct"`
+	AllAcct              bool     `xml:"All_Acct"`
+	StartDate            string   `xml:"StartDate,omitempty"`
+	EndDate              string   `xml:"EndDate,omitempty"`
+	MaxNoEntries         int      `xml:"MaxNo_Entries,omitempty"`
+	ScrollRef            string   `xml:"ScrollRef,omitempty"`
+}
+
+// OrderName implements Order
+func (a *AcctMvmtsSpecifiedPeriodReq) OrderName() string {
+	return "AcctMvmtsSpecifiedPeriod_2_Req"
+}
+
+// SetContinuationReference sets the reference to the next page of
+// transactions
+func (a *AcctMvmtsSpecifiedPeriodReq) SetContinuationReference(continuationReference string) {
+	a.ScrollRef = continuationReference
+}
+
+// AcctMvmtsSpecifiedPeriodResp contains the transactions of accounts
+// (AcctMvmtsSpecifiedPeriod_2_Resp)
+type AcctMvmtsSpecifiedPeriodResp struct {
+	RespRecs []AcctMvmtsSpecifiedPeriodRespRec `xml:"RespRec"`
+}
+
+// AcctMvmtsSpecifiedPeriodRespRec contains the transactions of an account as
+// Base64 encoded S.W.I.F.T. MT940 for booked and MT942 for non booked
+// transactions
+type AcctMvmtsSpecifiedPeriodRespRec struct {
+	BookedTrans    string `xml:"BookedTrans"`
+	NonbookedTrans string `xml:"NonbookedTrans"`
+}
+
+// BookedTransactions returns the booked transactions as MT940 messages
+func (r *AcctMvmtsSpecifiedPeriodRespRec) BookedTransactions() (*swift.MT940Messages, error) {
+	data, err := base64.StdEncoding.DecodeString(r.BookedTrans)
+	if err != nil {
+		return nil, fmt.Errorf("malformed booked transactions: %w", err)
+	}
+	return swift.NewMT940Messages(data), nil
+}
+
+func amountVal(amount *Amount) *domain.Amount {
+	if amount == nil {
+		return nil
+	}
+	val := amount.Val()
+	return &val
+}
@@ -0,0 +1,286 @@
+package fints4
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// The XML namespaces of FinTS 4.1
+const (
+	MessagesNamespace     = "http://www.fints.org/spec/xmlschema/4.1/messages"
+	TypesNamespace        = "http://www.fints.org/spec/xmlschema/4.1/types"
+	TransactionsNamespace = "http://www.fints.org/spec/xmlschema/4.1/transactions"
+	// EncryptionNamespace is the namespace of XML Encryption, used for
+	// encrypted message bodies
+	EncryptionNamespace = "http://www.w3.org/2001/04/xmlenc#"
+)
+
+// ErrEncryptedMessage is returned when the institute sends an encrypted
+// message body
+var ErrEncryptedMessage = errors.New("fints4: encrypted messages are not supported")
+
+// A ReqMsg is a message sent by the user. Its body is either sent in plain
+// text or encrypted as EncryptedData.
+type ReqMsg struct {
+	XMLName       xml.Name       `xml:"http://www.fints.org/spec/xmlschema/4.1/messages ReqMsg"`
+	Header        ReqMsgHeader   `xml:"ReqMsgHeader"`
+	Body          *ReqMsgBody    `xml:"ReqMsgBody,omitempty"`
+	EncryptedData *EncryptedData `xml:"http://www.w3.org/2001/04/xmlenc# EncryptedData,omitempty"`
+}
+
+// NewReqMsg returns a message of the dialog identified by userRef and
+// bankRef containing request.
+func NewReqMsg(bankID domain.BankID, userRef, bankRef string, messageNumber int, request *StandardReq) *ReqMsg {
+	return &ReqMsg{
+		Header: ReqMsgHeader{
+			BankID:      NewBank(bankID),
+			UserRef:     userRef,
+			UserTextRef: userTextRef,
+			BankRef:     bankRef,
+			MsgNo:       messageNumber,
+		},
+		Body: &ReqMsgBody{StandardReq: request},
+	}
+}
+
+// Marshal marshals m including the XML declaration
+func (m *ReqMsg) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(m); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Jobs returns the names of the parts of the standard request within m
+func (m *ReqMsg) Jobs() []string {
+	if m.Body == nil || m.Body.StandardReq == nil {
+		return nil
+	}
+	var jobs []string
+	request := m.Body.StandardReq
+	if request.InitReq != nil {
+		jobs = append(jobs, "InitReq")
+	}
+	if request.TermSession != nil {
+		jobs = append(jobs, "TermSession")
+	}
+	if request.RequestList != nil {
+		for _, order := range request.RequestList.Orders.Orders {
+			jobs = append(jobs, order.OrderName())
+		}
+	}
+	return jobs
+}
+
+// ReqMsgHeader identifies a message within a dialog. The dialog is
+// identified by the reference chosen by the user and, after the
+// initialization, by the reference assigned by the institute.
+type ReqMsgHeader struct {
+	BankID      Bank   `xml:"BankID"`
+	UserRef     string `xml:"UserRef"`
+	UserTextRef string `xml:"UserTextRef"`
+	BankRef     string `xml:"BankRef,omitempty"`
+	MsgNo       int    `xml:"MsgNo"`
+}
+
+// ReqMsgBody contains the messenger signature and the request
+type ReqMsgBody struct {
+	MessengerSig *MessengerSig `xml:"MessengerSig,omitempty"`
+	StandardReq  *StandardReq  `xml:"StandardReq,omitempty"`
+}
+
+// StandardReq is the request of a personalized dialog. The first message of a
+// dialog contains InitReq, the last one TermSession.
+type StandardReq struct {
+	InitReq     *InitReq     `xml:"InitReq,omitempty"`
+	TermSession *Flag        `xml:"TermSession,omitempty"`
+	RequestList *RequestList `xml:"RequestList,omitempty"`
+}
+
+// Flag is an element without content which is set by being present
+type Flag struct{}
+
+// InitReq initializes a personalized dialog
+type InitReq struct {
+	Identification  PersonalizedIdentification `xml:"PersonalizedIdentification"`
+	ProcPreparation ProcPreparation            `xml:"ProcPreparation"`
+}
+
+// PersonalizedIdentification identifies the customer at the institute
+type PersonalizedIdentification struct {
+	BankID Bank   `xml:"BankID"`
+	CustID string `xml:"CustID"`
+}
+
+// ProcPreparation announces the known BPD and UPD versions and the client
+// product
+type ProcPreparation struct {
+	BpdVersion     int    `xml:"BpdVersion,omitempty"`
+	UpdVersion     int    `xml:"UpdVersion,omitempty"`
+	SessionLang    string `xml:"SessionLang,omitempty"`
+	ProductName    string `xml:"ProductName"`
+	ProductVersion string `xml:"ProductVersion"`
+}
+
+// RequestList contains the orders of a message
+type RequestList struct {
+	Orders Orders `xml:"Orders"`
+}
+
+// Orders contains the jobs of a message
+type Orders struct {
+	Orders []Order `xml:",any"`
+}
+
+// An Order is a job sent within the Orders of a message. Orders define their
+// element name and namespace by their XMLName field.
+type Order interface {
+	// OrderName returns the element name of the order
+	OrderName() string
+}
+
+// EncryptedData is an encrypted message body as defined by XML Encryption.
+// It is only modelled to detect encrypted messages, which are not used with
+// PIN/TAN.
+type EncryptedData struct {
+	Type             string            `xml:"Type,attr,omitempty"`
+	EncryptionMethod *EncryptionMethod `xml:"http://www.w3.org/2001/04/xmlenc# EncryptionMethod,omitempty"`
+	CipherValue      string            `xml:"http://www.w3.org/2001/04/xmlenc# CipherData>CipherValue"`
+}
+
+// EncryptionMethod names the algorithm used to encrypt EncryptedData
+type EncryptionMethod struct {
+	Algorithm string `xml:"Algorithm,attr"`
+}
+
+// A RespMsg is a message sent by the institute
+type RespMsg struct {
+	XMLName       xml.Name       `xml:"http://www.fints.org/spec/xmlschema/4.1/messages RespMsg"`
+	Header        RespMsgHeader  `xml:"RespMsgHeader"`
+	Body          *RespMsgBody   `xml:"RespMsgBody"`
+	EncryptedData *EncryptedData `xml:"http://www.w3.org/2001/04/xmlenc# EncryptedData"`
+}
+
+// RespMsgHeader returns the references of the dialog and the number of the
+// message answered
+type RespMsgHeader struct {
+	UserRef     string `xml:"UserRef"`
+	UserTextRef string `xml:"UserTextRef"`
+	BankRef     string `xml:"BankRef"`
+	MsgNo       int    `xml:"MsgNo"`
+}
+
+// RespMsgBody contains the response of the institute
+type RespMsgBody struct {
+	StandardResp *StandardResp `xml:"StandardResp"`
+}
+
+// StandardResp is the response to a StandardReq
+type StandardResp struct {
+	MsgTotalState ResponseState   `xml:"MsgTotalState"`
+	MsgRespStates []ResponseState `xml:"MsgRespState"`
+	InitResp      *InitResp       `xml:"InitResp"`
+	TermSession   *Flag           `xml:"TermSession"`
+	ResponseLists []ResponseList  `xml:"ResponseList"`
+}
+
+// InitResp is the response to an InitReq
+type InitResp struct {
+	BankParamData *BankParamData `xml:"BankParamData"`
+}
+
+// BankParamData contains the parts of the BPD used by the dialog
+type BankParamData struct {
+	BpdVersion int    `xml:"GenericBankParam>BpdVersion"`
+	BankName   string `xml:"GenericBankParam>BankName"`
+}
+
+// ResponseList contains the responses to the orders of a RequestList
+type ResponseList struct {
+	ReqListTotalState ResponseState   `xml:"ReqListTotalState"`
+	ReqListRespStates []ResponseState `xml:"ReqListRespState"`
+	Responses         []Response      `xml:"Response"`
+}
+
+// Response is the response to an order. RequestRef is an XPath expression
+// referencing the order within the RequestList.
+type Response struct {
+	RequestRef        string                        `xml:"RequestRef"`
+	RequestRespStates []ResponseState               `xml:"RequestRespState"`
+	AcctBal           *AcctBalResp                  `xml:"http://www.fints.org/spec/xmlschema/4.1/transactions AcctBal_2_Resp"`
+	AcctMvmts         *AcctMvmtsSpecifiedPeriodResp `xml:"http://www.fints.org/spec/xmlschema/4.1/transactions AcctMvmtsSpecifiedPeriod_2_Resp"`
+}
+
+// ResponseState is a return value of the institute
+type ResponseState struct {
+	RespCode   int      `xml:"RespCode"`
+	ElementRef string   `xml:"ElementRef"`
+	RespText   string   `xml:"RespText"`
+	RespParams []string `xml:"RespParam"`
+}
+
+// ParseRespMsg parses data into a RespMsg
+func ParseRespMsg(data []byte) (*RespMsg, error) {
+	var respMsg RespMsg
+	if err := xml.Unmarshal(data, &respMsg); err != nil {
+		return nil, fmt.Errorf("error parsing FinTS 4.1 message: %w", err)
+	}
+	if respMsg.EncryptedData != nil {
+		return nil, ErrEncryptedMessage
+	}
+	if respMsg.Body == nil || respMsg.Body.StandardResp == nil {
+		return nil, fmt.Errorf("error parsing FinTS 4.1 message: missing StandardResp")
+	}
+	return &respMsg, nil
+}
+
+// Responses returns all responses within r
+func (r *RespMsg) Responses() []Response {
+	var responses []Response
+	for _, list := range r.Body.StandardResp.ResponseLists {
+		responses = append(responses, list.Responses...)
+	}
+	return responses
+}
+
+// Acknowledgements returns the message and order acknowledgements of r. The
+// states of the message and of the request lists are returned as message
+// acknowledgements, the states of the responses as segment acknowledgements
+// referencing the position of the response. The institute sends the
+// responses in the order of the orders.
+func (r *RespMsg) Acknowledgements() []domain.Acknowledgement {
+	reference := domain.MessageReference{DialogID: r.Header.BankRef, MessageNumber: r.Header.MsgNo}
+	resp := r.Body.StandardResp
+	acknowledgements := stateAcknowledgements(domain.MessageAcknowledgement, reference, 0, resp.MsgTotalState)
+	acknowledgements = append(acknowledgements, stateAcknowledgements(domain.MessageAcknowledgement, reference, 0, resp.MsgRespStates...)...)
+	for _, list := range resp.ResponseLists {
+		acknowledgements = append(acknowledgements, stateAcknowledgements(domain.MessageAcknowledgement, reference, 0, list.ReqListTotalState)...)
+		acknowledgements = append(acknowledgements, stateAcknowledgements(domain.MessageAcknowledgement, reference, 0, list.ReqListRespStates...)...)
+		for i, response := range list.Responses {
+			acknowledgements = append(acknowledgements, stateAcknowledgements(domain.SegmentAcknowledgement, reference, i+1, response.RequestRespStates...)...)
+		}
+	}
+	return acknowledgements
+}
+
+func stateAcknowledgements(ackType string, reference domain.MessageReference, position int, states ...ResponseState) []domain.Acknowledgement {
+	var acknowledgements []domain.Acknowledgement
+	for _, state := range states {
+		acknowledgements = append(acknowledgements, domain.Acknowledgement{
+			Type:                     ackType,
+			Code:                     state.RespCode,
+			ReferenceDataElement:     state.ElementRef,
+			Text:                     state.RespText,
+			Params:                   state.RespParams,
+			ReferencingMessage:       reference,
+			ReferencingSegmentNumber: position,
+		})
+	}
+	return acknowledgements
+}
@@ -0,0 +1,169 @@
+package fints4
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	data, err := ioutil.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return data
+}
+
+func TestReqMsgMarshal(t *testing.T) {
+	bankID := domain.BankID{CountryCode: 280, ID: "10000000"}
+	account := domain.AccountConnection{AccountID: "1000000000", CountryCode: 280, BankID: "10000000"}
+	request := &StandardReq{RequestList: &RequestList{Orders: Orders{Orders: []Order{NewAcctBalReq(account, false)}}}}
+	message := NewReqMsg(bankID, "c0ffee", "4711", 2, request)
+	signer := NewPinTanSigner(bankID, "12345", "secret")
+	signer.now = func() time.Time { return time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC) }
+	if err := signer.Sign(message); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	marshaled, err := message.Marshal()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{
+		xml.Header,
+		`<ReqMsg xmlns="http://www.fints.org/spec/xmlschema/4.1/messages"><ReqMsgHeader><BankID><CountryCode xmlns="http://www.fints.org/spec/xmlschema/4.1/types">280</CountryCode><BankCode xmlns="http://www.fints.org/spec/xmlschema/4.1/types">10000000</BankCode></BankID>`,
+		`<UserRef>c0ffee</UserRef><UserTextRef>go-hbci</UserTextRef><BankRef>4711</BankRef><MsgNo>2</MsgNo></ReqMsgHeader>`,
+		`<ReqMsgBody><MessengerSig><OneTimePassword><FinTSProperty><SignerRole>ISS</SignerRole><Timestamp><Date xmlns="http://www.fints.org/spec/xmlschema/4.1/types">2024-03-05</Date><Time xmlns="http://www.fints.org/spec/xmlschema/4.1/types">14:30:00</Time></Timestamp></FinTSProperty>`,
+		`<UserID>12345</UserID><PIN>secret</PIN></OneTimePassword></MessengerSig>`,
+		`<StandardReq><RequestList><Orders><AcctBal_2_Req xmlns="http://www.fints.org/spec/xmlschema/4.1/transactions"><OrderingCustIntlAcct><NationalAcctInfo xmlns="http://www.fints.org/spec/xmlschema/4.1/types"><AcctNo xmlns="http://www.fints.org/spec/xmlschema/4.1/types">1000000000</AcctNo>`,
+		`</OrderingCustIntlAcct><All_Acct>false</All_Acct></AcctBal_2_Req></Orders></RequestList></StandardReq></ReqMsgBody></ReqMsg>`,
+	}
+	for _, e := range expected {
+		if !bytes.Contains(marshaled, []byte(e)) {
+			t.Logf("Expected message to contain\n%s\ngot\n%s\n", e, marshaled)
+			t.Fail()
+		}
+	}
+	if count := bytes.Count(marshaled, []byte("secret")); count != 1 {
+		t.Logf("Expected the PIN only once within the messenger signature, got %d times\n", count)
+		t.Fail()
+	}
+	if !reflect.DeepEqual(message.Jobs(), []string{"AcctBal_2_Req"}) {
+		t.Logf("Expected jobs %v, got %v\n", []string{"AcctBal_2_Req"}, message.Jobs())
+		t.Fail()
+	}
+}
+
+func TestPinTanSignerWithoutPIN(t *testing.T) {
+	message := NewReqMsg(domain.BankID{CountryCode: 280, ID: "10000000"}, "c0ffee", "", 1, &StandardReq{})
+
+	err := NewPinTanSigner(domain.BankID{CountryCode: 280, ID: "10000000"}, "12345", "").Sign(message)
+
+	if err != ErrMissingPIN {
+		t.Logf("Expected ErrMissingPIN, got %v\n", err)
+		t.Fail()
+	}
+}
+
+func TestParseRespMsg(t *testing.T) {
+	respMsg, err := ParseRespMsg(readFixture(t, "init_response.xml"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if respMsg.Header.BankRef != "4711" || respMsg.Header.MsgNo != 1 {
+		t.Logf("Expected message 1 of dialog 4711, got %+v\n", respMsg.Header)
+		t.Fail()
+	}
+	reference := domain.MessageReference{DialogID: "4711", MessageNumber: 1}
+	expected := []domain.Acknowledgement{
+		{Type: domain.MessageAcknowledgement, Code: 10, Text: "Nachricht entgegengenommen.", ReferencingMessage: reference},
+		{Type: domain.MessageAcknowledgement, Code: 1050, Text: "UPD nicht mehr aktuell, aktuelle Version enthalten.", ReferencingMessage: reference},
+	}
+	if acknowledgements := respMsg.Acknowledgements(); !reflect.DeepEqual(acknowledgements, expected) {
+		t.Logf("Expected acknowledgements\n%v\ngot\n%v\n", expected, acknowledgements)
+		t.Fail()
+	}
+	bpd := respMsg.Body.StandardResp.InitResp.BankParamData
+	if bpd.BpdVersion != 12 || bpd.BankName != "Testbank & Co" {
+		t.Logf("Expected BPD version 12 of Testbank & Co, got %+v\n", bpd)
+		t.Fail()
+	}
+
+	invalidMessages := map[string][]byte{
+		"FinTS 3.0":         []byte("HNHBK:1:3+000000000100+300+0+1'"),
+		"unknown namespace": []byte(`<RespMsg xmlns="http://www.fints.org/schema/4.1"><RespMsgHeader><MsgNo>1</MsgNo></RespMsgHeader></RespMsg>`),
+	}
+	for name, data := range invalidMessages {
+		if _, err := ParseRespMsg(data); err == nil {
+			t.Logf("Expected an error for a %s message\n", name)
+			t.Fail()
+		}
+	}
+}
+
+func TestParseRespMsgEncrypted(t *testing.T) {
+	data := []byte(`<RespMsg xmlns="http://www.fints.org/spec/xmlschema/4.1/messages"><RespMsgHeader><MsgNo>1</MsgNo></RespMsgHeader>` +
+		`<xenc:EncryptedData xmlns:xenc="http://www.w3.org/2001/04/xmlenc#" Type="http://www.w3.org/2001/04/xmlenc#Element">` +
+		`<xenc:CipherData><xenc:CipherValue>AAAA</xenc:CipherValue></xenc:CipherData></xenc:EncryptedData></RespMsg>`)
+
+	_, err := ParseRespMsg(data)
+
+	if err != ErrEncryptedMessage {
+		t.Logf("Expected ErrEncryptedMessage, got %v\n", err)
+		t.Fail()
+	}
+}
+
+func TestAcctBalRespRecAccountBalance(t *testing.T) {
+	respMsg, err := ParseRespMsg(readFixture(t, "balance_response.xml"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	responses := respMsg.Responses()
+	if len(responses) != 1 || responses[0].AcctBal == nil || len(responses[0].AcctBal.RespRecs) != 1 {
+		t.Fatalf("Expected one AcctBal_2_Resp with one record, got %+v", responses)
+	}
+
+	balance, err := responses[0].AcctBal.RespRecs[0].AccountBalance()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	account := domain.AccountConnection{AccountID: "1000000000", CountryCode: 280, BankID: "10000000"}
+	if balance.Account != account {
+		t.Logf("Expected account %v, got %v\n", account, balance.Account)
+		t.Fail()
+	}
+	if balance.BookedBalance.Amount != (domain.Amount{Amount: -1234.56, Currency: "EUR"}) {
+		t.Logf("Expected a debit booked balance, got %v\n", balance.BookedBalance.Amount)
+		t.Fail()
+	}
+	if balance.BookedBalance.TransmissionTime == nil || balance.BookedBalance.TransmissionTime.Hour() != 14 {
+		t.Logf("Expected transmission time 14:30, got %v\n", balance.BookedBalance.TransmissionTime)
+		t.Fail()
+	}
+	if balance.EarmarkedBalance == nil || balance.EarmarkedBalance.Amount.Amount != 100 {
+		t.Logf("Expected earmarked balance of 100, got %v\n", balance.EarmarkedBalance)
+		t.Fail()
+	}
+	if balance.CreditLimit == nil || balance.CreditLimit.Amount != 2000 || balance.UsedAmount != nil {
+		t.Logf("Expected credit limit of 2000 and no used amount, got %v, %v\n", balance.CreditLimit, balance.UsedAmount)
+		t.Fail()
+	}
+	if balance.BookingDate == nil || balance.BookingDate.Format(dateFormat) != "2024-03-05" || balance.DueDate != nil {
+		t.Logf("Expected booking date 2024-03-05 and no due date, got %v, %v\n", balance.BookingDate, balance.DueDate)
+		t.Fail()
+	}
+	acknowledgements := respMsg.Acknowledgements()
+	if last := acknowledgements[len(acknowledgements)-1]; last.Type != domain.SegmentAcknowledgement || last.Code != 20 || last.ReferencingSegmentNumber != 1 {
+		t.Logf("Expected the order acknowledgement to reference the first order, got %v\n", last)
+		t.Fail()
+	}
+}
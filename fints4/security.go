@@ -0,0 +1,87 @@
+package fints4
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+// ErrMissingPIN is returned when a message should be signed without a PIN
+var ErrMissingPIN = errors.New("fints4: missing PIN")
+
+// signerRoleIssuer is the role of the user signing its own messages
+const signerRoleIssuer = "ISS"
+
+// A Signer signs client messages by adding the messenger signature to the
+// message body
+type Signer interface {
+	Sign(*ReqMsg) error
+}
+
+// MessengerSig contains the signature of the messenger of a message. For
+// PIN/TAN it contains the OneTimePassword.
+type MessengerSig struct {
+	OneTimePassword *OneTimePassword `xml:"OneTimePassword,omitempty"`
+}
+
+// OneTimePassword is the signature of the PIN/TAN procedure
+type OneTimePassword struct {
+	FinTSProperty FinTSProperty `xml:"FinTSProperty"`
+	BankID        Bank          `xml:"BankID"`
+	UserID        string        `xml:"UserID"`
+	CustSysID     string        `xml:"CustSysID,omitempty"`
+	PIN           string        `xml:"PIN"`
+}
+
+// FinTSProperty names the role of the signer and the time of signing
+type FinTSProperty struct {
+	SignerRole string     `xml:"SignerRole"`
+	Timestamp  *Timestamp `xml:"Timestamp,omitempty"`
+}
+
+// NewPinTanSigner returns a Signer for the one step PIN/TAN procedure
+func NewPinTanSigner(bankID domain.BankID, userID, pin string) *PinTanSigner {
+	return &PinTanSigner{
+		bankID: bankID,
+		userID: userID,
+		pin:    pin,
+		now:    time.Now,
+	}
+}
+
+// PinTanSigner signs messages with the PIN of the user
+type PinTanSigner struct {
+	bankID         domain.BankID
+	userID         string
+	pin            string
+	clientSystemID string
+	now            func() time.Time
+}
+
+// SetClientSystemID sets the client system ID sent within the signature
+func (p *PinTanSigner) SetClientSystemID(clientSystemID string) {
+	p.clientSystemID = clientSystemID
+}
+
+// Sign adds the messenger signature containing the PIN to message. The
+// message is not encrypted, the PIN is protected by the transport only.
+func (p *PinTanSigner) Sign(message *ReqMsg) error {
+	if p.pin == "" {
+		return ErrMissingPIN
+	}
+	if message.Body == nil {
+		return errors.New("fints4: message without body can not be signed")
+	}
+	timestamp := NewTimestamp(p.now())
+	message.Body.MessengerSig = &MessengerSig{
+		OneTimePassword: &OneTimePassword{
+			FinTSProperty: FinTSProperty{SignerRole: signerRoleIssuer, Timestamp: &timestamp},
+			BankID:        NewBank(p.bankID),
+			UserID:        p.userID,
+			CustSysID:     p.clientSystemID,
+			PIN:           p.pin,
+		},
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package fintstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+)
+
+// A Fixture describes the bank institute simulated by a Server.
+type Fixture struct {
+	BankID   string `json:"bank_id"`
+	BankName string `json:"bank_name"`
+	UserID   string `json:"user_id"`
+	PIN      string `json:"pin"`
+	// PageSize limits the number of transactions within one HIKAZ
+	// response. If there are more, the response contains a continuation
+	// reference. Zero means no limit.
+	PageSize int `json:"page_size"`
+	// TransactionDays is the number of days the transactions are kept as
+	// stated within the BPD. Zero states no restriction.
+	TransactionDays int       `json:"transaction_days"`
+	TAN             TANConfig `json:"tan"`
+	Accounts        []Account `json:"accounts"`
+}
+
+// TANConfig configures which jobs need a TAN and the challenge sent to the
+// client.
+type TANConfig struct {
+	// Jobs contains the IDs of the job segments needing a TAN, e.g. HKKAZ.
+	Jobs      []string `json:"jobs"`
+	Challenge string   `json:"challenge"`
+	// TAN is the only TAN accepted by the simulator.
+	TAN string `json:"tan"`
+}
+
+func (t TANConfig) required(segmentID string) bool {
+	for _, id := range t.Jobs {
+		if id == segmentID {
+			return true
+		}
+	}
+	return false
+}
+
+// An Account is an account of the simulated user.
+type Account struct {
+	AccountID    string        `json:"account_id"`
+	IBAN         string        `json:"iban"`
+	BIC          string        `json:"bic"`
+	Currency     string        `json:"currency"`
+	Name         string        `json:"name"`
+	ProductName  string        `json:"product_name"`
+	Balance      float64       `json:"balance"`
+	Transactions []Transaction `json:"transactions"`
+}
+
+// A Transaction is a booked transaction of an account. Negative amounts are
+// debits.
+type Transaction struct {
+	BookingDate time.Time `json:"booking_date"`
+	ValutaDate  time.Time `json:"valuta_date"`
+	Amount      float64   `json:"amount"`
+	BookingText string    `json:"booking_text"`
+	Name        string    `json:"name"`
+	BankID      string    `json:"bank_id"`
+	AccountID   string    `json:"account_id"`
+	Purpose     string    `json:"purpose"`
+}
+
+// LoadFixture reads a JSON encoded Fixture from the file at path.
+func LoadFixture(path string) (Fixture, error) {
+	var fixture Fixture
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fixture, fmt.Errorf("error reading fixture: %w", err)
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return fixture, fmt.Errorf("error unmarshaling fixture: %w", err)
+	}
+	return fixture, nil
+}
+
+// DefaultFixture returns a Fixture with one account and a few transactions.
+func DefaultFixture() Fixture {
+	return Fixture{
+		BankID:   "10000000",
+		BankName: "Simulator Bank",
+		UserID:   "user",
+		PIN:      "12345",
+		TAN: TANConfig{
+			Challenge: "Bitte geben Sie die TAN ein",
+			TAN:       "123456",
+		},
+		Accounts: []Account{
+			{
+				AccountID:   "1234567890",
+				IBAN:        "DE89100000001234567890",
+				BIC:         "SIMUDEFFXXX",
+				Currency:    "EUR",
+				Name:        "Max Mustermann",
+				ProductName: "Girokonto",
+				Balance:     1500.25,
+				Transactions: []Transaction{
+					{
+						BookingDate: time.Date(2018, 11, 1, 0, 0, 0, 0, time.UTC),
+						ValutaDate:  time.Date(2018, 11, 1, 0, 0, 0, 0, time.UTC),
+						Amount:      2500,
+						BookingText: "Gutschrift",
+						Name:        "Arbeitgeber GmbH",
+						BankID:      "20000000",
+						AccountID:   "9876543210",
+						Purpose:     "Gehalt November",
+					},
+					{
+						BookingDate: time.Date(2018, 11, 5, 0, 0, 0, 0, time.UTC),
+						ValutaDate:  time.Date(2018, 11, 5, 0, 0, 0, 0, time.UTC),
+						Amount:      -850,
+						BookingText: "Dauerauftrag",
+						Name:        "Vermieter",
+						BankID:      "30000000",
+						AccountID:   "1111111111",
+						Purpose:     "Miete November",
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,239 @@
+package fintstest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// execute answers a single job of req within the dialog.
+func (h *Handler) execute(dialogID string, dialog *dialogState, req *request, job requestSegment, tanJobs map[int]requestSegment, res *response) {
+	switch job.id {
+	case segment.IdentificationID:
+		h.identification(job, res)
+	case segment.ProcessingPreparationID:
+		h.processingPreparation(req, job, res)
+	case "HKSYN":
+		h.synchronisation(job, res)
+	case "HKTAN":
+		h.tan(dialog, req, job, tanJobs, res)
+	case "HKSAL":
+		h.balances(job, res)
+	case "HKKAZ":
+		h.transactions(job, res)
+	case "HKSPA":
+		h.sepaAccounts(job, res)
+	case "HKEND":
+		delete(h.dialogs, dialogID)
+		res.acknowledge(job.position, acknowledgement(100, "Dialog beendet."))
+	default:
+		res.acknowledge(job.position, acknowledgement(9010, fmt.Sprintf("Geschäftsvorfall %s wird nicht unterstützt.", job.id)))
+	}
+}
+
+func (h *Handler) identification(job requestSegment, res *response) {
+	customerID := job.text(2)
+	if customerID != h.fixture.UserID && customerID != anonymousUserID {
+		res.acknowledge(job.position, acknowledgement(9931, "Anmeldename oder PIN ist falsch."))
+	}
+}
+
+func (h *Handler) processingPreparation(req *request, job requestSegment, res *response) {
+	res.acknowledge(job.position, acknowledgement(20, "Auftrag ausgeführt."))
+	if job.number(1) < bpdVersion {
+		res.add(h.bankParameterData(job.position)...)
+	}
+	identification, _ := req.job(segment.IdentificationID)
+	if job.number(2) < updVersion && identification.text(2) != anonymousUserID {
+		res.add(h.userParameterData(job.position)...)
+	}
+}
+
+func (h *Handler) synchronisation(job requestSegment, res *response) {
+	if job.text(1) != "0" {
+		res.acknowledge(job.position, acknowledgement(9010, "Synchronisierungsmodus wird nicht unterstützt."))
+		return
+	}
+	res.acknowledge(job.position, acknowledgement(20, "Auftrag ausgeführt."))
+	res.add(synchronisationResponse(job.position, fmt.Sprintf("system%d", h.nextCounter())))
+}
+
+// tan handles the two step TAN process. With process 4 the jobs of the
+// message needing a TAN are held back and a challenge is returned. With
+// process 2 the held back jobs are executed, if the TAN is valid.
+func (h *Handler) tan(dialog *dialogState, req *request, job requestSegment, tanJobs map[int]requestSegment, res *response) {
+	switch job.text(1) {
+	case "4":
+		if len(tanJobs) == 0 {
+			res.acknowledge(job.position, acknowledgement(3076, "Starke Kundenauthentifizierung nicht notwendig."))
+			res.add(tanResponse(job.position, "4", "noref", ""))
+			return
+		}
+		jobReference := fmt.Sprintf("job%d", h.nextCounter())
+		var pending []requestSegment
+		for _, j := range req.jobs {
+			if _, ok := tanJobs[j.position]; ok {
+				pending = append(pending, j)
+			}
+		}
+		dialog.pending[jobReference] = pending
+		res.acknowledge(job.position, acknowledgement(30, "Auftrag empfangen - Sicherheitsfreigabe erforderlich."))
+		res.add(tanResponse(job.position, "4", jobReference, h.fixture.TAN.Challenge))
+	case "2":
+		jobReference := job.text(3)
+		if job.version >= 6 {
+			jobReference = job.text(4)
+		}
+		pending, ok := dialog.pending[jobReference]
+		if !ok {
+			res.acknowledge(job.position, acknowledgement(9010, "Auftragsreferenz unbekannt."))
+			return
+		}
+		if req.tan != h.fixture.TAN.TAN {
+			res.acknowledge(job.position, acknowledgement(9941, "TAN ungültig."))
+			return
+		}
+		delete(dialog.pending, jobReference)
+		res.acknowledge(job.position, acknowledgement(20, "Auftrag ausgeführt."))
+		res.add(tanResponse(job.position, "2", jobReference, ""))
+		for _, p := range pending {
+			h.execute("", dialog, req, p, nil, res)
+		}
+	default:
+		res.acknowledge(job.position, acknowledgement(9010, "TAN-Prozess wird nicht unterstützt."))
+	}
+}
+
+func (h *Handler) balances(job requestSegment, res *response) {
+	accounts, ok := h.accounts(job, res)
+	if !ok {
+		return
+	}
+	res.acknowledge(job.position, acknowledgement(20, "Auftrag ausgeführt."))
+	for _, account := range accounts {
+		res.add(h.balanceResponse(job.position, account, h.now()))
+	}
+}
+
+// transactions answers HKKAZ. The continuation reference is the number of
+// transactions already sent.
+func (h *Handler) transactions(job requestSegment, res *response) {
+	accounts, ok := h.accounts(job, res)
+	if !ok {
+		return
+	}
+	from, to := job.date(3), job.date(4)
+	maxEntries := h.fixture.PageSize
+	if n := job.number(5); n > 0 {
+		maxEntries = n
+	}
+	offset := 0
+	if ref := job.text(6); ref != "" {
+		var err error
+		offset, err = strconv.Atoi(ref)
+		if err != nil {
+			res.acknowledge(job.position, acknowledgement(9010, "Aufsetzpunkt ungültig."))
+			return
+		}
+	}
+
+	type page struct {
+		account      Account
+		transactions []Transaction
+		opening      float64
+	}
+	var pages []*page
+	remaining := 0
+	count := 0
+	for _, account := range accounts {
+		sorted := make([]Transaction, len(account.Transactions))
+		copy(sorted, account.Transactions)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].BookingDate.Before(sorted[j].BookingDate) })
+		opening := account.Balance
+		for _, tr := range sorted {
+			opening -= tr.Amount
+		}
+		var current *page
+		for _, tr := range sorted {
+			inRange := (from.IsZero() || !tr.BookingDate.Before(from)) && (to.IsZero() || !tr.BookingDate.After(to))
+			if inRange {
+				switch {
+				case count < offset:
+				case maxEntries > 0 && count >= offset+maxEntries:
+					remaining++
+				default:
+					if current == nil {
+						current = &page{account: account, opening: opening}
+						pages = append(pages, current)
+					}
+					current.transactions = append(current.transactions, tr)
+				}
+				count++
+			}
+			opening += tr.Amount
+		}
+	}
+	if len(pages) == 0 {
+		res.acknowledge(job.position, acknowledgement(3010, "Keine Umsätze im angegebenen Zeitraum vorhanden."))
+		return
+	}
+	var mt940 []byte
+	for _, p := range pages {
+		mt940 = append(mt940, h.mt940(p.account, p.transactions, p.opening, h.now())...)
+	}
+	if remaining > 0 {
+		next := strconv.Itoa(offset + maxEntries)
+		res.acknowledge(job.position, acknowledgement(element.AcknowledgementAdditionalInformation, "Es liegen weitere Informationen vor.", next))
+	} else {
+		res.acknowledge(job.position, acknowledgement(20, "Auftrag ausgeführt."))
+	}
+	res.add(transactionResponse(job.position, job.version, mt940))
+}
+
+func (h *Handler) sepaAccounts(job requestSegment, res *response) {
+	res.acknowledge(job.position, acknowledgement(20, "Auftrag ausgeführt."))
+	res.add(h.sepaAccountsResponse(job.position, h.fixture.Accounts))
+}
+
+// accounts returns the accounts addressed by the account connection within
+// the first data element of job and the all accounts flag within the second
+// one. If there is no such account, it acknowledges the job with an error.
+func (h *Handler) accounts(job requestSegment, res *response) ([]Account, bool) {
+	if job.text(2) == "J" {
+		return h.fixture.Accounts, true
+	}
+	// international account connections are used from version 7 on
+	var accountID, iban string
+	if job.version >= 7 {
+		account := &element.InternationalAccountConnectionDataElement{}
+		if err := account.UnmarshalHBCI(job.element(1)); err == nil {
+			iban, accountID = account.Val().IBAN, account.Val().AccountID
+		}
+	} else {
+		account := &element.AccountConnectionDataElement{}
+		if err := account.UnmarshalHBCI(job.element(1)); err == nil {
+			accountID = account.Val().AccountID
+		}
+	}
+	for _, account := range h.fixture.Accounts {
+		if (iban != "" && account.IBAN == iban) || (accountID != "" && account.AccountID == accountID) {
+			return []Account{account}, true
+		}
+	}
+	res.acknowledge(job.position, acknowledgement(9010, "Konto unbekannt."))
+	return nil, false
+}
+
+// date returns the date within the data element at index i or the zero time
+// if it is empty or malformed.
+func (r requestSegment) date(i int) time.Time {
+	value := &element.DateDataElement{}
+	if err := value.UnmarshalHBCI(r.element(i)); err != nil {
+		return time.Time{}
+	}
+	return value.Val()
+}
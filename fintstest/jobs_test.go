@@ -0,0 +1,128 @@
+package fintstest
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func newJob(id string, version, position int, elements ...string) requestSegment {
+	s := requestSegment{id: id, version: version, position: position}
+	for _, e := range elements {
+		s.elements = append(s.elements, []byte(e))
+	}
+	return s
+}
+
+// acknowledged reports whether res acknowledges a job with code.
+func acknowledged(res *response, code int) bool {
+	for _, seg := range res.acknowledgements {
+		marshaled, _ := seg.MarshalHBCI()
+		if bytes.Contains(marshaled, []byte(fmt.Sprintf("+%04d:", code))) {
+			return true
+		}
+	}
+	return false
+}
+
+func segmentIDs(res *response) []string {
+	var ids []string
+	for _, seg := range res.segments {
+		ids = append(ids, seg.Header().ID.Val())
+	}
+	return ids
+}
+
+func TestHandlerTanProcess(t *testing.T) {
+	fixture := DefaultFixture()
+	fixture.TAN.Jobs = []string{"HKSAL"}
+	h := NewHandler(fixture)
+	dialogID, res := h.handle(&request{
+		hbciVersion: 300,
+		dialogID:    initialDialogID,
+		number:      1,
+		signed:      true,
+		pin:         fixture.PIN,
+		jobs: []requestSegment{
+			newJob("HKIDN", 2, 3, "280:10000000", "user", "0", "1"),
+			newJob("HKVVB", 3, 4, "1", "1", "0", "0", "go-hbci", "1"),
+		},
+	})
+	if res.hasError {
+		t.Fatalf("Expected dialog initialization to succeed\n")
+	}
+
+	balanceRequest := &request{
+		hbciVersion: 300,
+		dialogID:    dialogID,
+		number:      2,
+		signed:      true,
+		pin:         fixture.PIN,
+		jobs: []requestSegment{
+			newJob("HKTAN", 6, 3, "4", "HKSAL"),
+			newJob("HKSAL", 7, 4, "DE89100000001234567890:SIMUDEFFXXX:1234567890::280:10000000", "N"),
+		},
+	}
+	_, res = h.handle(balanceRequest)
+
+	if !acknowledged(res, 30) {
+		t.Logf("Expected a TAN to be required\n")
+		t.Fail()
+	}
+	if ids := segmentIDs(res); len(ids) != 1 || ids[0] != "HITAN" {
+		t.Logf("Expected only a HITAN segment, got %v\n", ids)
+		t.Fail()
+	}
+	var jobReference string
+	for ref := range h.dialogs[dialogID].pending {
+		jobReference = ref
+	}
+
+	tests := []struct {
+		tan         string
+		code        int
+		responseIDs []string
+	}{
+		{"000000", 9941, nil},
+		{fixture.TAN.TAN, 20, []string{"HITAN", "HISAL"}},
+		{fixture.TAN.TAN, 9010, nil},
+	}
+	for i, test := range tests {
+		_, res = h.handle(&request{
+			hbciVersion: 300,
+			dialogID:    dialogID,
+			number:      3 + i,
+			signed:      true,
+			pin:         fixture.PIN,
+			tan:         test.tan,
+			jobs: []requestSegment{
+				newJob("HKTAN", 6, 3, "2", "", "", jobReference),
+			},
+		})
+
+		if !acknowledged(res, test.code) {
+			t.Logf("%d: Expected acknowledgement %04d\n", i, test.code)
+			t.Fail()
+		}
+		if ids := segmentIDs(res); fmt.Sprint(ids) != fmt.Sprint(test.responseIDs) {
+			t.Logf("%d: Expected segments %v, got %v\n", i, test.responseIDs, ids)
+			t.Fail()
+		}
+	}
+}
+
+func TestHandlerUnknownDialog(t *testing.T) {
+	h := NewHandler(DefaultFixture())
+
+	_, res := h.handle(&request{
+		hbciVersion: 300,
+		dialogID:    "unknown",
+		number:      2,
+		jobs:        []requestSegment{newJob("HKEND", 1, 3, "unknown")},
+	})
+
+	if len(res.messageAcknowledgements) != 1 || res.messageAcknowledgements[0].Code != 9800 {
+		t.Logf("Expected message acknowledgement 9800, got %v\n", res.messageAcknowledgements)
+		t.Fail()
+	}
+}
@@ -0,0 +1,51 @@
+package fintstest
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// mt940 returns a SWIFT MT940 statement of account containing transactions.
+// opening is the balance before the first transaction.
+func (h *Handler) mt940(account Account, transactions []Transaction, opening float64, date time.Time) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("\r\n:20:STARTUMS")
+	fmt.Fprintf(&buf, "\r\n:25:%s/%s", h.fixture.BankID, account.AccountID)
+	buf.WriteString("\r\n:28C:0")
+	if len(transactions) > 0 {
+		date = transactions[0].BookingDate
+	}
+	fmt.Fprintf(&buf, "\r\n:60F:%s", balance(opening, date, account.Currency))
+	closing := opening
+	for _, tr := range transactions {
+		valutaDate := tr.ValutaDate
+		if valutaDate.IsZero() {
+			valutaDate = tr.BookingDate
+		}
+		fmt.Fprintf(
+			&buf, "\r\n:61:%s%s%s%sNMSCNONREF",
+			valutaDate.Format("060102"), tr.BookingDate.Format("0102"), debitCredit(tr.Amount), formatAmount(tr.Amount),
+		)
+		fmt.Fprintf(
+			&buf, "\r\n:86:166?00%s?20%s?30%s?31%s?32%s",
+			tr.BookingText, tr.Purpose, tr.BankID, tr.AccountID, tr.Name,
+		)
+		closing += tr.Amount
+		date = tr.BookingDate
+	}
+	fmt.Fprintf(&buf, "\r\n:62F:%s", balance(closing, date, account.Currency))
+	buf.WriteString("\r\n-")
+	return buf.Bytes()
+}
+
+func balance(amount float64, date time.Time, currency string) string {
+	return fmt.Sprintf("%s%s%s%s", debitCredit(amount), date.Format("060102"), currency, formatAmount(amount))
+}
+
+func debitCredit(amount float64) string {
+	if amount < 0 {
+		return "D"
+	}
+	return "C"
+}
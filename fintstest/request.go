@@ -0,0 +1,197 @@
+package fintstest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// envelopeSegments are the segments wrapping the jobs of a message.
+var envelopeSegments = map[string]bool{
+	segment.MessageHeaderID: true,
+	"HNHBS":                 true,
+	"HNVSK":                 true,
+	"HNVSD":                 true,
+	"HNSHK":                 true,
+	"HNSHA":                 true,
+}
+
+// A request is a parsed client message.
+type request struct {
+	hbciVersion int
+	dialogID    string
+	number      int
+	signed      bool
+	pin         string
+	tan         string
+	jobs        []requestSegment
+}
+
+// A requestSegment is a segment sent by the client.
+type requestSegment struct {
+	id       string
+	version  int
+	position int
+	elements [][]byte
+}
+
+// element returns the data element at index i, counting from 1 like the
+// specification does. It returns nil if the segment has no such element.
+func (r requestSegment) element(i int) []byte {
+	if i < 1 || i > len(r.elements) {
+		return nil
+	}
+	return r.elements[i-1]
+}
+
+// text returns the unescaped value of the data element at index i.
+func (r requestSegment) text(i int) string {
+	value := &element.AlphaNumericDataElement{}
+	value.UnmarshalHBCI(r.element(i))
+	return value.Val()
+}
+
+// number returns the numeric value of the data element at index i or 0 if
+// it is empty or malformed.
+func (r requestSegment) number(i int) int {
+	value := &element.NumberDataElement{}
+	if err := value.UnmarshalHBCI(r.element(i)); err != nil {
+		return 0
+	}
+	return value.Val()
+}
+
+// parseRequest parses the Base64 encoded or plain message within body.
+func parseRequest(body []byte) (*request, error) {
+	body = bytes.TrimSpace(body)
+	if !bytes.HasPrefix(body, []byte(segment.MessageHeaderID)) {
+		decoded, err := ioutil.ReadAll(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(body)))
+		if err != nil {
+			return nil, fmt.Errorf("error decoding message: %w", err)
+		}
+		body = decoded
+	}
+	segments, err := message.NewSegmentExtractor(body).Extract()
+	if err != nil {
+		return nil, fmt.Errorf("error extracting segments: %w", err)
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("empty message")
+	}
+	header := &segment.MessageHeaderSegment{}
+	if err := header.UnmarshalHBCI(segments[0]); err != nil {
+		return nil, fmt.Errorf("error unmarshaling message header: %w", err)
+	}
+	req := &request{
+		hbciVersion: header.HBCIVersion.Val(),
+		dialogID:    header.DialogID.Val(),
+		number:      header.Number.Val(),
+	}
+	if err := req.addSegments(segments[1:]); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// addSegments adds the jobs within segments to r. Segments known to
+// segment.KnownSegments, like the envelope segments, are unmarshaled by their
+// types, so malformed segments are rejected.
+func (r *request) addSegments(segments [][]byte) error {
+	for _, seg := range segments {
+		elements, err := segment.ExtractElements(seg)
+		if err != nil {
+			return fmt.Errorf("error extracting elements: %w", err)
+		}
+		header := &element.SegmentHeader{}
+		if err := header.UnmarshalHBCI(elements[0]); err != nil {
+			return fmt.Errorf("error unmarshaling segment header: %w", err)
+		}
+		parsed := requestSegment{
+			id:       header.ID.Val(),
+			version:  header.Version.Val(),
+			position: header.Position.Val(),
+			elements: elements[1:],
+		}
+		versioned := segment.VersionedSegment{ID: parsed.id, Version: parsed.version}
+		switch {
+		case parsed.id == "HNSHA":
+			pin, tan, err := signatureCredentials(parsed.version, seg)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling signature end: %w", err)
+			}
+			r.signed = true
+			r.pin, r.tan = pin, tan
+		case segment.KnownSegments.IsUnmarshaler(versioned):
+			known, err := segment.KnownSegments.UnmarshalerForSegment(versioned)
+			if err != nil {
+				return err
+			}
+			if err := known.UnmarshalHBCI(seg); err != nil {
+				return fmt.Errorf("error unmarshaling %s: %w", parsed.id, err)
+			}
+			encryptedData, ok := known.(*segment.EncryptedDataSegment)
+			if !ok {
+				break
+			}
+			// PIN/TAN messages are not really encrypted
+			inner, err := message.NewSegmentExtractor(encryptedData.Data.Val()).Extract()
+			if err != nil {
+				return fmt.Errorf("error extracting encrypted segments: %w", err)
+			}
+			if err := r.addSegments(inner); err != nil {
+				return err
+			}
+		}
+		if !envelopeSegments[parsed.id] {
+			r.jobs = append(r.jobs, parsed)
+		}
+	}
+	return nil
+}
+
+// signatureCredentials returns the PIN and the TAN within the signature end
+// seg of version.
+func signatureCredentials(version int, seg []byte) (string, string, error) {
+	var pinTan *element.PinTanDataElement
+	switch version {
+	case 1:
+		signatureEnd := &segment.SignatureEndV1{}
+		if err := signatureEnd.UnmarshalHBCI(seg); err != nil {
+			return "", "", err
+		}
+		pinTan = signatureEnd.PinTan
+	case 2:
+		signatureEnd := &segment.SignatureEndV2{}
+		if err := signatureEnd.UnmarshalHBCI(seg); err != nil {
+			return "", "", err
+		}
+		if signatureEnd.CustomSignature != nil {
+			pinTan = signatureEnd.CustomSignature.PinTanDataElement
+		}
+	default:
+		return "", "", fmt.Errorf("unknown segment version: %d", version)
+	}
+	if pinTan == nil {
+		return "", "", nil
+	}
+	var tan string
+	if pinTan.TAN != nil {
+		tan = pinTan.TAN.Val()
+	}
+	return pinTan.PIN.Val(), tan, nil
+}
+
+// job returns the first job segment with the given ID.
+func (r *request) job(id string) (requestSegment, bool) {
+	for _, job := range r.jobs {
+		if job.id == id {
+			return job, true
+		}
+	}
+	return requestSegment{}, false
+}
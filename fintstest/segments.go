@@ -0,0 +1,214 @@
+package fintstest
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitch000001/go-hbci/charset"
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+const (
+	bpdVersion = 1
+	updVersion = 1
+)
+
+var escaper = strings.NewReplacer("?", "??", "@", "?@", "'", "?'", ":", "?:", "+", "?+")
+
+// A rawSegment is a bank segment without a counterpart within the segment
+// package. Every data element consists of group data elements, which are
+// escaped when marshaling.
+type rawSegment struct {
+	header   *element.SegmentHeader
+	elements [][]string
+}
+
+func newRawSegment(id string, version int, ref int, elements ...[]string) *rawSegment {
+	return &rawSegment{
+		header:   element.NewReferencingSegmentHeader(id, 0, version, ref),
+		elements: elements,
+	}
+}
+
+func (r *rawSegment) Header() *element.SegmentHeader {
+	return r.header
+}
+
+func (r *rawSegment) SetPosition(positionFn func() int) {
+	r.header.SetPosition(positionFn())
+}
+
+func (r *rawSegment) String() string {
+	marshaled, _ := r.MarshalHBCI()
+	return charset.ToUTF8(marshaled)
+}
+
+func (r *rawSegment) MarshalHBCI() ([]byte, error) {
+	header, err := r.header.MarshalHBCI()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.Write(header)
+	for _, de := range r.elements {
+		components := make([]string, len(de))
+		for i, component := range de {
+			components[i] = escaper.Replace(component)
+		}
+		buf.WriteByte('+')
+		buf.Write(charset.ToISO8859_1(strings.Join(components, ":")))
+	}
+	buf.WriteByte('\'')
+	return buf.Bytes(), nil
+}
+
+// bankParameterData returns the BPD segments answering the processing
+// preparation at ref.
+func (h *Handler) bankParameterData(ref int) []segment.ClientSegment {
+	bankID := domain.BankID{CountryCode: 280, ID: h.fixture.BankID}
+	common := &segment.CommonBankParameterV3{
+		BPDVersion:               element.NewNumber(bpdVersion, 3),
+		BankID:                   element.NewBankIdentification(bankID),
+		BankName:                 element.NewAlphaNumeric(h.fixture.BankName, 60),
+		BusinessTransactionCount: element.NewNumber(3, 3),
+		SupportedLanguages:       element.NewSupportedLanguages(int(domain.German)),
+		SupportedHBCIVersions:    element.NewSupportedHBCIVersions(220, 300),
+	}
+	common.Segment = segment.NewReferencingBasicSegment(0, ref, common)
+	pinTanJobs := []string{"1", "1", "0", "5", "20", "6", "Benutzer ID", "Kunden ID"}
+	for _, id := range []string{"HKSAL", "HKKAZ", "HKSPA"} {
+		pinTanJobs = append(pinTanJobs, id, yesNo(h.fixture.TAN.required(id)))
+	}
+	transactionParams := []string{strconv.Itoa(h.fixture.TransactionDays), "J"}
+	return []segment.ClientSegment{
+		common.Segment.(segment.ClientSegment),
+		newRawSegment(segment.PinTanBankParameterID, 1, ref, pinTanJobs[0:1], pinTanJobs[1:2], pinTanJobs[2:3], pinTanJobs[3:]),
+		newRawSegment("HISALS", 5, ref, []string{"1"}, []string{"1"}),
+		newRawSegment("HIKAZS", 5, ref, []string{"1"}, []string{"1"}, transactionParams),
+		newRawSegment("HIKAZS", 6, ref, []string{"1"}, []string{"1"}, transactionParams),
+		newRawSegment("HISPAS", 1, ref, []string{"1"}, []string{"1"}, []string{"J", "J", "N"}),
+	}
+}
+
+// userParameterData returns the UPD segments answering the processing
+// preparation at ref.
+func (h *Handler) userParameterData(ref int) []segment.ClientSegment {
+	common := &segment.CommonUserParameterDataV4{
+		UserID:     element.NewIdentification(h.fixture.UserID),
+		UPDVersion: element.NewNumber(updVersion, 3),
+		UPDUsage:   element.NewNumber(0, 1),
+	}
+	common.Segment = segment.NewReferencingBasicSegment(0, ref, common)
+	segments := []segment.ClientSegment{common.Segment.(segment.ClientSegment)}
+	for _, account := range h.fixture.Accounts {
+		// The allowed business transactions are repeated data elements,
+		// which AccountInformationV6 does not marshal
+		elements := [][]string{
+			{account.AccountID, "", "280", h.fixture.BankID},
+			{account.IBAN},
+			{h.fixture.UserID},
+			{""},
+			{account.Currency},
+			{account.Name},
+			{""},
+			{account.ProductName},
+			{""},
+		}
+		for _, id := range []string{"HKSAL", "HKKAZ", "HKSPA"} {
+			elements = append(elements, []string{id, "1"})
+		}
+		segments = append(segments, newRawSegment(segment.AccountInformationID, 6, ref, elements...))
+	}
+	return segments
+}
+
+// synchronisationResponse returns a HISYN segment with clientSystemID.
+func synchronisationResponse(ref int, clientSystemID string) segment.ClientSegment {
+	s := &segment.SynchronisationResponseSegmentV4{
+		ClientSystemIDResponse: element.NewIdentification(clientSystemID),
+	}
+	s.Segment = segment.NewReferencingBasicSegment(0, ref, s)
+	return s.Segment.(segment.ClientSegment)
+}
+
+// balanceResponse returns a HISAL segment for account.
+func (h *Handler) balanceResponse(ref int, account Account, now time.Time) segment.ClientSegment {
+	s := &segment.AccountBalanceResponseSegment{
+		AccountConnection:  element.NewAccountConnection(h.accountConnection(account)),
+		AccountProductName: element.NewAlphaNumeric(account.ProductName, 35),
+		AccountCurrency:    element.NewCurrency(account.Currency),
+		BookedBalance:      element.NewBalance(domain.Amount{Amount: account.Balance, Currency: account.Currency}, now, false),
+	}
+	s.Segment = segment.NewReferencingBasicSegment(0, ref, s)
+	return s.Segment.(segment.ClientSegment)
+}
+
+// transactionResponse returns a HIKAZ segment of the given version containing
+// the MT940 data.
+func transactionResponse(ref int, version int, mt940 []byte) segment.ClientSegment {
+	booked := &element.SwiftMT940DataElement{BinaryDataElement: element.NewBinary(mt940, len(mt940))}
+	var s segment.Segment
+	switch version {
+	case 5:
+		v5 := &segment.AccountTransactionResponseSegmentV5{BookedTransactions: booked}
+		v5.Segment = segment.NewReferencingBasicSegment(0, ref, v5)
+		s = v5.Segment
+	case 6:
+		v6 := &segment.AccountTransactionResponseSegmentV6{BookedTransactions: booked}
+		v6.Segment = segment.NewReferencingBasicSegment(0, ref, v6)
+		s = v6.Segment
+	default:
+		v7 := &segment.AccountTransactionResponseSegmentV7{BookedTransactions: booked}
+		v7.Segment = segment.NewReferencingBasicSegment(0, ref, v7)
+		s = v7.Segment
+	}
+	return s.(segment.ClientSegment)
+}
+
+// sepaAccountsResponse returns a HISPA segment listing accounts.
+func (h *Handler) sepaAccountsResponse(ref int, accounts []Account) segment.ClientSegment {
+	var elements [][]string
+	for _, account := range accounts {
+		elements = append(elements, []string{"J", account.IBAN, account.BIC, account.AccountID, "", "280", h.fixture.BankID})
+	}
+	return newRawSegment("HISPA", 1, ref, elements...)
+}
+
+// tanResponse returns a HITAN segment for the given TAN process.
+func tanResponse(ref int, process string, jobReference string, challenge string) segment.ClientSegment {
+	s := &segment.TanResponseSegmentV6{
+		TANProcess:   element.NewAlphaNumeric(process, 1),
+		JobReference: element.NewAlphaNumeric(jobReference, 35),
+	}
+	if challenge != "" {
+		s.Challenge = element.NewAlphaNumeric(challenge, 2048)
+	}
+	s.Segment = segment.NewReferencingBasicSegment(0, ref, s)
+	return s.Segment.(segment.ClientSegment)
+}
+
+func (h *Handler) accountConnection(account Account) domain.AccountConnection {
+	return domain.AccountConnection{
+		AccountID:   account.AccountID,
+		CountryCode: 280,
+		BankID:      h.fixture.BankID,
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "J"
+	}
+	return "N"
+}
+
+func formatAmount(amount float64) string {
+	if amount < 0 {
+		amount = -amount
+	}
+	return strings.Replace(strconv.FormatFloat(amount, 'f', 2, 64), ".", ",", 1)
+}
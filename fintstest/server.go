@@ -0,0 +1,280 @@
+// Package fintstest provides a simulated bank institute for end to end tests.
+//
+// A Server answers FinTS PIN/TAN messages over HTTP, like the institutes do.
+// It supports dialog initialization and end, synchronisation, account
+// balances (HKSAL), account transactions (HKKAZ), SEPA account information
+// (HKSPA) and the two step TAN process (HKTAN). The accounts and
+// transactions are taken from a Fixture.
+//
+//	server := fintstest.NewServer(fintstest.DefaultFixture())
+//	defer server.Close()
+//	c, err := client.New(client.Config{
+//		URL:         server.URL,
+//		BankID:      "10000000",
+//		AccountID:   "user",
+//		PIN:         "12345",
+//		HBCIVersion: 300,
+//	})
+//
+// All responses are built with the marshalers of the segment package, so
+// they are understood by this library by design. The simulator is no
+// reference implementation of the specification.
+package fintstest
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// initialDialogID is the dialog ID of messages initializing a dialog.
+const initialDialogID = "0"
+
+// anonymousUserID is the customer ID of anonymous dialogs.
+const anonymousUserID = "9999999999"
+
+// NewServer starts a Server simulating the institute described by fixture.
+// The caller should call Close when finished, to shut it down.
+func NewServer(fixture Fixture) *Server {
+	handler := NewHandler(fixture)
+	server := httptest.NewServer(handler)
+	return &Server{
+		Handler: handler,
+		URL:     server.URL,
+		server:  server,
+	}
+}
+
+// A Server is a HTTP server simulating a bank institute, listening on a
+// loopback address.
+type Server struct {
+	*Handler
+	// URL is the base URL of the form http://ipaddr:port with no trailing
+	// slash. It can be used as URL within a client.Config.
+	URL    string
+	server *httptest.Server
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// NewHandler returns a Handler simulating the institute described by
+// fixture. The PIN of fixture must not be empty.
+func NewHandler(fixture Fixture) *Handler {
+	return &Handler{
+		fixture:  fixture,
+		now:      time.Now,
+		dialogs:  make(map[string]*dialogState),
+		injected: make(map[string][]domain.Acknowledgement),
+	}
+}
+
+// A Handler implements http.Handler and answers Base64 encoded FinTS
+// messages. It keeps track of the open dialogs.
+type Handler struct {
+	fixture  Fixture
+	now      func() time.Time
+	mu       sync.Mutex
+	dialogs  map[string]*dialogState
+	counter  int
+	injected map[string][]domain.Acknowledgement
+}
+
+type dialogState struct {
+	// pending contains the jobs waiting for a TAN by job reference
+	pending map[string][]requestSegment
+}
+
+// InjectAcknowledgement makes the Handler answer the next job segment with
+// the ID segmentID with ack instead of its regular answer. If ack is an
+// error, the job is not executed. Acknowledgements injected for the same
+// segment ID are used in order.
+//
+// It can be used to simulate errors like a wrong PIN (9931) for HKIDN or a
+// rejected job.
+func (h *Handler) InjectAcknowledgement(segmentID string, ack domain.Acknowledgement) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.injected[segmentID] = append(h.injected[segmentID], ack)
+}
+
+// OpenDialogs returns the number of dialogs which are not yet ended.
+func (h *Handler) OpenDialogs() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.dialogs)
+}
+
+// ServeHTTP answers the message within the request body.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req, err := parseRequest(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	dialogID, res := h.handle(req)
+	marshaled, err := h.marshal(req, dialogID, res)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte(base64.StdEncoding.EncodeToString(marshaled)))
+}
+
+// handle executes all jobs of req. It returns the dialog ID of the response,
+// which is a new one if req initializes a dialog.
+func (h *Handler) handle(req *request) (string, *response) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	res := &response{}
+	dialogID := req.dialogID
+	dialog, ok := h.dialogs[dialogID]
+	if dialogID == initialDialogID {
+		if _, ok := req.job(segment.IdentificationID); !ok {
+			res.messageAcknowledgements = append(res.messageAcknowledgements, acknowledgement(9010, "Dialoginitialisierung fehlt."))
+			return dialogID, res
+		}
+		dialogID = fmt.Sprintf("dialog%d", h.nextCounter())
+		dialog = &dialogState{pending: make(map[string][]requestSegment)}
+	} else if !ok {
+		res.messageAcknowledgements = append(res.messageAcknowledgements, acknowledgement(9800, "Dialog abgebrochen - Dialog-ID unbekannt."))
+		return dialogID, res
+	}
+	if req.signed && req.pin != h.fixture.PIN {
+		res.messageAcknowledgements = append(res.messageAcknowledgements, acknowledgement(9931, "Anmeldename oder PIN ist falsch."))
+		delete(h.dialogs, dialogID)
+		return dialogID, res
+	}
+	tanJobs := h.tanJobs(req)
+	_, hasTanRequest := req.job("HKTAN")
+	for _, job := range req.jobs {
+		if _, ok := tanJobs[job.position]; ok {
+			if !hasTanRequest {
+				res.acknowledge(job.position, acknowledgement(9075, "Starke Kundenauthentifizierung notwendig."))
+			}
+			continue
+		}
+		if ack, ok := h.popInjected(job.id); ok {
+			res.acknowledge(job.position, ack)
+			if ack.IsError() {
+				continue
+			}
+		}
+		h.execute(dialogID, dialog, req, job, tanJobs, res)
+	}
+	if req.dialogID == initialDialogID && !res.hasError {
+		h.dialogs[dialogID] = dialog
+	}
+	return dialogID, res
+}
+
+// tanJobs returns the jobs of req which need a TAN by segment position.
+func (h *Handler) tanJobs(req *request) map[int]requestSegment {
+	jobs := make(map[int]requestSegment)
+	for _, job := range req.jobs {
+		if h.fixture.TAN.required(job.id) {
+			jobs[job.position] = job
+		}
+	}
+	return jobs
+}
+
+func (h *Handler) popInjected(segmentID string) (domain.Acknowledgement, bool) {
+	acks := h.injected[segmentID]
+	if len(acks) == 0 {
+		return domain.Acknowledgement{}, false
+	}
+	h.injected[segmentID] = acks[1:]
+	return acks[0], true
+}
+
+func (h *Handler) nextCounter() int {
+	h.counter++
+	return h.counter
+}
+
+// marshal returns the response message answering req.
+func (h *Handler) marshal(req *request, dialogID string, res *response) ([]byte, error) {
+	version, ok := segment.SupportedHBCIVersions[req.hbciVersion]
+	if !ok {
+		version = segment.FINTS300
+	}
+	segments := []segment.ClientSegment{segment.NewMessageAcknowledgement(res.messageAcknowledgement()...).Segment.(segment.ClientSegment)}
+	segments = append(segments, res.acknowledgements...)
+	segments = append(segments, res.segments...)
+	header := segment.NewReferencingMessageHeaderSegment(
+		0, version.Version(), dialogID, req.number,
+		domain.MessageReference{DialogID: req.dialogID, MessageNumber: req.number},
+	)
+	end := segment.NewMessageEndSegment(0, req.number)
+	bankMessage := message.NewBasicMessageWithHeaderAndEnd(header, end, message.NewHBCIMessage(version, segments...))
+	bankMessage.SetSegmentPositions()
+	bankID := domain.BankID{CountryCode: 280, ID: h.fixture.BankID}
+	key := domain.NewPinKey(h.fixture.PIN, domain.NewPinTanKeyName(bankID, h.fixture.UserID, domain.KeyTypeEncryption))
+	encryptedMessage, err := bankMessage.Encrypt(message.NewPinTanCryptoProvider(key, initialDialogID))
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting response: %w", err)
+	}
+	return encryptedMessage.MarshalHBCI()
+}
+
+// A response collects the answers to the jobs of a request.
+type response struct {
+	messageAcknowledgements []domain.Acknowledgement
+	acknowledgements        []segment.ClientSegment
+	segments                []segment.ClientSegment
+	hasError                bool
+	hasWarning              bool
+}
+
+// acknowledge adds a segment acknowledgement for the segment at ref.
+func (r *response) acknowledge(ref int, acks ...domain.Acknowledgement) {
+	for _, ack := range acks {
+		r.hasError = r.hasError || ack.IsError()
+		r.hasWarning = r.hasWarning || ack.IsWarning()
+	}
+	r.acknowledgements = append(r.acknowledgements, segment.NewSegmentAcknowledgement(ref, acks...).Segment.(segment.ClientSegment))
+}
+
+func (r *response) add(segments ...segment.ClientSegment) {
+	r.segments = append(r.segments, segments...)
+}
+
+// messageAcknowledgement summarizes the segment acknowledgements, unless
+// there are explicit message acknowledgements.
+func (r *response) messageAcknowledgement() []domain.Acknowledgement {
+	switch {
+	case len(r.messageAcknowledgements) > 0:
+		return r.messageAcknowledgements
+	case r.hasError:
+		return []domain.Acknowledgement{acknowledgement(9050, "Die Nachricht enthält Fehler.")}
+	case r.hasWarning:
+		return []domain.Acknowledgement{acknowledgement(3060, "Bitte beachten Sie die enthaltenen Warnungen/Hinweise.")}
+	default:
+		return []domain.Acknowledgement{acknowledgement(10, "Nachricht entgegengenommen.")}
+	}
+}
+
+func acknowledgement(code int, text string, params ...string) domain.Acknowledgement {
+	return domain.Acknowledgement{Code: code, Text: text, Params: params}
+}
@@ -0,0 +1,183 @@
+package fintstest_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/client"
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/fintstest"
+)
+
+func newClient(t *testing.T, server *fintstest.Server, hbciVersion int) *client.Client {
+	c, err := client.New(client.Config{
+		URL:         server.URL,
+		BankID:      "10000000",
+		AccountID:   "user",
+		PIN:         "12345",
+		HBCIVersion: hbciVersion,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	return c
+}
+
+var testAccount = domain.AccountConnection{AccountID: "1234567890", CountryCode: 280, BankID: "10000000"}
+
+func TestServerAccounts(t *testing.T) {
+	for _, version := range []int{220, 300} {
+		server := fintstest.NewServer(fintstest.DefaultFixture())
+		c := newClient(t, server, version)
+
+		accounts, err := c.Accounts()
+
+		if err != nil {
+			t.Logf("HBCI %d: Expected no error, got %T:%v\n", version, err, err)
+			t.Fail()
+		}
+		if len(accounts) != 1 {
+			t.Logf("HBCI %d: Expected 1 account, got %d\n", version, len(accounts))
+			t.Fail()
+		} else if accounts[0].AccountConnection.AccountID != "1234567890" {
+			t.Logf("HBCI %d: Expected account ID %q, got %q\n", version, "1234567890", accounts[0].AccountConnection.AccountID)
+			t.Fail()
+		}
+		if open := server.OpenDialogs(); open != 0 {
+			t.Logf("HBCI %d: Expected all dialogs to be ended, got %d open\n", version, open)
+			t.Fail()
+		}
+		server.Close()
+	}
+}
+
+func TestServerAccountBalances(t *testing.T) {
+	server := fintstest.NewServer(fintstest.DefaultFixture())
+	defer server.Close()
+	c := newClient(t, server, 300)
+
+	balances, err := c.AccountBalances(testAccount, false)
+
+	if err != nil {
+		t.Logf("Expected no error, got %T:%v\n", err, err)
+		t.FailNow()
+	}
+	if len(balances) != 1 {
+		t.Logf("Expected 1 balance, got %d\n", len(balances))
+		t.FailNow()
+	}
+	if amount := balances[0].BookedBalance.Amount.Amount; amount != 1500.25 {
+		t.Logf("Expected booked balance to equal 1500.25, got %v\n", amount)
+		t.Fail()
+	}
+}
+
+func TestServerAccountTransactions(t *testing.T) {
+	for _, pageSize := range []int{0, 1} {
+		fixture := fintstest.DefaultFixture()
+		fixture.PageSize = pageSize
+		server := fintstest.NewServer(fixture)
+		c := newClient(t, server, 300)
+
+		timeframe := domain.Timeframe{
+			StartDate: domain.NewShortDate(time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)),
+		}
+		transactions, err := c.AccountTransactions(testAccount, timeframe, false, "")
+
+		if err != nil {
+			t.Logf("Page size %d: Expected no error, got %T:%v\n", pageSize, err, err)
+			t.Fail()
+		}
+		if len(transactions) != 2 {
+			t.Logf("Page size %d: Expected 2 transactions, got %d\n", pageSize, len(transactions))
+			t.Fail()
+		}
+		var purposes []string
+		for _, tr := range transactions {
+			purposes = append(purposes, tr.Purpose)
+		}
+		for _, purpose := range []string{"Gehalt November", "Miete November"} {
+			if !strings.Contains(strings.Join(purposes, ","), purpose) {
+				t.Logf("Page size %d: Expected transactions to contain %q, got %q\n", pageSize, purpose, purposes)
+				t.Fail()
+			}
+		}
+		server.Close()
+	}
+}
+
+func TestServerInjectAcknowledgement(t *testing.T) {
+	server := fintstest.NewServer(fintstest.DefaultFixture())
+	defer server.Close()
+	server.InjectAcknowledgement("HKIDN", domain.Acknowledgement{Code: 9931, Text: "Anmeldename oder PIN ist falsch."})
+	c := newClient(t, server, 300)
+
+	_, err := c.Accounts()
+
+	if err == nil {
+		t.Logf("Expected error, got nil\n")
+		t.Fail()
+	} else if !errors.Is(err, domain.ErrWrongPIN) {
+		t.Logf("Expected error to be a wrong PIN error, got %v\n", err)
+		t.Fail()
+	}
+}
+
+func TestServerWrongPIN(t *testing.T) {
+	server := fintstest.NewServer(fintstest.DefaultFixture())
+	defer server.Close()
+	c, err := client.New(client.Config{
+		URL:         server.URL,
+		BankID:      "10000000",
+		AccountID:   "user",
+		PIN:         "54321",
+		HBCIVersion: 300,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	_, err = c.Accounts()
+
+	var ackErr *domain.AcknowledgementError
+	if !errors.As(err, &ackErr) {
+		t.Logf("Expected an AcknowledgementError, got %T:%v\n", err, err)
+		t.Fail()
+	} else if !ackErr.HasCode(9931) {
+		t.Logf("Expected acknowledgement 9931, got %v\n", ackErr.Acknowledgements)
+		t.Fail()
+	}
+	if open := server.OpenDialogs(); open != 0 {
+		t.Logf("Expected no open dialogs, got %d\n", open)
+		t.Fail()
+	}
+}
+
+func TestServerTANRequired(t *testing.T) {
+	fixture := fintstest.DefaultFixture()
+	fixture.TAN.Jobs = []string{"HKSAL"}
+	server := fintstest.NewServer(fixture)
+	defer server.Close()
+	c := newClient(t, server, 300)
+
+	_, err := c.AccountBalances(testAccount, false)
+
+	if !errors.Is(err, domain.ErrTANRequired) {
+		t.Logf("Expected a TAN required error, got %T:%v\n", err, err)
+		t.Fail()
+	}
+	var tanErr *client.TANRequiredError
+	if !errors.As(err, &tanErr) {
+		t.Fatalf("Expected a *client.TANRequiredError, got %T:%v\n", err, err)
+	}
+	if tanErr.Response.FindSegment("HITAN") == nil {
+		t.Logf("Expected the error to carry the HITAN challenge\n")
+		t.Fail()
+	}
+	if open := server.OpenDialogs(); open != 0 {
+		t.Logf("Expected all dialogs to be ended, got %d open\n", open)
+		t.Fail()
+	}
+}
@@ -14,6 +14,7 @@ type elementVisitor struct {
 	fileSet      *token.FileSet
 	object       *ast.Object
 	fields       []field
+	found        bool
 	err          error
 }
 
@@ -27,6 +28,7 @@ func (e *elementVisitor) Visit(node ast.Node) ast.Visitor {
 						if res, ok := ret.Results[0].(*ast.CompositeLit); ok {
 							resType := nodeToString(res.Type, e.fileSet)
 							if resType == segmentElementsMethodReturnType {
+								e.found = true
 								for _, element := range res.Elts {
 									if sel, ok := element.(*ast.SelectorExpr); ok {
 										pos := sel.Pos()
@@ -104,9 +106,14 @@ func (s *structFieldVisitor) Visit(node ast.Node) ast.Visitor {
 				} else {
 					continue // anonymous field
 				}
-				if starExpr, ok := f.Type.(*ast.StarExpr); ok {
-					s.fieldTypeDecl = nodeToString(starExpr.X, s.fileSet)
-				} else {
+				switch typ := f.Type.(type) {
+				case *ast.StarExpr:
+					s.fieldTypeDecl = nodeToString(typ.X, s.fileSet)
+				case *ast.Ident, *ast.SelectorExpr:
+					// interface types, e.g. element.DataElementGroup, can
+					// be marshaled but not unmarshaled
+					s.fieldTypeDecl = nodeToString(typ, s.fileSet)
+				default:
 					s.err = fmt.Errorf("Unexpected type found: %q", nodeToString(f.Type, s.fileSet))
 					return nil
 				}
@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NewSegmentMarshaler returns a marshaler generator for the provided segments
+// of one file
+func NewSegmentMarshaler(segments []SegmentIdentifier, packageName string, fileSet *token.FileSet, file *ast.File) *SegmentMarshalerGenerator {
+	return &SegmentMarshalerGenerator{
+		segments:    segments,
+		packageName: packageName,
+		fileSet:     fileSet,
+		file:        file,
+	}
+}
+
+// SegmentMarshalerGenerator generates marshalers for segment definitions. The
+// generated methods marshal the data elements returned by the elements method
+// of a segment without using reflection.
+type SegmentMarshalerGenerator struct {
+	segments    []SegmentIdentifier
+	packageName string
+	fileSet     *token.FileSet
+	file        *ast.File
+}
+
+// Generate generates the marshalers for the segment definitions and provides
+// them as an io.Reader
+func (s *SegmentMarshalerGenerator) Generate() (io.Reader, error) {
+	templObj := &marshalerTemplateObject{
+		CodeGenerator: fmt.Sprintf("%T", s),
+		Package:       s.packageName,
+	}
+	for _, segment := range s.segments {
+		fieldExtractor := &fieldExtractor{
+			segment: segment,
+			file:    s.file,
+			fileSet: s.fileSet,
+		}
+		sortedFields, err := fieldExtractor.extractFields()
+		if err != nil {
+			return nil, err
+		}
+		r, _ := utf8.DecodeRuneInString(segment.Name)
+		templObj.Segments = append(templObj.Segments, &segmentTemplateObject{
+			CodeGenerator: templObj.CodeGenerator,
+			Package:       s.packageName,
+			Name:          segment.Name,
+			NameVar:       string(unicode.ToLower(r)),
+			Fields:        sortedFields,
+		})
+	}
+	t, err := template.New("executor").Parse(marshalerExecutorTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("Error while parsing template: %v", err)
+	}
+	t, err = t.Parse(segmentMarshalingTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("Error while parsing template: %v", err)
+	}
+	t, err = t.Parse(generationNoticeTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("Error while parsing template: %v", err)
+	}
+	var buf bytes.Buffer
+	err = t.Execute(&buf, templObj)
+	if err != nil {
+		return nil, fmt.Errorf("%T: Error while executing template: %v", s, err)
+	}
+	return &buf, nil
+}
+
+type marshalerTemplateObject struct {
+	CodeGenerator string
+	Package       string
+	Segments      []*segmentTemplateObject
+}
+
+const marshalerExecutorTemplate = `{{template "generation_notice" .}}
+package {{.Package}}
+
+import (
+	"bytes"
+)
+{{range $segment := .Segments}}{{template "segment_marshaler" $segment}}{{end}}`
+
+const segmentMarshalingTemplate = `{{define "segment_marshaler"}}
+func ({{.NameVar}} *{{.Name}}) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+{{- range $field := .Fields}}
+	separators++
+	if {{$.NameVar}}.{{$field.Name}} != nil {
+		marshaled, err := {{$.NameVar}}.{{$field.Name}}.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+{{- end}}
+	return nil
+}
+
+func ({{.NameVar}} *{{.Name}}) writeElementStrings(buf *bytes.Buffer) {
+{{- range $field := .Fields}}
+	buf.WriteByte('+')
+	if {{$.NameVar}}.{{$field.Name}} != nil {
+		buf.WriteString({{$.NameVar}}.{{$field.Name}}.String())
+	}
+{{- end}}
+}
+{{end}}`
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"testing"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+func TestSegmentMarshalerGeneratorGenerate(t *testing.T) {
+	fileSet := token.NewFileSet()
+	f, err := parser.ParseFile(fileSet, "test_files/test_segment.go", nil, 0)
+	if err != nil {
+		t.Fatalf("Error while parsing source: %T:%v", err, err)
+	}
+
+	expectedSrc, err := ioutil.ReadFile("test_files/test_segment_marshaler.go")
+	if err != nil {
+		t.Fatalf("Error while reading fixture: %T:%v", err, err)
+	}
+
+	generator := NewSegmentMarshaler([]SegmentIdentifier{{Name: "TestSegment"}}, "test_files", fileSet, f)
+
+	reader, err := generator.Generate()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v", err, err)
+	}
+	generatedSourcebytes, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Error while reading source: %T:%v", err, err)
+	}
+	if !bytes.Equal(expectedSrc, generatedSourcebytes) {
+		diffs := diffmatchpatch.New().DiffMain(string(expectedSrc), string(generatedSourcebytes), true)
+		t.Logf("Expected generated sources to equal\n%s\n\tgot\n%s\n", expectedSrc, generatedSourcebytes)
+		t.Logf("Diff: \n%s\n", diffPrettyPrint(diffs))
+		t.Fail()
+	}
+
+	// segment without elements method
+	generator = NewSegmentMarshaler([]SegmentIdentifier{{Name: "TestSegment"}, {Name: "UnknownSegment"}}, "test_files", fileSet, f)
+
+	_, err = generator.Generate()
+
+	expectedMessage := `No segment with name "UnknownSegment" found`
+	if err == nil || err.Error() != expectedMessage {
+		t.Logf("Expected error message to equal\n%q\n\tgot\n%v\n", expectedMessage, err)
+		t.Fail()
+	}
+}
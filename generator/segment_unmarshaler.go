@@ -93,13 +93,14 @@ func (v *VersionedSegmentUnmarshalerGenerator) Generate() (io.Reader, error) {
 		r, _ := utf8.DecodeRuneInString(version.Name)
 		nameVar := string(unicode.ToLower(r))
 		templObj := &segmentTemplateObject{
-			CodeGenerator: fmt.Sprintf("%T", v),
-			Package:       v.packageName,
-			Name:          version.Name,
-			NameVar:       nameVar,
-			InterfaceName: version.InterfaceName,
-			Version:       version.Version,
-			Fields:        sortedFields,
+			CodeGenerator:     fmt.Sprintf("%T", v),
+			Package:           v.packageName,
+			Name:              version.Name,
+			NameVar:           nameVar,
+			InterfaceName:     version.InterfaceName,
+			Version:           version.Version,
+			Fields:            sortedFields,
+			CustomUnmarshaler: declaresUnmarshaler(v.file, version.Name),
 		}
 		versionedTemplateObjects = append(versionedTemplateObjects, templObj)
 	}
@@ -161,7 +162,31 @@ type segmentTemplateObject struct {
 	InterfaceName string
 	Version       int
 	Fields        []field
-	counter       int
+	// CustomUnmarshaler is true if the segment version implements
+	// UnmarshalHBCI itself. Only the dispatch to it is generated then.
+	CustomUnmarshaler bool
+	counter           int
+}
+
+// declaresUnmarshaler returns true if file declares an UnmarshalHBCI method
+// with a pointer receiver of type typeName.
+func declaresUnmarshaler(file *ast.File, typeName string) bool {
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv == nil || funcDecl.Name.Name != "UnmarshalHBCI" {
+			continue
+		}
+		for _, receiver := range funcDecl.Recv.List {
+			starExpr, ok := receiver.Type.(*ast.StarExpr)
+			if !ok {
+				continue
+			}
+			if ident, ok := starExpr.X.(*ast.Ident); ok && ident.Name == typeName {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 type fieldExtractor struct {
@@ -180,9 +205,12 @@ func (f *fieldExtractor) extractFields() ([]field, error) {
 	if elemVisitor.err != nil {
 		return nil, elemVisitor.err
 	}
+	if !elemVisitor.found {
+		return nil, fmt.Errorf("No elements method found for segment %q", f.segment.Name)
+	}
 
 	sortedFields := sortedFields(elemVisitor.fields)
-	sort.Sort(sortedFields)
+	sort.Stable(sortedFields)
 	return sortedFields, nil
 }
 
@@ -346,6 +374,6 @@ func ({{.NameVar}} *{{.Name}}) UnmarshalHBCI(value []byte) error {
 	}
 	{{.NameVar}}.{{.InterfaceName}} = segment
 	return nil
-}{{ range $versioned := .SegmentVersions }}
-{{template "segment" $versioned}}{{end}}{{end}}
+}{{ range $versioned := .SegmentVersions }}{{ if not $versioned.CustomUnmarshaler }}
+{{template "segment" $versioned}}{{end}}{{end}}{{end}}
 `
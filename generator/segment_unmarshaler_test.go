@@ -179,6 +179,26 @@ func TestVersionedSegmentUnmarshalerGeneratorGenerate(t *testing.T) {
 		}
 		testGenerator(t, "test_files/multiple_versioned_test_segment_custom_interfaces.go", "test_files/multiple_versioned_test_segment_custom_interfaces_unmarshaler.go", segment)
 	})
+
+	t.Run("multiple versions custom unmarshaler", func(t *testing.T) {
+		segment := SegmentIdentifier{
+			Name:          "MultipleVersionedTestSegmentCustomUnmarshaler",
+			InterfaceName: "BankSegment",
+			Versions: []SegmentIdentifier{
+				{
+					Name:          "MultipleVersionedTestSegmentCustomUnmarshalerV1",
+					Version:       1,
+					InterfaceName: "Segment",
+				},
+				{
+					Name:          "MultipleVersionedTestSegmentCustomUnmarshalerV2",
+					Version:       2,
+					InterfaceName: "Segment",
+				},
+			},
+		}
+		testGenerator(t, "test_files/multiple_versioned_test_segment_custom_unmarshaler.go", "test_files/multiple_versioned_test_segment_custom_unmarshaler_unmarshaler.go", segment)
+	})
 }
 
 func diffPrettyPrint(diffs []diffmatchpatch.Diff) string {
@@ -0,0 +1,37 @@
+package test_files
+
+import "github.com/mitch000001/go-hbci/element"
+
+type MultipleVersionedTestSegmentCustomUnmarshaler struct {
+	BankSegment
+}
+
+type MultipleVersionedTestSegmentCustomUnmarshalerV1 struct {
+	Segment
+	Abc *element.AlphaNumericDataElement
+	Def *element.NumberDataElement
+}
+
+func (m *MultipleVersionedTestSegmentCustomUnmarshalerV1) elements() []element.DataElement {
+	return []element.DataElement{
+		m.Abc,
+		m.Def,
+	}
+}
+
+type MultipleVersionedTestSegmentCustomUnmarshalerV2 struct {
+	Segment
+	Abc *element.AlphaNumericDataElement
+	Def *element.NumberDataElement
+}
+
+func (m *MultipleVersionedTestSegmentCustomUnmarshalerV2) elements() []element.DataElement {
+	return []element.DataElement{
+		m.Abc,
+		m.Def,
+	}
+}
+
+func (m *MultipleVersionedTestSegmentCustomUnmarshalerV2) UnmarshalHBCI(value []byte) error {
+	return nil
+}
@@ -0,0 +1,49 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package test_files
+
+import (
+	"bytes"
+)
+
+func (t *TestSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if t.Abc != nil {
+		marshaled, err := t.Abc.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.Xyz != nil {
+		marshaled, err := t.Xyz.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (t *TestSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if t.Abc != nil {
+		buf.WriteString(t.Abc.String())
+	}
+	buf.WriteByte('+')
+	if t.Xyz != nil {
+		buf.WriteString(t.Xyz.String())
+	}
+}
@@ -1,6 +1,6 @@
 module github.com/mitch000001/go-hbci
 
-go 1.18
+go 1.21
 
 require (
 	github.com/kr/pretty v0.3.0
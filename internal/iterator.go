@@ -36,3 +36,56 @@ func (a *arrayIterator) HasNext() bool {
 func (a *arrayIterator) Remainder() [][]byte {
 	return a.data[a.position:]
 }
+
+// NewContinuationIterator returns an iterator over the pages returned by
+// fetch. fetch gets the continuation reference returned along with the
+// previous page and returns the page and the reference to the next one. The
+// iteration starts with continuationReference and ends as soon as fetch
+// returns an error or no further reference.
+func NewContinuationIterator[T any](continuationReference string, fetch func(continuationReference string) (T, string, error)) *ContinuationIterator[T] {
+	return &ContinuationIterator[T]{fetch: fetch, next: continuationReference}
+}
+
+// ContinuationIterator iterates lazily over pages linked by continuation
+// references.
+type ContinuationIterator[T any] struct {
+	fetch   func(continuationReference string) (T, string, error)
+	next    string
+	current T
+	err     error
+	started bool
+}
+
+// Next fetches the next page. It returns false when there are no more pages
+// or an error occurred.
+func (c *ContinuationIterator[T]) Next() bool {
+	if c.err != nil || (c.started && c.next == "") {
+		return false
+	}
+	c.started = true
+	page, next, err := c.fetch(c.next)
+	if err != nil {
+		c.err = err
+		var zero T
+		c.current = zero
+		return false
+	}
+	c.current, c.next = page, next
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (c *ContinuationIterator[T]) Page() T {
+	return c.current
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (c *ContinuationIterator[T]) Err() error {
+	return c.err
+}
+
+// ContinuationReference returns the reference to the next page. It is empty
+// if there are no more pages.
+func (c *ContinuationIterator[T]) ContinuationReference() string {
+	return c.next
+}
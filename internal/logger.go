@@ -1,9 +1,11 @@
 package internal
 
 import (
+	"context"
 	"flag"
 	"io"
 	"log"
+	"log/slog"
 	"os"
 )
 
@@ -68,3 +70,67 @@ func (c *conditionalWriter) Write(p []byte) (n int, err error) {
 	}
 	return 0, nil
 }
+
+// Logger returns a slog.Logger which writes debug records to Debug and all
+// other records to Info. It is used if no logger is configured.
+func Logger() *slog.Logger {
+	return defaultLogger
+}
+
+var defaultLogger = slog.New(newLegacyHandler())
+
+// legacyHandler is a slog.Handler writing to the conditional loggers Debug
+// and Info.
+type legacyHandler struct {
+	debug slog.Handler
+	info  slog.Handler
+}
+
+func newLegacyHandler() *legacyHandler {
+	opts := &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// the loggers add the time on their own
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}
+	return &legacyHandler{
+		debug: slog.NewTextHandler(loggerWriter{Debug}, opts),
+		info:  slog.NewTextHandler(loggerWriter{Info}, opts),
+	}
+}
+
+func (l *legacyHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if level < slog.LevelInfo {
+		return debugMode
+	}
+	return infoMode
+}
+
+func (l *legacyHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < slog.LevelInfo {
+		return l.debug.Handle(ctx, r)
+	}
+	return l.info.Handle(ctx, r)
+}
+
+func (l *legacyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &legacyHandler{debug: l.debug.WithAttrs(attrs), info: l.info.WithAttrs(attrs)}
+}
+
+func (l *legacyHandler) WithGroup(name string) slog.Handler {
+	return &legacyHandler{debug: l.debug.WithGroup(name), info: l.info.WithGroup(name)}
+}
+
+// loggerWriter writes every record as a single entry to the logger.
+type loggerWriter struct {
+	logger *log.Logger
+}
+
+func (l loggerWriter) Write(p []byte) (int, error) {
+	l.logger.Print(string(p))
+	return len(p), nil
+}
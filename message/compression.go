@@ -0,0 +1,160 @@
+package message
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// CompressionFunction represents the code of a compression function as used
+// within the encryption header and the BPD
+type CompressionFunction string
+
+// The compression functions defined by the specification. Only deflate, which
+// the specification defines by reference to zlib, is supported for
+// compression, see SupportedCompressionFunctions. The specification does not
+// define the exact encoding of the other ones.
+const (
+	// CompressionNone means no compression (NULL)
+	CompressionNone CompressionFunction = "0"
+	// CompressionLZW means Lempel, Ziv, Welch (LZW)
+	CompressionLZW CompressionFunction = "1"
+	// CompressionCOM means optimized LZW (COM)
+	CompressionCOM CompressionFunction = "2"
+	// CompressionLZSS means Lempel, Ziv (LZSS)
+	CompressionLZSS CompressionFunction = "3"
+	// CompressionLZHuf means LZ + Huffman Coding (LZHuf)
+	CompressionLZHuf CompressionFunction = "4"
+	// CompressionZIP means PKZIP (ZIP)
+	CompressionZIP CompressionFunction = "5"
+	// CompressionDeflate means deflate (GZIP) as described on
+	// http://www.gzip.org/zlib
+	CompressionDeflate CompressionFunction = "6"
+	// CompressionBZip2 means bzip2. It is only supported for decompression.
+	CompressionBZip2 CompressionFunction = "7"
+)
+
+// SupportedCompressionFunctions contains the compression functions the
+// client can compress with, in order of preference.
+var SupportedCompressionFunctions = []CompressionFunction{
+	CompressionDeflate,
+}
+
+// NegotiateCompression returns the most preferred of the
+// SupportedCompressionFunctions the institute supports. It returns
+// CompressionNone if there is no common compression function.
+func NegotiateCompression(institutesFunctions []string) CompressionFunction {
+	offered := make(map[CompressionFunction]bool)
+	for _, fn := range institutesFunctions {
+		offered[CompressionFunction(fn)] = true
+	}
+	for _, fn := range SupportedCompressionFunctions {
+		if offered[fn] {
+			return fn
+		}
+	}
+	return CompressionNone
+}
+
+// ExtractCompression returns the compression function named within the
+// marshaled encryption header. It does not unmarshal the whole segment, as
+// only the compression function is needed to decompress a message.
+func ExtractCompression(marshaledEncryptionHeader []byte) (CompressionFunction, error) {
+	elements, err := segment.ExtractElements(marshaledEncryptionHeader)
+	if err != nil {
+		return "", err
+	}
+	if len(elements) == 0 {
+		return "", fmt.Errorf("malformed encryption header")
+	}
+	header, err := element.ExtractElements(elements[0])
+	if err != nil {
+		return "", err
+	}
+	if len(header) < 3 {
+		return "", fmt.Errorf("malformed encryption header: missing version")
+	}
+	// the security profile got added with version 3
+	position := 7
+	if string(header[2]) != "2" {
+		position = 8
+	}
+	if len(elements) <= position || len(elements[position]) == 0 {
+		return CompressionNone, nil
+	}
+	return CompressionFunction(elements[position]), nil
+}
+
+// Compress compresses data with the compression function fn
+func Compress(fn CompressionFunction, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch fn {
+	case CompressionNone, "":
+		return data, nil
+	case CompressionDeflate:
+		w = zlib.NewWriter(&buf)
+	default:
+		return nil, fmt.Errorf("unsupported compression function %q", fn)
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress decompresses data compressed with the compression function fn.
+// For CompressionDeflate, zlib, gzip and raw deflate streams are accepted.
+func Decompress(fn CompressionFunction, data []byte) ([]byte, error) {
+	var r io.Reader
+	switch fn {
+	case CompressionNone, "":
+		return data, nil
+	case CompressionDeflate:
+		dr, err := deflateReader(data)
+		if err != nil {
+			return nil, err
+		}
+		defer dr.Close()
+		r = dr
+	case CompressionBZip2:
+		r = bzip2.NewReader(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("unsupported compression function %q", fn)
+	}
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing data: %w", err)
+	}
+	return decompressed, nil
+}
+
+// deflateReader detects whether data is a gzip, zlib or raw deflate stream.
+func deflateReader(data []byte) (io.ReadCloser, error) {
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("error reading gzip data: %w", err)
+		}
+		return r, nil
+	}
+	// a zlib header uses deflate with a window of at most 32K and a check sum
+	if len(data) >= 2 && data[0]&0x0f == 8 && data[0]>>4 <= 7 && (uint16(data[0])<<8|uint16(data[1]))%31 == 0 {
+		r, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("error reading zlib data: %w", err)
+		}
+		return r, nil
+	}
+	return flate.NewReader(bytes.NewReader(data)), nil
+}
@@ -0,0 +1,176 @@
+package message
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+const compressionTestBody = "HIRMG:2:2:1+0100::Dialog beendet'HISYN:2:3:8+newClientSystemID'"
+
+// generated with bzip2 -9, as the standard library only supports decompression
+const bzip2TestBody = "\x42\x5a\x68\x39\x31\x41\x59\x26\x53\x59\x6a\x80\xd1\x81\x00\x00\x0d\x9f\x80\x40\x88\x78\x50\x0c\xe3\x18\x20\x36\xa7\x8c\xa0\x20\x00\x48\x8a\x7a\x47\xa9\xa3\x26\x87\xa8\x3d\x43\x34\xd4\x0c\x30\x00\x00\x86\x00\xea\x2c\xf8\x0b\xf0\x88\x8d\x81\x00\x91\xba\x44\x5e\x9b\xb6\xd4\x17\x70\xe1\x1e\x06\x94\x4a\xcc\x94\x94\x9a\x6d\x56\x37\x56\xf0\x5b\x01\xb6\x06\xf0\x5d\xc9\x14\xe1\x42\x41\xaa\x03\x46\x04"
+
+// generated with zlib 1.2.13 at level 9, as referenced by the specification
+// for deflate
+const zlibTestBody = "\x78\xda\xf3\xf0\x0c\xf2\x75\xb7\x32\x02\x42\x43\x6d\x03\x43\x03\x03\x2b\x2b\x97\xcc\xc4\x9c\xfc\x74\x85\xa4\xd4\xd4\xbc\x94\xd4\x12\x75\x0f\xcf\xe0\x48\x3f\xa0\xb4\xb1\x95\x85\x76\x5e\x6a\xb9\x73\x4e\x66\x6a\x5e\x49\x70\x65\x71\x49\x6a\xae\xa7\x8b\x3a\x00\x41\xc3\x13\x6c"
+
+func TestCompressDecompress(t *testing.T) {
+	for _, fn := range []CompressionFunction{CompressionNone, CompressionDeflate} {
+		compressed, err := Compress(fn, []byte(compressionTestBody))
+		if err != nil {
+			t.Logf("%s: Expected no error, got %v\n", fn, err)
+			t.Fail()
+			continue
+		}
+		if fn != CompressionNone && bytes.Equal(compressed, []byte(compressionTestBody)) {
+			t.Logf("%s: Expected data to be compressed, got %q\n", fn, compressed)
+			t.Fail()
+		}
+
+		decompressed, err := Decompress(fn, compressed)
+
+		if err != nil {
+			t.Logf("%s: Expected no error, got %v\n", fn, err)
+			t.Fail()
+		}
+		if string(decompressed) != compressionTestBody {
+			t.Logf("%s: Expected %q, got %q\n", fn, compressionTestBody, decompressed)
+			t.Fail()
+		}
+	}
+}
+
+func TestDecompressLocallyGeneratedData(t *testing.T) {
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	gw.Write([]byte(compressionTestBody))
+	gw.Close()
+	var deflated bytes.Buffer
+	fw, _ := flate.NewWriter(&deflated, flate.BestCompression)
+	fw.Write([]byte(compressionTestBody))
+	fw.Close()
+
+	tests := []struct {
+		name string
+		fn   CompressionFunction
+		data []byte
+	}{
+		{"zlib", CompressionDeflate, []byte(zlibTestBody)},
+		{"gzip", CompressionDeflate, gzipped.Bytes()},
+		{"raw deflate", CompressionDeflate, deflated.Bytes()},
+		{"bzip2", CompressionBZip2, []byte(bzip2TestBody)},
+	}
+	for _, test := range tests {
+		decompressed, err := Decompress(test.fn, test.data)
+
+		if err != nil {
+			t.Logf("%s: Expected no error, got %v\n", test.name, err)
+			t.Fail()
+		}
+		if string(decompressed) != compressionTestBody {
+			t.Logf("%s: Expected %q, got %q\n", test.name, compressionTestBody, decompressed)
+			t.Fail()
+		}
+	}
+
+	for _, fn := range []CompressionFunction{CompressionLZW, CompressionZIP, CompressionBZip2} {
+		if _, err := Compress(fn, []byte(compressionTestBody)); err == nil {
+			t.Logf("%s: Expected compressing to fail\n", fn)
+			t.Fail()
+		}
+	}
+}
+
+func TestNegotiateCompression(t *testing.T) {
+	tests := []struct {
+		institutes []string
+		expected   CompressionFunction
+	}{
+		{nil, CompressionNone},
+		{[]string{"0", "3", "7"}, CompressionNone},
+		{[]string{"1", "5"}, CompressionNone},
+		{[]string{"1", "6", "7"}, CompressionDeflate},
+	}
+	for _, test := range tests {
+		actual := NegotiateCompression(test.institutes)
+
+		if actual != test.expected {
+			t.Logf("%v: Expected %q, got %q\n", test.institutes, test.expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func TestExtractCompression(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected CompressionFunction
+	}{
+		{"HNVSK:998:2:+998+1+1::0+1:20150713:173634+2:2:13:@8@\x00\x00\x00\x00\x00\x00\x00\x00:5:1:+280:10000000:12345:V:0:0+6+'", CompressionDeflate},
+		{"HNVSK:998:3+PIN:1+998+1+1::0+1:20150713:173634+2:2:13:@8@\x00\x00\x00\x00\x00\x00\x00\x00:5:1+280:10000000:12345:V:0:0+5'", CompressionZIP},
+		{"HNVSK:998:3+PIN:1+998+1+1::0+1:20150713:173634+2:2:13:@8@\x00\x00\x00\x00\x00\x00\x00\x00:5:1+280:10000000:12345:V:0:0'", CompressionNone},
+	}
+	for _, test := range tests {
+		actual, err := ExtractCompression([]byte(test.header))
+
+		if err != nil {
+			t.Logf("Expected no error, got %v\n", err)
+			t.Fail()
+		}
+		if actual != test.expected {
+			t.Logf("Expected %q, got %q\n", test.expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func TestBasicMessageEncryptCompressed(t *testing.T) {
+	keyName := domain.NewPinTanKeyName(domain.BankID{CountryCode: 280, ID: "1"}, "userID", "V")
+	provider := NewPinTanCryptoProvider(domain.NewPinKey("abcde", keyName), "clientSystemID")
+	for _, version := range []segment.HBCIVersion{segment.HBCI220, segment.FINTS300} {
+		message := NewBasicMessage(NewHBCIMessage(version, segment.NewProcessingPreparationSegmentV3(0, 0, domain.German)))
+		message.Header = segment.NewMessageHeaderSegment(0, version.Version(), "abcde", 2)
+		message.End = segment.NewMessageEndSegment(-1, 2)
+		message.SetCompression(CompressionDeflate)
+
+		encryptedMessage, err := message.Encrypt(provider)
+		if err != nil {
+			t.Fatalf("%d: Expected no error, got %v", version.Version(), err)
+		}
+
+		if compression := encryptedMessage.EncryptionHeader.Compression(); compression != string(CompressionDeflate) {
+			t.Logf("%d: Expected encryption header to name compression %q, got %q\n", version.Version(), CompressionDeflate, compression)
+			t.Fail()
+		}
+		decrypted, err := Decompress(CompressionDeflate, encryptedMessage.EncryptedData.Data.Val())
+		if err != nil || !bytes.HasPrefix(decrypted, []byte("HKVVB:")) {
+			t.Logf("%d: Expected compressed data to contain the jobs, got %q (%v)\n", version.Version(), decrypted, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestEncryptedMessageDecryptCompressed(t *testing.T) {
+	keyName := domain.NewPinTanKeyName(domain.BankID{CountryCode: 280, ID: "1"}, "userID", "V")
+	provider := NewPinTanCryptoProvider(domain.NewPinKey("abcde", keyName), "clientSystemID")
+	header := segment.NewMessageHeaderSegment(1, 300, "abcde", 1)
+	encryptedMessage := NewEncryptedMessage(header, segment.NewMessageEndSegment(4, 1), segment.FINTS300)
+	encryptedMessage.Compression = CompressionDeflate
+	encryptedMessage.EncryptedData = segment.NewEncryptedDataSegment([]byte(zlibTestBody))
+
+	decryptedMessage, err := encryptedMessage.Decrypt(provider)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	syncSegment := decryptedMessage.FindMarshaledSegment("HISYN")
+	if expected := "HISYN:2:3:8+newClientSystemID'"; string(syncSegment) != expected {
+		t.Logf("Expected decrypted message to contain %q, got %q\n", expected, syncSegment)
+		t.Fail()
+	}
+}
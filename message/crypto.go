@@ -17,6 +17,49 @@ type CryptoProvider interface {
 	WriteEncryptionHeader(header segment.EncryptionHeader)
 }
 
+// A MessageKeyDecrypter decrypts messages whose message key is transported
+// encrypted within the encryption header, as done by RDH and RAH.
+type MessageKeyDecrypter interface {
+	DecryptWithMessageKey(encryptedMessageKey, encryptedMessage []byte) ([]byte, error)
+}
+
+// ExtractMessageKey returns the encrypted message key named within the
+// marshaled encryption header. It returns nil if the header does not
+// transport a message key, e.g. for PIN/TAN.
+func ExtractMessageKey(marshaledEncryptionHeader []byte) ([]byte, error) {
+	elements, err := segment.ExtractElements(marshaledEncryptionHeader)
+	if err != nil {
+		return nil, err
+	}
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("malformed encryption header")
+	}
+	header, err := element.ExtractElements(elements[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(header) < 3 {
+		return nil, fmt.Errorf("malformed encryption header: missing version")
+	}
+	// the security profile got added with version 3
+	position := 5
+	if string(header[2]) != "2" {
+		position = 6
+	}
+	if len(elements) <= position || len(elements[position]) == 0 {
+		return nil, nil
+	}
+	algorithm := &element.EncryptionAlgorithmDataElement{}
+	if err := algorithm.UnmarshalHBCI(elements[position]); err != nil {
+		return nil, fmt.Errorf("malformed encryption algorithm: %w", err)
+	}
+	// "6" is KYP, a symmetric key encrypted with a public key
+	if algorithm.KeyParamID == nil || algorithm.KeyParamID.Val() != "6" || algorithm.Key == nil {
+		return nil, nil
+	}
+	return algorithm.Key.Val(), nil
+}
+
 // NewPinTanCryptoProvider creates a new CryptoProvider for the pin key
 func NewPinTanCryptoProvider(key *domain.PinKey, clientSystemID string) CryptoProvider {
 	return &pinTanCryptoProvider{
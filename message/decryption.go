@@ -85,6 +85,22 @@ func (d *decryptedMessage) FindSegments(segmentID string) []segment.Segment {
 	return d.unmarshaler.SegmentsByID(segmentID)
 }
 
+// FindGenericSegments returns all segments for segmentID which are unknown to
+// the library
+func (d *decryptedMessage) FindGenericSegments(segmentID string) []*segment.GenericSegment {
+	return d.unmarshaler.GenericSegmentsByID(segmentID)
+}
+
+// TANRequired returns true if one of the acknowledgements requests a TAN
+func (d *decryptedMessage) TANRequired() bool {
+	for _, ack := range d.Acknowledgements() {
+		if ack.Code == element.AcknowledgementTanRequired {
+			return true
+		}
+	}
+	return false
+}
+
 func (d *decryptedMessage) SegmentPosition(segmentID string) int {
 	seg := d.unmarshaler.MarshaledSegmentByID(segmentID)
 	if len(seg) == 0 {
@@ -85,10 +85,12 @@ func (d *DialogFinishingMessage) jobs() []segment.ClientSegment {
 	}
 }
 
-// NewDialogCancellationMessage creates a message to cancel a dialog
-func NewDialogCancellationMessage(messageAcknowledgement *segment.MessageAcknowledgement) *DialogCancellationMessage {
+// NewDialogCancellationMessage creates a message to cancel a dialog. The
+// messageAcknowledgement tells the institute why the dialog is cancelled.
+func NewDialogCancellationMessage(hbciVersion segment.HBCIVersion, messageAcknowledgement *segment.MessageAcknowledgement) *DialogCancellationMessage {
 	d := &DialogCancellationMessage{
 		MessageAcknowledgements: messageAcknowledgement,
+		hbciVersion:             hbciVersion,
 	}
 	return d
 }
@@ -97,6 +99,23 @@ func NewDialogCancellationMessage(messageAcknowledgement *segment.MessageAcknowl
 type DialogCancellationMessage struct {
 	*BasicMessage
 	MessageAcknowledgements *segment.MessageAcknowledgement
+	hbciVersion             segment.HBCIVersion
+}
+
+// HBCIVersion returns the version used for this message
+func (d *DialogCancellationMessage) HBCIVersion() segment.HBCIVersion {
+	return d.hbciVersion
+}
+
+// HBCISegments returns all segment from this message
+func (d *DialogCancellationMessage) HBCISegments() []segment.ClientSegment {
+	return []segment.ClientSegment{
+		d.MessageAcknowledgements.Segment.(segment.ClientSegment),
+	}
+}
+
+func (d *DialogCancellationMessage) jobs() []segment.ClientSegment {
+	return d.HBCISegments()
 }
 
 // AnonymousDialogMessage represents a message used by anonymous dialogs
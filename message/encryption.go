@@ -2,6 +2,7 @@ package message
 
 import (
 	"crypto/rand"
+	"fmt"
 
 	"github.com/mitch000001/go-hbci/segment"
 )
@@ -32,7 +33,13 @@ type EncryptedMessage struct {
 	ClientMessage
 	EncryptionHeader segment.EncryptionHeader
 	EncryptedData    *segment.EncryptedDataSegment
-	hbciVersion      segment.HBCIVersion
+	// Compression is the compression function applied to the data before
+	// encryption. If empty, the one of the EncryptionHeader is used.
+	Compression CompressionFunction
+	// MessageKey is the encrypted message key transported within the
+	// EncryptionHeader, if any. It is used with a MessageKeyDecrypter.
+	MessageKey  []byte
+	hbciVersion segment.HBCIVersion
 }
 
 // HBCIVersion returns the HBCIVersion of this message
@@ -48,12 +55,28 @@ func (e *EncryptedMessage) HBCISegments() []segment.ClientSegment {
 	}
 }
 
-// Decrypt decrypts the message using the CryptoProvider
+// Decrypt decrypts the message using the CryptoProvider. If the message is
+// compressed, the data get decompressed afterwards. If the message has a
+// MessageKey, the provider must implement MessageKeyDecrypter.
 func (e *EncryptedMessage) Decrypt(provider CryptoProvider) (BankMessage, error) {
-	decryptedMessageBytes, err := provider.Decrypt(e.EncryptedData.Data.Val())
+	var decryptedMessageBytes []byte
+	var err error
+	if keyDecrypter, ok := provider.(MessageKeyDecrypter); ok && len(e.MessageKey) > 0 {
+		decryptedMessageBytes, err = keyDecrypter.DecryptWithMessageKey(e.MessageKey, e.EncryptedData.Data.Val())
+	} else {
+		decryptedMessageBytes, err = provider.Decrypt(e.EncryptedData.Data.Val())
+	}
 	if err != nil {
 		return nil, err
 	}
+	compression := e.Compression
+	if compression == "" && e.EncryptionHeader != nil {
+		compression = CompressionFunction(e.EncryptionHeader.Compression())
+	}
+	decryptedMessageBytes, err = Decompress(compression, decryptedMessageBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing message: %w", err)
+	}
 	decryptedMessage, err := NewDecryptedMessage(e.MessageHeader(), e.MessageEnd(), decryptedMessageBytes)
 	if err != nil {
 		return nil, err
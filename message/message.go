@@ -33,6 +33,10 @@ type BankMessage interface {
 	Message
 	Acknowledgements() []domain.Acknowledgement
 	SupportedSegments() []segment.VersionedSegment
+	FindGenericSegments(segmentID string) []*segment.GenericSegment
+	// TANRequired returns true if the institute needs a TAN to execute the
+	// jobs of the message (0030). The challenge is found in the HITAN segment.
+	TANRequired() bool
 }
 
 // HBCIMessage represents a basic set of message for introspecting HBCI messages
@@ -105,6 +109,13 @@ type BasicMessage struct {
 	HBCIMessage
 	hbciVersion      segment.HBCIVersion
 	marshaledContent []byte
+	compression      CompressionFunction
+}
+
+// SetCompression sets the compression function used to compress the message
+// before encryption
+func (b *BasicMessage) SetCompression(compression CompressionFunction) {
+	b.compression = compression
 }
 
 // SetSegmentPositions sets the message number on every segment within the message
@@ -239,11 +250,20 @@ func (b *BasicMessage) Sign(provider SignatureProvider) (*BasicSignedMessage, er
 	provider.WriteSignatureHeader(b.SignatureBegin)
 	b.SignatureEnd = b.hbciVersion.SignatureEnd()
 	b.SetSegmentPositions()
+	// the signature covers the data as sent
 	var buffer bytes.Buffer
-	buffer.WriteString(b.SignatureBegin.String())
+	sigBytes, err := b.SignatureBegin.MarshalHBCI()
+	if err != nil {
+		return nil, err
+	}
+	buffer.Write(sigBytes)
 	for _, segment := range b.HBCIMessage.HBCISegments() {
 		if !reflect.ValueOf(segment).IsNil() {
-			buffer.WriteString(segment.String())
+			segBytes, err := segment.MarshalHBCI()
+			if err != nil {
+				return nil, err
+			}
+			buffer.Write(segBytes)
 		}
 	}
 	sig, err := provider.Sign(buffer.Bytes())
@@ -284,6 +304,10 @@ func (b *BasicMessage) Encrypt(provider CryptoProvider) (*EncryptedMessage, erro
 		}
 		messageBytes = append(messageBytes, sigEndBytes...)
 	}
+	messageBytes, err := Compress(b.compression, messageBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error compressing message: %w", err)
+	}
 	encryptedMessage, err := provider.Encrypt(messageBytes)
 	if err != nil {
 		return nil, err
@@ -291,6 +315,9 @@ func (b *BasicMessage) Encrypt(provider CryptoProvider) (*EncryptedMessage, erro
 	encryptionMessage := NewEncryptedMessage(b.Header, b.End, b.hbciVersion)
 	encryptionMessage.EncryptionHeader = b.hbciVersion.PinTanEncryptionHeader("", domain.KeyName{})
 	provider.WriteEncryptionHeader(encryptionMessage.EncryptionHeader)
+	if b.compression != "" {
+		encryptionMessage.EncryptionHeader.SetCompression(string(b.compression))
+	}
 	encryptionMessage.EncryptedData = segment.NewEncryptedDataSegment(encryptedMessage)
 	return encryptionMessage, nil
 }
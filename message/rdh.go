@@ -0,0 +1,376 @@
+package message
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// ErrInvalidSignature is returned if the signature of a message does not
+// match the signing key of the institute.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// A SecurityProfile names a key based security method and its version, e.g.
+// RDH-10.
+type SecurityProfile struct {
+	Method  string
+	Version int
+}
+
+// The supported security profiles. Both sign with RSASSA-PSS over SHA-256
+// and transport the message key with RSAES-PKCS#1 v1.5. RDH-10 encrypts
+// messages with 2-Key-Triple-DES, RAH-10 with AES-256, both in CBC mode.
+//
+// The signature scheme follows FinTS 3.0 Security - Sicherheitsverfahren HBCI
+// (final version 2018-11-29): chapter B.1.5 requires RSASSA-PSS with a salt
+// as long as the hash and double hashing with SHA-256 for RAH-10, and
+// chapter B.6.2.1 states that changing from RDH-10 to RAH-10 only changes
+// the encryption algorithm, so RDH-10 signs the same way. The data
+// dictionary (Operationsmodus, kodiert) no longer permits the ISO 9796
+// modes implemented in package crypto, which are left to the older RDH
+// profiles.
+var (
+	RDH10 = SecurityProfile{Method: "RDH", Version: 10}
+	RAH10 = SecurityProfile{Method: "RAH", Version: 10}
+)
+
+const (
+	hashAlgorithmSHA256SHA256  = "6"
+	signatureOperationModePSS  = "19"
+	encryptionAlgorithm2K3DES  = "13"
+	encryptionAlgorithmAES256  = "14"
+	securityFunctionEncryption = "4"
+	securityFunctionSigning    = "1"
+)
+
+func (s SecurityProfile) String() string {
+	return fmt.Sprintf("%s-%d", s.Method, s.Version)
+}
+
+// Validate returns an error if the profile is not supported.
+func (s SecurityProfile) Validate() error {
+	if s != RDH10 && s != RAH10 {
+		return fmt.Errorf("unsupported security profile %s", s)
+	}
+	return nil
+}
+
+// encryptionAlgorithm returns the code of the symmetric algorithm and the
+// length of its message keys
+func (s SecurityProfile) encryptionAlgorithm() (string, int) {
+	if s.Method == RAH10.Method {
+		return encryptionAlgorithmAES256, 32
+	}
+	return encryptionAlgorithm2K3DES, 16
+}
+
+func (s SecurityProfile) newCipher(messageKey []byte) (cipher.Block, error) {
+	algorithm, keyLength := s.encryptionAlgorithm()
+	if len(messageKey) != keyLength {
+		return nil, fmt.Errorf("%s: message key must have %d bytes, got %d", s, keyLength, len(messageKey))
+	}
+	if algorithm == encryptionAlgorithmAES256 {
+		return aes.NewCipher(messageKey)
+	}
+	// 2-Key-Triple-DES uses the first key for the third stage as well
+	tripleDESKey := append(append([]byte(nil), messageKey...), messageKey[:8]...)
+	return des.NewTripleDESCipher(tripleDESKey)
+}
+
+// signatureHash returns the hash signed with RSASSA-PSS. The message is
+// hashed twice, once by the application and once as part of the padding,
+// as announced by the hash algorithm "SHA-256 / SHA-256".
+func signatureHash(message []byte) []byte {
+	applicationHash := sha256.Sum256(message)
+	hash := sha256.Sum256(applicationHash[:])
+	return hash[:]
+}
+
+var pssOptions = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}
+
+func (s SecurityProfile) sign(key *rsa.PrivateKey, message []byte) ([]byte, error) {
+	if key == nil {
+		return nil, fmt.Errorf("%s: missing private signing key", s)
+	}
+	return rsa.SignPSS(rand.Reader, key, crypto.SHA256, signatureHash(message), pssOptions)
+}
+
+func (s SecurityProfile) verify(key *rsa.PublicKey, message, signature []byte) error {
+	err := rsa.VerifyPSS(key, crypto.SHA256, signatureHash(message), signature, pssOptions)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// encrypt encrypts message with a new message key. It returns the message key
+// and the encrypted message.
+func (s SecurityProfile) encrypt(message []byte) ([]byte, []byte, error) {
+	_, keyLength := s.encryptionAlgorithm()
+	messageKey := make([]byte, keyLength)
+	if _, err := rand.Read(messageKey); err != nil {
+		return nil, nil, err
+	}
+	block, err := s.newCipher(messageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	encrypted := pad(message, block.BlockSize())
+	cipher.NewCBCEncrypter(block, make([]byte, block.BlockSize())).CryptBlocks(encrypted, encrypted)
+	return messageKey, encrypted, nil
+}
+
+func (s SecurityProfile) decrypt(messageKey, encryptedMessage []byte) ([]byte, error) {
+	block, err := s.newCipher(messageKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(encryptedMessage) == 0 || len(encryptedMessage)%block.BlockSize() != 0 {
+		return nil, fmt.Errorf("%s: encrypted message is not a multiple of the block size", s)
+	}
+	decrypted := make([]byte, len(encryptedMessage))
+	cipher.NewCBCDecrypter(block, make([]byte, block.BlockSize())).CryptBlocks(decrypted, encryptedMessage)
+	return unpad(decrypted, block.BlockSize())
+}
+
+// pad pads message according to ANSI X9.23: zeros followed by a byte stating
+// the number of padding bytes. At least one byte is added.
+func pad(message []byte, blockSize int) []byte {
+	padding := blockSize - len(message)%blockSize
+	padded := make([]byte, len(message)+padding)
+	copy(padded, message)
+	padded[len(padded)-1] = byte(padding)
+	return padded
+}
+
+func unpad(message []byte, blockSize int) ([]byte, error) {
+	padding := int(message[len(message)-1])
+	if padding == 0 || padding > blockSize || padding > len(message) {
+		return nil, fmt.Errorf("malformed padding")
+	}
+	return message[:len(message)-padding], nil
+}
+
+// NewRDHSignatureProvider creates a new SignatureProvider signing with
+// signingKey according to profile. signatureID is the last signature ID
+// used with the key, it gets incremented for every signed message.
+func NewRDHSignatureProvider(profile SecurityProfile, signingKey *domain.RSAKey, signatureID int) SignatureProvider {
+	controlReference := generateControlReference(signingKey)
+	return &rdhSignatureProvider{
+		profile:          profile,
+		signingKey:       signingKey,
+		controlReference: controlReference,
+		signatureID:      signatureID,
+		securityFn:       securityFunctionSigning,
+	}
+}
+
+type rdhSignatureProvider struct {
+	profile          SecurityProfile
+	signingKey       *domain.RSAKey
+	clientSystemID   string
+	controlReference string
+	securityFn       string
+	signatureID      int
+}
+
+func (r *rdhSignatureProvider) SetClientSystemID(clientSystemID string) {
+	r.clientSystemID = clientSystemID
+}
+
+func (r *rdhSignatureProvider) SetSecurityFunction(securityFn string) {
+	r.securityFn = securityFn
+}
+
+// SignatureID returns the last signature ID used
+func (r *rdhSignatureProvider) SignatureID() int {
+	return r.signatureID
+}
+
+// SetSignatureID sets the last signature ID used, e.g. as reported by the
+// institute on synchronization
+func (r *rdhSignatureProvider) SetSignatureID(signatureID int) {
+	r.signatureID = signatureID
+}
+
+func (r *rdhSignatureProvider) Sign(message []byte) ([]byte, error) {
+	return r.profile.sign(r.signingKey.SigningKey(), message)
+}
+
+func (r *rdhSignatureProvider) WriteSignatureHeader(header segment.SignatureHeader) {
+	r.signatureID++
+	header.SetSecurityFunction(r.securityFn)
+	header.SetSecurityMethod(r.profile.Method, r.profile.Version)
+	header.SetClientSystemID(r.clientSystemID)
+	header.SetSigningKeyName(r.signingKey.KeyName())
+	header.SetSignatureID(r.signatureID)
+	header.SetControlReference(r.controlReference)
+	header.SetHashAlgorithm(element.NewHashAlgorithm(hashAlgorithmSHA256SHA256))
+	header.SetSignatureAlgorithm(element.NewRSASignatureAlgorithm(signatureOperationModePSS))
+}
+
+func (r *rdhSignatureProvider) WriteSignature(end segment.SignatureEnd, signature []byte) {
+	end.SetSignature(signature)
+	end.SetControlReference(r.controlReference)
+}
+
+// A SignatureVerifier verifies the signature of messages sent by the
+// institute
+type SignatureVerifier interface {
+	Verify(bankMessage BankMessage) error
+}
+
+// NewRDHSignatureVerifier returns a SignatureVerifier checking signatures
+// made according to profile with the signing key of the institute. Messages
+// without signature are only accepted if requireSignature is false, as
+// institutes do not sign every message, e.g. error messages.
+func NewRDHSignatureVerifier(profile SecurityProfile, bankSigningKey *domain.RSAKey, requireSignature bool) SignatureVerifier {
+	return &rdhSignatureVerifier{
+		profile:          profile,
+		signingKey:       bankSigningKey,
+		requireSignature: requireSignature,
+	}
+}
+
+type rdhSignatureVerifier struct {
+	profile          SecurityProfile
+	signingKey       *domain.RSAKey
+	requireSignature bool
+}
+
+type marshaledSegmentsProvider interface {
+	MarshaledSegments() [][]byte
+}
+
+// Verify checks the signature over the segments from the signature header up
+// to the signature end, as sent by the institute.
+func (r *rdhSignatureVerifier) Verify(bankMessage BankMessage) error {
+	provider, ok := bankMessage.(marshaledSegmentsProvider)
+	if !ok {
+		return fmt.Errorf("cannot verify signature of %T", bankMessage)
+	}
+	segments := provider.MarshaledSegments()
+	begin, end := -1, -1
+	for i, seg := range segments {
+		if bytes.HasPrefix(seg, []byte("HNSHK:")) && begin == -1 {
+			begin = i
+		}
+		if bytes.HasPrefix(seg, []byte("HNSHA:")) {
+			end = i
+		}
+	}
+	if begin == -1 && end == -1 {
+		if r.requireSignature {
+			return fmt.Errorf("%w: message is not signed", ErrInvalidSignature)
+		}
+		return nil
+	}
+	if begin == -1 || end < begin {
+		return fmt.Errorf("%w: malformed signature segments", ErrInvalidSignature)
+	}
+	signature, err := extractSignature(segments[end])
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	signedData := bytes.Join(segments[begin:end], nil)
+	return r.profile.verify(r.signingKey.RSAPublicKey(), signedData, signature)
+}
+
+// extractSignature returns the signature of the marshaled signature end
+func extractSignature(marshaledSignatureEnd []byte) ([]byte, error) {
+	elements, err := segment.ExtractElements(marshaledSignatureEnd)
+	if err != nil {
+		return nil, err
+	}
+	if len(elements) < 3 || len(elements[2]) == 0 {
+		return nil, fmt.Errorf("missing signature")
+	}
+	signature := &element.BinaryDataElement{}
+	if err := signature.UnmarshalHBCI(elements[2]); err != nil {
+		return nil, err
+	}
+	return signature.Val(), nil
+}
+
+// NewRDHCryptoProvider creates a new CryptoProvider for profile. Messages
+// get encrypted with a new message key, which is transported encrypted with
+// the public encryptionKey of the receiver. Received messages get decrypted
+// with the private decryptionKey.
+func NewRDHCryptoProvider(profile SecurityProfile, encryptionKey, decryptionKey *domain.RSAKey, clientSystemID string) CryptoProvider {
+	return &rdhCryptoProvider{
+		profile:        profile,
+		encryptionKey:  encryptionKey,
+		decryptionKey:  decryptionKey,
+		clientSystemID: clientSystemID,
+		securityFn:     securityFunctionEncryption,
+	}
+}
+
+type rdhCryptoProvider struct {
+	profile             SecurityProfile
+	encryptionKey       *domain.RSAKey
+	decryptionKey       *domain.RSAKey
+	clientSystemID      string
+	securityFn          string
+	encryptedMessageKey []byte
+}
+
+func (r *rdhCryptoProvider) SetClientSystemID(clientSystemID string) {
+	r.clientSystemID = clientSystemID
+}
+
+func (r *rdhCryptoProvider) SetSecurityFunction(securityFn string) {
+	r.securityFn = securityFn
+}
+
+func (r *rdhCryptoProvider) Encrypt(message []byte) ([]byte, error) {
+	if r.encryptionKey == nil || r.encryptionKey.RSAPublicKey() == nil {
+		return nil, fmt.Errorf("%s: missing public encryption key of the receiver", r.profile)
+	}
+	messageKey, encrypted, err := r.profile.encrypt(message)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting message: %w", err)
+	}
+	r.encryptedMessageKey, err = r.encryptionKey.Encrypt(messageKey)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting message key: %w", err)
+	}
+	return encrypted, nil
+}
+
+// Decrypt returns an error, as the message key is needed to decrypt, see
+// DecryptWithMessageKey.
+func (r *rdhCryptoProvider) Decrypt(encryptedMessage []byte) ([]byte, error) {
+	return nil, fmt.Errorf("%s: missing message key", r.profile)
+}
+
+func (r *rdhCryptoProvider) DecryptWithMessageKey(encryptedMessageKey, encryptedMessage []byte) ([]byte, error) {
+	if r.decryptionKey == nil || r.decryptionKey.SigningKey() == nil {
+		return nil, fmt.Errorf("%s: missing private decryption key", r.profile)
+	}
+	messageKey, err := rsa.DecryptPKCS1v15(rand.Reader, r.decryptionKey.SigningKey(), encryptedMessageKey)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting message key: %w", err)
+	}
+	return r.profile.decrypt(messageKey, encryptedMessage)
+}
+
+func (r *rdhCryptoProvider) WriteEncryptionHeader(header segment.EncryptionHeader) {
+	algorithm, _ := r.profile.encryptionAlgorithm()
+	header.SetSecurityFunction(r.securityFn)
+	header.SetSecurityMethod(r.profile.Method, r.profile.Version)
+	header.SetClientSystemID(r.clientSystemID)
+	header.SetEncryptionKeyName(r.encryptionKey.KeyName())
+	header.SetEncryptionAlgorithm(element.NewHybridEncryptionAlgorithm(algorithm, r.encryptedMessageKey))
+}
@@ -0,0 +1,233 @@
+package message
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// testKeySize keeps the key generation fast, real keys have 2048 bits
+const testKeySize = 1024
+
+func generateTestKey(t *testing.T, keyType domain.KeyType) *domain.RSAKey {
+	key, err := domain.GenerateKey(keyType, testKeySize)
+	if err != nil {
+		t.Fatalf("Expected no error generating key, got %v", err)
+	}
+	return domain.NewRSAKey(key, domain.NewInitialKeyName(280, "10000000", "12345", keyType))
+}
+
+func TestRDHCryptoProviderRoundTrip(t *testing.T) {
+	receiverKey := generateTestKey(t, domain.KeyTypeEncryption)
+	publicKey := domain.NewRSAKey(
+		domain.NewPublicKey(domain.KeyTypeEncryption, receiverKey.RSAPublicKey()),
+		domain.NewInitialKeyName(280, "10000000", "12345", domain.KeyTypeEncryption),
+	)
+	body := []byte("HNSHK:2:4+RDH:10+1'HIRMG:3:2+0010::Nachricht entgegengenommen'")
+
+	for _, profile := range []SecurityProfile{RDH10, RAH10} {
+		sender := NewRDHCryptoProvider(profile, publicKey, nil, "xyz")
+		receiver := NewRDHCryptoProvider(profile, nil, receiverKey, "xyz")
+
+		encrypted, err := sender.Encrypt(body)
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", profile, err)
+		}
+		if bytes.Contains(encrypted, []byte("HIRMG")) {
+			t.Logf("%s: Expected message to be encrypted, got %q\n", profile, encrypted)
+			t.Fail()
+		}
+		header := segment.FINTS300.PinTanEncryptionHeader("xyz", domain.KeyName{})
+		sender.WriteEncryptionHeader(header)
+		header.SetPosition(func() int { return 998 })
+		marshaledHeader, err := header.MarshalHBCI()
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", profile, err)
+		}
+		if !bytes.Contains(marshaledHeader, []byte(fmt.Sprintf("+%s:%d+4+", profile.Method, profile.Version))) {
+			t.Logf("%s: Expected header to contain profile and security function, got %q\n", profile, marshaledHeader)
+			t.Fail()
+		}
+
+		messageKey, err := ExtractMessageKey(marshaledHeader)
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", profile, err)
+		}
+		decrypted, err := receiver.(MessageKeyDecrypter).DecryptWithMessageKey(messageKey, encrypted)
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", profile, err)
+		}
+		if !bytes.Equal(body, decrypted) {
+			t.Logf("%s: Expected decrypted message to equal\n%q\n\tgot\n%q\n", profile, body, decrypted)
+			t.Fail()
+		}
+	}
+}
+
+func TestExtractMessageKeyPinTan(t *testing.T) {
+	header := "HNVSK:998:3+PIN:1+998+1+1::xyz+1:20150713:173634+2:2:13:@8@\x00\x00\x00\x00\x00\x00\x00\x00:5:1+280:10000000:12345:V:0:0+0'"
+
+	messageKey, err := ExtractMessageKey([]byte(header))
+
+	if err != nil {
+		t.Logf("Expected no error, got %v\n", err)
+		t.Fail()
+	}
+	if messageKey != nil {
+		t.Logf("Expected no message key for PIN/TAN, got %q\n", messageKey)
+		t.Fail()
+	}
+}
+
+func TestRDHSignatureVerifier(t *testing.T) {
+	bankKey := generateTestKey(t, domain.KeyTypeSigning)
+	signer := NewRDHSignatureProvider(RAH10, bankKey, 41)
+	body := "HIRMG:3:2+0010::Nachricht entgegengenommen'"
+
+	signedMessage := func(body string) []byte {
+		signatureHeader := "HNSHK:2:4+RAH:10+1+ctrlref+1+1+1::xyz+42+1:20150713:173634+1:6:1+6:10:19+280:10000000:12345:S:1:1'"
+		signature, err := signer.Sign([]byte(signatureHeader + body))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		signatureEnd := fmt.Sprintf("HNSHA:4:2+ctrlref+@%d@%s'", len(signature), signature)
+		return []byte(signatureHeader + body + signatureEnd)
+	}
+	bankMessage := func(raw []byte) BankMessage {
+		header := segment.NewMessageHeaderSegment(1, 300, "abcde", 1)
+		msg, err := NewDecryptedMessage(header, nil, raw)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return msg
+	}
+	verifier := NewRDHSignatureVerifier(RAH10, bankKey, false)
+	strictVerifier := NewRDHSignatureVerifier(RAH10, bankKey, true)
+
+	err := verifier.Verify(bankMessage(signedMessage(body)))
+	if err != nil {
+		t.Logf("Expected signed message to verify, got %v\n", err)
+		t.Fail()
+	}
+
+	tampered := bytes.Replace(signedMessage(body), []byte("0010"), []byte("0020"), 1)
+	err = verifier.Verify(bankMessage(tampered))
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Logf("Expected tampered message to fail with ErrInvalidSignature, got %v\n", err)
+		t.Fail()
+	}
+
+	err = verifier.Verify(bankMessage([]byte(body)))
+	if err != nil {
+		t.Logf("Expected unsigned message to be accepted, got %v\n", err)
+		t.Fail()
+	}
+	err = strictVerifier.Verify(bankMessage([]byte(body)))
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Logf("Expected unsigned message to be rejected, got %v\n", err)
+		t.Fail()
+	}
+}
+
+// pssTestVector was signed with OpenSSL as required by the FinTS security
+// specification: SHA-256 over the message, followed by RSASSA-PSS with
+// SHA-256 and a salt of 32 bytes over the resulting hash.
+var pssTestVector = struct {
+	modulus   string
+	message   string
+	signature string
+}{
+	modulus: "EA6401EF8B0ECB0B9FBB70786FCA649DF5886369E9FE50C04FCE2ACA172B34F79E1F06212F032087D8380DD72A66DEB01A0380CC5FF8B0FE98627DF12E52B4FE1F3DF51DF4D870DE0DDC1868A86EC8DC31E809CAF1148F727B8DD66587691B0FD2EB3B19BE5C66D489FA3DD8EF6B7B3EBFE50D3AC669D59E99BE27419D8AF35B",
+	message: "HNSHK:2:4+RAH:10+1+ctrlref+1+1+1::xyz+42+1:20150713:173634+1:6:1+6:10:19+280:10000000:12345:S:1:1'" +
+		"HIRMG:3:2+0010::Nachricht entgegengenommen'",
+	signature: "a1dca1c2b2e22d8410e571141c7ff611221602ef73d1897e1f5e8208bb99303a3415840064a7af41990774e4b3112e72e4fc2c9c1cd0ea831a87a37fc3ac2edd846de39480168e92e12cce9d095e744559e8d8ed341b3222c664bd3734435f1a22eeef6d82d8a0addb4493924f1e93247bf20aeb59e0a7b91faf68b5591c0470",
+}
+
+func TestSecurityProfileVerifyKnownVector(t *testing.T) {
+	modulus, ok := new(big.Int).SetString(pssTestVector.modulus, 16)
+	if !ok {
+		t.Fatalf("Expected valid modulus")
+	}
+	key := &rsa.PublicKey{N: modulus, E: 65537}
+	signature, err := hex.DecodeString(pssTestVector.signature)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, profile := range []SecurityProfile{RDH10, RAH10} {
+		err := profile.verify(key, []byte(pssTestVector.message), signature)
+		if err != nil {
+			t.Logf("%s: Expected known signature to verify, got %v\n", profile, err)
+			t.Fail()
+		}
+
+		tampered := strings.Replace(pssTestVector.message, "0010", "0020", 1)
+		err = profile.verify(key, []byte(tampered), signature)
+		if !errors.Is(err, ErrInvalidSignature) {
+			t.Logf("%s: Expected tampered message to fail with ErrInvalidSignature, got %v\n", profile, err)
+			t.Fail()
+		}
+	}
+
+	singleHash := sha256.Sum256([]byte(pssTestVector.message))
+	err = rsa.VerifyPSS(key, crypto.SHA256, singleHash[:], signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto})
+	if err == nil {
+		t.Logf("Expected signature not to verify over the single hashed message\n")
+		t.Fail()
+	}
+}
+
+func TestSecurityProfileSignRoundTrip(t *testing.T) {
+	key := generateTestKey(t, domain.KeyTypeSigning)
+	message := []byte(pssTestVector.message)
+
+	for _, profile := range []SecurityProfile{RDH10, RAH10} {
+		signature, err := profile.sign(key.SigningKey(), message)
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", profile, err)
+		}
+		if len(signature) != testKeySize/8 {
+			t.Logf("%s: Expected signature to have %d bytes, got %d\n", profile, testKeySize/8, len(signature))
+			t.Fail()
+		}
+		err = rsa.VerifyPSS(key.RSAPublicKey(), crypto.SHA256, signatureHash(message), signature, &rsa.PSSOptions{SaltLength: sha256.Size})
+		if err != nil {
+			t.Logf("%s: Expected signature with salt length of the hash, got %v\n", profile, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestRDHSignatureProviderSignatureID(t *testing.T) {
+	key := generateTestKey(t, domain.KeyTypeSigning)
+	provider := NewRDHSignatureProvider(RDH10, key, 41)
+
+	header := segment.FINTS300.SignatureHeader()
+	provider.WriteSignatureHeader(header)
+	header.SetPosition(func() int { return 2 })
+	marshaled, err := header.MarshalHBCI()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, expected := range []string{"+RDH:10+1+", "+42+", "+1:6:1+6:10:19+"} {
+		if !bytes.Contains(marshaled, []byte(expected)) {
+			t.Logf("Expected signature header to contain %q, got %q\n", expected, marshaled)
+			t.Fail()
+		}
+	}
+	if id := provider.(interface{ SignatureID() int }).SignatureID(); id != 42 {
+		t.Logf("Expected signature ID to be incremented to 42, got %d\n", id)
+		t.Fail()
+	}
+}
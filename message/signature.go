@@ -88,48 +88,3 @@ func (p *pinTanSignatureProvider) WriteSignature(end segment.SignatureEnd, signa
 	end.SetPinTan(p.key.Pin(), "")
 	end.SetControlReference(p.controlReference)
 }
-
-// NewRDHSignatureProvider creates a new SignatureProvider for the given signingKey
-func NewRDHSignatureProvider(signingKey *domain.RSAKey, signatureID int) SignatureProvider {
-	controlReference := generateControlReference(signingKey)
-	return &rdhSignatureProvider{
-		signingKey:       signingKey,
-		controlReference: controlReference,
-		signatureID:      signatureID,
-		securityFn:       "1",
-	}
-}
-
-type rdhSignatureProvider struct {
-	signingKey       *domain.RSAKey
-	clientSystemID   string
-	controlReference string
-	securityFn       string
-	signatureID      int
-}
-
-func (r *rdhSignatureProvider) SetClientSystemID(clientSystemID string) {
-	r.clientSystemID = clientSystemID
-}
-
-func (r *rdhSignatureProvider) SetSecurityFunction(securityFn string) {
-	r.securityFn = securityFn
-}
-
-func (r *rdhSignatureProvider) Sign(message []byte) ([]byte, error) {
-	hashSum := HashSum(string(message))
-	return r.signingKey.Sign(hashSum)
-}
-
-func (r *rdhSignatureProvider) WriteSignatureHeader(header segment.SignatureHeader) {
-	header.SetSecurityFunction(r.securityFn)
-	header.SetClientSystemID(r.clientSystemID)
-	header.SetSigningKeyName(r.signingKey.KeyName())
-	header.SetSignatureID(r.signatureID)
-	header.SetControlReference(r.controlReference)
-}
-
-func (r *rdhSignatureProvider) WriteSignature(end segment.SignatureEnd, signature []byte) {
-	end.SetSignature(signature)
-	end.SetControlReference(r.controlReference)
-}
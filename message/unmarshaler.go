@@ -13,6 +13,7 @@ func NewUnmarshaler(message []byte) *Unmarshaler {
 		rawMessage:       message,
 		segmentExtractor: NewSegmentExtractor(message),
 		segments:         make(map[string][]segment.Segment),
+		genericSegments:  make(map[string][]*segment.GenericSegment),
 	}
 }
 
@@ -21,6 +22,7 @@ type Unmarshaler struct {
 	rawMessage       []byte
 	segmentExtractor *SegmentExtractor
 	segments         map[string][]segment.Segment
+	genericSegments  map[string][]*segment.GenericSegment
 }
 
 // CanUnmarshal returns true if the segment with the ID and version can be
@@ -34,7 +36,8 @@ func (u *Unmarshaler) CanUnmarshal(segmentID string, version int) bool {
 	)
 }
 
-// Unmarshal unmarshals the raw message
+// Unmarshal unmarshals the raw message. Segments without an unmarshaler are
+// unmarshaled as GenericSegment.
 func (u *Unmarshaler) Unmarshal() error {
 	rawSegments, err := u.segmentExtractor.Extract()
 	if err != nil {
@@ -60,6 +63,13 @@ func (u *Unmarshaler) Unmarshal() error {
 			}
 			segments = append(segments, unmarshaler.(segment.Segment))
 			u.segments[segmentID.ID] = segments
+		} else {
+			generic := &segment.GenericSegment{}
+			err = generic.UnmarshalHBCI(seg)
+			if err != nil {
+				return err
+			}
+			u.genericSegments[segmentID.ID] = append(u.genericSegments[segmentID.ID], generic)
 		}
 	}
 	return nil
@@ -110,6 +120,12 @@ func (u *Unmarshaler) SegmentByID(segmentID string) segment.Segment {
 	return nil
 }
 
+// GenericSegmentsByID returns all already unmarshaled segments for the given
+// ID which have no dedicated segment type
+func (u *Unmarshaler) GenericSegmentsByID(segmentID string) []*segment.GenericSegment {
+	return u.genericSegments[segmentID]
+}
+
 // MarshaledSegmentsByID returns all segments for a given ID as array of bytes
 func (u *Unmarshaler) MarshaledSegmentsByID(segmentID string) [][]byte {
 	return u.segmentExtractor.FindSegments(segmentID)
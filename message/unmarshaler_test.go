@@ -95,3 +95,35 @@ func TestUnmarshalerUnmarshalSegment(t *testing.T) {
 		}
 	}
 }
+
+func TestUnmarshalerUnmarshalGenericSegments(t *testing.T) {
+	test := "HNHBK:1:3+000000000273+220+abcde+1+'HIXYZ:2:1:1+Neuer Auftrag+12:EUR'HIXYZ:3:1:1+Zweiter Auftrag'"
+
+	unmarshaler := NewUnmarshaler([]byte(test))
+
+	err := unmarshaler.Unmarshal()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v", err, err)
+	}
+	if unmarshaler.SegmentByID("HNHBK") == nil || unmarshaler.GenericSegmentsByID("HNHBK") != nil {
+		t.Logf("Expected known segments not to be unmarshaled as generic segments\n")
+		t.Fail()
+	}
+	generic := unmarshaler.GenericSegmentsByID("HIXYZ")
+	if len(generic) != 2 {
+		t.Fatalf("Expected 2 generic segments, got %d", len(generic))
+	}
+	if text := generic[0].Element(2).Text(); text != "Neuer Auftrag" {
+		t.Logf("Expected element 2 to equal %q, got %q\n", "Neuer Auftrag", text)
+		t.Fail()
+	}
+	if currency := generic[0].Element(3, 2).Text(); currency != "EUR" {
+		t.Logf("Expected element 3:2 to equal EUR, got %q\n", currency)
+		t.Fail()
+	}
+	if position := generic[1].Header().Position.Val(); position != 3 {
+		t.Logf("Expected second segment at position 3, got %d\n", position)
+		t.Fail()
+	}
+}
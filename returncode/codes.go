@@ -0,0 +1,177 @@
+package returncode
+
+// codes contains the return codes documented within "FinTS
+// Rückmeldungscodes", Stand 07.07.2021, except the institute specific ranges
+// covered by individualRanges. The severity is derived from the code.
+var codes = []ReturnCode{
+	// Success
+	{Code: 10, German: "Entgegengenommen", English: "Received"},
+	{Code: 20, German: "Ausgeführt", English: "Executed"},
+	{Code: 30, German: "Auftrag empfangen - Sicherheitsfreigabe erforderlich", English: "Job received - security clearance required", UserActionRequired: true},
+	{Code: 31, German: "Auftragsstorno durchgeführt", English: "Job cancelled"},
+	{Code: 40, German: "Letzter Dialog endete am %1 um %2", English: "Last dialog ended on %1 at %2"},
+	{Code: 41, German: "Falls Datum/Uhrzeit nicht korrekt, wenden Sie sich an Ihren Berater unter %1 bzw. %2", English: "If date or time are not correct, contact your advisor at %1 or %2"},
+	{Code: 90, German: "TAN OK (%1)", English: "TAN OK (%1)"},
+	{Code: 100, German: "Beendet", English: "Dialog ended"},
+	{Code: 900, German: "TAN gültig", English: "TAN valid"},
+	{Code: 901, German: "PIN gültig", English: "PIN valid"},
+
+	// Notices
+	{Code: 1010, German: "Es liegen neue Kontoinformationen vor", English: "New account information available"},
+	{Code: 1040, German: "BPD nicht mehr aktuell, aktuelle Version enthalten", English: "BPD outdated, current version enclosed", Retry: RetryWithNewDialog},
+	{Code: 1050, German: "UPD nicht mehr aktuell, aktuelle Version enthalten", English: "UPD outdated, current version enclosed", Retry: RetryWithNewDialog},
+	{Code: 1060, German: "Teilweise liegen Hinweise vor", English: "Notices present"},
+
+	// Warnings
+	{Code: 3000, German: "Auftrag nur teilweise ausgeführt", English: "Job executed partially"},
+	{Code: 3010, German: "Nicht verfügbar", English: "Not available"},
+	{Code: 3020, German: "Korrigiert, da nicht mehr aktuell", English: "Corrected as outdated"},
+	{Code: 3021, German: "IBAN %1 / BIC %2 für Auftrag", English: "IBAN %1 / BIC %2 used for job"},
+	{Code: 3030, German: "Korrigiert, da ungültig", English: "Corrected as invalid"},
+	{Code: 3040, German: "Es liegen weitere Informationen vor", English: "More information available"},
+	{Code: 3045, German: "SEPA Instant Payment Statusabfrage HKIPS veranlassen", English: "Request the SEPA instant payment status with HKIPS"},
+	{Code: 3046, German: "Überprüfen Sie Ihre Umsätze", English: "Check your transactions", UserActionRequired: true},
+	{Code: 3050, German: "Nicht mehr aktuell. Wird noch bis zum %1 akzeptiert", English: "Outdated, still accepted until %1"},
+	{Code: 3051, German: "Zeitüberschreitung bei außerbörslichem Direkthandel", English: "Timeout within off-exchange direct trading"},
+	{Code: 3060, German: "Teilweise liegen Warnungen vor", English: "Warnings present"},
+	{Code: 3070, German: "Neuanlage einer PIN, TAN-Liste oder eines TAN-Generators für Benutzer %1 schlug fehl", English: "Creating a PIN, TAN list or TAN generator for user %1 failed"},
+	{Code: 3071, German: "Die Benachrichtigung des Autorisierungssystems für Benutzer %1 schlug fehl", English: "Notifying the authorization system for user %1 failed"},
+	{Code: 3072, German: "Neue Anmeldedaten - bitte berücksichtigen", English: "New login data, please take them into account", UserActionRequired: true},
+	{Code: 3075, German: "Starke Authentifizierung ab dem %1 erforderlich", English: "Strong authentication required from %1 on"},
+	{Code: 3076, German: "Keine starke Authentifizierung erforderlich", English: "No strong authentication required"},
+	{Code: 3077, German: "Verwendung gehärteter Browser erforderlich", English: "Hardened browser required"},
+	{Code: 3078, German: "Unregistriertes FinTS-Produkt - nur zugelassen bis %1", English: "Unregistered FinTS product, only permitted until %1"},
+	{Code: 3079, German: "Bitte an SW-Hersteller wenden", English: "Please contact the software vendor"},
+	{Code: 3080, German: "Es liegen weitere Informationen vor", English: "More information available"},
+	{Code: 3081, German: "Aktualisierte Parameterdaten beachten", English: "Mind the updated parameter data", Retry: RetryWithNewDialog},
+	{Code: 3210, German: "Auftrag angenommen, fehlerhafte Einzelpositionen", English: "Job accepted with erroneous items"},
+	{Code: 3220, German: "Auftrag ausgeführt, fehlerhafte Einzelpositionen", English: "Job executed with erroneous items"},
+	{Code: 3230, German: "Die Zahlung erfolgt an neue Empfänger-Konto-/Bankverbindung", English: "Payment goes to the new account of the recipient"},
+	{Code: 3260, German: "Sammler unvollständig verarbeitet", English: "Batch processed incompletely"},
+	{Code: 3290, German: "Die eingegebene Bankleitzahl ist ungültig", English: "The bank code entered is invalid"},
+	{Code: 3300, German: "Kein Schlüssel verfügbar. Keine Signatur von Kreditinstitutsnachrichten", English: "No key available, institute messages are not signed"},
+	{Code: 3310, German: "Ini-Brief erforderlich", English: "INI letter required", UserActionRequired: true},
+	{Code: 3320, German: "Ini-Brief nicht erforderlich", English: "INI letter not required"},
+	{Code: 3330, German: "Schlüssel liegen bereits vor", English: "Keys already present"},
+	{Code: 3340, German: "Karte erneuern. Benutzerschlüssel noch gültig bis zum %1", English: "Renew the card, user keys are valid until %1", UserActionRequired: true},
+	{Code: 3345, German: "Sicherheitsprofilwechsel durchführen bis %1", English: "Change the security profile until %1", UserActionRequired: true},
+	{Code: 3361, German: "Sicherheitsverfahren nur noch zulässig bis %1", English: "Security procedure only permitted until %1"},
+	{Code: 3390, German: "Doppeleinreichung Signatur-ID %1", English: "Duplicate submission of signature ID %1"},
+	{Code: 3710, German: "Bei Beträgen > 50.000 EUR ist eine AWV-Meldung erforderlich", English: "Amounts above 50.000 EUR have to be reported to the central bank (AWV)", UserActionRequired: true},
+	{Code: 3810, German: "Zusätzlich Datei %1 abholen", English: "Fetch the additional file %1"},
+	{Code: 3820, German: "Prüfen Sie zu gegebener Zeit den Orderstatus", English: "Check the order status in due time", UserActionRequired: true},
+	{Code: 3900, German: "Mitteilung ohne Text erhalten", English: "Message without text received"},
+	{Code: 3910, German: "TAN wurde nicht verbraucht", English: "TAN not used up"},
+	{Code: 3911, German: "Bitte neue TAN-Liste aktivieren", English: "Please activate a new TAN list", UserActionRequired: true},
+	{Code: 3912, German: "Neue TAN-Liste wird automatisch verschickt", English: "New TAN list is sent automatically"},
+	{Code: 3913, German: "TAN wurde verbraucht", English: "TAN used up"},
+	{Code: 3914, German: "TAN-Vorrat kritisch", English: "TAN supply critical", UserActionRequired: true},
+	{Code: 3915, German: "Neue TAN-Liste aktiviert", English: "New TAN list activated"},
+	{Code: 3916, German: "PIN muss wegen erstmaliger Anmeldung zwangsweise geändert werden", English: "PIN has to be changed due to the first login", UserActionRequired: true},
+	{Code: 3917, German: "Alte TAN-Liste ist infolge der Aktivierung einer neuen TAN-Liste ungültig", English: "Old TAN list invalid due to the activation of a new one"},
+	{Code: 3918, German: "Kompetenz nicht ausreichend - weitere TAN erforderlich", English: "Authority insufficient, another TAN required", UserActionRequired: true},
+	{Code: 3920, German: "Zugelassene Ein- und Zwei-Schritt-Verfahren für den Benutzer", English: "One and two step procedures permitted for the user"},
+	{Code: 3921, German: "Zugelassene AZS-Verfahren für den Benutzer", English: "Authorization procedures permitted for the user"},
+	{Code: 3931, German: "PIN gesperrt. Entsperren mit PIN-Sperre aufheben", English: "PIN locked, unlock it by revoking the PIN block", UserActionRequired: true},
+	{Code: 3932, German: "Bitte führen Sie zunächst eine PIN-Änderung durch", English: "Please change your PIN first", UserActionRequired: true},
+	{Code: 3933, German: "TAN-Generator gesperrt, Synchronisierung erforderlich", English: "TAN generator locked, synchronisation required", UserActionRequired: true},
+	{Code: 3934, German: "Bitte eine Karte zur Verwendung mit chipTAN zulassen", English: "Please permit a card for chipTAN", UserActionRequired: true},
+	{Code: 3935, German: "Bitte eine Karte zur Verwendung mit chipTAN zulassen", English: "Please permit a card for chipTAN", UserActionRequired: true},
+	{Code: 3936, German: "Die neuen Funktionen stehen erst nach einer erneuten Anmeldung zur Verfügung", English: "The new functions are available after the next login", Retry: RetryWithNewDialog},
+	{Code: 3938, German: "Ihr Zugang ist vorläufig gesperrt - bitte PIN-Sperre aufheben", English: "Access locked temporarily, please revoke the PIN block", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 3939, German: "mobileTAN-Freischaltung erforderlich. SMS-Freischaltcode wurde versendet", English: "mobileTAN activation required, activation code sent by SMS", UserActionRequired: true},
+	{Code: 3940, German: "Reservierte TAN wurde entwertet", English: "Reserved TAN cancelled"},
+	{Code: 3941, German: "Zur PIN-Änderung stehen folgende Rufnummern zur Verfügung", English: "The following phone numbers are available to change the PIN"},
+	{Code: 3942, German: "Freischaltung einer Mobilfunkverbindung zwingend erforderlich", English: "Activation of a mobile phone connection required", UserActionRequired: true},
+	{Code: 3944, German: "Bitte benutzen Sie die erhaltene Folgekarte %1 zur TAN-Erzeugung", English: "Please use the follow-up card %1 to generate TANs", UserActionRequired: true},
+	{Code: 3950, German: "Die Selbstumstellung auf ein anderes Sicherheitsverfahren ist möglich", English: "Switching to another security procedure is possible"},
+	{Code: 3951, German: "Die Selbstumstellung auf ein anderes Sicherheitsverfahren ist erforderlich", English: "Switching to another security procedure is required", UserActionRequired: true},
+	{Code: 3952, German: "Erfolgreicher Prozessschritt bei der Selbstumstellung", English: "Successful step while switching the security procedure"},
+	{Code: 3955, German: "Sicherheitsfreigabe erfolgt über anderen Kanal", English: "Security clearance happens via another channel", UserActionRequired: true},
+	{Code: 3956, German: "Starke Kundenauthentifizierung noch ausstehend", English: "Strong customer authentication pending", UserActionRequired: true},
+	{Code: 3957, German: "Auf Push-Nachricht warten", English: "Wait for the push notification", UserActionRequired: true},
+	{Code: 3958, German: "Freigabe-Anwendung unterstützt das gewählte Verfahren nicht", English: "The approval app does not support the chosen procedure", UserActionRequired: true},
+
+	// Errors
+	{Code: 9000, German: "Status indifferent", English: "Status indeterminate"},
+	{Code: 9010, German: "Verarbeitung nicht möglich", English: "Processing not possible"},
+	{Code: 9020, German: "Antwort zu groß", English: "Response too large"},
+	{Code: 9021, German: "IBAN %1 / BIC %2 konnte nicht ermittelt werden", English: "IBAN %1 / BIC %2 could not be determined", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9030, German: "Fehler bei Entschlüsselung", English: "Decryption failed"},
+	{Code: 9040, German: "Fehler bei Dekomprimierung", English: "Decompression failed"},
+	{Code: 9050, German: "Teilweise fehlerhaft", English: "Partially erroneous"},
+	{Code: 9075, German: "Dialog abgebrochen - starke Authentifizierung erforderlich", English: "Dialog aborted, strong authentication required", UserActionRequired: true, Retry: RetryWithNewDialog},
+	{Code: 9077, German: "Dialog abgebrochen - gehärteter Browser erforderlich", English: "Dialog aborted, hardened browser required"},
+	{Code: 9078, German: "Dialog abgebrochen - FinTS-Produkt ist nicht registriert", English: "Dialog aborted, FinTS product not registered"},
+	{Code: 9110, German: "Unbekannter Aufbau", English: "Unknown structure"},
+	{Code: 9120, German: "Nicht erwartet", English: "Not expected"},
+	{Code: 9130, German: "Inhalt syntaktisch ungültig", English: "Content syntactically invalid", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9140, German: "Inhalt zu lang", English: "Content too long", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9145, German: "Inhalt zu kurz", English: "Content too short", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9150, German: "Belegung nicht erlaubt", English: "Value not permitted", Retry: RetryAfterCorrection},
+	{Code: 9160, German: "Fehlt", English: "Missing", Retry: RetryAfterCorrection},
+	{Code: 9170, German: "Tritt zu oft auf", English: "Occurs too often", Retry: RetryAfterCorrection},
+	{Code: 9180, German: "Wird nicht mehr akzeptiert", English: "No longer accepted"},
+	{Code: 9185, German: "HBCI-/FinTS-Version %1 wird nicht unterstützt", English: "HBCI/FinTS version %1 not supported"},
+	{Code: 9190, German: "Nachricht enthält kein erkennbares Sicherheitsmerkmal (Signatur)", English: "Message contains no recognizable signature"},
+	{Code: 9210, German: "Inhaltlich ungültig", English: "Content invalid", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9211, German: "Verwendung eines Secoders verpflichtend", English: "Secoder required"},
+	{Code: 9212, German: "Inhalt zu groß", English: "Value too large", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9215, German: "Inhalt zu klein", English: "Value too small", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9219, German: "Schlüsselart falsch", English: "Wrong key type"},
+	{Code: 9220, German: "Einzelposition %1 inhaltlich ungültig", English: "Item %1 invalid", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9230, German: "Unzureichendes Guthaben des Kontos", English: "Insufficient funds", UserActionRequired: true},
+	{Code: 9310, German: "Elektronische Signatur noch nicht hinterlegt", English: "Electronic signature not yet deposited", UserActionRequired: true},
+	{Code: 9311, German: "PIN/TAN-System nicht verfügbar", English: "PIN/TAN system not available", Retry: RetryLater},
+	{Code: 9315, German: "Intern genutzter Code", English: "Code used internally"},
+	{Code: 9320, German: "Elektronische Signatur noch nicht freigeschaltet", English: "Electronic signature not yet activated", UserActionRequired: true},
+	{Code: 9330, German: "Elektronische Signatur gesperrt", English: "Electronic signature locked", UserActionRequired: true},
+	{Code: 9331, German: "Synchronisieren des neuen TAN-Mediums nicht möglich", English: "Synchronising the new TAN medium not possible", UserActionRequired: true},
+	{Code: 9333, German: "PIN-Zugang (noch) nicht freigeschaltet", English: "PIN access not (yet) activated", UserActionRequired: true},
+	{Code: 9340, German: "Elektronische Signatur falsch", English: "Electronic signature wrong", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9350, German: "Zertifikat abgelaufen", English: "Certificate expired", UserActionRequired: true},
+	{Code: 9351, German: "Zertifikat gesperrt", English: "Certificate revoked", UserActionRequired: true},
+	{Code: 9352, German: "Zertifikatseigner unbekannt", English: "Certificate owner unknown"},
+	{Code: 9353, German: "Zertifikatssignatur falsch", English: "Certificate signature wrong"},
+	{Code: 9354, German: "Bitte Einreichung der Zweitkennung wiederholen mit RDHx", English: "Please submit the second identification again with RDHx", Retry: RetryAfterCorrection},
+	{Code: 9355, German: "Fehler im Zertifikatsaufbau", English: "Malformed certificate"},
+	{Code: 9356, German: "Zertifikatstyp nicht akzeptiert", English: "Certificate type not accepted"},
+	{Code: 9357, German: "Zertifikat erwartet", English: "Certificate expected"},
+	{Code: 9359, German: "OCSP-Anfrage nicht beendet", English: "OCSP request not finished", Retry: RetryLater},
+	{Code: 9360, German: "Sperrung der Signatur nach weiteren %1 Falschsignaturen", English: "Signature is locked after %1 more wrong signatures", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9361, German: "Dialog abgebrochen - Sicherheitsverfahren nicht mehr zulässig", English: "Dialog aborted, security procedure no longer permitted", UserActionRequired: true},
+	{Code: 9370, German: "Signaturberechtigung reicht nicht aus", English: "Signing authority insufficient"},
+	{Code: 9380, German: "Benutzer hat keine Auftragsberechtigung", English: "User is not authorized for the job"},
+	{Code: 9390, German: "Doppeleinreichung", English: "Duplicate submission"},
+	{Code: 9400, German: "Allgemeiner Fehler des Sicherheitsmediums", English: "General error of the security medium"},
+	{Code: 9420, German: "Challenge-Betrag passt nicht zum Auftrag", English: "Challenge amount does not match the job"},
+	{Code: 9800, German: "Dialog abgebrochen", English: "Dialog aborted", Retry: RetryWithNewDialog},
+	{Code: 9901, German: "Fehler Kryptomodul Signaturverifizierung", English: "Crypto module failed to verify the signature"},
+	{Code: 9910, German: "PIN ungültig, bitte richtige PIN eingeben", English: "PIN invalid, please enter the correct PIN", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9920, German: "Reservierung nicht möglich, keine freien TANs mehr vorhanden", English: "Reservation not possible, no TANs left", UserActionRequired: true},
+	{Code: 9930, German: "Ihre PIN ist gesperrt", English: "Your PIN is locked", UserActionRequired: true},
+	{Code: 9931, German: "Sperrung des Kontos nach %1 Fehlversuchen", English: "Account is locked after %1 failed attempts", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9939, German: "Freischalten der Mobilfunknummer für mobileTAN nicht möglich", English: "Activating the mobile phone number for mobileTAN not possible", UserActionRequired: true},
+	{Code: 9941, German: "TAN ungültig", English: "TAN invalid", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9942, German: "PIN ungültig", English: "PIN invalid", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9943, German: "TAN bereits verbraucht", English: "TAN already used", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9951, German: "Zeitüberschreitung im Zwei-Schritt-Verfahren - TAN ungültig", English: "Timeout within the two step procedure, TAN invalid", UserActionRequired: true, Retry: RetryWithNewDialog},
+	{Code: 9953, German: "Nur ein TAN-pflichtiger Auftrag pro Nachricht erlaubt", English: "Only one job requiring a TAN allowed per message"},
+	{Code: 9954, German: "Mehrfach-TANs nicht erlaubt", English: "Multiple TANs not allowed"},
+	{Code: 9955, German: "Ein-Schritt-TAN-Verfahren nicht zugelassen", English: "One step TAN procedure not permitted"},
+	{Code: 9956, German: "Zeitversetzte Eingabe von Mehrfach-TANs nicht erlaubt", English: "Delayed entry of multiple TANs not allowed"},
+	{Code: 9957, German: "Wechsel des TAN-Prozesses bei Mehrfach-TANs nicht erlaubt", English: "Changing the TAN process with multiple TANs not allowed"},
+	{Code: 9958, German: "Das genutzte Legitimationsverfahren wird nicht mehr unterstützt", English: "The authentication procedure used is no longer supported", UserActionRequired: true},
+	{Code: 9959, German: "SMS konnte nicht gesendet werden - bitte Vorgang wiederholen", English: "SMS could not be sent, please repeat", Retry: RetryLater},
+	{Code: 9960, German: "Es kann kein TAN-pflichtiger Auftrag durchgeführt werden", English: "No job requiring a TAN can be executed", UserActionRequired: true},
+	{Code: 9961, German: "Bitte schalten Sie die Mobilfunkverbindung für mobileTAN frei", English: "Please activate the mobile phone connection for mobileTAN", UserActionRequired: true},
+	{Code: 9962, German: "Auftrag nicht ausgeführt - die Telefonbezeichnung ist unbekannt", English: "Job not executed, the phone name is unknown", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9963, German: "Auftrag nicht ausgeführt - Rufnummer für SMS fehlerhaft", English: "Job not executed, phone number for SMS invalid", UserActionRequired: true, Retry: RetryAfterCorrection},
+	{Code: 9964, German: "Auftrag nicht ausgeführt - keine gültige Karte für chipTAN", English: "Job not executed, no valid card for chipTAN", UserActionRequired: true},
+	{Code: 9980, German: "Abgebrochen - Zweischrittdialog", English: "Two step dialog aborted", Retry: RetryWithNewDialog},
+	{Code: 9991, German: "chipTAN nicht zulässig bei Benutzerkennung für TANalt", English: "chipTAN not permitted for users of the old TAN procedure"},
+	{Code: 9992, German: "Eine neue TAN-Liste wurde bereits erstellt", English: "A new TAN list has already been created"},
+	{Code: 9997, German: "Zurzeit Wartungsarbeiten", English: "Maintenance in progress", Retry: RetryLater},
+	{Code: 9998, German: "Daten sind nicht zu entschlüsseln", English: "Data cannot be decrypted"},
+	{Code: 9999, German: "Auftrag konnte aus technischen Gründen nicht verarbeitet werden", English: "Job could not be processed for technical reasons", Retry: RetryLater},
+}
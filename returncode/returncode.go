@@ -0,0 +1,188 @@
+// Package returncode provides a catalog of the FinTS return codes as
+// documented within the FinTS specification "Rückmeldungscodes".
+//
+// Institutes send return codes within acknowledgements. As the text sent
+// along with a code is institute specific, the catalog gives a uniform
+// description in German and English, the severity and hints how a client
+// should react.
+package returncode
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity describes how a return code has to be interpreted.
+type Severity int
+
+// The severities are derived from the first digit of a code.
+const (
+	// Success codes start with 0.
+	Success Severity = iota
+	// Notice codes start with 1 and are only used in FinTS 4.
+	Notice
+	// Warning codes start with 3.
+	Warning
+	// Error codes start with 9.
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Success:
+		return "success"
+	case Notice:
+		return "notice"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// RetryHint describes whether a rejected job can be sent again.
+type RetryHint int
+
+const (
+	// NoRetry means that sending the job again will not succeed.
+	NoRetry RetryHint = iota
+	// RetryLater means that the job can be sent again later unchanged, e.g.
+	// after maintenance.
+	RetryLater
+	// RetryAfterCorrection means that the job can be sent again after the
+	// user corrected the input, e.g. the PIN or TAN.
+	RetryAfterCorrection
+	// RetryWithNewDialog means that the job can be sent again within a new
+	// dialog, e.g. with updated parameter data.
+	RetryWithNewDialog
+)
+
+func (r RetryHint) String() string {
+	switch r {
+	case NoRetry:
+		return "no retry"
+	case RetryLater:
+		return "retry later"
+	case RetryAfterCorrection:
+		return "retry after correction"
+	case RetryWithNewDialog:
+		return "retry with new dialog"
+	default:
+		return "unknown"
+	}
+}
+
+// A ReturnCode describes a single return code. Some codes have several
+// meanings within the specification depending on the context, a ReturnCode
+// contains the general one.
+type ReturnCode struct {
+	Code     int
+	German   string
+	English  string
+	Severity Severity
+	// UserActionRequired is true if the user has to do something to
+	// resolve the situation, e.g. enter a TAN or unlock the PIN.
+	UserActionRequired bool
+	Retry              RetryHint
+}
+
+// Description returns the English description or the German one if
+// german is true.
+func (r ReturnCode) Description(german bool) string {
+	if german {
+		return r.German
+	}
+	return r.English
+}
+
+// Format returns the description like Description and replaces the
+// placeholders %1 to %9 with the parameters sent along with the code.
+// Placeholders without a parameter are kept.
+func (r ReturnCode) Format(german bool, params ...string) string {
+	description := r.Description(german)
+	for i, param := range params {
+		if i == 9 {
+			break
+		}
+		description = strings.Replace(description, fmt.Sprintf("%%%d", i+1), param, -1)
+	}
+	return description
+}
+
+// Institute specific codes are reserved within these ranges.
+var individualRanges = [][2]int{{950, 999}, {1950, 1999}, {3960, 3999}}
+
+var catalog = func() map[int]ReturnCode {
+	m := make(map[int]ReturnCode, len(codes))
+	for _, c := range codes {
+		c.Severity = SeverityOf(c.Code)
+		m[c.Code] = c
+	}
+	return m
+}()
+
+// SeverityOf returns the severity of code.
+func SeverityOf(code int) Severity {
+	switch {
+	case code >= 9000:
+		return Error
+	case code >= 3000:
+		return Warning
+	case code >= 1000:
+		return Notice
+	default:
+		return Success
+	}
+}
+
+// Lookup returns the ReturnCode documented for code. Institute specific codes
+// are reported as such. It returns false, if code is not documented.
+func Lookup(code int) (ReturnCode, bool) {
+	if c, ok := catalog[code]; ok {
+		return c, true
+	}
+	for _, r := range individualRanges {
+		if code >= r[0] && code <= r[1] {
+			return ReturnCode{
+				Code:     code,
+				German:   "Institutsindividuelle Rückmeldung",
+				English:  "Institute specific acknowledgement",
+				Severity: SeverityOf(code),
+			}, true
+		}
+	}
+	return ReturnCode{}, false
+}
+
+// Describe is like Lookup, but returns a generic ReturnCode for undocumented
+// codes instead of reporting their absence.
+func Describe(code int) ReturnCode {
+	if c, ok := Lookup(code); ok {
+		return c
+	}
+	c := ReturnCode{Code: code, Severity: SeverityOf(code)}
+	switch c.Severity {
+	case Error:
+		c.German, c.English = "Unbekannter Fehler", "Unknown error"
+	case Warning:
+		c.German, c.English = "Unbekannte Warnung", "Unknown warning"
+	case Notice:
+		c.German, c.English = "Unbekannter Hinweis", "Unknown notice"
+	default:
+		c.German, c.English = "Unbekannte Erfolgsmeldung", "Unknown success"
+	}
+	return c
+}
+
+// All returns all documented return codes ordered by code.
+func All() []ReturnCode {
+	all := make([]ReturnCode, 0, len(catalog))
+	for _, c := range catalog {
+		all = append(all, c)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Code < all[j].Code })
+	return all
+}
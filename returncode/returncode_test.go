@@ -0,0 +1,139 @@
+package returncode
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		code               int
+		found              bool
+		severity           Severity
+		userActionRequired bool
+		retry              RetryHint
+	}{
+		{20, true, Success, false, NoRetry},
+		{30, true, Success, true, NoRetry},
+		{975, true, Success, false, NoRetry},
+		{1040, true, Notice, false, RetryWithNewDialog},
+		{3040, true, Warning, false, NoRetry},
+		{3980, true, Warning, false, NoRetry},
+		{9942, true, Error, true, RetryAfterCorrection},
+		{9997, true, Error, false, RetryLater},
+		{9001, false, Error, false, NoRetry},
+	}
+	for _, test := range tests {
+		code, found := Lookup(test.code)
+
+		if found != test.found {
+			t.Logf("%04d: Expected found to be %t, got %t\n", test.code, test.found, found)
+			t.Fail()
+		}
+		if !found {
+			continue
+		}
+		if code.Code != test.code {
+			t.Logf("%04d: Expected code to equal %d, got %d\n", test.code, test.code, code.Code)
+			t.Fail()
+		}
+		if code.Severity != test.severity {
+			t.Logf("%04d: Expected severity %s, got %s\n", test.code, test.severity, code.Severity)
+			t.Fail()
+		}
+		if code.UserActionRequired != test.userActionRequired {
+			t.Logf("%04d: Expected user action required to be %t, got %t\n", test.code, test.userActionRequired, code.UserActionRequired)
+			t.Fail()
+		}
+		if code.Retry != test.retry {
+			t.Logf("%04d: Expected retry hint %s, got %s\n", test.code, test.retry, code.Retry)
+			t.Fail()
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	code := Describe(9001)
+
+	if code.English != "Unknown error" || code.Severity != Error {
+		t.Logf("Expected generic error description, got %+v\n", code)
+		t.Fail()
+	}
+}
+
+func TestReturnCodeFormat(t *testing.T) {
+	code := Describe(9931)
+
+	english := code.Format(false, "3")
+	german := code.Format(true, "3")
+
+	if expected := "Account is locked after 3 failed attempts"; english != expected {
+		t.Logf("Expected %q, got %q\n", expected, english)
+		t.Fail()
+	}
+	if expected := "Sperrung des Kontos nach 3 Fehlversuchen"; german != expected {
+		t.Logf("Expected %q, got %q\n", expected, german)
+		t.Fail()
+	}
+	if expected := "Letzter Dialog endete am %1 um %2"; Describe(40).Format(true) != expected {
+		t.Logf("Expected placeholders to be kept, got %q\n", Describe(40).Format(true))
+		t.Fail()
+	}
+}
+
+func TestAll(t *testing.T) {
+	all := All()
+
+	if len(all) != len(codes) {
+		t.Logf("Expected %d codes, got %d\n", len(codes), len(all))
+		t.Fail()
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i-1].Code >= all[i].Code {
+			t.Logf("Expected codes to be ordered and unique, got %d before %d\n", all[i-1].Code, all[i].Code)
+			t.Fail()
+		}
+	}
+}
+
+//go:generate go run ../cmd/returncode_extractor -pdf ../doc/FinTS_Rueckmeldungscodes_2021-07-07_final_version.pdf -out testdata/rueckmeldungscodes_2021-07-07.txt
+
+func TestCatalogMatchesSpecification(t *testing.T) {
+	file, err := os.Open("testdata/rueckmeldungscodes_2021-07-07.txt")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer file.Close()
+	documented := make(map[int]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		code, err := strconv.Atoi(line)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		documented[code] = true
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for code := range documented {
+		if _, found := Lookup(code); !found {
+			t.Logf("%04d: Expected documented code to be found\n", code)
+			t.Fail()
+		}
+	}
+	for _, code := range All() {
+		if !documented[code.Code] {
+			t.Logf("%04d: Expected code to be documented within the specification\n", code.Code)
+			t.Fail()
+		}
+	}
+}
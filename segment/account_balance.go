@@ -9,6 +9,8 @@ import (
 	"github.com/mitch000001/go-hbci/element"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments AccountBalanceRequestSegmentV5,AccountBalanceRequestSegmentV6,AccountBalanceResponseSegment
+
 var accountBalanceRequests = map[int]func(account domain.AccountConnection, allAccounts bool) AccountBalanceRequest{
 	5: NewAccountBalanceRequestV5,
 	6: NewAccountBalanceRequestV6,
@@ -29,6 +31,7 @@ func AccountBalanceRequestBuilder(versions []int) (func(account domain.AccountCo
 type AccountBalanceRequest interface {
 	ClientSegment
 	SetContinuationMark(continuationMark string)
+	SetContinuationReference(continuationReference string)
 }
 
 func NewAccountBalanceRequestV5(account domain.AccountConnection, allAccounts bool) AccountBalanceRequest {
@@ -42,8 +45,8 @@ func NewAccountBalanceRequestV5(account domain.AccountConnection, allAccounts bo
 
 type AccountBalanceRequestSegmentV5 struct {
 	ClientSegment
-	AccountConnection     *element.AccountConnectionDataElement
-	AllAccounts           *element.BooleanDataElement
+	AccountConnection     *element.AccountConnectionDataElement `hbci:"required"`
+	AllAccounts           *element.BooleanDataElement           `hbci:"required"`
 	MaxEntries            *element.NumberDataElement
 	ContinuationReference *element.AlphaNumericDataElement
 }
@@ -66,6 +69,10 @@ func (a *AccountBalanceRequestSegmentV5) SetContinuationMark(continuationMark st
 	a.ContinuationReference = element.NewAlphaNumeric(continuationMark, 35)
 }
 
+func (a *AccountBalanceRequestSegmentV5) SetContinuationReference(continuationReference string) {
+	a.SetContinuationMark(continuationReference)
+}
+
 func NewAccountBalanceRequestV6(account domain.AccountConnection, allAccounts bool) AccountBalanceRequest {
 	a := &AccountBalanceRequestSegmentV6{
 		AccountConnection: element.NewAccountConnection(account),
@@ -77,8 +84,8 @@ func NewAccountBalanceRequestV6(account domain.AccountConnection, allAccounts bo
 
 type AccountBalanceRequestSegmentV6 struct {
 	ClientSegment
-	AccountConnection     *element.AccountConnectionDataElement
-	AllAccounts           *element.BooleanDataElement
+	AccountConnection     *element.AccountConnectionDataElement `hbci:"required"`
+	AllAccounts           *element.BooleanDataElement           `hbci:"required"`
 	MaxEntries            *element.NumberDataElement
 	ContinuationReference *element.AlphaNumericDataElement
 }
@@ -101,6 +108,10 @@ func (a *AccountBalanceRequestSegmentV6) SetContinuationMark(continuationMark st
 	a.ContinuationReference = element.NewAlphaNumeric(continuationMark, 35)
 }
 
+func (a *AccountBalanceRequestSegmentV6) SetContinuationReference(continuationReference string) {
+	a.SetContinuationMark(continuationReference)
+}
+
 //go:generate go run ../cmd/unmarshaler/unmarshaler_generator.go -segment AccountBalanceResponseSegment
 
 type AccountBalanceResponse interface {
@@ -0,0 +1,354 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (a *AccountBalanceRequestSegmentV5) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.AccountConnection != nil {
+		marshaled, err := a.AccountConnection.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AllAccounts != nil {
+		marshaled, err := a.AllAccounts.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.MaxEntries != nil {
+		marshaled, err := a.MaxEntries.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.ContinuationReference != nil {
+		marshaled, err := a.ContinuationReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountBalanceRequestSegmentV5) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.AccountConnection != nil {
+		buf.WriteString(a.AccountConnection.String())
+	}
+	buf.WriteByte('+')
+	if a.AllAccounts != nil {
+		buf.WriteString(a.AllAccounts.String())
+	}
+	buf.WriteByte('+')
+	if a.MaxEntries != nil {
+		buf.WriteString(a.MaxEntries.String())
+	}
+	buf.WriteByte('+')
+	if a.ContinuationReference != nil {
+		buf.WriteString(a.ContinuationReference.String())
+	}
+}
+
+func (a *AccountBalanceRequestSegmentV6) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.AccountConnection != nil {
+		marshaled, err := a.AccountConnection.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AllAccounts != nil {
+		marshaled, err := a.AllAccounts.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.MaxEntries != nil {
+		marshaled, err := a.MaxEntries.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.ContinuationReference != nil {
+		marshaled, err := a.ContinuationReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountBalanceRequestSegmentV6) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.AccountConnection != nil {
+		buf.WriteString(a.AccountConnection.String())
+	}
+	buf.WriteByte('+')
+	if a.AllAccounts != nil {
+		buf.WriteString(a.AllAccounts.String())
+	}
+	buf.WriteByte('+')
+	if a.MaxEntries != nil {
+		buf.WriteString(a.MaxEntries.String())
+	}
+	buf.WriteByte('+')
+	if a.ContinuationReference != nil {
+		buf.WriteString(a.ContinuationReference.String())
+	}
+}
+
+func (a *AccountBalanceResponseSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.AccountConnection != nil {
+		marshaled, err := a.AccountConnection.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountProductName != nil {
+		marshaled, err := a.AccountProductName.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountCurrency != nil {
+		marshaled, err := a.AccountCurrency.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.BookedBalance != nil {
+		marshaled, err := a.BookedBalance.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.EarmarkedBalance != nil {
+		marshaled, err := a.EarmarkedBalance.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.CreditLimit != nil {
+		marshaled, err := a.CreditLimit.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AvailableAmount != nil {
+		marshaled, err := a.AvailableAmount.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.UsedAmount != nil {
+		marshaled, err := a.UsedAmount.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.BookingDate != nil {
+		marshaled, err := a.BookingDate.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.BookingTime != nil {
+		marshaled, err := a.BookingTime.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.DueDate != nil {
+		marshaled, err := a.DueDate.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountBalanceResponseSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.AccountConnection != nil {
+		buf.WriteString(a.AccountConnection.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountProductName != nil {
+		buf.WriteString(a.AccountProductName.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountCurrency != nil {
+		buf.WriteString(a.AccountCurrency.String())
+	}
+	buf.WriteByte('+')
+	if a.BookedBalance != nil {
+		buf.WriteString(a.BookedBalance.String())
+	}
+	buf.WriteByte('+')
+	if a.EarmarkedBalance != nil {
+		buf.WriteString(a.EarmarkedBalance.String())
+	}
+	buf.WriteByte('+')
+	if a.CreditLimit != nil {
+		buf.WriteString(a.CreditLimit.String())
+	}
+	buf.WriteByte('+')
+	if a.AvailableAmount != nil {
+		buf.WriteString(a.AvailableAmount.String())
+	}
+	buf.WriteByte('+')
+	if a.UsedAmount != nil {
+		buf.WriteString(a.UsedAmount.String())
+	}
+	buf.WriteByte('+')
+	if a.BookingDate != nil {
+		buf.WriteString(a.BookingDate.String())
+	}
+	buf.WriteByte('+')
+	if a.BookingTime != nil {
+		buf.WriteString(a.BookingTime.String())
+	}
+	buf.WriteByte('+')
+	if a.DueDate != nil {
+		buf.WriteString(a.DueDate.String())
+	}
+}
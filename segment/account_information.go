@@ -5,6 +5,8 @@ import (
 	"github.com/mitch000001/go-hbci/element"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments AccountInformationRequestSegmentV1,AccountInformationRequestSegmentV2,AccountInformationRequestSegmentV3,AccountInformationRequestSegmentV4,AccountInformationRequestSegmentV5,AccountInformationRequestSegmentV6,AccountInformationResponseSegment
+
 type AccountInformationRequest interface {
 	ClientSegment
 	SetContinuationMark(continuationMark string)
@@ -21,8 +23,8 @@ func NewAccountInformationRequestSegmentV1(account domain.AccountConnection, all
 
 type AccountInformationRequestSegmentV1 struct {
 	ClientSegment
-	AccountConnection     *element.AccountConnectionDataElement
-	AllAccounts           *element.BooleanDataElement
+	AccountConnection     *element.AccountConnectionDataElement `hbci:"required"`
+	AllAccounts           *element.BooleanDataElement           `hbci:"required"`
 	MaxEntries            *element.NumberDataElement
 	ContinuationReference *element.AlphaNumericDataElement
 }
@@ -56,8 +58,8 @@ func NewAccountInformationRequestSegmentV2(account domain.AccountConnection, all
 
 type AccountInformationRequestSegmentV2 struct {
 	ClientSegment
-	AccountConnection     *element.AccountConnectionDataElement
-	AllAccounts           *element.BooleanDataElement
+	AccountConnection     *element.AccountConnectionDataElement `hbci:"required"`
+	AllAccounts           *element.BooleanDataElement           `hbci:"required"`
 	MaxEntries            *element.NumberDataElement
 	ContinuationReference *element.AlphaNumericDataElement
 }
@@ -91,8 +93,8 @@ func NewAccountInformationRequestSegmentV3(account domain.InternationalAccountCo
 
 type AccountInformationRequestSegmentV3 struct {
 	ClientSegment
-	AccountConnection     *element.InternationalAccountConnectionDataElement
-	AllAccounts           *element.BooleanDataElement
+	AccountConnection     *element.InternationalAccountConnectionDataElement `hbci:"required"`
+	AllAccounts           *element.BooleanDataElement                        `hbci:"required"`
 	MaxEntries            *element.NumberDataElement
 	ContinuationReference *element.AlphaNumericDataElement
 }
@@ -126,8 +128,8 @@ func NewAccountInformationRequestSegmentV4(account domain.InternationalAccountCo
 
 type AccountInformationRequestSegmentV4 struct {
 	ClientSegment
-	AccountConnection     *element.InternationalAccountConnectionDataElement
-	AllAccounts           *element.BooleanDataElement
+	AccountConnection     *element.InternationalAccountConnectionDataElement `hbci:"required"`
+	AllAccounts           *element.BooleanDataElement                        `hbci:"required"`
 	MaxEntries            *element.NumberDataElement
 	ContinuationReference *element.AlphaNumericDataElement
 }
@@ -161,8 +163,8 @@ func NewAccountInformationRequestSegmentV5(account domain.InternationalAccountCo
 
 type AccountInformationRequestSegmentV5 struct {
 	ClientSegment
-	AccountConnection     *element.InternationalAccountConnectionDataElement
-	AllAccounts           *element.BooleanDataElement
+	AccountConnection     *element.InternationalAccountConnectionDataElement `hbci:"required"`
+	AllAccounts           *element.BooleanDataElement                        `hbci:"required"`
 	MaxEntries            *element.NumberDataElement
 	ContinuationReference *element.AlphaNumericDataElement
 }
@@ -196,8 +198,8 @@ func NewAccountInformationRequestSegmentV6(account domain.InternationalAccountCo
 
 type AccountInformationRequestSegmentV6 struct {
 	ClientSegment
-	AccountConnection     *element.InternationalAccountConnectionDataElement
-	AllAccounts           *element.BooleanDataElement
+	AccountConnection     *element.InternationalAccountConnectionDataElement `hbci:"required"`
+	AllAccounts           *element.BooleanDataElement                        `hbci:"required"`
 	MaxEntries            *element.NumberDataElement
 	ContinuationReference *element.AlphaNumericDataElement
 }
@@ -0,0 +1,743 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (a *AccountInformationRequestSegmentV1) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.AccountConnection != nil {
+		marshaled, err := a.AccountConnection.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AllAccounts != nil {
+		marshaled, err := a.AllAccounts.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.MaxEntries != nil {
+		marshaled, err := a.MaxEntries.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.ContinuationReference != nil {
+		marshaled, err := a.ContinuationReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountInformationRequestSegmentV1) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.AccountConnection != nil {
+		buf.WriteString(a.AccountConnection.String())
+	}
+	buf.WriteByte('+')
+	if a.AllAccounts != nil {
+		buf.WriteString(a.AllAccounts.String())
+	}
+	buf.WriteByte('+')
+	if a.MaxEntries != nil {
+		buf.WriteString(a.MaxEntries.String())
+	}
+	buf.WriteByte('+')
+	if a.ContinuationReference != nil {
+		buf.WriteString(a.ContinuationReference.String())
+	}
+}
+
+func (a *AccountInformationRequestSegmentV2) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.AccountConnection != nil {
+		marshaled, err := a.AccountConnection.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AllAccounts != nil {
+		marshaled, err := a.AllAccounts.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.MaxEntries != nil {
+		marshaled, err := a.MaxEntries.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.ContinuationReference != nil {
+		marshaled, err := a.ContinuationReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountInformationRequestSegmentV2) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.AccountConnection != nil {
+		buf.WriteString(a.AccountConnection.String())
+	}
+	buf.WriteByte('+')
+	if a.AllAccounts != nil {
+		buf.WriteString(a.AllAccounts.String())
+	}
+	buf.WriteByte('+')
+	if a.MaxEntries != nil {
+		buf.WriteString(a.MaxEntries.String())
+	}
+	buf.WriteByte('+')
+	if a.ContinuationReference != nil {
+		buf.WriteString(a.ContinuationReference.String())
+	}
+}
+
+func (a *AccountInformationRequestSegmentV3) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.AccountConnection != nil {
+		marshaled, err := a.AccountConnection.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AllAccounts != nil {
+		marshaled, err := a.AllAccounts.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.MaxEntries != nil {
+		marshaled, err := a.MaxEntries.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.ContinuationReference != nil {
+		marshaled, err := a.ContinuationReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountInformationRequestSegmentV3) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.AccountConnection != nil {
+		buf.WriteString(a.AccountConnection.String())
+	}
+	buf.WriteByte('+')
+	if a.AllAccounts != nil {
+		buf.WriteString(a.AllAccounts.String())
+	}
+	buf.WriteByte('+')
+	if a.MaxEntries != nil {
+		buf.WriteString(a.MaxEntries.String())
+	}
+	buf.WriteByte('+')
+	if a.ContinuationReference != nil {
+		buf.WriteString(a.ContinuationReference.String())
+	}
+}
+
+func (a *AccountInformationRequestSegmentV4) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.AccountConnection != nil {
+		marshaled, err := a.AccountConnection.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AllAccounts != nil {
+		marshaled, err := a.AllAccounts.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.MaxEntries != nil {
+		marshaled, err := a.MaxEntries.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.ContinuationReference != nil {
+		marshaled, err := a.ContinuationReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountInformationRequestSegmentV4) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.AccountConnection != nil {
+		buf.WriteString(a.AccountConnection.String())
+	}
+	buf.WriteByte('+')
+	if a.AllAccounts != nil {
+		buf.WriteString(a.AllAccounts.String())
+	}
+	buf.WriteByte('+')
+	if a.MaxEntries != nil {
+		buf.WriteString(a.MaxEntries.String())
+	}
+	buf.WriteByte('+')
+	if a.ContinuationReference != nil {
+		buf.WriteString(a.ContinuationReference.String())
+	}
+}
+
+func (a *AccountInformationRequestSegmentV5) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.AccountConnection != nil {
+		marshaled, err := a.AccountConnection.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AllAccounts != nil {
+		marshaled, err := a.AllAccounts.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.MaxEntries != nil {
+		marshaled, err := a.MaxEntries.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.ContinuationReference != nil {
+		marshaled, err := a.ContinuationReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountInformationRequestSegmentV5) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.AccountConnection != nil {
+		buf.WriteString(a.AccountConnection.String())
+	}
+	buf.WriteByte('+')
+	if a.AllAccounts != nil {
+		buf.WriteString(a.AllAccounts.String())
+	}
+	buf.WriteByte('+')
+	if a.MaxEntries != nil {
+		buf.WriteString(a.MaxEntries.String())
+	}
+	buf.WriteByte('+')
+	if a.ContinuationReference != nil {
+		buf.WriteString(a.ContinuationReference.String())
+	}
+}
+
+func (a *AccountInformationRequestSegmentV6) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.AccountConnection != nil {
+		marshaled, err := a.AccountConnection.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AllAccounts != nil {
+		marshaled, err := a.AllAccounts.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.MaxEntries != nil {
+		marshaled, err := a.MaxEntries.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.ContinuationReference != nil {
+		marshaled, err := a.ContinuationReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountInformationRequestSegmentV6) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.AccountConnection != nil {
+		buf.WriteString(a.AccountConnection.String())
+	}
+	buf.WriteByte('+')
+	if a.AllAccounts != nil {
+		buf.WriteString(a.AllAccounts.String())
+	}
+	buf.WriteByte('+')
+	if a.MaxEntries != nil {
+		buf.WriteString(a.MaxEntries.String())
+	}
+	buf.WriteByte('+')
+	if a.ContinuationReference != nil {
+		buf.WriteString(a.ContinuationReference.String())
+	}
+}
+
+func (a *AccountInformationResponseSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.AccountConnection != nil {
+		marshaled, err := a.AccountConnection.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountKind != nil {
+		marshaled, err := a.AccountKind.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.Name1 != nil {
+		marshaled, err := a.Name1.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.Name2 != nil {
+		marshaled, err := a.Name2.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountProductID != nil {
+		marshaled, err := a.AccountProductID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountCurrency != nil {
+		marshaled, err := a.AccountCurrency.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.OpeningDate != nil {
+		marshaled, err := a.OpeningDate.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.DebitInterest != nil {
+		marshaled, err := a.DebitInterest.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.CreditInterest != nil {
+		marshaled, err := a.CreditInterest.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.OverDebitInterest != nil {
+		marshaled, err := a.OverDebitInterest.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.CreditLimit != nil {
+		marshaled, err := a.CreditLimit.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.ReferenceAccount != nil {
+		marshaled, err := a.ReferenceAccount.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountStatementShippingType != nil {
+		marshaled, err := a.AccountStatementShippingType.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountStatementShippingRotation != nil {
+		marshaled, err := a.AccountStatementShippingRotation.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AdditionalInformation != nil {
+		marshaled, err := a.AdditionalInformation.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.DisposalEligiblePersons != nil {
+		marshaled, err := a.DisposalEligiblePersons.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountInformationResponseSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.AccountConnection != nil {
+		buf.WriteString(a.AccountConnection.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountKind != nil {
+		buf.WriteString(a.AccountKind.String())
+	}
+	buf.WriteByte('+')
+	if a.Name1 != nil {
+		buf.WriteString(a.Name1.String())
+	}
+	buf.WriteByte('+')
+	if a.Name2 != nil {
+		buf.WriteString(a.Name2.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountProductID != nil {
+		buf.WriteString(a.AccountProductID.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountCurrency != nil {
+		buf.WriteString(a.AccountCurrency.String())
+	}
+	buf.WriteByte('+')
+	if a.OpeningDate != nil {
+		buf.WriteString(a.OpeningDate.String())
+	}
+	buf.WriteByte('+')
+	if a.DebitInterest != nil {
+		buf.WriteString(a.DebitInterest.String())
+	}
+	buf.WriteByte('+')
+	if a.CreditInterest != nil {
+		buf.WriteString(a.CreditInterest.String())
+	}
+	buf.WriteByte('+')
+	if a.OverDebitInterest != nil {
+		buf.WriteString(a.OverDebitInterest.String())
+	}
+	buf.WriteByte('+')
+	if a.CreditLimit != nil {
+		buf.WriteString(a.CreditLimit.String())
+	}
+	buf.WriteByte('+')
+	if a.ReferenceAccount != nil {
+		buf.WriteString(a.ReferenceAccount.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountStatementShippingType != nil {
+		buf.WriteString(a.AccountStatementShippingType.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountStatementShippingRotation != nil {
+		buf.WriteString(a.AccountStatementShippingRotation.String())
+	}
+	buf.WriteByte('+')
+	if a.AdditionalInformation != nil {
+		buf.WriteString(a.AdditionalInformation.String())
+	}
+	buf.WriteByte('+')
+	if a.DisposalEligiblePersons != nil {
+		buf.WriteString(a.DisposalEligiblePersons.String())
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/mitch000001/go-hbci/swift"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments AccountTransactionRequestV5,AccountTransactionRequestV6,AccountTransactionRequestV7,AccountTransactionResponseSegmentV5,AccountTransactionResponseSegmentV6,AccountTransactionResponseSegmentV7
+
 var accountTransactionRequests = map[int](func(account domain.AccountConnection, allAccounts bool) *AccountTransactionRequestSegment){
 	6: NewAccountTransactionRequestSegmentV6,
 	5: NewAccountTransactionRequestSegmentV5,
@@ -48,6 +50,15 @@ type AccountTransactionRequest interface {
 	ClientSegment
 	SetContinuationReference(string)
 	SetTransactionRange(domain.Timeframe)
+	SetMaxEntries(int)
+	// Timeframe returns the requested transaction range
+	Timeframe() domain.Timeframe
+	// ForAllAccounts returns true if the transactions of all accounts are
+	// requested
+	ForAllAccounts() bool
+	// EntryCount returns the maximum number of entries requested, or zero
+	// if not restricted
+	EntryCount() int
 }
 
 func NewAccountTransactionRequestSegmentV5(account domain.AccountConnection, allAccounts bool) *AccountTransactionRequestSegment {
@@ -65,8 +76,8 @@ func NewAccountTransactionRequestSegmentV5(account domain.AccountConnection, all
 
 type AccountTransactionRequestV5 struct {
 	ClientSegment
-	Account               *element.AccountConnectionDataElement
-	AllAccounts           *element.BooleanDataElement
+	Account               *element.AccountConnectionDataElement `hbci:"required"`
+	AllAccounts           *element.BooleanDataElement           `hbci:"required"`
 	From                  *element.DateDataElement
 	To                    *element.DateDataElement
 	MaxEntries            *element.NumberDataElement
@@ -90,6 +101,25 @@ func (a *AccountTransactionRequestV5) SetTransactionRange(timeframe domain.Timef
 	a.To = element.NewDate(to.Time)
 }
 
+func (a *AccountTransactionRequestV5) SetMaxEntries(maxEntries int) {
+	a.MaxEntries = element.NewNumber(maxEntries, 4)
+}
+
+func (a *AccountTransactionRequestV5) Timeframe() domain.Timeframe {
+	return timeframe(a.From, a.To)
+}
+
+func (a *AccountTransactionRequestV5) ForAllAccounts() bool {
+	return a.AllAccounts != nil && a.AllAccounts.Val()
+}
+
+func (a *AccountTransactionRequestV5) EntryCount() int {
+	if a.MaxEntries == nil {
+		return 0
+	}
+	return a.MaxEntries.Val()
+}
+
 func (a *AccountTransactionRequestV5) Version() int         { return 5 }
 func (a *AccountTransactionRequestV5) ID() string           { return "HKKAZ" }
 func (a *AccountTransactionRequestV5) referencedId() string { return "" }
@@ -121,8 +151,8 @@ func NewAccountTransactionRequestSegmentV6(account domain.AccountConnection, all
 
 type AccountTransactionRequestV6 struct {
 	ClientSegment
-	Account               *element.AccountConnectionDataElement
-	AllAccounts           *element.BooleanDataElement
+	Account               *element.AccountConnectionDataElement `hbci:"required"`
+	AllAccounts           *element.BooleanDataElement           `hbci:"required"`
 	From                  *element.DateDataElement
 	To                    *element.DateDataElement
 	MaxEntries            *element.NumberDataElement
@@ -146,6 +176,25 @@ func (a *AccountTransactionRequestV6) SetTransactionRange(timeframe domain.Timef
 	a.To = element.NewDate(to.Time)
 }
 
+func (a *AccountTransactionRequestV6) SetMaxEntries(maxEntries int) {
+	a.MaxEntries = element.NewNumber(maxEntries, 4)
+}
+
+func (a *AccountTransactionRequestV6) Timeframe() domain.Timeframe {
+	return timeframe(a.From, a.To)
+}
+
+func (a *AccountTransactionRequestV6) ForAllAccounts() bool {
+	return a.AllAccounts != nil && a.AllAccounts.Val()
+}
+
+func (a *AccountTransactionRequestV6) EntryCount() int {
+	if a.MaxEntries == nil {
+		return 0
+	}
+	return a.MaxEntries.Val()
+}
+
 func (a *AccountTransactionRequestV6) Version() int         { return 6 }
 func (a *AccountTransactionRequestV6) ID() string           { return "HKKAZ" }
 func (a *AccountTransactionRequestV6) referencedId() string { return "" }
@@ -177,8 +226,8 @@ func NewAccountTransactionRequestSegmentV7(account domain.InternationalAccountCo
 
 type AccountTransactionRequestV7 struct {
 	ClientSegment
-	InternationalAccount  *element.InternationalAccountConnectionDataElement
-	AllAccounts           *element.BooleanDataElement
+	InternationalAccount  *element.InternationalAccountConnectionDataElement `hbci:"required"`
+	AllAccounts           *element.BooleanDataElement                        `hbci:"required"`
 	From                  *element.DateDataElement
 	To                    *element.DateDataElement
 	MaxEntries            *element.NumberDataElement
@@ -202,6 +251,25 @@ func (a *AccountTransactionRequestV7) SetTransactionRange(timeframe domain.Timef
 	a.To = element.NewDate(to.Time)
 }
 
+func (a *AccountTransactionRequestV7) SetMaxEntries(maxEntries int) {
+	a.MaxEntries = element.NewNumber(maxEntries, 4)
+}
+
+func (a *AccountTransactionRequestV7) Timeframe() domain.Timeframe {
+	return timeframe(a.From, a.To)
+}
+
+func (a *AccountTransactionRequestV7) ForAllAccounts() bool {
+	return a.AllAccounts != nil && a.AllAccounts.Val()
+}
+
+func (a *AccountTransactionRequestV7) EntryCount() int {
+	if a.MaxEntries == nil {
+		return 0
+	}
+	return a.MaxEntries.Val()
+}
+
 func (a *AccountTransactionRequestV7) Version() int         { return 7 }
 func (a *AccountTransactionRequestV7) ID() string           { return "HKKAZ" }
 func (a *AccountTransactionRequestV7) referencedId() string { return "" }
@@ -218,6 +286,17 @@ func (a *AccountTransactionRequestV7) elements() []element.DataElement {
 	}
 }
 
+func timeframe(from, to *element.DateDataElement) domain.Timeframe {
+	var tf domain.Timeframe
+	if from != nil {
+		tf.StartDate = domain.NewShortDate(from.Val())
+	}
+	if to != nil {
+		tf.EndDate = domain.NewShortDate(to.Val())
+	}
+	return tf
+}
+
 type AccountTransactionResponse interface {
 	BankSegment
 	BookedSwiftTransactions() *swift.MT940Messages
@@ -0,0 +1,463 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (a *AccountTransactionRequestV5) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.Account != nil {
+		marshaled, err := a.Account.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AllAccounts != nil {
+		marshaled, err := a.AllAccounts.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.From != nil {
+		marshaled, err := a.From.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.To != nil {
+		marshaled, err := a.To.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.MaxEntries != nil {
+		marshaled, err := a.MaxEntries.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.ContinuationReference != nil {
+		marshaled, err := a.ContinuationReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountTransactionRequestV5) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.Account != nil {
+		buf.WriteString(a.Account.String())
+	}
+	buf.WriteByte('+')
+	if a.AllAccounts != nil {
+		buf.WriteString(a.AllAccounts.String())
+	}
+	buf.WriteByte('+')
+	if a.From != nil {
+		buf.WriteString(a.From.String())
+	}
+	buf.WriteByte('+')
+	if a.To != nil {
+		buf.WriteString(a.To.String())
+	}
+	buf.WriteByte('+')
+	if a.MaxEntries != nil {
+		buf.WriteString(a.MaxEntries.String())
+	}
+	buf.WriteByte('+')
+	if a.ContinuationReference != nil {
+		buf.WriteString(a.ContinuationReference.String())
+	}
+}
+
+func (a *AccountTransactionRequestV6) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.Account != nil {
+		marshaled, err := a.Account.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AllAccounts != nil {
+		marshaled, err := a.AllAccounts.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.From != nil {
+		marshaled, err := a.From.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.To != nil {
+		marshaled, err := a.To.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.MaxEntries != nil {
+		marshaled, err := a.MaxEntries.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.ContinuationReference != nil {
+		marshaled, err := a.ContinuationReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountTransactionRequestV6) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.Account != nil {
+		buf.WriteString(a.Account.String())
+	}
+	buf.WriteByte('+')
+	if a.AllAccounts != nil {
+		buf.WriteString(a.AllAccounts.String())
+	}
+	buf.WriteByte('+')
+	if a.From != nil {
+		buf.WriteString(a.From.String())
+	}
+	buf.WriteByte('+')
+	if a.To != nil {
+		buf.WriteString(a.To.String())
+	}
+	buf.WriteByte('+')
+	if a.MaxEntries != nil {
+		buf.WriteString(a.MaxEntries.String())
+	}
+	buf.WriteByte('+')
+	if a.ContinuationReference != nil {
+		buf.WriteString(a.ContinuationReference.String())
+	}
+}
+
+func (a *AccountTransactionRequestV7) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.InternationalAccount != nil {
+		marshaled, err := a.InternationalAccount.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AllAccounts != nil {
+		marshaled, err := a.AllAccounts.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.From != nil {
+		marshaled, err := a.From.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.To != nil {
+		marshaled, err := a.To.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.MaxEntries != nil {
+		marshaled, err := a.MaxEntries.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.ContinuationReference != nil {
+		marshaled, err := a.ContinuationReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountTransactionRequestV7) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.InternationalAccount != nil {
+		buf.WriteString(a.InternationalAccount.String())
+	}
+	buf.WriteByte('+')
+	if a.AllAccounts != nil {
+		buf.WriteString(a.AllAccounts.String())
+	}
+	buf.WriteByte('+')
+	if a.From != nil {
+		buf.WriteString(a.From.String())
+	}
+	buf.WriteByte('+')
+	if a.To != nil {
+		buf.WriteString(a.To.String())
+	}
+	buf.WriteByte('+')
+	if a.MaxEntries != nil {
+		buf.WriteString(a.MaxEntries.String())
+	}
+	buf.WriteByte('+')
+	if a.ContinuationReference != nil {
+		buf.WriteString(a.ContinuationReference.String())
+	}
+}
+
+func (a *AccountTransactionResponseSegmentV5) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.BookedTransactions != nil {
+		marshaled, err := a.BookedTransactions.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.UnbookedTransactions != nil {
+		marshaled, err := a.UnbookedTransactions.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountTransactionResponseSegmentV5) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.BookedTransactions != nil {
+		buf.WriteString(a.BookedTransactions.String())
+	}
+	buf.WriteByte('+')
+	if a.UnbookedTransactions != nil {
+		buf.WriteString(a.UnbookedTransactions.String())
+	}
+}
+
+func (a *AccountTransactionResponseSegmentV6) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.BookedTransactions != nil {
+		marshaled, err := a.BookedTransactions.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.UnbookedTransactions != nil {
+		marshaled, err := a.UnbookedTransactions.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountTransactionResponseSegmentV6) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.BookedTransactions != nil {
+		buf.WriteString(a.BookedTransactions.String())
+	}
+	buf.WriteByte('+')
+	if a.UnbookedTransactions != nil {
+		buf.WriteString(a.UnbookedTransactions.String())
+	}
+}
+
+func (a *AccountTransactionResponseSegmentV7) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.BookedTransactions != nil {
+		marshaled, err := a.BookedTransactions.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.UnbookedTransactions != nil {
+		marshaled, err := a.UnbookedTransactions.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountTransactionResponseSegmentV7) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.BookedTransactions != nil {
+		buf.WriteString(a.BookedTransactions.String())
+	}
+	buf.WriteByte('+')
+	if a.UnbookedTransactions != nil {
+		buf.WriteString(a.UnbookedTransactions.String())
+	}
+}
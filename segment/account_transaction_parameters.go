@@ -0,0 +1,84 @@
+package segment
+
+import (
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/element"
+)
+
+// AccountTransactionParametersID is the ID of the AccountTransactionParameterSegment
+const AccountTransactionParametersID = "HIKAZS"
+
+// AccountTransactionParameterSegment contains the parameters of the account
+// transaction request (HKKAZ) stated within the BPD
+type AccountTransactionParameterSegment struct {
+	*BusinessTransactionParamsSegment
+	// MaxDays is the number of days the institute keeps the transactions
+	MaxDays *element.NumberDataElement
+	// EntryCountAllowed states whether the number of entries may be
+	// restricted by the client
+	EntryCountAllowed *element.BooleanDataElement
+	// AllAccountsAllowed states whether the transactions of all accounts may
+	// be requested at once. It is missing in version 4.
+	AllAccountsAllowed *element.BooleanDataElement
+}
+
+func (a *AccountTransactionParameterSegment) ID() string { return AccountTransactionParametersID }
+
+// UnmarshalHBCI unmarshals the segment in the versions 4 to 7. The
+// parameters are optional, as some institutes omit them.
+func (a *AccountTransactionParameterSegment) UnmarshalHBCI(value []byte) error {
+	businessTransactionSegment := &BusinessTransactionParamsSegment{}
+	err := businessTransactionSegment.UnmarshalHBCI(value)
+	if err != nil {
+		return err
+	}
+	a.BusinessTransactionParamsSegment = businessTransactionSegment
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	// the security class got added with FinTS 3.0
+	paramsPosition := len(elements) - 1
+	if paramsPosition < 3 || len(elements[paramsPosition]) == 0 {
+		return nil
+	}
+	params, err := element.ExtractElements(append([]byte{}, elements[paramsPosition]...))
+	if err != nil {
+		return fmt.Errorf("%T: Malformed parameters: %v", a, err)
+	}
+	if len(params) < 2 {
+		// only the security class is present
+		return nil
+	}
+	parameter := &element.AccountTransactionParameter{}
+	if err := parameter.UnmarshalHBCI(elements[paramsPosition]); err != nil {
+		return fmt.Errorf("%T: %v", a, err)
+	}
+	a.Params = parameter
+	a.MaxDays = parameter.MaxDays
+	a.EntryCountAllowed = parameter.EntryCountAllowed
+	a.AllAccountsAllowed = parameter.AllAccountsAllowed
+	return nil
+}
+
+// MaxDaysBack returns the number of days the institute keeps transactions, or
+// zero if not stated
+func (a *AccountTransactionParameterSegment) MaxDaysBack() int {
+	if a.MaxDays == nil {
+		return 0
+	}
+	return a.MaxDays.Val()
+}
+
+// EntryCountRestrictable returns true if the client may restrict the number
+// of entries
+func (a *AccountTransactionParameterSegment) EntryCountRestrictable() bool {
+	return a.EntryCountAllowed != nil && a.EntryCountAllowed.Val()
+}
+
+// AllAccountsRequestable returns true if the transactions of all accounts
+// may be requested at once
+func (a *AccountTransactionParameterSegment) AllAccountsRequestable() bool {
+	return a.AllAccountsAllowed != nil && a.AllAccountsAllowed.Val()
+}
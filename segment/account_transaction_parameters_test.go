@@ -0,0 +1,50 @@
+package segment
+
+import "testing"
+
+func TestAccountTransactionParameterSegmentUnmarshalHBCI(t *testing.T) {
+	tests := []struct {
+		marshaled          string
+		version            int
+		maxJobs            int
+		maxDays            int
+		entryCountAllowed  bool
+		allAccountsAllowed bool
+	}{
+		{"HIKAZS:23:4:4+1+1+90:J'", 4, 1, 90, true, false},
+		{"HIKAZS:23:5:4+1+1+360:J:N'", 5, 1, 360, true, false},
+		{"HIKAZS:24:6:4+2+1+0+730:N:J'", 6, 2, 730, false, true},
+		{"HIKAZS:25:7:4+1+1+0'", 7, 1, 0, false, false},
+	}
+	for _, test := range tests {
+		seg := &AccountTransactionParameterSegment{}
+
+		err := seg.UnmarshalHBCI([]byte(test.marshaled))
+
+		if err != nil {
+			t.Logf("%s: Expected no error, got %T:%v\n", test.marshaled, err, err)
+			t.Fail()
+			continue
+		}
+		if seg.ID() != AccountTransactionParametersID || seg.Version() != test.version {
+			t.Logf("%s: Expected segment %s:%d, got %s:%d\n", test.marshaled, AccountTransactionParametersID, test.version, seg.ID(), seg.Version())
+			t.Fail()
+		}
+		if seg.MaxJobsPerMessage() != test.maxJobs {
+			t.Logf("%s: Expected max jobs %d, got %d\n", test.marshaled, test.maxJobs, seg.MaxJobsPerMessage())
+			t.Fail()
+		}
+		if seg.MaxDaysBack() != test.maxDays {
+			t.Logf("%s: Expected max days %d, got %d\n", test.marshaled, test.maxDays, seg.MaxDaysBack())
+			t.Fail()
+		}
+		if seg.EntryCountRestrictable() != test.entryCountAllowed {
+			t.Logf("%s: Expected entry count allowed to be %t\n", test.marshaled, test.entryCountAllowed)
+			t.Fail()
+		}
+		if seg.AllAccountsRequestable() != test.allAccountsAllowed {
+			t.Logf("%s: Expected all accounts allowed to be %t\n", test.marshaled, test.allAccountsAllowed)
+			t.Fail()
+		}
+	}
+}
@@ -1,12 +1,24 @@
 package segment
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/mitch000001/go-hbci/domain"
 	"github.com/mitch000001/go-hbci/element"
 )
 
+// NewMessageAcknowledgement returns a new message acknowledgement segment
+// containing acknowledgements.
+func NewMessageAcknowledgement(acknowledgements ...domain.Acknowledgement) *MessageAcknowledgement {
+	m := &MessageAcknowledgement{}
+	for _, ack := range acknowledgements {
+		m.acknowledgements = append(m.acknowledgements, element.NewAcknowledgement(ack))
+	}
+	m.Segment = NewBasicSegment(0, m)
+	return m
+}
+
 type MessageAcknowledgement struct {
 	Segment
 	acknowledgements   []*element.AcknowledgementDataElement
@@ -73,6 +85,25 @@ func (m *MessageAcknowledgement) elements() []element.DataElement {
 	return dataElements
 }
 
+func (m *MessageAcknowledgement) marshalHBCIElements(buf *bytes.Buffer) error {
+	return marshalDataElements(buf, m.elements())
+}
+
+func (m *MessageAcknowledgement) writeElementStrings(buf *bytes.Buffer) {
+	writeDataElementStrings(buf, m.elements())
+}
+
+// NewSegmentAcknowledgement returns a new segment acknowledgement segment
+// containing acknowledgements for the segment at referencedSegment.
+func NewSegmentAcknowledgement(referencedSegment int, acknowledgements ...domain.Acknowledgement) *SegmentAcknowledgement {
+	s := &SegmentAcknowledgement{}
+	for _, ack := range acknowledgements {
+		s.acknowledgements = append(s.acknowledgements, element.NewAcknowledgement(ack))
+	}
+	s.Segment = NewReferencingBasicSegment(0, referencedSegment, s)
+	return s
+}
+
 type SegmentAcknowledgement struct {
 	Segment
 	acknowledgements   []*element.AcknowledgementDataElement
@@ -136,3 +167,11 @@ func (s *SegmentAcknowledgement) elements() []element.DataElement {
 	}
 	return dataElements
 }
+
+func (s *SegmentAcknowledgement) marshalHBCIElements(buf *bytes.Buffer) error {
+	return marshalDataElements(buf, s.elements())
+}
+
+func (s *SegmentAcknowledgement) writeElementStrings(buf *bytes.Buffer) {
+	writeDataElementStrings(buf, s.elements())
+}
@@ -0,0 +1,49 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (b *BankAnnouncementSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if b.Subject != nil {
+		marshaled, err := b.Subject.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if b.Body != nil {
+		marshaled, err := b.Body.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (b *BankAnnouncementSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if b.Subject != nil {
+		buf.WriteString(b.Subject.String())
+	}
+	buf.WriteByte('+')
+	if b.Body != nil {
+		buf.WriteString(b.Body.String())
+	}
+}
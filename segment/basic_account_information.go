@@ -1,10 +1,16 @@
 package segment
 
 import (
+	"bytes"
+	"fmt"
+	"regexp"
+
 	"github.com/mitch000001/go-hbci/domain"
 	"github.com/mitch000001/go-hbci/element"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments AccountInformationV4,AccountInformationV5,AccountInformationV6,AccountInformationV7
+
 const AccountInformationID string = "HIUPD"
 
 type AccountInformation interface {
@@ -261,3 +267,127 @@ func (a *AccountInformationV7) elements() []element.DataElement {
 		a.AccountExtensions,
 	}
 }
+
+var businessTransactionIDPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{4,5}$`)
+
+// UnmarshalHBCI unmarshals value into a. The allowed business transactions
+// are repeated and followed by the account extensions, so they are told apart
+// by their business transaction ID.
+func (a *AccountInformationV6) UnmarshalHBCI(value []byte) error {
+	return unmarshalAccountInformation(value, a, a)
+}
+
+// UnmarshalHBCI unmarshals value into a like AccountInformationV6.UnmarshalHBCI.
+func (a *AccountInformationV7) UnmarshalHBCI(value []byte) error {
+	// both versions consist of the same elements
+	return unmarshalAccountInformation(value, a, (*AccountInformationV6)(a))
+}
+
+// unmarshalAccountInformation unmarshals value into the elements of
+// account, using seg for the segment header.
+func unmarshalAccountInformation(value []byte, seg basicSegment, account *AccountInformationV6) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) == 0 {
+		return fmt.Errorf("Malformed marshaled value")
+	}
+	header, err := SegmentFromHeaderBytes(elements[0], seg)
+	if err != nil {
+		return err
+	}
+	account.Segment = header
+	if len(elements) > 1 && len(elements[1]) > 0 {
+		account.AccountConnection = &element.AccountConnectionDataElement{}
+		if err := account.AccountConnection.UnmarshalHBCI(elements[1]); err != nil {
+			return err
+		}
+	}
+	if len(elements) > 2 && len(elements[2]) > 0 {
+		account.IBAN = &element.AlphaNumericDataElement{}
+		if err := account.IBAN.UnmarshalHBCI(elements[2]); err != nil {
+			return err
+		}
+	}
+	if len(elements) > 3 && len(elements[3]) > 0 {
+		account.UserID = &element.IdentificationDataElement{}
+		if err := account.UserID.UnmarshalHBCI(elements[3]); err != nil {
+			return err
+		}
+	}
+	if len(elements) > 4 && len(elements[4]) > 0 {
+		account.AccountType = &element.NumberDataElement{}
+		if err := account.AccountType.UnmarshalHBCI(elements[4]); err != nil {
+			return err
+		}
+	}
+	if len(elements) > 5 && len(elements[5]) > 0 {
+		account.AccountCurrency = &element.CurrencyDataElement{}
+		if err := account.AccountCurrency.UnmarshalHBCI(elements[5]); err != nil {
+			return err
+		}
+	}
+	if len(elements) > 6 && len(elements[6]) > 0 {
+		account.Name1 = &element.AlphaNumericDataElement{}
+		if err := account.Name1.UnmarshalHBCI(elements[6]); err != nil {
+			return err
+		}
+	}
+	if len(elements) > 7 && len(elements[7]) > 0 {
+		account.Name2 = &element.AlphaNumericDataElement{}
+		if err := account.Name2.UnmarshalHBCI(elements[7]); err != nil {
+			return err
+		}
+	}
+	if len(elements) > 8 && len(elements[8]) > 0 {
+		account.AccountProductID = &element.AlphaNumericDataElement{}
+		if err := account.AccountProductID.UnmarshalHBCI(elements[8]); err != nil {
+			return err
+		}
+	}
+	if len(elements) > 9 && len(elements[9]) > 0 {
+		account.AccountLimit = &element.AccountLimitDataElement{}
+		if err := account.AccountLimit.UnmarshalHBCI(elements[9]); err != nil {
+			return err
+		}
+	}
+	if len(elements) <= 10 {
+		return nil
+	}
+	rest := elements[10:]
+	var transactions [][]byte
+	for len(rest) > 0 && isAllowedBusinessTransaction(rest[0]) {
+		transactions = append(transactions, rest[0])
+		rest = rest[1:]
+	}
+	if len(transactions) != 0 {
+		account.AllowedBusinessTransactions = &element.AllowedBusinessTransactionsDataElement{}
+		if err := account.AllowedBusinessTransactions.UnmarshalHBCI(bytes.Join(transactions, []byte("+"))); err != nil {
+			return err
+		}
+	} else if len(rest) > 1 && len(rest[0]) == 0 {
+		// no allowed business transactions, but account extensions
+		rest = rest[1:]
+	}
+	if len(rest) > 1 {
+		return fmt.Errorf("Malformed account information: unexpected element after account extensions")
+	}
+	if len(rest) == 1 && len(rest[0]) > 0 {
+		account.AccountExtensions = &element.AlphaNumericDataElement{}
+		if err := account.AccountExtensions.UnmarshalHBCI(rest[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isAllowedBusinessTransaction returns true if value is a marshaled allowed
+// business transaction.
+func isAllowedBusinessTransaction(value []byte) bool {
+	transaction := &element.AllowedBusinessTransactionDataElement{}
+	if err := transaction.UnmarshalHBCI(value); err != nil {
+		return false
+	}
+	return businessTransactionIDPattern.MatchString(transaction.BusinessTransactionID.Val())
+}
@@ -0,0 +1,702 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (a *AccountInformationV4) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.AccountConnection != nil {
+		marshaled, err := a.AccountConnection.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.UserID != nil {
+		marshaled, err := a.UserID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountCurrency != nil {
+		marshaled, err := a.AccountCurrency.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.Name1 != nil {
+		marshaled, err := a.Name1.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.Name2 != nil {
+		marshaled, err := a.Name2.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountProductID != nil {
+		marshaled, err := a.AccountProductID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountLimit != nil {
+		marshaled, err := a.AccountLimit.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AllowedBusinessTransactions != nil {
+		marshaled, err := a.AllowedBusinessTransactions.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountInformationV4) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.AccountConnection != nil {
+		buf.WriteString(a.AccountConnection.String())
+	}
+	buf.WriteByte('+')
+	if a.UserID != nil {
+		buf.WriteString(a.UserID.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountCurrency != nil {
+		buf.WriteString(a.AccountCurrency.String())
+	}
+	buf.WriteByte('+')
+	if a.Name1 != nil {
+		buf.WriteString(a.Name1.String())
+	}
+	buf.WriteByte('+')
+	if a.Name2 != nil {
+		buf.WriteString(a.Name2.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountProductID != nil {
+		buf.WriteString(a.AccountProductID.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountLimit != nil {
+		buf.WriteString(a.AccountLimit.String())
+	}
+	buf.WriteByte('+')
+	if a.AllowedBusinessTransactions != nil {
+		buf.WriteString(a.AllowedBusinessTransactions.String())
+	}
+}
+
+func (a *AccountInformationV5) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.AccountConnection != nil {
+		marshaled, err := a.AccountConnection.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.UserID != nil {
+		marshaled, err := a.UserID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountType != nil {
+		marshaled, err := a.AccountType.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountCurrency != nil {
+		marshaled, err := a.AccountCurrency.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.Name1 != nil {
+		marshaled, err := a.Name1.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.Name2 != nil {
+		marshaled, err := a.Name2.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountProductID != nil {
+		marshaled, err := a.AccountProductID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountLimit != nil {
+		marshaled, err := a.AccountLimit.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AllowedBusinessTransactions != nil {
+		marshaled, err := a.AllowedBusinessTransactions.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountInformationV5) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.AccountConnection != nil {
+		buf.WriteString(a.AccountConnection.String())
+	}
+	buf.WriteByte('+')
+	if a.UserID != nil {
+		buf.WriteString(a.UserID.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountType != nil {
+		buf.WriteString(a.AccountType.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountCurrency != nil {
+		buf.WriteString(a.AccountCurrency.String())
+	}
+	buf.WriteByte('+')
+	if a.Name1 != nil {
+		buf.WriteString(a.Name1.String())
+	}
+	buf.WriteByte('+')
+	if a.Name2 != nil {
+		buf.WriteString(a.Name2.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountProductID != nil {
+		buf.WriteString(a.AccountProductID.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountLimit != nil {
+		buf.WriteString(a.AccountLimit.String())
+	}
+	buf.WriteByte('+')
+	if a.AllowedBusinessTransactions != nil {
+		buf.WriteString(a.AllowedBusinessTransactions.String())
+	}
+}
+
+func (a *AccountInformationV6) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.AccountConnection != nil {
+		marshaled, err := a.AccountConnection.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.IBAN != nil {
+		marshaled, err := a.IBAN.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.UserID != nil {
+		marshaled, err := a.UserID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountType != nil {
+		marshaled, err := a.AccountType.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountCurrency != nil {
+		marshaled, err := a.AccountCurrency.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.Name1 != nil {
+		marshaled, err := a.Name1.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.Name2 != nil {
+		marshaled, err := a.Name2.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountProductID != nil {
+		marshaled, err := a.AccountProductID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountLimit != nil {
+		marshaled, err := a.AccountLimit.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AllowedBusinessTransactions != nil {
+		marshaled, err := a.AllowedBusinessTransactions.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountExtensions != nil {
+		marshaled, err := a.AccountExtensions.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountInformationV6) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.AccountConnection != nil {
+		buf.WriteString(a.AccountConnection.String())
+	}
+	buf.WriteByte('+')
+	if a.IBAN != nil {
+		buf.WriteString(a.IBAN.String())
+	}
+	buf.WriteByte('+')
+	if a.UserID != nil {
+		buf.WriteString(a.UserID.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountType != nil {
+		buf.WriteString(a.AccountType.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountCurrency != nil {
+		buf.WriteString(a.AccountCurrency.String())
+	}
+	buf.WriteByte('+')
+	if a.Name1 != nil {
+		buf.WriteString(a.Name1.String())
+	}
+	buf.WriteByte('+')
+	if a.Name2 != nil {
+		buf.WriteString(a.Name2.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountProductID != nil {
+		buf.WriteString(a.AccountProductID.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountLimit != nil {
+		buf.WriteString(a.AccountLimit.String())
+	}
+	buf.WriteByte('+')
+	if a.AllowedBusinessTransactions != nil {
+		buf.WriteString(a.AllowedBusinessTransactions.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountExtensions != nil {
+		buf.WriteString(a.AccountExtensions.String())
+	}
+}
+
+func (a *AccountInformationV7) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if a.AccountConnection != nil {
+		marshaled, err := a.AccountConnection.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.IBAN != nil {
+		marshaled, err := a.IBAN.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.UserID != nil {
+		marshaled, err := a.UserID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountType != nil {
+		marshaled, err := a.AccountType.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountCurrency != nil {
+		marshaled, err := a.AccountCurrency.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.Name1 != nil {
+		marshaled, err := a.Name1.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.Name2 != nil {
+		marshaled, err := a.Name2.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountProductID != nil {
+		marshaled, err := a.AccountProductID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountLimit != nil {
+		marshaled, err := a.AccountLimit.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AllowedBusinessTransactions != nil {
+		marshaled, err := a.AllowedBusinessTransactions.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if a.AccountExtensions != nil {
+		marshaled, err := a.AccountExtensions.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (a *AccountInformationV7) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if a.AccountConnection != nil {
+		buf.WriteString(a.AccountConnection.String())
+	}
+	buf.WriteByte('+')
+	if a.IBAN != nil {
+		buf.WriteString(a.IBAN.String())
+	}
+	buf.WriteByte('+')
+	if a.UserID != nil {
+		buf.WriteString(a.UserID.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountType != nil {
+		buf.WriteString(a.AccountType.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountCurrency != nil {
+		buf.WriteString(a.AccountCurrency.String())
+	}
+	buf.WriteByte('+')
+	if a.Name1 != nil {
+		buf.WriteString(a.Name1.String())
+	}
+	buf.WriteByte('+')
+	if a.Name2 != nil {
+		buf.WriteString(a.Name2.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountProductID != nil {
+		buf.WriteString(a.AccountProductID.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountLimit != nil {
+		buf.WriteString(a.AccountLimit.String())
+	}
+	buf.WriteByte('+')
+	if a.AllowedBusinessTransactions != nil {
+		buf.WriteString(a.AllowedBusinessTransactions.String())
+	}
+	buf.WriteByte('+')
+	if a.AccountExtensions != nil {
+		buf.WriteString(a.AccountExtensions.String())
+	}
+}
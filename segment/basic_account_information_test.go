@@ -73,3 +73,96 @@ func TestAccountInformationSegment_Account(t *testing.T) {
 		})
 	}
 }
+
+func TestAccountInformationSegment_AccountUnmarshaled(t *testing.T) {
+	dailyLimit := &domain.AccountLimit{Kind: "T", Amount: domain.Amount{Amount: 1000, Currency: "EUR"}, Days: 1}
+	testCases := []struct {
+		desc            string
+		rawSegment      string
+		expectedAccount domain.AccountInformation
+	}{
+		{
+			desc:       "v6 with limits and several allowed transactions",
+			rawSegment: "HIUPD:5:6:3+1234567::280:10000000+DE12100000000001234567+12345+1+EUR+Muster+Max+Girokonto+T:5000,:EUR:1+HKSAL:1+HKKAZ:1+HKCCS:1:T:1000,:EUR:1'",
+			expectedAccount: domain.AccountInformation{
+				AccountConnection: domain.AccountConnection{AccountID: "1234567", CountryCode: 280, BankID: "10000000"},
+				UserID:            "12345",
+				Currency:          "EUR",
+				Name1:             "Muster",
+				Name2:             "Max",
+				ProductID:         "Girokonto",
+				Limit:             &domain.AccountLimit{Kind: "T", Amount: domain.Amount{Amount: 5000, Currency: "EUR"}, Days: 1},
+				AllowedBusinessTransactions: []domain.BusinessTransaction{
+					{ID: "HKSAL", NeededSignatures: 1},
+					{ID: "HKKAZ", NeededSignatures: 1},
+					{ID: "HKCCS", NeededSignatures: 1, Limit: dailyLimit},
+				},
+			},
+		},
+		{
+			desc:       "v7 with account extension",
+			rawSegment: "HIUPD:5:7:3+1234567::280:10000000+DE12100000000001234567+12345+1+EUR+Muster++Girokonto++HKSAL:1+HKCAZ:1+{\"umsltzt\"?:\"2019-01-01\"}'",
+			expectedAccount: domain.AccountInformation{
+				AccountConnection: domain.AccountConnection{AccountID: "1234567", CountryCode: 280, BankID: "10000000"},
+				UserID:            "12345",
+				Currency:          "EUR",
+				Name1:             "Muster",
+				ProductID:         "Girokonto",
+				AllowedBusinessTransactions: []domain.BusinessTransaction{
+					{ID: "HKSAL", NeededSignatures: 1},
+					{ID: "HKCAZ", NeededSignatures: 1},
+				},
+			},
+		},
+		{
+			desc:       "v6 with account extension but no allowed transactions",
+			rawSegment: "HIUPD:5:6:3+1234567::280:10000000++12345++EUR+Muster+++++{\"umsltzt\"?:\"2019-01-01\"}'",
+			expectedAccount: domain.AccountInformation{
+				AccountConnection: domain.AccountConnection{AccountID: "1234567", CountryCode: 280, BankID: "10000000"},
+				UserID:            "12345",
+				Currency:          "EUR",
+				Name1:             "Muster",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			seg := &AccountInformationSegment{}
+			err := seg.UnmarshalHBCI([]byte(tt.rawSegment))
+			if err != nil {
+				t.Fatalf("Expected no error, got %T:%v\n", err, err)
+			}
+
+			account := seg.Account()
+
+			if !reflect.DeepEqual(tt.expectedAccount, account) {
+				t.Errorf("Expected accout to equal\n%#v\n\tgot\n%#v\n", tt.expectedAccount, account)
+			}
+		})
+	}
+}
+
+func TestAccountInformationV7UnmarshalHBCI(t *testing.T) {
+	seg := &AccountInformationV7{}
+	err := seg.UnmarshalHBCI([]byte("HIUPD:5:7:3+1234567::280:10000000++12345++EUR+Muster++++HKSAL:1+HKCAZ:1+{\"umsltzt\"?:\"2019-01-01\"}'"))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+	if transactions := seg.AllowedBusinessTransactions.AllowedBusinessTransactions(); len(transactions) != 2 {
+		t.Logf("Expected 2 allowed business transactions, got %v\n", transactions)
+		t.Fail()
+	}
+	if seg.AccountExtensions == nil || seg.AccountExtensions.Val() != `{"umsltzt":"2019-01-01"}` {
+		t.Logf("Expected account extensions to be set, got %v\n", seg.AccountExtensions)
+		t.Fail()
+	}
+
+	err = (&AccountInformationV7{}).UnmarshalHBCI([]byte("HIUPD:5:7:3+1234567::280:10000000++12345++EUR+Muster++++HKSAL:1+{}+{}'"))
+
+	if err == nil {
+		t.Logf("Expected error for elements following the account extensions, got nil\n")
+		t.Fail()
+	}
+}
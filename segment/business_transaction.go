@@ -9,6 +9,20 @@ import (
 	"github.com/mitch000001/go-hbci/element"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments BusinessTransactionParamsSegment
+
+// BusinessTransactionParameters is implemented by the parameter segments of
+// business transactions within the BPD
+type BusinessTransactionParameters interface {
+	BankSegment
+	// MaxJobsPerMessage returns the maximum number of jobs of the business
+	// transaction within one message, or zero if not restricted
+	MaxJobsPerMessage() int
+}
+
+// BusinessTransactionParamsSegment contains the parameters common to all
+// business transactions. It can unmarshal the parameter segment of any
+// business transaction.
 type BusinessTransactionParamsSegment struct {
 	Segment
 	id            string
@@ -41,7 +55,9 @@ func (b *BusinessTransactionParamsSegment) UnmarshalHBCI(value []byte) error {
 		return err
 	}
 	b.Segment = seg
-	if len(elements) < 4 {
+	b.id = seg.Header().ID.Val()
+	b.version = seg.Header().Version.Val()
+	if len(elements) < 3 {
 		return fmt.Errorf("%T: Malformed marshaled value", b)
 	}
 	maxJobs, err := strconv.Atoi(charset.ToUTF8(elements[1]))
@@ -57,6 +73,14 @@ func (b *BusinessTransactionParamsSegment) UnmarshalHBCI(value []byte) error {
 	return nil
 }
 
+// MaxJobsPerMessage implements BusinessTransactionParameters
+func (b *BusinessTransactionParamsSegment) MaxJobsPerMessage() int {
+	if b.MaxJobs == nil {
+		return 0
+	}
+	return b.MaxJobs.Val()
+}
+
 const PinTanBusinessTransactionParamsID string = "DIPINS"
 
 type PinTanBusinessTransactionParams interface {
@@ -84,6 +108,9 @@ func (p *PinTanBusinessTransactionParamsSegment) UnmarshalHBCI(value []byte) err
 	if err != nil {
 		return err
 	}
+	if len(elements) < 4 {
+		return fmt.Errorf("%T: Malformed marshaled value", p)
+	}
 	pinTanParams := &element.PinTanBusinessTransactionParameters{}
 	err = pinTanParams.UnmarshalHBCI(elements[3])
 	if err != nil {
@@ -0,0 +1,66 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (b *BusinessTransactionParamsSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if b.MaxJobs != nil {
+		marshaled, err := b.MaxJobs.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if b.MinSignatures != nil {
+		marshaled, err := b.MinSignatures.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if b.Params != nil {
+		marshaled, err := b.Params.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (b *BusinessTransactionParamsSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if b.MaxJobs != nil {
+		buf.WriteString(b.MaxJobs.String())
+	}
+	buf.WriteByte('+')
+	if b.MinSignatures != nil {
+		buf.WriteString(b.MinSignatures.String())
+	}
+	buf.WriteByte('+')
+	if b.Params != nil {
+		buf.WriteString(b.Params.String())
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"github.com/mitch000001/go-hbci/element"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments CommonBankParameterV2,CommonBankParameterV3
+
 const CommonBankParameterID string = "HIBPA"
 
 type CommonBankParameter interface {
@@ -0,0 +1,295 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (c *CommonBankParameterV2) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if c.BPDVersion != nil {
+		marshaled, err := c.BPDVersion.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.BankID != nil {
+		marshaled, err := c.BankID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.BankName != nil {
+		marshaled, err := c.BankName.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.BusinessTransactionCount != nil {
+		marshaled, err := c.BusinessTransactionCount.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.SupportedLanguages != nil {
+		marshaled, err := c.SupportedLanguages.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.SupportedHBCIVersions != nil {
+		marshaled, err := c.SupportedHBCIVersions.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.MaxMessageSize != nil {
+		marshaled, err := c.MaxMessageSize.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (c *CommonBankParameterV2) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if c.BPDVersion != nil {
+		buf.WriteString(c.BPDVersion.String())
+	}
+	buf.WriteByte('+')
+	if c.BankID != nil {
+		buf.WriteString(c.BankID.String())
+	}
+	buf.WriteByte('+')
+	if c.BankName != nil {
+		buf.WriteString(c.BankName.String())
+	}
+	buf.WriteByte('+')
+	if c.BusinessTransactionCount != nil {
+		buf.WriteString(c.BusinessTransactionCount.String())
+	}
+	buf.WriteByte('+')
+	if c.SupportedLanguages != nil {
+		buf.WriteString(c.SupportedLanguages.String())
+	}
+	buf.WriteByte('+')
+	if c.SupportedHBCIVersions != nil {
+		buf.WriteString(c.SupportedHBCIVersions.String())
+	}
+	buf.WriteByte('+')
+	if c.MaxMessageSize != nil {
+		buf.WriteString(c.MaxMessageSize.String())
+	}
+}
+
+func (c *CommonBankParameterV3) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if c.BPDVersion != nil {
+		marshaled, err := c.BPDVersion.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.BankID != nil {
+		marshaled, err := c.BankID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.BankName != nil {
+		marshaled, err := c.BankName.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.BusinessTransactionCount != nil {
+		marshaled, err := c.BusinessTransactionCount.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.SupportedLanguages != nil {
+		marshaled, err := c.SupportedLanguages.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.SupportedHBCIVersions != nil {
+		marshaled, err := c.SupportedHBCIVersions.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.MaxMessageSize != nil {
+		marshaled, err := c.MaxMessageSize.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.MinTimeoutValue != nil {
+		marshaled, err := c.MinTimeoutValue.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.MaxTimeoutValue != nil {
+		marshaled, err := c.MaxTimeoutValue.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (c *CommonBankParameterV3) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if c.BPDVersion != nil {
+		buf.WriteString(c.BPDVersion.String())
+	}
+	buf.WriteByte('+')
+	if c.BankID != nil {
+		buf.WriteString(c.BankID.String())
+	}
+	buf.WriteByte('+')
+	if c.BankName != nil {
+		buf.WriteString(c.BankName.String())
+	}
+	buf.WriteByte('+')
+	if c.BusinessTransactionCount != nil {
+		buf.WriteString(c.BusinessTransactionCount.String())
+	}
+	buf.WriteByte('+')
+	if c.SupportedLanguages != nil {
+		buf.WriteString(c.SupportedLanguages.String())
+	}
+	buf.WriteByte('+')
+	if c.SupportedHBCIVersions != nil {
+		buf.WriteString(c.SupportedHBCIVersions.String())
+	}
+	buf.WriteByte('+')
+	if c.MaxMessageSize != nil {
+		buf.WriteString(c.MaxMessageSize.String())
+	}
+	buf.WriteByte('+')
+	if c.MinTimeoutValue != nil {
+		buf.WriteString(c.MinTimeoutValue.String())
+	}
+	buf.WriteByte('+')
+	if c.MaxTimeoutValue != nil {
+		buf.WriteString(c.MaxTimeoutValue.String())
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"github.com/mitch000001/go-hbci/element"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments CommunicationAccessRequestSegment,CommunicationAccessResponseSegment
+
 func NewCommunicationAccessRequestSegment(fromBank domain.BankID, toBank domain.BankID, maxEntries int, continuationReference string) *CommunicationAccessRequestSegment {
 	c := &CommunicationAccessRequestSegment{
 		FromBankID: element.NewBankIdentification(fromBank),
@@ -40,6 +42,10 @@ func (c *CommunicationAccessRequestSegment) elements() []element.DataElement {
 	}
 }
 
+func (c *CommunicationAccessRequestSegment) SetContinuationReference(continuationReference string) {
+	c.ContinuationReference = element.NewAlphaNumeric(continuationReference, 35)
+}
+
 const HKKOMSegmentNumber = -1
 
 func NewCommunicationAccessResponseSegment(bankId domain.BankID, language int, params domain.CommunicationParameter) *CommunicationAccessResponseSegment {
@@ -0,0 +1,142 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (c *CommunicationAccessRequestSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if c.FromBankID != nil {
+		marshaled, err := c.FromBankID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.ToBankID != nil {
+		marshaled, err := c.ToBankID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.MaxEntries != nil {
+		marshaled, err := c.MaxEntries.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.ContinuationReference != nil {
+		marshaled, err := c.ContinuationReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (c *CommunicationAccessRequestSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if c.FromBankID != nil {
+		buf.WriteString(c.FromBankID.String())
+	}
+	buf.WriteByte('+')
+	if c.ToBankID != nil {
+		buf.WriteString(c.ToBankID.String())
+	}
+	buf.WriteByte('+')
+	if c.MaxEntries != nil {
+		buf.WriteString(c.MaxEntries.String())
+	}
+	buf.WriteByte('+')
+	if c.ContinuationReference != nil {
+		buf.WriteString(c.ContinuationReference.String())
+	}
+}
+
+func (c *CommunicationAccessResponseSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if c.BankID != nil {
+		marshaled, err := c.BankID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.StandardLanguage != nil {
+		marshaled, err := c.StandardLanguage.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.CommunicationParams != nil {
+		marshaled, err := c.CommunicationParams.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (c *CommunicationAccessResponseSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if c.BankID != nil {
+		buf.WriteString(c.BankID.String())
+	}
+	buf.WriteByte('+')
+	if c.StandardLanguage != nil {
+		buf.WriteString(c.StandardLanguage.String())
+	}
+	buf.WriteByte('+')
+	if c.CommunicationParams != nil {
+		buf.WriteString(c.CommunicationParams.String())
+	}
+}
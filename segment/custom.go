@@ -0,0 +1,151 @@
+package segment
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/mitch000001/go-hbci/element"
+)
+
+// SegmentDefinition defines a segment type outside of this package, e.g. a
+// bank specific job. Types implementing it embed the Segment returned by
+// NewSegment, NewReferencingSegment or NewSegmentWithHeader:
+//
+//	type CustomRequest struct {
+//		segment.ClientSegment
+//		Account *element.AccountConnectionDataElement
+//	}
+//
+//	func NewCustomRequest(account domain.AccountConnection) *CustomRequest {
+//		c := &CustomRequest{Account: element.NewAccountConnection(account)}
+//		c.ClientSegment = segment.NewSegment(1, c)
+//		return c
+//	}
+//
+// Exported fields of type element.DataElement are validated when marshaling
+// and may be tagged with `hbci:"required"`.
+type SegmentDefinition interface {
+	// ID returns the segment ID, e.g. DKXYZ
+	ID() string
+	// Version returns the segment version
+	Version() int
+	// ReferencedID returns the ID of the segment a bank segment answers, or an
+	// empty string
+	ReferencedID() string
+	// Sender returns who sends the segment, i.e. "K" for the client, "I" for
+	// the institute or "K/I" for both
+	Sender() string
+	// DataElements returns the data elements following the segment header in
+	// order
+	DataElements() []element.DataElement
+}
+
+// definedSegment adapts a SegmentDefinition to a basicSegment
+type definedSegment struct {
+	SegmentDefinition
+}
+
+func (d definedSegment) referencedId() string { return d.ReferencedID() }
+
+func (d definedSegment) sender() string { return d.Sender() }
+
+func (d definedSegment) elements() []element.DataElement { return d.DataElements() }
+
+// marshalHBCIElements implements elementMarshaler, as the data elements of
+// custom segments are only known at runtime
+func (d definedSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	return marshalDataElements(buf, d.DataElements())
+}
+
+func (d definedSegment) writeElementStrings(buf *bytes.Buffer) {
+	writeDataElementStrings(buf, d.DataElements())
+}
+
+// CustomSegment is a segment defined outside of this package. It can be
+// marshaled and embedded as ClientSegment, BankSegment or CommonSegment, as
+// UnmarshalHBCI is implemented by the defining type.
+type CustomSegment interface {
+	Segment
+	Marshaler
+	SetReference(ref int)
+}
+
+// NewSegment returns the segment for definition at position
+func NewSegment(position int, definition SegmentDefinition) CustomSegment {
+	return NewBasicSegment(position, definedSegment{definition})
+}
+
+// NewReferencingSegment returns the segment for definition at position which
+// references the segment at ref
+func NewReferencingSegment(position int, ref int, definition SegmentDefinition) CustomSegment {
+	return NewReferencingBasicSegment(position, ref, definedSegment{definition})
+}
+
+// NewSegmentWithHeader returns the segment for definition with the given
+// header. It is meant to be used when unmarshaling a segment.
+func NewSegmentWithHeader(header *element.SegmentHeader, definition SegmentDefinition) CustomSegment {
+	return NewBasicSegmentWithHeader(header, definedSegment{definition})
+}
+
+// RequestIndex maps request segments to functions returning new requests. It
+// is safe for concurrent use.
+type RequestIndex struct {
+	mu       sync.RWMutex
+	requests map[string]map[int]func() ClientSegment
+}
+
+// Register adds the request segment identified by request to the index.
+// requestFn must return a new request on every call. Register returns an error
+// if the request is already indexed.
+func (r *RequestIndex) Register(request VersionedSegment, requestFn func() ClientSegment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.requests[request.ID][request.Version]; ok {
+		return fmt.Errorf("Request already in index: %s", request)
+	}
+	if r.requests[request.ID] == nil {
+		r.requests[request.ID] = make(map[int]func() ClientSegment)
+	}
+	r.requests[request.ID][request.Version] = requestFn
+	return nil
+}
+
+// MustRegister is like Register but panics if the request is already indexed.
+// It is meant to be called within init functions.
+func (r *RequestIndex) MustRegister(request VersionedSegment, requestFn func() ClientSegment) {
+	err := r.Register(request, requestFn)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// RequestBuilder returns the highest version of the request with segmentID
+// matching versions
+func (r *RequestIndex) RequestBuilder(segmentID string, versions []int) (func() ClientSegment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sorted := append([]int(nil), versions...)
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+	for _, version := range sorted {
+		builder, ok := r.requests[segmentID][version]
+		if ok {
+			return builder, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: unsupported versions %v", segmentID, versions)
+}
+
+// KnownRequests contains the requests built by Builder.Request
+var KnownRequests = RequestIndex{requests: make(map[string]map[int]func() ClientSegment)}
+
+// parameterSegmentID returns the ID of the bank parameter segment announcing
+// the versions of the request with segmentID, e.g. HISALS for HKSAL or DIXYZS
+// for DKXYZ.
+func parameterSegmentID(segmentID string) string {
+	if len(segmentID) < 2 {
+		return segmentID
+	}
+	return segmentID[:1] + "I" + segmentID[2:] + "S"
+}
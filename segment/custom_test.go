@@ -0,0 +1,146 @@
+package segment
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/element"
+)
+
+type customRequest struct {
+	ClientSegment
+	Name  *element.AlphaNumericDataElement `hbci:"required"`
+	Limit *element.NumberDataElement
+}
+
+func newCustomRequest(name string) *customRequest {
+	c := &customRequest{Name: element.NewAlphaNumeric(name, 10)}
+	c.ClientSegment = NewSegment(1, c)
+	return c
+}
+
+func (c *customRequest) ID() string           { return "DKTST" }
+func (c *customRequest) Version() int         { return 2 }
+func (c *customRequest) ReferencedID() string { return "" }
+func (c *customRequest) Sender() string       { return senderUser }
+func (c *customRequest) DataElements() []element.DataElement {
+	return []element.DataElement{c.Name, c.Limit}
+}
+
+type customResponse struct {
+	Segment
+	Name *element.AlphaNumericDataElement
+}
+
+func (c *customResponse) ID() string           { return "DITST" }
+func (c *customResponse) Version() int         { return 2 }
+func (c *customResponse) ReferencedID() string { return "DKTST" }
+func (c *customResponse) Sender() string       { return senderBank }
+func (c *customResponse) DataElements() []element.DataElement {
+	return []element.DataElement{c.Name}
+}
+
+func (c *customResponse) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	header := &element.SegmentHeader{}
+	if err := header.UnmarshalHBCI(elements[0]); err != nil {
+		return err
+	}
+	c.Segment = NewSegmentWithHeader(header, c)
+	if len(elements) > 1 {
+		c.Name = &element.AlphaNumericDataElement{}
+		return c.Name.UnmarshalHBCI(elements[1])
+	}
+	return nil
+}
+
+func TestCustomSegmentMarshalHBCI(t *testing.T) {
+	request := newCustomRequest("Test?Name")
+	request.SetPosition(func() int { return 3 })
+
+	marshaled, err := request.MarshalHBCI()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "DKTST:3:2+Test??Name'"
+	if string(marshaled) != expected {
+		t.Logf("Expected segment to marshal to %q, got %q\n", expected, marshaled)
+		t.Fail()
+	}
+
+	request = newCustomRequest(strings.Repeat("a", 11))
+
+	_, err = request.MarshalHBCI()
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Segment != "DKTST" || validationErr.Path != "Name" {
+		t.Logf("Expected too long name to be reported, got %v\n", err)
+		t.Fail()
+	}
+
+	request.Name = nil
+
+	_, err = request.MarshalHBCI()
+
+	if !errors.As(err, &validationErr) || validationErr.Rule != element.RuleRequired {
+		t.Logf("Expected missing name to be reported, got %v\n", err)
+		t.Fail()
+	}
+}
+
+func TestSegmentIndexRegister(t *testing.T) {
+	index := SegmentIndex{segmentMap: make(map[VersionedSegment]func() Segment)}
+	id := VersionedSegment{ID: "DITST", Version: 2}
+
+	err := index.Register(id, func() Segment { return &customResponse{} })
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := index.Register(id, func() Segment { return &customResponse{} }); err == nil {
+		t.Logf("Expected error registering a segment twice, got nil\n")
+		t.Fail()
+	}
+	unmarshaler, err := index.UnmarshalerForSegment(id)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := unmarshaler.UnmarshalHBCI([]byte("DITST:4:2:3+Antwort'")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	response := unmarshaler.(*customResponse)
+	if response.Name.Val() != "Antwort" || response.Header().Ref.Val() != 3 {
+		t.Logf("Expected response %q referencing segment 3, got %s\n", "Antwort", response)
+		t.Fail()
+	}
+}
+
+func TestBuilderRequest(t *testing.T) {
+	KnownRequests.MustRegister(VersionedSegment{ID: "DKTST", Version: 1}, func() ClientSegment { return newCustomRequest("v1") })
+	KnownRequests.MustRegister(VersionedSegment{ID: "DKTST", Version: 2}, func() ClientSegment { return newCustomRequest("v2") })
+	KnownRequests.MustRegister(VersionedSegment{ID: "DKTST", Version: 3}, func() ClientSegment { return newCustomRequest("v3") })
+
+	builder := NewBuilder([]VersionedSegment{{"DITSTS", 1}, {"DITSTS", 2}, {"DITSTS", 4}})
+
+	request, err := builder.Request("DKTST")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if name := request.(*customRequest).Name.Val(); name != "v2" {
+		t.Logf("Expected highest supported version 2 to be built, got %q\n", name)
+		t.Fail()
+	}
+
+	_, err = builder.Request("DKXXX")
+
+	if err == nil {
+		t.Logf("Expected error for request not supported by the institute, got nil\n")
+		t.Fail()
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/mitch000001/go-hbci/element"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments DialogEndSegment,ProcessingPreparationSegmentV2,ProcessingPreparationSegmentV3
+
 const productName = "5A624F86A785F4024DD914404"
 const productVersion = hbci.Version
 
@@ -19,7 +21,7 @@ func NewDialogEndSegment(dialogId string) *DialogEndSegment {
 
 type DialogEndSegment struct {
 	ClientSegment
-	DialogID *element.IdentificationDataElement
+	DialogID *element.IdentificationDataElement `hbci:"required"`
 }
 
 func (d *DialogEndSegment) Version() int         { return 1 }
@@ -61,17 +63,17 @@ func NewProcessingPreparationSegmentV3(bdpVersion int, udpVersion int, language
 
 type ProcessingPreparationSegmentV2 struct {
 	ClientSegment
-	BPDVersion *element.NumberDataElement
-	UPDVersion *element.NumberDataElement
+	BPDVersion *element.NumberDataElement `hbci:"required"`
+	UPDVersion *element.NumberDataElement `hbci:"required"`
 	// 0 for undefined
 	// Sprachkennzeichen | Bedeutung   | Sprachencode ISO 639 | ISO 8859 Subset | ISO 8859- Codeset
 	// --------------------------------------------------------------------------------------------
 	// 1				 | Deutsch	   | de (German) ￼	      | Deutsch ￼ ￼		| 1 (Latin 1)
 	// 2				 | Englisch	   | en (English)		  | Englisch		| 1 (Latin 1)
 	// 3 				 | Französisch | fr (French)  		   | Französisch ￼	  | 1 (Latin 1)
-	DialogLanguage *element.NumberDataElement
-	ProductName    *element.AlphaNumericDataElement
-	ProductVersion *element.AlphaNumericDataElement
+	DialogLanguage *element.NumberDataElement       `hbci:"required"`
+	ProductName    *element.AlphaNumericDataElement `hbci:"required"`
+	ProductVersion *element.AlphaNumericDataElement `hbci:"required"`
 }
 
 func (p *ProcessingPreparationSegmentV2) Version() int         { return 2 }
@@ -91,17 +93,17 @@ func (p *ProcessingPreparationSegmentV2) elements() []element.DataElement {
 
 type ProcessingPreparationSegmentV3 struct {
 	ClientSegment
-	BPDVersion *element.NumberDataElement
-	UPDVersion *element.NumberDataElement
+	BPDVersion *element.NumberDataElement `hbci:"required"`
+	UPDVersion *element.NumberDataElement `hbci:"required"`
 	// 0 for Standard = Institute language
 	// Sprachkennzeichen | Bedeutung   | Sprachencode ISO 639 | ISO 8859 Subset | ISO 8859- Codeset
 	// --------------------------------------------------------------------------------------------
 	// 1				 | Deutsch	   | de (German) ￼	      | Deutsch ￼ ￼		| 1 (Latin 1)
 	// 2				 | Englisch	   | en (English)		  | Englisch		| 1 (Latin 1)
 	// 3 				 | Französisch | fr (French)  		   | Französisch ￼	  | 1 (Latin 1)
-	DialogLanguage *element.NumberDataElement
-	ProductName    *element.AlphaNumericDataElement
-	ProductVersion *element.AlphaNumericDataElement
+	DialogLanguage *element.NumberDataElement       `hbci:"required"`
+	ProductName    *element.AlphaNumericDataElement `hbci:"required"`
+	ProductVersion *element.AlphaNumericDataElement `hbci:"required"`
 }
 
 func (p *ProcessingPreparationSegmentV3) Version() int         { return 3 }
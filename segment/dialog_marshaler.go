@@ -0,0 +1,218 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (d *DialogEndSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if d.DialogID != nil {
+		marshaled, err := d.DialogID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (d *DialogEndSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if d.DialogID != nil {
+		buf.WriteString(d.DialogID.String())
+	}
+}
+
+func (p *ProcessingPreparationSegmentV2) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if p.BPDVersion != nil {
+		marshaled, err := p.BPDVersion.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.UPDVersion != nil {
+		marshaled, err := p.UPDVersion.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.DialogLanguage != nil {
+		marshaled, err := p.DialogLanguage.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.ProductName != nil {
+		marshaled, err := p.ProductName.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.ProductVersion != nil {
+		marshaled, err := p.ProductVersion.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (p *ProcessingPreparationSegmentV2) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if p.BPDVersion != nil {
+		buf.WriteString(p.BPDVersion.String())
+	}
+	buf.WriteByte('+')
+	if p.UPDVersion != nil {
+		buf.WriteString(p.UPDVersion.String())
+	}
+	buf.WriteByte('+')
+	if p.DialogLanguage != nil {
+		buf.WriteString(p.DialogLanguage.String())
+	}
+	buf.WriteByte('+')
+	if p.ProductName != nil {
+		buf.WriteString(p.ProductName.String())
+	}
+	buf.WriteByte('+')
+	if p.ProductVersion != nil {
+		buf.WriteString(p.ProductVersion.String())
+	}
+}
+
+func (p *ProcessingPreparationSegmentV3) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if p.BPDVersion != nil {
+		marshaled, err := p.BPDVersion.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.UPDVersion != nil {
+		marshaled, err := p.UPDVersion.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.DialogLanguage != nil {
+		marshaled, err := p.DialogLanguage.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.ProductName != nil {
+		marshaled, err := p.ProductName.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.ProductVersion != nil {
+		marshaled, err := p.ProductVersion.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (p *ProcessingPreparationSegmentV3) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if p.BPDVersion != nil {
+		buf.WriteString(p.BPDVersion.String())
+	}
+	buf.WriteByte('+')
+	if p.UPDVersion != nil {
+		buf.WriteString(p.UPDVersion.String())
+	}
+	buf.WriteByte('+')
+	if p.DialogLanguage != nil {
+		buf.WriteString(p.DialogLanguage.String())
+	}
+	buf.WriteByte('+')
+	if p.ProductName != nil {
+		buf.WriteString(p.ProductName.String())
+	}
+	buf.WriteByte('+')
+	if p.ProductVersion != nil {
+		buf.WriteString(p.ProductVersion.String())
+	}
+}
@@ -2,6 +2,8 @@ package segment
 
 import "github.com/mitch000001/go-hbci/element"
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments EncryptedDataSegment
+
 func NewEncryptedDataSegment(encryptedData []byte) *EncryptedDataSegment {
 	e := &EncryptedDataSegment{
 		Data: element.NewBinary(encryptedData, -1),
@@ -14,7 +16,7 @@ func NewEncryptedDataSegment(encryptedData []byte) *EncryptedDataSegment {
 
 type EncryptedDataSegment struct {
 	ClientSegment
-	Data *element.BinaryDataElement
+	Data *element.BinaryDataElement `hbci:"required"`
 }
 
 func (e *EncryptedDataSegment) Version() int         { return 1 }
@@ -0,0 +1,32 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (e *EncryptedDataSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if e.Data != nil {
+		marshaled, err := e.Data.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (e *EncryptedDataSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if e.Data != nil {
+		buf.WriteString(e.Data.String())
+	}
+}
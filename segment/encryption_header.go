@@ -7,14 +7,22 @@ import (
 	"github.com/mitch000001/go-hbci/element"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments EncryptionHeaderV2,EncryptionHeaderSegmentV3
+
 const EncryptionHeaderSegmentID = "HNVSK"
 
 type EncryptionHeader interface {
 	ClientSegment
 	SetClientSystemID(clientSystemID string)
 	SetSecurityProfile(securityFn string)
+	// SetSecurityMethod sets the security profile, e.g. "RDH" and 10. It is
+	// a no-op for versions without a security profile.
+	SetSecurityMethod(method string, version int)
+	SetSecurityFunction(securityFn string)
 	SetEncryptionKeyName(keyName domain.KeyName)
 	SetEncryptionAlgorithm(algorithm *element.EncryptionAlgorithmDataElement)
+	SetCompression(compressionFunction string)
+	Compression() string
 }
 
 func NewPinTanEncryptionHeaderSegment(clientSystemId string, keyName domain.KeyName) *EncryptionHeaderSegment {
@@ -68,16 +76,16 @@ type EncryptionHeaderV2 struct {
 	ClientSegment
 	// "4" for ENC, Encryption (encryption and eventually compression)
 	// "998" for Cleartext
-	SecurityFunction *element.AlphaNumericDataElement
+	SecurityFunction *element.AlphaNumericDataElement `hbci:"required"`
 	// "1" for ISS,  Herausgeber der chiffrierten Nachricht (Erfasser)
 	// "4" for WIT, der Unterzeichnete ist Zeuge, aber für den Inhalt der
 	// Nachricht nicht verantwortlich (Übermittler, welcher nicht Erfasser ist)
-	SecuritySupplierRole *element.AlphaNumericDataElement
-	SecurityID           *element.SecurityIdentificationDataElement
-	SecurityDate         *element.SecurityDateDataElement
-	EncryptionAlgorithm  *element.EncryptionAlgorithmDataElement
-	KeyName              *element.KeyNameDataElement
-	CompressionFunction  *element.AlphaNumericDataElement
+	SecuritySupplierRole *element.AlphaNumericDataElement           `hbci:"required"`
+	SecurityID           *element.SecurityIdentificationDataElement `hbci:"required"`
+	SecurityDate         *element.SecurityDateDataElement           `hbci:"required"`
+	EncryptionAlgorithm  *element.EncryptionAlgorithmDataElement    `hbci:"required"`
+	KeyName              *element.KeyNameDataElement                `hbci:"required"`
+	CompressionFunction  *element.AlphaNumericDataElement           `hbci:"required"`
 	Certificate          *element.CertificateDataElement
 }
 
@@ -115,6 +123,26 @@ func (e *EncryptionHeaderV2) SetSecurityProfile(securityFn string) {
 	// NO OP
 }
 
+func (e *EncryptionHeaderV2) SetSecurityMethod(method string, version int) {
+	// NO OP
+}
+
+func (e *EncryptionHeaderV2) SetSecurityFunction(securityFn string) {
+	e.SecurityFunction = element.NewAlphaNumeric(securityFn, 3)
+}
+
+func (e *EncryptionHeaderV2) SetCompression(compressionFunction string) {
+	e.CompressionFunction = element.NewAlphaNumeric(compressionFunction, 3)
+}
+
+// Compression returns the code of the compression function, "0" if none is set
+func (e *EncryptionHeaderV2) Compression() string {
+	if e.CompressionFunction == nil || e.CompressionFunction.Val() == "" {
+		return "0"
+	}
+	return e.CompressionFunction.Val()
+}
+
 func NewPinTanEncryptionHeaderSegmentV3(clientSystemId string, keyName domain.KeyName) *EncryptionHeaderSegment {
 	e := &EncryptionHeaderSegmentV3{
 		SecurityProfile:      element.NewPinTanSecurityProfile(1),
@@ -134,20 +162,41 @@ func NewPinTanEncryptionHeaderSegmentV3(clientSystemId string, keyName domain.Ke
 	return segment
 }
 
+// NewEncryptionHeaderSegmentV3 creates an encryption header for RDH-10,
+// transporting the encrypted 2-key triple DES message key
+func NewEncryptionHeaderSegmentV3(clientSystemId string, keyName domain.KeyName, key []byte) *EncryptionHeaderSegment {
+	e := &EncryptionHeaderSegmentV3{
+		SecurityProfile:      element.NewSecurityProfile("RDH", 10),
+		SecurityFunction:     element.NewCode("4", 3, []string{"4", "998"}),
+		SecuritySupplierRole: element.NewCode("1", 3, []string{"1", "4"}),
+		SecurityID:           element.NewRDHSecurityIdentification(element.SecurityHolderMessageSender, clientSystemId),
+		SecurityDate:         element.NewSecurityDate(element.SecurityTimestamp, time.Now()),
+		EncryptionAlgorithm:  element.NewHybridEncryptionAlgorithm("13", key),
+		KeyName:              element.NewKeyName(keyName),
+		CompressionFunction:  element.NewCode("0", 3, []string{"0", "1", "2", "3", "4", "5", "6", "7", "999"}),
+	}
+	e.ClientSegment = NewBasicSegment(998, e)
+
+	segment := &EncryptionHeaderSegment{
+		encryptionHeaderSegment: e,
+	}
+	return segment
+}
+
 type EncryptionHeaderSegmentV3 struct {
 	ClientSegment
-	SecurityProfile *element.SecurityProfileDataElement
+	SecurityProfile *element.SecurityProfileDataElement `hbci:"required"`
 	// "4" for ENC, Encryption (encryption and eventually compression)
 	// "998" for Cleartext
-	SecurityFunction *element.CodeDataElement
+	SecurityFunction *element.CodeDataElement `hbci:"required"`
 	// "1" for ISS,  Herausgeber der chiffrierten Nachricht (Erfasser)
 	// "4" for WIT, der Unterzeichnete ist Zeuge, aber für den Inhalt der
 	// Nachricht nicht verantwortlich (Übermittler, welcher nicht Erfasser ist)
-	SecuritySupplierRole *element.CodeDataElement
-	SecurityID           *element.SecurityIdentificationDataElement
-	SecurityDate         *element.SecurityDateDataElement
-	EncryptionAlgorithm  *element.EncryptionAlgorithmDataElement
-	KeyName              *element.KeyNameDataElement
+	SecuritySupplierRole *element.CodeDataElement                   `hbci:"required"`
+	SecurityID           *element.SecurityIdentificationDataElement `hbci:"required"`
+	SecurityDate         *element.SecurityDateDataElement           `hbci:"required"`
+	EncryptionAlgorithm  *element.EncryptionAlgorithmDataElement    `hbci:"required"`
+	KeyName              *element.KeyNameDataElement                `hbci:"required"`
 	// 0: no compression (NULL)
 	// 1: Lempel, Ziv, Welch (LZW)
 	// 2: Optimized LZW (COM)
@@ -157,7 +206,7 @@ type EncryptionHeaderSegmentV3 struct {
 	// 6: deflate (GZIP) (http://www.gzip.org/zlib)
 	// 7: bzip2 (http://sourceware.cygnus.com/bzip2/)
 	// 999: Gegenseitig vereinbart (ZZZ)
-	CompressionFunction *element.CodeDataElement
+	CompressionFunction *element.CodeDataElement `hbci:"required"`
 	Certificate         *element.CertificateDataElement
 }
 
@@ -199,3 +248,23 @@ func (e *EncryptionHeaderSegmentV3) SetSecurityProfile(securityFn string) {
 		e.SecurityProfile = element.NewPinTanSecurityProfile(2)
 	}
 }
+
+func (e *EncryptionHeaderSegmentV3) SetSecurityMethod(method string, version int) {
+	e.SecurityProfile = element.NewSecurityProfile(method, version)
+}
+
+func (e *EncryptionHeaderSegmentV3) SetSecurityFunction(securityFn string) {
+	e.SecurityFunction = element.NewCode(securityFn, 3, []string{"4", "998"})
+}
+
+func (e *EncryptionHeaderSegmentV3) SetCompression(compressionFunction string) {
+	e.CompressionFunction = element.NewCode(compressionFunction, 3, []string{"0", "1", "2", "3", "4", "5", "6", "7", "999"})
+}
+
+// Compression returns the code of the compression function, "0" if none is set
+func (e *EncryptionHeaderSegmentV3) Compression() string {
+	if e.CompressionFunction == nil || e.CompressionFunction.Val() == "" {
+		return "0"
+	}
+	return e.CompressionFunction.Val()
+}
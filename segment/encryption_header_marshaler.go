@@ -0,0 +1,312 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (e *EncryptionHeaderV2) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if e.SecurityFunction != nil {
+		marshaled, err := e.SecurityFunction.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if e.SecuritySupplierRole != nil {
+		marshaled, err := e.SecuritySupplierRole.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if e.SecurityID != nil {
+		marshaled, err := e.SecurityID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if e.SecurityDate != nil {
+		marshaled, err := e.SecurityDate.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if e.EncryptionAlgorithm != nil {
+		marshaled, err := e.EncryptionAlgorithm.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if e.KeyName != nil {
+		marshaled, err := e.KeyName.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if e.CompressionFunction != nil {
+		marshaled, err := e.CompressionFunction.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if e.Certificate != nil {
+		marshaled, err := e.Certificate.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (e *EncryptionHeaderV2) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if e.SecurityFunction != nil {
+		buf.WriteString(e.SecurityFunction.String())
+	}
+	buf.WriteByte('+')
+	if e.SecuritySupplierRole != nil {
+		buf.WriteString(e.SecuritySupplierRole.String())
+	}
+	buf.WriteByte('+')
+	if e.SecurityID != nil {
+		buf.WriteString(e.SecurityID.String())
+	}
+	buf.WriteByte('+')
+	if e.SecurityDate != nil {
+		buf.WriteString(e.SecurityDate.String())
+	}
+	buf.WriteByte('+')
+	if e.EncryptionAlgorithm != nil {
+		buf.WriteString(e.EncryptionAlgorithm.String())
+	}
+	buf.WriteByte('+')
+	if e.KeyName != nil {
+		buf.WriteString(e.KeyName.String())
+	}
+	buf.WriteByte('+')
+	if e.CompressionFunction != nil {
+		buf.WriteString(e.CompressionFunction.String())
+	}
+	buf.WriteByte('+')
+	if e.Certificate != nil {
+		buf.WriteString(e.Certificate.String())
+	}
+}
+
+func (e *EncryptionHeaderSegmentV3) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if e.SecurityProfile != nil {
+		marshaled, err := e.SecurityProfile.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if e.SecurityFunction != nil {
+		marshaled, err := e.SecurityFunction.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if e.SecuritySupplierRole != nil {
+		marshaled, err := e.SecuritySupplierRole.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if e.SecurityID != nil {
+		marshaled, err := e.SecurityID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if e.SecurityDate != nil {
+		marshaled, err := e.SecurityDate.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if e.EncryptionAlgorithm != nil {
+		marshaled, err := e.EncryptionAlgorithm.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if e.KeyName != nil {
+		marshaled, err := e.KeyName.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if e.CompressionFunction != nil {
+		marshaled, err := e.CompressionFunction.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if e.Certificate != nil {
+		marshaled, err := e.Certificate.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (e *EncryptionHeaderSegmentV3) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if e.SecurityProfile != nil {
+		buf.WriteString(e.SecurityProfile.String())
+	}
+	buf.WriteByte('+')
+	if e.SecurityFunction != nil {
+		buf.WriteString(e.SecurityFunction.String())
+	}
+	buf.WriteByte('+')
+	if e.SecuritySupplierRole != nil {
+		buf.WriteString(e.SecuritySupplierRole.String())
+	}
+	buf.WriteByte('+')
+	if e.SecurityID != nil {
+		buf.WriteString(e.SecurityID.String())
+	}
+	buf.WriteByte('+')
+	if e.SecurityDate != nil {
+		buf.WriteString(e.SecurityDate.String())
+	}
+	buf.WriteByte('+')
+	if e.EncryptionAlgorithm != nil {
+		buf.WriteString(e.EncryptionAlgorithm.String())
+	}
+	buf.WriteByte('+')
+	if e.KeyName != nil {
+		buf.WriteString(e.KeyName.String())
+	}
+	buf.WriteByte('+')
+	if e.CompressionFunction != nil {
+		buf.WriteString(e.CompressionFunction.String())
+	}
+	buf.WriteByte('+')
+	if e.Certificate != nil {
+		buf.WriteString(e.Certificate.String())
+	}
+}
@@ -1,15 +1,18 @@
 package segment
 
+// FINTS300 defines the segments of FinTS 3.0
 var FINTS300 = HBCIVersion{
 	version:                       300,
 	PinTanEncryptionHeader:        NewPinTanEncryptionHeaderSegmentV3,
-	SynchronisationRequest:        NewSynchronisationSegmentV3,
+	RDHEncryptionHeader:           NewEncryptionHeaderSegmentV3,
+	synchronisationRequest:        NewSynchronisationSegmentV3,
 	SignatureHeader:               NewSignatureHeaderSegmentV4,
 	PinTanSignatureHeader:         NewPinTanSignatureHeaderSegmentV4,
+	RDHSignatureHeader:            NewRDHSignatureHeaderSegmentV4,
 	SignatureEnd:                  NewSignatureEndSegmentV2,
-	AccountBalanceRequest:         NewAccountBalanceRequestV6,
-	AccountTransactionRequest:     NewAccountTransactionRequestSegmentV6,
-	SepaAccountTransactionRequest: NewAccountTransactionRequestSegmentV7,
-	StatusProtocolRequest:         NewStatusProtocolRequestV4,
-	TanProcess4Request:            NewTanProcess4RequestSegmentV6,
+	accountBalanceRequest:         NewAccountBalanceRequestV6,
+	accountTransactionRequest:     NewAccountTransactionRequestSegmentV6,
+	sepaAccountTransactionRequest: NewAccountTransactionRequestSegmentV7,
+	statusProtocolRequest:         NewStatusProtocolRequestV4,
+	tanProcess4Request:            NewTanProcess4RequestSegmentV6,
 }
@@ -0,0 +1,253 @@
+package segment
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/token"
+)
+
+// GenericSegment represents a segment without a dedicated type, e.g. a bank
+// parameter segment unknown to this library. It keeps the header and the data
+// elements of the segment as a tree of GenericElements which can be accessed
+// by their position.
+type GenericSegment struct {
+	header   *element.SegmentHeader
+	elements []*GenericElement
+}
+
+// UnmarshalHBCI unmarshals value into g
+func (g *GenericSegment) UnmarshalHBCI(value []byte) error {
+	elements, err := parseGenericElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) == 0 {
+		return fmt.Errorf("Malformed segment: missing segment header")
+	}
+	header := &element.SegmentHeader{}
+	if err := header.UnmarshalHBCI([]byte(elements[0].String())); err != nil {
+		return fmt.Errorf("Error while unmarshaling segment header: %v", err)
+	}
+	*g = GenericSegment{header: header, elements: elements}
+	return nil
+}
+
+// MarshalHBCI marshals g into HBCI wire format
+func (g *GenericSegment) MarshalHBCI() ([]byte, error) {
+	return []byte(g.String()), nil
+}
+
+// Header returns the segment header of g
+func (g *GenericSegment) Header() *element.SegmentHeader {
+	return g.header
+}
+
+// ID returns the segment ID of g
+func (g *GenericSegment) ID() string {
+	return g.header.ID.Val()
+}
+
+// Version returns the segment version of g
+func (g *GenericSegment) Version() int {
+	return g.header.Version.Val()
+}
+
+// SetPosition sets the position of g within a message
+func (g *GenericSegment) SetPosition(positionFn func() int) {
+	g.header.SetPosition(positionFn())
+}
+
+// Elements returns all data elements of g, starting with the segment header.
+func (g *GenericSegment) Elements() []*GenericElement {
+	return g.elements
+}
+
+// Element returns the data element found at path. The first index is the
+// position of the data element within the segment, starting with 1 for the
+// segment header. The second index is the position of the group data element
+// within a data element group, also starting with 1. Element returns nil if
+// the segment has no data element at path.
+//
+// E.g. Element(3, 2) returns the second group data element of the third data
+// element.
+func (g *GenericSegment) Element(path ...int) *GenericElement {
+	if len(path) == 0 || path[0] < 1 || path[0] > len(g.elements) {
+		return nil
+	}
+	return g.elements[path[0]-1].Element(path[1:]...)
+}
+
+func (g *GenericSegment) String() string {
+	var buf bytes.Buffer
+	for i, elem := range g.elements {
+		if i == 0 {
+			header, err := g.header.MarshalHBCI()
+			if err != nil {
+				header = []byte(elem.String())
+			}
+			buf.Write(header)
+			continue
+		}
+		buf.WriteByte('+')
+		buf.WriteString(elem.String())
+	}
+	buf.WriteByte('\'')
+	return buf.String()
+}
+
+// GenericElement represents a data element or a data element group within a
+// GenericSegment. The value is kept as received, i.e. escaped and ISO-8859-1
+// encoded. The accessor methods convert it as the corresponding DataElement
+// types would.
+type GenericElement struct {
+	typ      token.Type
+	value    []byte
+	elements []*GenericElement
+}
+
+// IsGroup returns true if g is a data element group
+func (g *GenericElement) IsGroup() bool {
+	return g.elements != nil
+}
+
+// IsEmpty returns true if g has no value
+func (g *GenericElement) IsEmpty() bool {
+	return !g.IsGroup() && len(g.value) == 0
+}
+
+// Type returns the token type the lexer assigned to the value of g. For
+// groups it returns token.ILLEGAL.
+func (g *GenericElement) Type() token.Type {
+	return g.typ
+}
+
+// Elements returns the group data elements of g, or nil if g is no group.
+func (g *GenericElement) Elements() []*GenericElement {
+	return g.elements
+}
+
+// Element returns the group data element found at path, starting with 1. An
+// empty path returns g itself. As optional group data elements can be omitted
+// a data element without group data elements is treated as group containing
+// only itself. Element returns nil if there is no element at path.
+func (g *GenericElement) Element(path ...int) *GenericElement {
+	if len(path) == 0 {
+		return g
+	}
+	if len(path) > 1 || path[0] < 1 {
+		return nil
+	}
+	if !g.IsGroup() {
+		if path[0] == 1 {
+			return g
+		}
+		return nil
+	}
+	if path[0] > len(g.elements) {
+		return nil
+	}
+	return g.elements[path[0]-1]
+}
+
+// Text returns the unescaped value of g
+func (g *GenericElement) Text() string {
+	text := &element.TextDataElement{}
+	text.UnmarshalHBCI(g.value)
+	return text.Val()
+}
+
+// Int returns the value of g as int
+func (g *GenericElement) Int() (int, error) {
+	number := &element.NumberDataElement{}
+	if err := number.UnmarshalHBCI(g.value); err != nil {
+		return 0, err
+	}
+	return number.Val(), nil
+}
+
+// Float returns the value of g as float64
+func (g *GenericElement) Float() (float64, error) {
+	float := &element.FloatDataElement{}
+	if err := float.UnmarshalHBCI(g.value); err != nil {
+		return 0, err
+	}
+	return float.Val(), nil
+}
+
+// Date returns the value of g as date
+func (g *GenericElement) Date() (time.Time, error) {
+	date := &element.DateDataElement{}
+	if err := date.UnmarshalHBCI(g.value); err != nil {
+		return time.Time{}, err
+	}
+	return date.Val(), nil
+}
+
+// Bytes returns the binary data of g
+func (g *GenericElement) Bytes() ([]byte, error) {
+	binary := &element.BinaryDataElement{}
+	if err := binary.UnmarshalHBCI(g.value); err != nil {
+		return nil, err
+	}
+	return binary.Val(), nil
+}
+
+// String returns g in HBCI wire format
+func (g *GenericElement) String() string {
+	if !g.IsGroup() {
+		return string(g.value)
+	}
+	var buf bytes.Buffer
+	for i, elem := range g.elements {
+		if i > 0 {
+			buf.WriteByte(':')
+		}
+		buf.Write(elem.value)
+	}
+	return buf.String()
+}
+
+// parseGenericElements parses the data elements of segment into a tree of
+// GenericElements
+func parseGenericElements(segment []byte) ([]*GenericElement, error) {
+	var (
+		elements []*GenericElement
+		group    []*GenericElement
+		current  = &GenericElement{}
+		pending  bool
+	)
+	finish := func() {
+		if group != nil {
+			current = &GenericElement{elements: append(group, current)}
+		}
+		elements = append(elements, current)
+		current, group, pending = &GenericElement{}, nil, false
+	}
+	lexer := token.NewLexer("GenericSegment", segment)
+	for lexer.HasNext() {
+		t := lexer.Next()
+		switch t.Type() {
+		case token.ERROR:
+			return nil, fmt.Errorf("SyntaxError at position %d: %q\n(%q)", t.Pos(), t.Value(), segment)
+		case token.EOF:
+			if pending {
+				finish()
+			}
+		case token.GROUP_DATA_ELEMENT_SEPARATOR:
+			group = append(group, current)
+			current, pending = &GenericElement{}, true
+		case token.DATA_ELEMENT_SEPARATOR, token.SEGMENT_END_MARKER:
+			finish()
+		default:
+			if len(current.value) == 0 {
+				current.typ = t.Type()
+			}
+			current.value = append(current.value, t.Value()...)
+			pending = true
+		}
+	}
+	return elements, nil
+}
@@ -0,0 +1,81 @@
+package segment
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGenericSegmentUnmarshalHBCI(t *testing.T) {
+	test := "HIXYZ:5:2:3+1+Konto?:Nr?+1:280:10000000+@3@a:b+1500,5:20240301::+'"
+
+	seg := &GenericSegment{}
+	err := seg.UnmarshalHBCI([]byte(test))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v", err, err)
+	}
+	if seg.ID() != "HIXYZ" || seg.Version() != 2 || seg.Header().Position.Val() != 5 || seg.Header().Ref.Val() != 3 {
+		t.Logf("Expected header HIXYZ:5:2:3, got %s\n", seg.Header())
+		t.Fail()
+	}
+	if len(seg.Elements()) != 6 {
+		t.Logf("Expected 6 data elements, got %d\n", len(seg.Elements()))
+		t.Fail()
+	}
+	if id := seg.Element(1, 1).Text(); id != "HIXYZ" {
+		t.Logf("Expected header ID to equal HIXYZ, got %q\n", id)
+		t.Fail()
+	}
+	if number, err := seg.Element(2).Int(); err != nil || number != 1 {
+		t.Logf("Expected element 2 to equal 1, got %d (%v)\n", number, err)
+		t.Fail()
+	}
+	if seg.Element(2, 1) != seg.Element(2) || seg.Element(2, 2) != nil {
+		t.Logf("Expected a simple element to be treated as group of one\n")
+		t.Fail()
+	}
+	if text := seg.Element(3, 1).Text(); text != "Konto:Nr+1" {
+		t.Logf("Expected element 3:1 to be unescaped to %q, got %q\n", "Konto:Nr+1", text)
+		t.Fail()
+	}
+	if bankID := seg.Element(3, 3).Text(); bankID != "10000000" {
+		t.Logf("Expected element 3:3 to equal 10000000, got %q\n", bankID)
+		t.Fail()
+	}
+	if data, err := seg.Element(4).Bytes(); err != nil || string(data) != "a:b" {
+		t.Logf("Expected binary data %q, got %q (%v)\n", "a:b", data, err)
+		t.Fail()
+	}
+	if value, err := seg.Element(5, 1).Float(); err != nil || value != 1500.5 {
+		t.Logf("Expected element 5:1 to equal 1500.5, got %v (%v)\n", value, err)
+		t.Fail()
+	}
+	if date, err := seg.Element(5, 2).Date(); err != nil || !date.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Logf("Expected element 5:2 to equal 2024-03-01, got %v (%v)\n", date, err)
+		t.Fail()
+	}
+	if !seg.Element(5, 4).IsEmpty() || !seg.Element(6).IsEmpty() || seg.Element(5, 5) != nil || seg.Element(7) != nil {
+		t.Logf("Expected empty and missing elements to be distinguished\n")
+		t.Fail()
+	}
+	if seg.String() != test {
+		t.Logf("Expected segment to marshal to\n%q\n\tgot\n%q\n", test, seg.String())
+		t.Fail()
+	}
+
+	withoutReference := "HIXYZ:5:2+1'"
+	if err := seg.UnmarshalHBCI([]byte(withoutReference)); err != nil {
+		t.Fatalf("Expected no error, got %T:%v", err, err)
+	}
+	if marshaled, _ := seg.MarshalHBCI(); string(marshaled) != withoutReference {
+		t.Logf("Expected segment to marshal to\n%q\n\tgot\n%q\n", withoutReference, marshaled)
+		t.Fail()
+	}
+
+	err = (&GenericSegment{}).UnmarshalHBCI([]byte("HIXYZ:5:2+?x'"))
+
+	if err == nil {
+		t.Logf("Expected error for malformed segment, got nil\n")
+		t.Fail()
+	}
+}
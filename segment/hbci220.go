@@ -1,5 +1,7 @@
 package segment
 
+// HBCI220 defines the segments of HBCI 2.2. Account transactions of
+// international accounts (HKKAZ version 7) are not defined.
 var HBCI220 = HBCIVersion{
 	version:                   220,
 	PinTanEncryptionHeader:    NewPinTanEncryptionHeaderSegment,
@@ -8,9 +10,9 @@ var HBCI220 = HBCIVersion{
 	PinTanSignatureHeader:     NewPinTanSignatureHeaderSegment,
 	RDHSignatureHeader:        NewRDHSignatureHeaderSegment,
 	SignatureEnd:              NewSignatureEndSegmentV1,
-	SynchronisationRequest:    NewSynchronisationSegmentV2,
-	AccountBalanceRequest:     NewAccountBalanceRequestV5,
-	AccountTransactionRequest: NewAccountTransactionRequestSegmentV5,
-	StatusProtocolRequest:     NewStatusProtocolRequestV3,
-	TanProcess4Request:        NewTanProcess4RequestSegmentV6,
+	synchronisationRequest:    NewSynchronisationSegmentV2,
+	accountBalanceRequest:     NewAccountBalanceRequestV5,
+	accountTransactionRequest: NewAccountTransactionRequestSegmentV5,
+	statusProtocolRequest:     NewStatusProtocolRequestV3,
+	tanProcess4Request:        NewTanProcess4RequestSegmentV6,
 }
@@ -1,6 +1,7 @@
 package segment
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,7 +14,13 @@ var SupportedHBCIVersions = map[int]HBCIVersion{
 	300: FINTS300,
 }
 
-// HBCIVersion defines segment constructors for a HBCI version
+// ErrNotSupported is returned if a HBCI version or the institute does not
+// define a segment
+var ErrNotSupported = errors.New("segment not supported")
+
+// HBCIVersion defines segment constructors for a HBCI version. Jobs are
+// built with the methods prefixed with New, which return an ErrNotSupported
+// if the version does not define the job.
 type HBCIVersion struct {
 	version                       int
 	PinTanEncryptionHeader        func(clientSystemId string, keyName domain.KeyName) *EncryptionHeaderSegment
@@ -22,12 +29,12 @@ type HBCIVersion struct {
 	PinTanSignatureHeader         func(controlReference string, clientSystemId string, keyName domain.KeyName) *SignatureHeaderSegment
 	RDHSignatureHeader            func(controlReference string, signatureId int, clientSystemId string, keyName domain.KeyName) *SignatureHeaderSegment
 	SignatureEnd                  func() *SignatureEndSegment
-	SynchronisationRequest        func(modus SyncMode) *SynchronisationRequestSegment
-	AccountBalanceRequest         func(account domain.AccountConnection, allAccounts bool) AccountBalanceRequest
-	AccountTransactionRequest     func(account domain.AccountConnection, allAccounts bool) *AccountTransactionRequestSegment
-	SepaAccountTransactionRequest func(account domain.InternationalAccountConnection, allAccounts bool) *AccountTransactionRequestSegment
-	StatusProtocolRequest         func(from, to time.Time, maxEntries int, continuationReference string) StatusProtocolRequest
-	TanProcess4Request            func(referencingSegmentID string) *TanRequestSegment
+	synchronisationRequest        func(modus SyncMode) *SynchronisationRequestSegment
+	accountBalanceRequest         func(account domain.AccountConnection, allAccounts bool) AccountBalanceRequest
+	accountTransactionRequest     func(account domain.AccountConnection, allAccounts bool) *AccountTransactionRequestSegment
+	sepaAccountTransactionRequest func(account domain.InternationalAccountConnection, allAccounts bool) *AccountTransactionRequestSegment
+	statusProtocolRequest         func(from, to time.Time, maxEntries int, continuationReference string) StatusProtocolRequest
+	tanProcess4Request            func(referencingSegmentID string) *TanRequestSegment
 }
 
 // Version returns the HBCI version as integer
@@ -35,6 +42,60 @@ func (v HBCIVersion) Version() int {
 	return v.version
 }
 
+func (v HBCIVersion) notSupported(segment string) error {
+	return fmt.Errorf("%s: %w by HBCI version %d", segment, ErrNotSupported, v.version)
+}
+
+// NewSynchronisationRequest returns the synchronisation request of the version
+func (v HBCIVersion) NewSynchronisationRequest(modus SyncMode) (*SynchronisationRequestSegment, error) {
+	if v.synchronisationRequest == nil {
+		return nil, v.notSupported("SynchronisationRequest")
+	}
+	return v.synchronisationRequest(modus), nil
+}
+
+// NewAccountBalanceRequest returns the account balance request of the version
+func (v HBCIVersion) NewAccountBalanceRequest(account domain.AccountConnection, allAccounts bool) (AccountBalanceRequest, error) {
+	if v.accountBalanceRequest == nil {
+		return nil, v.notSupported("AccountBalanceRequest")
+	}
+	return v.accountBalanceRequest(account, allAccounts), nil
+}
+
+// NewAccountTransactionRequest returns the account transaction request of
+// the version
+func (v HBCIVersion) NewAccountTransactionRequest(account domain.AccountConnection, allAccounts bool) (*AccountTransactionRequestSegment, error) {
+	if v.accountTransactionRequest == nil {
+		return nil, v.notSupported("AccountTransactionRequest")
+	}
+	return v.accountTransactionRequest(account, allAccounts), nil
+}
+
+// NewSepaAccountTransactionRequest returns the account transaction request
+// for international accounts of the version
+func (v HBCIVersion) NewSepaAccountTransactionRequest(account domain.InternationalAccountConnection, allAccounts bool) (*AccountTransactionRequestSegment, error) {
+	if v.sepaAccountTransactionRequest == nil {
+		return nil, v.notSupported("SepaAccountTransactionRequest")
+	}
+	return v.sepaAccountTransactionRequest(account, allAccounts), nil
+}
+
+// NewStatusProtocolRequest returns the status protocol request of the version
+func (v HBCIVersion) NewStatusProtocolRequest(from, to time.Time, maxEntries int, continuationReference string) (StatusProtocolRequest, error) {
+	if v.statusProtocolRequest == nil {
+		return nil, v.notSupported("StatusProtocolRequest")
+	}
+	return v.statusProtocolRequest(from, to, maxEntries, continuationReference), nil
+}
+
+// NewTanProcess4Request returns the TAN request for process 4 of the version
+func (v HBCIVersion) NewTanProcess4Request(referencingSegmentID string) (*TanRequestSegment, error) {
+	if v.tanProcess4Request == nil {
+		return nil, v.notSupported("TanProcess4Request")
+	}
+	return v.tanProcess4Request(referencingSegmentID), nil
+}
+
 // Builder represents a builder which returns certain builders based on the
 // provided versions
 type Builder interface {
@@ -42,6 +103,9 @@ type Builder interface {
 	AccountTransactionRequest(account domain.AccountConnection, allAccounts bool) (*AccountTransactionRequestSegment, error)
 	SepaAccountTransactionRequest(account domain.InternationalAccountConnection, allAccounts bool) (*AccountTransactionRequestSegment, error)
 	StatusProtocolRequest(from, to time.Time, maxEntries int, continuationReference string) (StatusProtocolRequest, error)
+	// Request returns the highest version of the request with segmentID
+	// registered within KnownRequests which is supported by the institute.
+	Request(segmentID string) (ClientSegment, error)
 }
 
 // NewBuilder returns a new Builder which uses the supported segments to
@@ -58,10 +122,14 @@ type builder struct {
 	supportedSegments map[string][]int
 }
 
+func notSupportedByInstitute(segmentID string) error {
+	return fmt.Errorf("%s: %w by the institute", segmentID, ErrNotSupported)
+}
+
 func (b *builder) AccountBalanceRequest(account domain.AccountConnection, allAccounts bool) (AccountBalanceRequest, error) {
 	versions, ok := b.supportedSegments["HISALS"]
 	if !ok {
-		return nil, fmt.Errorf("Segment %s not supported", "HKSAL")
+		return nil, notSupportedByInstitute("HKSAL")
 	}
 	request, err := AccountBalanceRequestBuilder(versions)
 	if err != nil {
@@ -72,7 +140,7 @@ func (b *builder) AccountBalanceRequest(account domain.AccountConnection, allAcc
 func (b *builder) AccountTransactionRequest(account domain.AccountConnection, allAccounts bool) (*AccountTransactionRequestSegment, error) {
 	versions, ok := b.supportedSegments["HIKAZS"]
 	if !ok {
-		return nil, fmt.Errorf("Segment %s not supported", "HKKAZ")
+		return nil, notSupportedByInstitute("HKKAZ")
 	}
 	request, err := AccountTransactionRequestBuilder(versions)
 	if err != nil {
@@ -83,7 +151,7 @@ func (b *builder) AccountTransactionRequest(account domain.AccountConnection, al
 func (b *builder) SepaAccountTransactionRequest(account domain.InternationalAccountConnection, allAccounts bool) (*AccountTransactionRequestSegment, error) {
 	versions, ok := b.supportedSegments["HIKAZS"]
 	if !ok {
-		return nil, fmt.Errorf("Segment %s not supported", "HKKAZ")
+		return nil, notSupportedByInstitute("HKKAZ")
 	}
 	request, err := SepaAccountTransactionRequestBuilder(versions)
 	if err != nil {
@@ -94,7 +162,7 @@ func (b *builder) SepaAccountTransactionRequest(account domain.InternationalAcco
 func (b *builder) StatusProtocolRequest(from, to time.Time, maxEntries int, continuationReference string) (StatusProtocolRequest, error) {
 	versions, ok := b.supportedSegments["HIPRO"]
 	if !ok {
-		return nil, fmt.Errorf("Segment %s not supported", "HKPRO")
+		return nil, notSupportedByInstitute("HKPRO")
 	}
 	request, err := StatusProtocolRequestBuilder(versions)
 	if err != nil {
@@ -102,3 +170,14 @@ func (b *builder) StatusProtocolRequest(from, to time.Time, maxEntries int, cont
 	}
 	return request(from, to, maxEntries, continuationReference), nil
 }
+func (b *builder) Request(segmentID string) (ClientSegment, error) {
+	versions, ok := b.supportedSegments[parameterSegmentID(segmentID)]
+	if !ok {
+		return nil, notSupportedByInstitute(segmentID)
+	}
+	request, err := KnownRequests.RequestBuilder(segmentID, versions)
+	if err != nil {
+		return nil, err
+	}
+	return request(), nil
+}
@@ -0,0 +1,176 @@
+package segment
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+func TestHBCIVersionConstructors(t *testing.T) {
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	internationalAccount := domain.InternationalAccountConnection{IBAN: "DE89370400440532013000", BIC: "COBADEFFXXX"}
+	keyName := domain.KeyName{BankID: domain.BankID{CountryCode: 280, ID: "10000000"}, UserID: "12345", KeyType: domain.KeyTypeSigning}
+	now := time.Now()
+
+	tests := []struct {
+		description string
+		construct   func(v HBCIVersion) (ClientSegment, error)
+		// expected segment ID and versions for HBCI220 and FINTS300, a
+		// version of zero means not supported
+		id               string
+		expectedVersions map[int]int
+	}{
+		{
+			"PinTanEncryptionHeader",
+			func(v HBCIVersion) (ClientSegment, error) { return v.PinTanEncryptionHeader("xyz", keyName), nil },
+			"HNVSK", map[int]int{220: 2, 300: 3},
+		},
+		{
+			"RDHEncryptionHeader",
+			func(v HBCIVersion) (ClientSegment, error) {
+				return v.RDHEncryptionHeader("xyz", keyName, []byte("key")), nil
+			},
+			"HNVSK", map[int]int{220: 2, 300: 3},
+		},
+		{
+			"SignatureHeader",
+			func(v HBCIVersion) (ClientSegment, error) { return v.SignatureHeader(), nil },
+			"HNSHK", map[int]int{220: 3, 300: 4},
+		},
+		{
+			"PinTanSignatureHeader",
+			func(v HBCIVersion) (ClientSegment, error) { return v.PinTanSignatureHeader("ref", "xyz", keyName), nil },
+			"HNSHK", map[int]int{220: 3, 300: 4},
+		},
+		{
+			"RDHSignatureHeader",
+			func(v HBCIVersion) (ClientSegment, error) { return v.RDHSignatureHeader("ref", 1, "xyz", keyName), nil },
+			"HNSHK", map[int]int{220: 3, 300: 4},
+		},
+		{
+			"SignatureEnd",
+			func(v HBCIVersion) (ClientSegment, error) { return v.SignatureEnd(), nil },
+			"HNSHA", map[int]int{220: 1, 300: 2},
+		},
+		{
+			"SynchronisationRequest",
+			func(v HBCIVersion) (ClientSegment, error) { return v.NewSynchronisationRequest(SyncModeAquireClientID) },
+			"HKSYN", map[int]int{220: 2, 300: 3},
+		},
+		{
+			"AccountBalanceRequest",
+			func(v HBCIVersion) (ClientSegment, error) { return v.NewAccountBalanceRequest(account, false) },
+			"HKSAL", map[int]int{220: 5, 300: 6},
+		},
+		{
+			"AccountTransactionRequest",
+			func(v HBCIVersion) (ClientSegment, error) { return v.NewAccountTransactionRequest(account, false) },
+			"HKKAZ", map[int]int{220: 5, 300: 6},
+		},
+		{
+			"SepaAccountTransactionRequest",
+			func(v HBCIVersion) (ClientSegment, error) {
+				return v.NewSepaAccountTransactionRequest(internationalAccount, false)
+			},
+			"HKKAZ", map[int]int{220: 0, 300: 7},
+		},
+		{
+			"StatusProtocolRequest",
+			func(v HBCIVersion) (ClientSegment, error) { return v.NewStatusProtocolRequest(now, now, 10, "") },
+			"HKPRO", map[int]int{220: 3, 300: 4},
+		},
+		{
+			"TanProcess4Request",
+			func(v HBCIVersion) (ClientSegment, error) { return v.NewTanProcess4Request(IdentificationID) },
+			"HKTAN", map[int]int{220: 6, 300: 6},
+		},
+	}
+
+	for _, version := range []HBCIVersion{HBCI220, FINTS300} {
+		for _, test := range tests {
+			expectedVersion := test.expectedVersions[version.Version()]
+
+			seg, err := test.construct(version)
+
+			if expectedVersion == 0 {
+				if !errors.Is(err, ErrNotSupported) {
+					t.Logf("%d %s: Expected ErrNotSupported, got %v\n", version.Version(), test.description, err)
+					t.Fail()
+				}
+				continue
+			}
+			if err != nil {
+				t.Logf("%d %s: Expected no error, got %v\n", version.Version(), test.description, err)
+				t.Fail()
+				continue
+			}
+			header := seg.Header()
+			if header.ID.Val() != test.id || header.Version.Val() != expectedVersion {
+				t.Logf(
+					"%d %s: Expected %s version %d, got %s version %d\n",
+					version.Version(), test.description, test.id, expectedVersion, header.ID.Val(), header.Version.Val(),
+				)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestBuilderNotSupportedByInstitute(t *testing.T) {
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	internationalAccount := domain.InternationalAccountConnection{IBAN: "DE89370400440532013000", BIC: "COBADEFFXXX"}
+	builder := NewBuilder(nil)
+	now := time.Now()
+
+	tests := map[string]func() error{
+		"AccountBalanceRequest": func() error {
+			_, err := builder.AccountBalanceRequest(account, false)
+			return err
+		},
+		"AccountTransactionRequest": func() error {
+			_, err := builder.AccountTransactionRequest(account, false)
+			return err
+		},
+		"SepaAccountTransactionRequest": func() error {
+			_, err := builder.SepaAccountTransactionRequest(internationalAccount, false)
+			return err
+		},
+		"StatusProtocolRequest": func() error {
+			_, err := builder.StatusProtocolRequest(now, now, 10, "")
+			return err
+		},
+		"Request": func() error {
+			_, err := builder.Request("HKTST")
+			return err
+		},
+	}
+
+	for description, build := range tests {
+		err := build()
+
+		if !errors.Is(err, ErrNotSupported) {
+			t.Logf("%s: Expected ErrNotSupported, got %v\n", description, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestSupportedHBCIVersions(t *testing.T) {
+	if len(SupportedHBCIVersions) != len(domain.SupportedHBCIVersions) {
+		t.Logf("Expected %d versions, got %d\n", len(domain.SupportedHBCIVersions), len(SupportedHBCIVersions))
+		t.Fail()
+	}
+	for _, version := range domain.SupportedHBCIVersions {
+		hbciVersion, ok := SupportedHBCIVersions[version]
+		if !ok || hbciVersion.Version() != version {
+			t.Logf("Expected version %d to be supported, got %v\n", version, hbciVersion.Version())
+			t.Fail()
+		}
+	}
+	if _, ok := SupportedHBCIVersions[domain.FINTSVersion410]; ok {
+		t.Logf("Expected FinTS 4.1 not to be built from segments\n")
+		t.Fail()
+	}
+}
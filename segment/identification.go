@@ -5,6 +5,8 @@ import (
 	"github.com/mitch000001/go-hbci/element"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments IdentificationSegment
+
 const IdentificationID = "HKIDN"
 
 func NewIdentificationSegment(bankId domain.BankID, clientId string, clientSystemId string, systemIdRequired bool) *IdentificationSegment {
@@ -26,10 +28,10 @@ func NewIdentificationSegment(bankId domain.BankID, clientId string, clientSyste
 
 type IdentificationSegment struct {
 	ClientSegment
-	BankId             *element.BankIdentificationDataElement
-	ClientId           *element.IdentificationDataElement
-	ClientSystemId     *element.IdentificationDataElement
-	ClientSystemStatus *element.NumberDataElement
+	BankId             *element.BankIdentificationDataElement `hbci:"required"`
+	ClientId           *element.IdentificationDataElement     `hbci:"required"`
+	ClientSystemId     *element.IdentificationDataElement     `hbci:"required"`
+	ClientSystemStatus *element.NumberDataElement             `hbci:"required"`
 }
 
 func (i *IdentificationSegment) Version() int         { return 2 }
@@ -0,0 +1,83 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (i *IdentificationSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if i.BankId != nil {
+		marshaled, err := i.BankId.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if i.ClientId != nil {
+		marshaled, err := i.ClientId.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if i.ClientSystemId != nil {
+		marshaled, err := i.ClientSystemId.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if i.ClientSystemStatus != nil {
+		marshaled, err := i.ClientSystemStatus.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (i *IdentificationSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if i.BankId != nil {
+		buf.WriteString(i.BankId.String())
+	}
+	buf.WriteByte('+')
+	if i.ClientId != nil {
+		buf.WriteString(i.ClientId.String())
+	}
+	buf.WriteByte('+')
+	if i.ClientSystemId != nil {
+		buf.WriteString(i.ClientSystemId.String())
+	}
+	buf.WriteByte('+')
+	if i.ClientSystemStatus != nil {
+		buf.WriteString(i.ClientSystemStatus.String())
+	}
+}
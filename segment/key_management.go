@@ -2,13 +2,14 @@ package segment
 
 import (
 	"fmt"
-	"sort"
 	"time"
 
 	"github.com/mitch000001/go-hbci/domain"
 	"github.com/mitch000001/go-hbci/element"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments PublicKeyRenewalSegment,PublicKeyRequestSegment,PublicKeyTransmissionSegment,PublicKeyRevocationSegment,PublicKeyRevocationConfirmationSegment
+
 func NewPublicKeyRenewalSegment(number int, keyName domain.KeyName, pubKey *domain.PublicKey) *PublicKeyRenewalSegment {
 	if keyName.KeyType == "B" {
 		panic(fmt.Errorf("KeyType may not be 'B'"))
@@ -19,19 +20,19 @@ func NewPublicKeyRenewalSegment(number int, keyName domain.KeyName, pubKey *doma
 		KeyName:    element.NewKeyName(keyName),
 		PublicKey:  element.NewPublicKey(pubKey),
 	}
-	p.Segment = NewBasicSegment(number, p)
+	p.ClientSegment = NewBasicSegment(number, p)
 	return p
 }
 
 type PublicKeyRenewalSegment struct {
-	Segment
+	ClientSegment
 	// "2" für ‘Key-Management-Nachricht erwartet Antwort’
-	MessageID *element.NumberDataElement
+	MessageID *element.NumberDataElement `hbci:"required"`
 	// "112" für ‘Certificate Replacement’ (Ersatz des Zertifikats))
-	FunctionID *element.NumberDataElement
+	FunctionID *element.NumberDataElement `hbci:"required"`
 	// Key type may not equal 'B'
-	KeyName     *element.KeyNameDataElement
-	PublicKey   *element.PublicKeyDataElement
+	KeyName     *element.KeyNameDataElement   `hbci:"required"`
+	PublicKey   *element.PublicKeyDataElement `hbci:"required"`
 	Certificate *element.CertificateDataElement
 }
 
@@ -63,10 +64,10 @@ func NewPublicKeyRequestSegment(number int, keyName domain.KeyName) *PublicKeyRe
 type PublicKeyRequestSegment struct {
 	ClientSegment
 	// "2" für ‘Key-Management-Nachricht erwartet Antwort’
-	MessageID *element.NumberDataElement
+	MessageID *element.NumberDataElement `hbci:"required"`
 	// "124" für ‘Certificate Status Request’
-	FunctionID  *element.NumberDataElement
-	KeyName     *element.KeyNameDataElement
+	FunctionID  *element.NumberDataElement  `hbci:"required"`
+	KeyName     *element.KeyNameDataElement `hbci:"required"`
 	Certificate *element.CertificateDataElement
 }
 
@@ -101,6 +102,8 @@ func NewPublicKeyTransmissionSegment(dialogId string, number int, messageReferen
 	return p
 }
 
+//go:generate go run ../cmd/unmarshaler/unmarshaler_generator.go -segment PublicKeyTransmissionSegment
+
 type PublicKeyTransmissionSegment struct {
 	Segment
 	// "1" für ‘Key-Management-Nachricht ist Antwort’
@@ -119,6 +122,12 @@ func (p *PublicKeyTransmissionSegment) ID() string           { return "HIISA" }
 func (p *PublicKeyTransmissionSegment) referencedId() string { return "HKISA" }
 func (p *PublicKeyTransmissionSegment) sender() string       { return senderBank }
 
+// Key returns the transmitted public key of the institute
+func (p *PublicKeyTransmissionSegment) Key() *domain.RSAKey {
+	keyName := p.KeyName.Val()
+	return domain.NewRSAKey(p.PublicKey.Val(), &keyName)
+}
+
 func (p *PublicKeyTransmissionSegment) elements() []element.DataElement {
 	return []element.DataElement{
 		p.MessageID,
@@ -143,8 +152,17 @@ var validRevocationReasons = []string{
 	KeyRevocationMisc,
 }
 
+func isValidRevocationReason(reason string) bool {
+	for _, r := range validRevocationReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
 func NewPublicKeyRevocationSegment(number int, keyName domain.KeyName, reason string) *PublicKeyRevocationSegment {
-	if sort.SearchStrings(validRevocationReasons, reason) > len(validRevocationReasons) {
+	if !isValidRevocationReason(reason) {
 		panic(fmt.Errorf("Reason must be one of %v", validRevocationReasons))
 	}
 	p := &PublicKeyRevocationSegment{
@@ -154,21 +172,21 @@ func NewPublicKeyRevocationSegment(number int, keyName domain.KeyName, reason st
 		RevocationReason: element.NewAlphaNumeric(reason, 3),
 		Date:             element.NewSecurityDate(element.SecurityTimestamp, time.Now()),
 	}
-	p.Segment = NewBasicSegment(number, p)
+	p.ClientSegment = NewBasicSegment(number, p)
 	return p
 }
 
 type PublicKeyRevocationSegment struct {
-	Segment
+	ClientSegment
 	// "2" für ‘Key-Management-Nachricht erwartet Antwort’
-	MessageID *element.NumberDataElement
+	MessageID *element.NumberDataElement `hbci:"required"`
 	// "130" für ‘Certificate Revocation’ (Zertifikatswiderruf)
-	FunctionID *element.NumberDataElement
-	KeyName    *element.KeyNameDataElement
+	FunctionID *element.NumberDataElement  `hbci:"required"`
+	KeyName    *element.KeyNameDataElement `hbci:"required"`
 	// "1" für ‘Schlüssel des Zertifikatseigentümers kompromittiert’
 	// "501" für ‘Zertifikat ungültig wegen Verdacht auf Kompromittierung’
 	// "999" für ‘gesperrt aus sonstigen Gründen’
-	RevocationReason *element.AlphaNumericDataElement
+	RevocationReason *element.AlphaNumericDataElement `hbci:"required"`
 	Date             *element.SecurityDateDataElement
 	Certificate      *element.CertificateDataElement
 }
@@ -193,7 +211,7 @@ func NewPublicKeyRevocationConfirmationSegment(dialogId string, number int, mess
 	if messageReference <= 0 {
 		panic(fmt.Errorf("Message Reference number must be greater 0"))
 	}
-	if sort.SearchStrings(validRevocationReasons, reason) > len(validRevocationReasons) {
+	if !isValidRevocationReason(reason) {
 		panic(fmt.Errorf("Reason must be one of %v", validRevocationReasons))
 	}
 	p := &PublicKeyRevocationConfirmationSegment{
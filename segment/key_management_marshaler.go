@@ -0,0 +1,557 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (p *PublicKeyRenewalSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if p.MessageID != nil {
+		marshaled, err := p.MessageID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.FunctionID != nil {
+		marshaled, err := p.FunctionID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.KeyName != nil {
+		marshaled, err := p.KeyName.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.PublicKey != nil {
+		marshaled, err := p.PublicKey.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.Certificate != nil {
+		marshaled, err := p.Certificate.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (p *PublicKeyRenewalSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if p.MessageID != nil {
+		buf.WriteString(p.MessageID.String())
+	}
+	buf.WriteByte('+')
+	if p.FunctionID != nil {
+		buf.WriteString(p.FunctionID.String())
+	}
+	buf.WriteByte('+')
+	if p.KeyName != nil {
+		buf.WriteString(p.KeyName.String())
+	}
+	buf.WriteByte('+')
+	if p.PublicKey != nil {
+		buf.WriteString(p.PublicKey.String())
+	}
+	buf.WriteByte('+')
+	if p.Certificate != nil {
+		buf.WriteString(p.Certificate.String())
+	}
+}
+
+func (p *PublicKeyRequestSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if p.MessageID != nil {
+		marshaled, err := p.MessageID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.FunctionID != nil {
+		marshaled, err := p.FunctionID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.KeyName != nil {
+		marshaled, err := p.KeyName.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.Certificate != nil {
+		marshaled, err := p.Certificate.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (p *PublicKeyRequestSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if p.MessageID != nil {
+		buf.WriteString(p.MessageID.String())
+	}
+	buf.WriteByte('+')
+	if p.FunctionID != nil {
+		buf.WriteString(p.FunctionID.String())
+	}
+	buf.WriteByte('+')
+	if p.KeyName != nil {
+		buf.WriteString(p.KeyName.String())
+	}
+	buf.WriteByte('+')
+	if p.Certificate != nil {
+		buf.WriteString(p.Certificate.String())
+	}
+}
+
+func (p *PublicKeyTransmissionSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if p.MessageID != nil {
+		marshaled, err := p.MessageID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.DialogID != nil {
+		marshaled, err := p.DialogID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.MessageRef != nil {
+		marshaled, err := p.MessageRef.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.FunctionID != nil {
+		marshaled, err := p.FunctionID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.KeyName != nil {
+		marshaled, err := p.KeyName.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.PublicKey != nil {
+		marshaled, err := p.PublicKey.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.Certificate != nil {
+		marshaled, err := p.Certificate.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (p *PublicKeyTransmissionSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if p.MessageID != nil {
+		buf.WriteString(p.MessageID.String())
+	}
+	buf.WriteByte('+')
+	if p.DialogID != nil {
+		buf.WriteString(p.DialogID.String())
+	}
+	buf.WriteByte('+')
+	if p.MessageRef != nil {
+		buf.WriteString(p.MessageRef.String())
+	}
+	buf.WriteByte('+')
+	if p.FunctionID != nil {
+		buf.WriteString(p.FunctionID.String())
+	}
+	buf.WriteByte('+')
+	if p.KeyName != nil {
+		buf.WriteString(p.KeyName.String())
+	}
+	buf.WriteByte('+')
+	if p.PublicKey != nil {
+		buf.WriteString(p.PublicKey.String())
+	}
+	buf.WriteByte('+')
+	if p.Certificate != nil {
+		buf.WriteString(p.Certificate.String())
+	}
+}
+
+func (p *PublicKeyRevocationSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if p.MessageID != nil {
+		marshaled, err := p.MessageID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.FunctionID != nil {
+		marshaled, err := p.FunctionID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.KeyName != nil {
+		marshaled, err := p.KeyName.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.RevocationReason != nil {
+		marshaled, err := p.RevocationReason.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.Date != nil {
+		marshaled, err := p.Date.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.Certificate != nil {
+		marshaled, err := p.Certificate.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (p *PublicKeyRevocationSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if p.MessageID != nil {
+		buf.WriteString(p.MessageID.String())
+	}
+	buf.WriteByte('+')
+	if p.FunctionID != nil {
+		buf.WriteString(p.FunctionID.String())
+	}
+	buf.WriteByte('+')
+	if p.KeyName != nil {
+		buf.WriteString(p.KeyName.String())
+	}
+	buf.WriteByte('+')
+	if p.RevocationReason != nil {
+		buf.WriteString(p.RevocationReason.String())
+	}
+	buf.WriteByte('+')
+	if p.Date != nil {
+		buf.WriteString(p.Date.String())
+	}
+	buf.WriteByte('+')
+	if p.Certificate != nil {
+		buf.WriteString(p.Certificate.String())
+	}
+}
+
+func (p *PublicKeyRevocationConfirmationSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if p.MessageID != nil {
+		marshaled, err := p.MessageID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.DialogID != nil {
+		marshaled, err := p.DialogID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.MessageRef != nil {
+		marshaled, err := p.MessageRef.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.FunctionID != nil {
+		marshaled, err := p.FunctionID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.KeyName != nil {
+		marshaled, err := p.KeyName.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.RevocationReason != nil {
+		marshaled, err := p.RevocationReason.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.Date != nil {
+		marshaled, err := p.Date.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.Certificate != nil {
+		marshaled, err := p.Certificate.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (p *PublicKeyRevocationConfirmationSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if p.MessageID != nil {
+		buf.WriteString(p.MessageID.String())
+	}
+	buf.WriteByte('+')
+	if p.DialogID != nil {
+		buf.WriteString(p.DialogID.String())
+	}
+	buf.WriteByte('+')
+	if p.MessageRef != nil {
+		buf.WriteString(p.MessageRef.String())
+	}
+	buf.WriteByte('+')
+	if p.FunctionID != nil {
+		buf.WriteString(p.FunctionID.String())
+	}
+	buf.WriteByte('+')
+	if p.KeyName != nil {
+		buf.WriteString(p.KeyName.String())
+	}
+	buf.WriteByte('+')
+	if p.RevocationReason != nil {
+		buf.WriteString(p.RevocationReason.String())
+	}
+	buf.WriteByte('+')
+	if p.Date != nil {
+		buf.WriteString(p.Date.String())
+	}
+	buf.WriteByte('+')
+	if p.Certificate != nil {
+		buf.WriteString(p.Certificate.String())
+	}
+}
@@ -0,0 +1,79 @@
+// Code generated by *generator.SegmentUnmarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/element"
+)
+
+func (p *PublicKeyTransmissionSegment) UnmarshalHBCI(value []byte) error {
+	elements, err := ExtractElements(value)
+	if err != nil {
+		return err
+	}
+	if len(elements) == 0 {
+		return fmt.Errorf("Malformed marshaled value")
+	}
+	seg, err := SegmentFromHeaderBytes(elements[0], p)
+	if err != nil {
+		return err
+	}
+	p.Segment = seg
+	if len(elements) > 1 && len(elements[1]) > 0 {
+		p.MessageID = &element.NumberDataElement{}
+		err = p.MessageID.UnmarshalHBCI(elements[1])
+		if err != nil {
+			return err
+		}
+	}
+	if len(elements) > 2 && len(elements[2]) > 0 {
+		p.DialogID = &element.IdentificationDataElement{}
+		err = p.DialogID.UnmarshalHBCI(elements[2])
+		if err != nil {
+			return err
+		}
+	}
+	if len(elements) > 3 && len(elements[3]) > 0 {
+		p.MessageRef = &element.NumberDataElement{}
+		err = p.MessageRef.UnmarshalHBCI(elements[3])
+		if err != nil {
+			return err
+		}
+	}
+	if len(elements) > 4 && len(elements[4]) > 0 {
+		p.FunctionID = &element.NumberDataElement{}
+		err = p.FunctionID.UnmarshalHBCI(elements[4])
+		if err != nil {
+			return err
+		}
+	}
+	if len(elements) > 5 && len(elements[5]) > 0 {
+		p.KeyName = &element.KeyNameDataElement{}
+		err = p.KeyName.UnmarshalHBCI(elements[5])
+		if err != nil {
+			return err
+		}
+	}
+	if len(elements) > 6 && len(elements[6]) > 0 {
+		p.PublicKey = &element.PublicKeyDataElement{}
+		err = p.PublicKey.UnmarshalHBCI(elements[6])
+		if err != nil {
+			return err
+		}
+	}
+	if len(elements) > 7 && len(elements[7]) > 0 {
+		p.Certificate = &element.CertificateDataElement{}
+		if len(elements)+1 > 7 {
+			err = p.Certificate.UnmarshalHBCI(bytes.Join(elements[7:], []byte("+")))
+		} else {
+			err = p.Certificate.UnmarshalHBCI(elements[7])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
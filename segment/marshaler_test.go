@@ -0,0 +1,357 @@
+package segment
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/domain"
+)
+
+var segmentFixtures = map[VersionedSegment]string{
+	{"HNHBK", 3}:  "HNHBK:1:3+000000000100+220+abcde+2'",
+	{"HNHBS", 1}:  "HNHBS:2:1+1'",
+	{"HNVSK", 2}:  "HNVSK:998:2+998+1+1::0+1:20150713:173634+2:2:13:@8@\x00\x00\x00\x00\x00\x00\x00\x00:5:1+280:10000000:12345:V:0:0+0'",
+	{"HNVSK", 3}:  "HNVSK:998:3+PIN:1+998+1+1::0+1:20150713:173634+2:2:13:@8@\x00\x00\x00\x00\x00\x00\x00\x00:5:1+280:10000000:12345:V:0:0+0'",
+	{"HNVSD", 1}:  "HNVSD:999:1+@5@abcde'",
+	{"HIRMG", 2}:  "HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+	{"HIRMS", 2}:  "HIRMS:2:2:3+3040::Es liegen weitere Informationen vor:ref1'",
+	{"HIISA", 2}:  "HIISA:3:2:3+1+abcde+1+224+280:10000000:USERID:V:1:1+6:16:10:@3@abc:12:@3@\x01\x00\x01:13'",
+	{"HISYN", 3}:  "HISYN:2:3:8+newClientSystemID'",
+	{"HISYN", 4}:  "HISYN:193:4:5+LRZYhZNbV2IBAAAd0?+VNqlkXrAQA'",
+	{"HIKIM", 2}:  "HIKIM:3:2+ec-Karte+Ihre neue ec-Karte liegt zur Abholung bereit.'",
+	{"HIBPA", 2}:  "HIBPA:2:2:+12+280:10000000+Bank Name+3+1+201:210:220+0'",
+	{"HIBPA", 3}:  "HIBPA:3:3:4+12+280:10000000+Testbank+0+1+220'",
+	{"HIPINS", 1}: "HIPINS:4:1:4+1+1+0+5:38:6:USERID:CUSTID:HKSAL:N:HKUEB:J'",
+	{"DIPINS", 1}: "DIPINS:3:1:4+1+1+HKSAL:N:HKUEB:J'",
+	{"HIKPV", 1}:  "HIKPV:4:1:4+1+6'",
+	{"HIKAZS", 4}: "HIKAZS:23:4:4+1+1+90:J'",
+	{"HIKAZS", 5}: "HIKAZS:23:5:4+1+1+360:J:N'",
+	{"HIKAZS", 6}: "HIKAZS:24:6:4+2+1+0+730:N:J'",
+	{"HIKAZS", 7}: "HIKAZS:25:7:4+1+1+0'",
+	{"HIUPA", 2}:  "HIUPA:3:2:4+12345+4+0'",
+	{"HIUPA", 3}:  "HIUPA:3:3:4+12345+4+0+Max Mustermann'",
+	{"HIUPA", 4}:  "HIUPA:6:4:4+12345+3+0'",
+	{"HIUPD", 4}:  "HIUPD:4:4:4+100000000::280:10000000+12345+EUR+Mustermann+Max+++HKSAL:1'",
+	{"HIUPD", 5}:  "HIUPD:1:5:4++Login+++Name++++HKPSA:1'",
+	{"HIUPD", 6}:  "HIUPD:5:6:3+1234567::280:10000000+DE12100000000001234567+12345+1+EUR+Muster+Max+Girokonto+T:5000,:EUR:1+HKSAL:1+HKKAZ:1+HKCCS:1:T:1000,:EUR:1'",
+	{"HIUPD", 7}:  "HIUPD:5:7:3+1234567::280:10000000+DE12100000000001234567+12345+1+EUR+Muster+Max+Girokonto+T:5000,:EUR:1+HKSAL:1+HKKAZ:1'",
+	{"HISAL", 5}:  "HISAL:4:5:3+100000000::280:10000000+Sichteinlagen+EUR+C:1000,15:EUR:20150812+C:20,:EUR:20150812+500,:EUR+1499,85:EUR'",
+	{"HIKIF", 1}:  "HIKIF:4:1:3+100000000::280:10000000+1+Max Mustermann++Girokonto+EUR+20150101+1,5+0,5+9,5+5000,:EUR+200000000::280:10000000+1+3+Hinweis'",
+	{"HIKAZ", 5}:  fmt.Sprintf("HIKAZ:5:5:3+@%d@%s'", len(mt940Fixture(1)), mt940Fixture(1)),
+	{"HIKAZ", 6}:  fmt.Sprintf("HIKAZ:5:6:3+@%d@%s'", len(mt940Fixture(1)), mt940Fixture(1)),
+	{"HIKAZ", 7}:  fmt.Sprintf("HIKAZ:5:7:3+@%d@%s'", len(mt940Fixture(1)), mt940Fixture(1)),
+	{"HIPRO", 3}:  "HIPRO:4:3:3+abcde:2+3+20150812+173634+0010::Nachricht entgegengenommen'",
+	{"HIPRO", 4}:  "HIPRO:4:4:3+abcde:2+3+20150812+173634+0010::Nachricht entgegengenommen'",
+	{"HITANS", 6}: "HITANS:5:6:4+1+1+0+N:N:0:942:2:MTAN2:mobileTAN::mobile TAN:6:1:SMS:2048:J:2:N:0:0:N:N:00:0:N:1'",
+	{"HITAN", 6}:  "HITAN:4:6:4+4++4711+Bitte TAN eingeben'",
+}
+
+func mt940Fixture(transactions int) string {
+	var buf strings.Builder
+	buf.WriteString("\r\n:20:HBCIKTOLST")
+	buf.WriteString("\r\n:25:12345678/1234123456")
+	buf.WriteString("\r\n:28C:0")
+	buf.WriteString("\r\n:60F:C181105EUR1234,56")
+	for i := 0; i < transactions; i++ {
+		fmt.Fprintf(&buf, "\r\n:61:1811051105D50,NMSCNONREF")
+		fmt.Fprintf(&buf, "\r\n:86:177?00SB-SEPA-Ueberweisung?20Miete %d?32Max Meier", i)
+	}
+	buf.WriteString("\r\n:62F:C190125EUR1234,56")
+	buf.WriteString("\r\n-")
+	return buf.String()
+}
+
+// unmarshalFixture unmarshals fixture into a new instance of the segment
+// indexed at id
+func unmarshalFixture(t testing.TB, id VersionedSegment, fixture string) Segment {
+	unmarshaler, err := KnownSegments.UnmarshalerForSegment(id)
+	if err != nil {
+		t.Fatalf("%s: Expected no error, got %v", id, err)
+	}
+	if err := unmarshaler.UnmarshalHBCI([]byte(fixture)); err != nil {
+		t.Fatalf("%s: Expected no error while unmarshaling, got %v", id, err)
+	}
+	return unmarshaler.(Segment)
+}
+
+// innerSegment returns the segment embedded into seg, descending into
+// versioned wrappers like AccountBalanceRequestSegment
+func innerSegment(t testing.TB, seg Segment) *segment {
+	// wrappers embedding an unexported interface can not be accessed by
+	// reflection
+	switch wrapper := seg.(type) {
+	case *EncryptionHeaderSegment:
+		return innerSegment(t, wrapper.encryptionHeaderSegment)
+	case *SignatureHeaderSegment:
+		return innerSegment(t, wrapper.signatureHeaderSegment)
+	case *SignatureEndSegment:
+		return innerSegment(t, wrapper.signatureEndSegment)
+	case *TanRequestSegment:
+		return innerSegment(t, wrapper.tanRequestSegment)
+	}
+	value := reflect.ValueOf(seg).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.Anonymous || !field.IsExported() {
+			continue
+		}
+		switch inner := value.Field(i).Interface().(type) {
+		case *segment:
+			return inner
+		case Segment:
+			return innerSegment(t, inner)
+		}
+	}
+	t.Fatalf("%T: Expected embedded segment", seg)
+	return nil
+}
+
+func TestKnownSegmentsMarshalRoundTrip(t *testing.T) {
+	for id := range KnownSegments.segmentMap {
+		fixture, ok := segmentFixtures[id]
+		if !ok {
+			t.Logf("Missing fixture for segment %s", id)
+			t.Fail()
+			continue
+		}
+		seg := unmarshalFixture(t, id, fixture)
+		marshaled, err := innerSegment(t, seg).MarshalHBCI()
+		if err != nil {
+			t.Logf("%s: Expected no error while marshaling, got %v", id, err)
+			t.Fail()
+			continue
+		}
+		roundTripped := unmarshalFixture(t, id, string(marshaled))
+		if expected, actual := seg.String(), roundTripped.String(); expected != actual {
+			t.Logf("%s: Expected round trip to yield\n%q\n\tgot\n%q\n", id, expected, actual)
+			t.Fail()
+		}
+		remarshaled, err := innerSegment(t, roundTripped).MarshalHBCI()
+		if err != nil {
+			t.Logf("%s: Expected no error while marshaling, got %v", id, err)
+			t.Fail()
+			continue
+		}
+		if !bytes.Equal(marshaled, remarshaled) {
+			t.Logf("%s: Expected round trip to marshal to\n%q\n\tgot\n%q\n", id, marshaled, remarshaled)
+			t.Fail()
+		}
+	}
+}
+
+func TestGeneratedMarshalerParity(t *testing.T) {
+	for id := range KnownSegments.segmentMap {
+		fixture, ok := segmentFixtures[id]
+		if !ok {
+			continue
+		}
+		inner := innerSegment(t, unmarshalFixture(t, id, fixture))
+		header, err := inner.header.MarshalHBCI()
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", id, err)
+		}
+		expected, err := inner.reflectMarshalHBCI(header)
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", id, err)
+		}
+		actual, err := inner.MarshalHBCI()
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", id, err)
+		}
+		if !bytes.Equal(expected, actual) {
+			t.Logf("%s: Expected MarshalHBCI to return\n%q\n\tgot\n%q\n", id, expected, actual)
+			t.Fail()
+		}
+		if expected, actual := inner.reflectString(), inner.String(); expected != actual {
+			t.Logf("%s: Expected String to return\n%q\n\tgot\n%q\n", id, expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+// clientSegments returns the client segments of version, built by the
+// constructors used within dialogs
+func clientSegments(t testing.TB, version HBCIVersion) []ClientSegment {
+	bankID := domain.BankID{CountryCode: 280, ID: "10000000"}
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	internationalAccount := domain.InternationalAccountConnection{IBAN: "DE89370400440532013000", BIC: "COBADEFFXXX"}
+	keyName := domain.KeyName{BankID: bankID, UserID: "12345", KeyType: domain.KeyTypeSigning}
+	date := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	segments := []ClientSegment{
+		NewMessageHeaderSegment(120, version.Version(), "0", 1),
+		NewIdentificationSegment(bankID, "12345", "0", true),
+		NewProcessingPreparationSegmentV3(0, 0, domain.German),
+		NewDialogEndSegment("4711"),
+		NewMessageEndSegment(8, 1),
+		version.PinTanEncryptionHeader("xyz", keyName),
+		version.RDHEncryptionHeader("xyz", keyName, []byte("key+:'")),
+		version.PinTanSignatureHeader("ref", "xyz", keyName),
+		version.RDHSignatureHeader("ref", 1, "xyz", keyName),
+	}
+	jobs := []func() (ClientSegment, error){
+		func() (ClientSegment, error) { return version.NewSynchronisationRequest(SyncModeAquireClientID) },
+		func() (ClientSegment, error) { return version.NewAccountBalanceRequest(account, false) },
+		func() (ClientSegment, error) { return version.NewAccountTransactionRequest(account, true) },
+		func() (ClientSegment, error) {
+			return version.NewSepaAccountTransactionRequest(internationalAccount, false)
+		},
+		func() (ClientSegment, error) { return version.NewStatusProtocolRequest(date, date, 10, "") },
+		func() (ClientSegment, error) { return version.NewTanProcess4Request(IdentificationID) },
+	}
+	for _, job := range jobs {
+		seg, err := job()
+		if errors.Is(err, ErrNotSupported) {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: Expected no error, got %v", version.Version(), err)
+		}
+		segments = append(segments, seg)
+	}
+	for i, seg := range segments {
+		position := i + 1
+		seg.SetPosition(func() int { return position })
+	}
+	return segments
+}
+
+func TestClientSegmentMarshalerParity(t *testing.T) {
+	for _, version := range []HBCIVersion{HBCI220, FINTS300} {
+		for _, seg := range clientSegments(t, version) {
+			id := fmt.Sprintf("%d %s:%d", version.Version(), seg.Header().ID.Val(), seg.Header().Version.Val())
+			inner := innerSegment(t, seg)
+			header, err := inner.header.MarshalHBCI()
+			if err != nil {
+				t.Fatalf("%s: Expected no error, got %v", id, err)
+			}
+			expected, err := inner.reflectMarshalHBCI(header)
+			if err != nil {
+				t.Fatalf("%s: Expected no error, got %v", id, err)
+			}
+			actual, err := seg.MarshalHBCI()
+			if err != nil {
+				t.Fatalf("%s: Expected no error, got %v", id, err)
+			}
+			if !bytes.Equal(expected, actual) {
+				t.Logf("%s: Expected MarshalHBCI to return\n%q\n\tgot\n%q\n", id, expected, actual)
+				t.Fail()
+			}
+			if expected, actual := inner.reflectString(), seg.String(); expected != actual {
+				t.Logf("%s: Expected String to return\n%q\n\tgot\n%q\n", id, expected, actual)
+				t.Fail()
+			}
+
+			// Client segments have no unmarshalers, so the round trip
+			// is done on the level of the data elements
+			var generic GenericSegment
+			if err := generic.UnmarshalHBCI(actual); err != nil {
+				t.Logf("%s: Expected no error while unmarshaling, got %v", id, err)
+				t.Fail()
+				continue
+			}
+			remarshaled, err := generic.MarshalHBCI()
+			if err != nil {
+				t.Fatalf("%s: Expected no error, got %v", id, err)
+			}
+			if !bytes.Equal(actual, remarshaled) {
+				t.Logf("%s: Expected round trip to marshal to\n%q\n\tgot\n%q\n", id, actual, remarshaled)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestSegmentMarshalHBCIBinaryDataEndingWithSeparator(t *testing.T) {
+	for _, data := range []string{"abc+", "abc:", "abc++"} {
+		seg := NewEncryptedDataSegment([]byte(data))
+		seg.SetPosition(func() int { return 999 })
+
+		marshaled, err := seg.MarshalHBCI()
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expected := fmt.Sprintf("HNVSD:999:1+@%d@%s'", len(data), data)
+		if string(marshaled) != expected {
+			t.Logf("Expected marshaled segment to equal\n%q\n\tgot\n%q\n", expected, marshaled)
+			t.Fail()
+		}
+	}
+}
+
+// benchmarkAccountTransactionResponse returns an account transaction response
+// containing a large MT940 statement
+func benchmarkAccountTransactionResponse(b *testing.B) *segment {
+	mt940 := mt940Fixture(2000)
+	fixture := fmt.Sprintf("HIKAZ:5:7:3+@%d@%s'", len(mt940), mt940)
+	return innerSegment(b, unmarshalFixture(b, VersionedSegment{"HIKAZ", 7}, fixture))
+}
+
+func BenchmarkSegmentMarshalHBCI(b *testing.B) {
+	seg := benchmarkAccountTransactionResponse(b)
+	b.Run("generated", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := seg.MarshalHBCI(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("reflection", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := seg.validate(); err != nil {
+				b.Fatal(err)
+			}
+			header, err := seg.header.MarshalHBCI()
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := seg.reflectMarshalHBCI(header); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// reflectMarshalHBCI marshals s by reflection. It is the reference the
+// generated marshalers are checked against.
+func (s *segment) reflectMarshalHBCI(headerBytes []byte) ([]byte, error) {
+	elementBytes := make([][]byte, len(s.segment.elements())+1)
+	elementBytes[0] = headerBytes
+	for i, de := range s.segment.elements() {
+		val := reflect.ValueOf(de)
+		if val.IsValid() && !val.IsNil() {
+			marshaled, err := de.MarshalHBCI()
+			if err != nil {
+				return nil, err
+			}
+			elementBytes[i+1] = marshaled
+		}
+	}
+	for len(elementBytes) > 1 && len(elementBytes[len(elementBytes)-1]) == 0 {
+		elementBytes = elementBytes[:len(elementBytes)-1]
+	}
+	marshaled := bytes.Join(elementBytes, []byte("+"))
+	marshaled = append(marshaled, '\'')
+	return marshaled, nil
+}
+
+// reflectString is the reference of String for the generated marshalers
+func (s *segment) reflectString() string {
+	elementStrings := make([]string, len(s.segment.elements())+1)
+	elementStrings[0] = s.header.String()
+	for i, de := range s.segment.elements() {
+		val := reflect.ValueOf(de)
+		if val.IsValid() && !val.IsNil() {
+			elementStrings[i+1] = de.String()
+		}
+	}
+	return strings.Join(elementStrings, "+") + "'"
+}
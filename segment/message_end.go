@@ -2,6 +2,8 @@ package segment
 
 import "github.com/mitch000001/go-hbci/element"
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments MessageEndSegment
+
 func NewMessageEndSegment(segmentNumber, messageNumber int) *MessageEndSegment {
 	end := &MessageEndSegment{
 		Number: element.NewNumber(messageNumber, 4),
@@ -14,7 +16,7 @@ func NewMessageEndSegment(segmentNumber, messageNumber int) *MessageEndSegment {
 
 type MessageEndSegment struct {
 	ClientSegment
-	Number *element.NumberDataElement
+	Number *element.NumberDataElement `hbci:"required"`
 }
 
 func (m *MessageEndSegment) Version() int         { return 1 }
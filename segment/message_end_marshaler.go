@@ -0,0 +1,32 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (m *MessageEndSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if m.Number != nil {
+		marshaled, err := m.Number.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (m *MessageEndSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if m.Number != nil {
+		buf.WriteString(m.Number.String())
+	}
+}
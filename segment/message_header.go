@@ -5,6 +5,8 @@ import (
 	"github.com/mitch000001/go-hbci/element"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments MessageHeaderSegment
+
 const MessageHeaderID string = "HNHBK"
 
 func NewReferencingMessageHeaderSegment(size int, hbciVersion int, dialogId string, number int, referencingMessage domain.MessageReference) *MessageHeaderSegment {
@@ -28,10 +30,10 @@ func NewMessageHeaderSegment(size int, hbciVersion int, dialogId string, number
 
 type MessageHeaderSegment struct {
 	ClientSegment
-	Size        *element.DigitDataElement
-	HBCIVersion *element.NumberDataElement
-	DialogID    *element.IdentificationDataElement
-	Number      *element.NumberDataElement
+	Size        *element.DigitDataElement          `hbci:"required"`
+	HBCIVersion *element.NumberDataElement         `hbci:"required"`
+	DialogID    *element.IdentificationDataElement `hbci:"required"`
+	Number      *element.NumberDataElement         `hbci:"required"`
 	Ref         *element.ReferencingMessageDataElement
 }
 
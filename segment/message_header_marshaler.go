@@ -0,0 +1,100 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (m *MessageHeaderSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if m.Size != nil {
+		marshaled, err := m.Size.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if m.HBCIVersion != nil {
+		marshaled, err := m.HBCIVersion.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if m.DialogID != nil {
+		marshaled, err := m.DialogID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if m.Number != nil {
+		marshaled, err := m.Number.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if m.Ref != nil {
+		marshaled, err := m.Ref.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (m *MessageHeaderSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if m.Size != nil {
+		buf.WriteString(m.Size.String())
+	}
+	buf.WriteByte('+')
+	if m.HBCIVersion != nil {
+		buf.WriteString(m.HBCIVersion.String())
+	}
+	buf.WriteByte('+')
+	if m.DialogID != nil {
+		buf.WriteString(m.DialogID.String())
+	}
+	buf.WriteByte('+')
+	if m.Number != nil {
+		buf.WriteString(m.Number.String())
+	}
+	buf.WriteByte('+')
+	if m.Ref != nil {
+		buf.WriteString(m.Ref.String())
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"github.com/mitch000001/go-hbci/element"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments PinTanBankParameterV1
+
 const PinTanBankParameterID = "HIPINS"
 
 type PinTanBankParameter interface {
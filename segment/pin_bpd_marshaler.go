@@ -0,0 +1,83 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (p *PinTanBankParameterV1) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if p.MaxJobs != nil {
+		marshaled, err := p.MaxJobs.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.MinSignatures != nil {
+		marshaled, err := p.MinSignatures.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.XXX_Unknown != nil {
+		marshaled, err := p.XXX_Unknown.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if p.PinTanSpecificParams != nil {
+		marshaled, err := p.PinTanSpecificParams.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (p *PinTanBankParameterV1) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if p.MaxJobs != nil {
+		buf.WriteString(p.MaxJobs.String())
+	}
+	buf.WriteByte('+')
+	if p.MinSignatures != nil {
+		buf.WriteString(p.MinSignatures.String())
+	}
+	buf.WriteByte('+')
+	if p.XXX_Unknown != nil {
+		buf.WriteString(p.XXX_Unknown.String())
+	}
+	buf.WriteByte('+')
+	if p.PinTanSpecificParams != nil {
+		buf.WriteString(p.PinTanSpecificParams.String())
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"fmt"
 	"reflect"
-	"strings"
 
 	"github.com/mitch000001/go-hbci/element"
 )
@@ -43,6 +42,7 @@ type basicSegment interface {
 	referencedId() string
 	sender() string
 	elements() []element.DataElement
+	elementMarshaler
 }
 
 type Marshaler interface {
@@ -81,39 +81,83 @@ type segment struct {
 	header  *element.SegmentHeader
 }
 
+// elementMarshaler marshals the data elements following the segment header.
+// It is generated by cmd/marshaler for all segments whose data elements are
+// known at compile time.
+type elementMarshaler interface {
+	marshalHBCIElements(buf *bytes.Buffer) error
+	writeElementStrings(buf *bytes.Buffer)
+}
+
 func (s *segment) String() string {
-	elementStrings := make([]string, len(s.segment.elements())+1)
-	elementStrings[0] = s.header.String()
-	for i, de := range s.segment.elements() {
-		val := reflect.ValueOf(de)
-		if val.IsValid() && !val.IsNil() {
-			elementStrings[i+1] = de.String()
-		}
-	}
-	return strings.Join(elementStrings, "+") + "'"
+	var buf bytes.Buffer
+	buf.WriteString(s.header.String())
+	s.segment.writeElementStrings(&buf)
+	buf.WriteByte('\'')
+	return buf.String()
 }
 
 func (s *segment) MarshalHBCI() ([]byte, error) {
-	elementBytes := make([][]byte, len(s.segment.elements())+1)
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
 	headerBytes, err := s.header.MarshalHBCI()
 	if err != nil {
 		return nil, err
 	}
-	elementBytes[0] = headerBytes
-	for i, de := range s.segment.elements() {
-		val := reflect.ValueOf(de)
-		if val.IsValid() && !val.IsNil() {
-			marshaled, err := de.MarshalHBCI()
-			if err != nil {
-				return nil, err
+	var buf bytes.Buffer
+	buf.Write(headerBytes)
+	if err := s.segment.marshalHBCIElements(&buf); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\'')
+	return buf.Bytes(), nil
+}
+
+// marshalDataElements marshals elements like the generated marshalers do. It
+// is meant for segments whose data elements are only known at runtime.
+func marshalDataElements(buf *bytes.Buffer, elements []element.DataElement) error {
+	separators := 0
+	for _, de := range elements {
+		separators++
+		if isNil(de) {
+			continue
+		}
+		marshaled, err := de.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		// omit trailing empty data elements, trimming the separators would
+		// also cut binary data ending with a plus sign
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
 			}
-			elementBytes[i+1] = marshaled
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+// writeDataElementStrings is the counterpart of marshalDataElements for
+// String
+func writeDataElementStrings(buf *bytes.Buffer, elements []element.DataElement) {
+	for _, de := range elements {
+		buf.WriteByte('+')
+		if !isNil(de) {
+			buf.WriteString(de.String())
 		}
 	}
-	marshaled := bytes.Join(elementBytes, []byte("+"))
-	marshaled = bytes.TrimRight(marshaled, "+")
-	marshaled = append(marshaled, '\'')
-	return marshaled, nil
+}
+
+// isNil reports whether de is nil. Data elements only known at runtime may
+// be typed nil pointers, which can only be detected by reflection.
+func isNil(de element.DataElement) bool {
+	if de == nil {
+		return true
+	}
+	val := reflect.ValueOf(de)
+	return val.Kind() == reflect.Ptr && val.IsNil()
 }
 
 func (s *segment) DataElements() []element.DataElement {
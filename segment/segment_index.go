@@ -1,6 +1,9 @@
 package segment
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+)
 
 type VersionedSegment struct {
 	ID      string
@@ -11,12 +14,22 @@ func (v VersionedSegment) String() string {
 	return fmt.Sprintf("%s:%d", v.ID, v.Version)
 }
 
+// SegmentIndex maps versioned segments to functions providing new instances of
+// the segment types. It is safe for concurrent use.
 type SegmentIndex struct {
+	mu         sync.RWMutex
 	segmentMap map[VersionedSegment]func() Segment
 }
 
-func (s SegmentIndex) UnmarshalerForSegment(segmentId VersionedSegment) (Unmarshaler, error) {
+func (s *SegmentIndex) segmentFn(segmentId VersionedSegment) (func() Segment, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	segmentFn, ok := s.segmentMap[segmentId]
+	return segmentFn, ok
+}
+
+func (s *SegmentIndex) UnmarshalerForSegment(segmentId VersionedSegment) (Unmarshaler, error) {
+	segmentFn, ok := s.segmentFn(segmentId)
 	if ok {
 		unmarshaler, ok := segmentFn().(Unmarshaler)
 		if ok {
@@ -29,13 +42,13 @@ func (s SegmentIndex) UnmarshalerForSegment(segmentId VersionedSegment) (Unmarsh
 	}
 }
 
-func (s SegmentIndex) IsIndexed(segmentId VersionedSegment) bool {
-	_, ok := s.segmentMap[segmentId]
+func (s *SegmentIndex) IsIndexed(segmentId VersionedSegment) bool {
+	_, ok := s.segmentFn(segmentId)
 	return ok
 }
 
-func (s SegmentIndex) IsUnmarshaler(segmentId VersionedSegment) bool {
-	segmentFn, ok := s.segmentMap[segmentId]
+func (s *SegmentIndex) IsUnmarshaler(segmentId VersionedSegment) bool {
+	segmentFn, ok := s.segmentFn(segmentId)
 	if ok {
 		_, ok := segmentFn().(Unmarshaler)
 		return ok
@@ -44,52 +57,67 @@ func (s SegmentIndex) IsUnmarshaler(segmentId VersionedSegment) bool {
 	}
 }
 
-func (s *SegmentIndex) addToIndex(segmentIdentifier VersionedSegment, segmentProviderFn func() Segment) error {
-	if s.IsIndexed(segmentIdentifier) {
+// Register adds the segment identified by segmentIdentifier to the index.
+// segmentProviderFn must return a new instance of the segment type on every
+// call. Segments implementing Unmarshaler are unmarshaled from bank messages
+// instead of being kept as GenericSegment. Register returns an error if the
+// segment is already indexed.
+func (s *SegmentIndex) Register(segmentIdentifier VersionedSegment, segmentProviderFn func() Segment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.segmentMap[segmentIdentifier]; ok {
 		return fmt.Errorf("Segment already in index: %s", segmentIdentifier)
 	}
 	s.segmentMap[segmentIdentifier] = segmentProviderFn
 	return nil
 }
 
-func (s *SegmentIndex) mustAddToIndex(segmentIdentifier VersionedSegment, segmentProviderFn func() Segment) {
-	err := s.addToIndex(segmentIdentifier, segmentProviderFn)
+// MustRegister is like Register but panics if the segment is already indexed.
+// It is meant to be called within init functions.
+func (s *SegmentIndex) MustRegister(segmentIdentifier VersionedSegment, segmentProviderFn func() Segment) {
+	err := s.Register(segmentIdentifier, segmentProviderFn)
 	if err != nil {
 		panic(err)
 	}
 }
 
+// KnownSegments contains all segments bank messages are unmarshaled into
 var KnownSegments = SegmentIndex{segmentMap: make(map[VersionedSegment]func() Segment)}
 
 func init() {
-	KnownSegments.mustAddToIndex(VersionedSegment{MessageHeaderID, 3}, func() Segment { return &MessageHeaderSegment{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{"HNHBS", 1}, func() Segment { return &MessageEndSegment{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{EncryptionHeaderSegmentID, 2}, func() Segment { return &EncryptionHeaderV2{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{EncryptionHeaderSegmentID, 3}, func() Segment { return &EncryptionHeaderSegmentV3{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{"HNVSD", 1}, func() Segment { return &EncryptedDataSegment{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{"HIRMG", 2}, func() Segment { return &MessageAcknowledgement{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{"HIRMS", 2}, func() Segment { return &SegmentAcknowledgement{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{"HISYN", 3}, func() Segment { return &SynchronisationResponseSegmentV3{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{"HISYN", 4}, func() Segment { return &SynchronisationResponseSegmentV4{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{BankAnnouncementID, 2}, func() Segment { return &BankAnnouncementSegment{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{CommonBankParameterID, 2}, func() Segment { return &CommonBankParameterV2{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{CommonBankParameterID, 3}, func() Segment { return &CommonBankParameterV3{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{PinTanBusinessTransactionParamsID, 1}, func() Segment { return &PinTanBusinessTransactionParamsSegment{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{PinTanBankParameterID, 1}, func() Segment { return &PinTanBankParameterV1{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{CommonUserParameterDataID, 2}, func() Segment { return &CommonUserParameterDataV2{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{CommonUserParameterDataID, 3}, func() Segment { return &CommonUserParameterDataV3{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{CommonUserParameterDataID, 4}, func() Segment { return &CommonUserParameterDataV4{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{AccountInformationID, 4}, func() Segment { return &AccountInformationV4{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{AccountInformationID, 5}, func() Segment { return &AccountInformationV5{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{AccountInformationID, 6}, func() Segment { return &AccountInformationV6{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{AccountInformationID, 7}, func() Segment { return &AccountInformationV7{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{"HISAL", 5}, func() Segment { return &AccountBalanceResponseSegment{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{"HIKIF", 1}, func() Segment { return &AccountInformationResponseSegment{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{"HIKAZ", 5}, func() Segment { return &AccountTransactionResponseSegmentV5{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{"HIKAZ", 6}, func() Segment { return &AccountTransactionResponseSegmentV6{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{"HIKAZ", 7}, func() Segment { return &AccountTransactionResponseSegmentV7{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{"HIPRO", 3}, func() Segment { return &StatusProtocolResponseSegmentV3{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{"HIPRO", 4}, func() Segment { return &StatusProtocolResponseSegmentV4{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{TanBankParameterID, 6}, func() Segment { return &TanBankParameterV6{} })
-	KnownSegments.mustAddToIndex(VersionedSegment{"HITAN", 6}, func() Segment { return &TanResponseSegmentV6{} })
+	KnownSegments.MustRegister(VersionedSegment{MessageHeaderID, 3}, func() Segment { return &MessageHeaderSegment{} })
+	KnownSegments.MustRegister(VersionedSegment{"HNHBS", 1}, func() Segment { return &MessageEndSegment{} })
+	KnownSegments.MustRegister(VersionedSegment{EncryptionHeaderSegmentID, 2}, func() Segment { return &EncryptionHeaderV2{} })
+	KnownSegments.MustRegister(VersionedSegment{EncryptionHeaderSegmentID, 3}, func() Segment { return &EncryptionHeaderSegmentV3{} })
+	KnownSegments.MustRegister(VersionedSegment{"HNVSD", 1}, func() Segment { return &EncryptedDataSegment{} })
+	KnownSegments.MustRegister(VersionedSegment{"HIRMG", 2}, func() Segment { return &MessageAcknowledgement{} })
+	KnownSegments.MustRegister(VersionedSegment{"HIRMS", 2}, func() Segment { return &SegmentAcknowledgement{} })
+	KnownSegments.MustRegister(VersionedSegment{"HIISA", 2}, func() Segment { return &PublicKeyTransmissionSegment{} })
+	KnownSegments.MustRegister(VersionedSegment{"HISYN", 3}, func() Segment { return &SynchronisationResponseSegmentV3{} })
+	KnownSegments.MustRegister(VersionedSegment{"HISYN", 4}, func() Segment { return &SynchronisationResponseSegmentV4{} })
+	KnownSegments.MustRegister(VersionedSegment{BankAnnouncementID, 2}, func() Segment { return &BankAnnouncementSegment{} })
+	KnownSegments.MustRegister(VersionedSegment{CommonBankParameterID, 2}, func() Segment { return &CommonBankParameterV2{} })
+	KnownSegments.MustRegister(VersionedSegment{CommonBankParameterID, 3}, func() Segment { return &CommonBankParameterV3{} })
+	KnownSegments.MustRegister(VersionedSegment{PinTanBusinessTransactionParamsID, 1}, func() Segment { return &PinTanBusinessTransactionParamsSegment{} })
+	KnownSegments.MustRegister(VersionedSegment{PinTanBankParameterID, 1}, func() Segment { return &PinTanBankParameterV1{} })
+	KnownSegments.MustRegister(VersionedSegment{CompressionMethodID, 1}, func() Segment { return &CompressionMethodSegment{} })
+	for version := 4; version <= 7; version++ {
+		KnownSegments.MustRegister(VersionedSegment{AccountTransactionParametersID, version}, func() Segment { return &AccountTransactionParameterSegment{} })
+	}
+	KnownSegments.MustRegister(VersionedSegment{CommonUserParameterDataID, 2}, func() Segment { return &CommonUserParameterDataV2{} })
+	KnownSegments.MustRegister(VersionedSegment{CommonUserParameterDataID, 3}, func() Segment { return &CommonUserParameterDataV3{} })
+	KnownSegments.MustRegister(VersionedSegment{CommonUserParameterDataID, 4}, func() Segment { return &CommonUserParameterDataV4{} })
+	KnownSegments.MustRegister(VersionedSegment{AccountInformationID, 4}, func() Segment { return &AccountInformationV4{} })
+	KnownSegments.MustRegister(VersionedSegment{AccountInformationID, 5}, func() Segment { return &AccountInformationV5{} })
+	KnownSegments.MustRegister(VersionedSegment{AccountInformationID, 6}, func() Segment { return &AccountInformationV6{} })
+	KnownSegments.MustRegister(VersionedSegment{AccountInformationID, 7}, func() Segment { return &AccountInformationV7{} })
+	KnownSegments.MustRegister(VersionedSegment{"HISAL", 5}, func() Segment { return &AccountBalanceResponseSegment{} })
+	KnownSegments.MustRegister(VersionedSegment{"HIKIF", 1}, func() Segment { return &AccountInformationResponseSegment{} })
+	KnownSegments.MustRegister(VersionedSegment{"HIKAZ", 5}, func() Segment { return &AccountTransactionResponseSegmentV5{} })
+	KnownSegments.MustRegister(VersionedSegment{"HIKAZ", 6}, func() Segment { return &AccountTransactionResponseSegmentV6{} })
+	KnownSegments.MustRegister(VersionedSegment{"HIKAZ", 7}, func() Segment { return &AccountTransactionResponseSegmentV7{} })
+	KnownSegments.MustRegister(VersionedSegment{"HIPRO", 3}, func() Segment { return &StatusProtocolResponseSegmentV3{} })
+	KnownSegments.MustRegister(VersionedSegment{"HIPRO", 4}, func() Segment { return &StatusProtocolResponseSegmentV4{} })
+	KnownSegments.MustRegister(VersionedSegment{TanBankParameterID, 6}, func() Segment { return &TanBankParameterV6{} })
+	KnownSegments.MustRegister(VersionedSegment{"HITAN", 6}, func() Segment { return &TanResponseSegmentV6{} })
 }
@@ -2,6 +2,8 @@ package segment
 
 import "github.com/mitch000001/go-hbci/element"
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments SignatureEndV1,SignatureEndV2
+
 type SignatureEnd interface {
 	SetControlReference(controlReference string)
 	SetSignature(signature []byte)
@@ -10,7 +12,7 @@ type SignatureEnd interface {
 
 func NewSignatureEndSegmentV1() *SignatureEndSegment {
 	s := &SignatureEndV1{}
-	s.ClientSegment = NewBasicSegment(-1, s)
+	s.ClientSegment = NewBasicSegment(0, s)
 
 	segment := &SignatureEndSegment{
 		signatureEndSegment: s,
@@ -32,7 +34,7 @@ type SignatureEndSegment struct {
 
 type SignatureEndV1 struct {
 	ClientSegment
-	SecurityControlRef *element.AlphaNumericDataElement
+	SecurityControlRef *element.AlphaNumericDataElement `hbci:"required"`
 	Signature          *element.BinaryDataElement
 	PinTan             *element.PinTanDataElement
 }
@@ -63,7 +65,7 @@ func (s *SignatureEndV1) SetPinTan(pin, tan string) {
 }
 func NewSignatureEndSegmentV2() *SignatureEndSegment {
 	s := &SignatureEndV2{}
-	s.ClientSegment = NewBasicSegment(-1, s)
+	s.ClientSegment = NewBasicSegment(0, s)
 
 	segment := &SignatureEndSegment{
 		signatureEndSegment: s,
@@ -73,7 +75,7 @@ func NewSignatureEndSegmentV2() *SignatureEndSegment {
 
 type SignatureEndV2 struct {
 	ClientSegment
-	SecurityControlRef *element.AlphaNumericDataElement
+	SecurityControlRef *element.AlphaNumericDataElement `hbci:"required"`
 	Signature          *element.BinaryDataElement
 	CustomSignature    *element.CustomSignatureDataElement
 }
@@ -0,0 +1,125 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (s *SignatureEndV1) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if s.SecurityControlRef != nil {
+		marshaled, err := s.SecurityControlRef.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.Signature != nil {
+		marshaled, err := s.Signature.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.PinTan != nil {
+		marshaled, err := s.PinTan.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (s *SignatureEndV1) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if s.SecurityControlRef != nil {
+		buf.WriteString(s.SecurityControlRef.String())
+	}
+	buf.WriteByte('+')
+	if s.Signature != nil {
+		buf.WriteString(s.Signature.String())
+	}
+	buf.WriteByte('+')
+	if s.PinTan != nil {
+		buf.WriteString(s.PinTan.String())
+	}
+}
+
+func (s *SignatureEndV2) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if s.SecurityControlRef != nil {
+		marshaled, err := s.SecurityControlRef.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.Signature != nil {
+		marshaled, err := s.Signature.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.CustomSignature != nil {
+		marshaled, err := s.CustomSignature.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (s *SignatureEndV2) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if s.SecurityControlRef != nil {
+		buf.WriteString(s.SecurityControlRef.String())
+	}
+	buf.WriteByte('+')
+	if s.Signature != nil {
+		buf.WriteString(s.Signature.String())
+	}
+	buf.WriteByte('+')
+	if s.CustomSignature != nil {
+		buf.WriteString(s.CustomSignature.String())
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/mitch000001/go-hbci/element"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments SignatureHeaderV3,SignatureHeaderSegmentV4
+
 type SignatureHeader interface {
 	ClientSegment
 	SetClientSystemID(clientSystemID string)
@@ -14,6 +16,11 @@ type SignatureHeader interface {
 	SetSignatureID(signatureId int)
 	SetSecurityFunction(string)
 	SetControlReference(string)
+	// SetSecurityMethod sets the security profile, e.g. "RDH" and 10. It is
+	// a no-op for versions without a security profile.
+	SetSecurityMethod(method string, version int)
+	SetHashAlgorithm(algorithm *element.HashAlgorithmDataElement)
+	SetSignatureAlgorithm(algorithm *element.SignatureAlgorithmDataElement)
 }
 
 func NewSignatureHeaderSegmentV3() *SignatureHeaderSegment {
@@ -90,21 +97,21 @@ type SignatureHeaderV3 struct {
 	// "1" for NRO, Non-Repudiation of Origin (RDH)
 	// "2" for AUT, Message Origin Authentication (DDV)
 	// "999" for PIN/TAN
-	SecurityFunction   *element.AlphaNumericDataElement
-	SecurityControlRef *element.AlphaNumericDataElement
+	SecurityFunction   *element.AlphaNumericDataElement `hbci:"required"`
+	SecurityControlRef *element.AlphaNumericDataElement `hbci:"required"`
 	// "1" for SHM (SignatureHeader and HBCI-Data)
 	// "2" for SHT (SignatureHeader to SignatureEnd)
-	SecurityApplicationRange *element.AlphaNumericDataElement
+	SecurityApplicationRange *element.AlphaNumericDataElement `hbci:"required"`
 	// "1" for ISS, Herausgeber der signierten Nachricht (z.B. Erfasser oder Erstsignatur)
 	// "3" for CON, der Unterzeichnete unterstützt den Inhalt der Nachricht (z.B. bei Zweitsignatur)
 	// "4" for WIT, der Unterzeichnete ist Zeuge (z.B. Übermittler), aber für den Inhalt der Nachricht nicht verantwortlich)
-	SecuritySupplierRole *element.AlphaNumericDataElement
-	SecurityID           *element.SecurityIdentificationDataElement
-	SecurityRefNumber    *element.NumberDataElement
-	SecurityDate         *element.SecurityDateDataElement
-	HashAlgorithm        *element.HashAlgorithmDataElement
-	SignatureAlgorithm   *element.SignatureAlgorithmDataElement
-	KeyName              *element.KeyNameDataElement
+	SecuritySupplierRole *element.AlphaNumericDataElement           `hbci:"required"`
+	SecurityID           *element.SecurityIdentificationDataElement `hbci:"required"`
+	SecurityRefNumber    *element.NumberDataElement                 `hbci:"required"`
+	SecurityDate         *element.SecurityDateDataElement           `hbci:"required"`
+	HashAlgorithm        *element.HashAlgorithmDataElement          `hbci:"required"`
+	SignatureAlgorithm   *element.SignatureAlgorithmDataElement     `hbci:"required"`
+	KeyName              *element.KeyNameDataElement                `hbci:"required"`
 	Certificate          *element.CertificateDataElement
 }
 
@@ -128,6 +135,18 @@ func (s *SignatureHeaderV3) SetSignatureID(signatureId int) {
 	s.SecurityRefNumber = element.NewNumber(signatureId, 16)
 }
 
+func (s *SignatureHeaderV3) SetSecurityMethod(method string, version int) {
+	// NO OP
+}
+
+func (s *SignatureHeaderV3) SetHashAlgorithm(algorithm *element.HashAlgorithmDataElement) {
+	s.HashAlgorithm = algorithm
+}
+
+func (s *SignatureHeaderV3) SetSignatureAlgorithm(algorithm *element.SignatureAlgorithmDataElement) {
+	s.SignatureAlgorithm = algorithm
+}
+
 func (s *SignatureHeaderV3) Version() int         { return 3 }
 func (s *SignatureHeaderV3) ID() string           { return "HNSHK" }
 func (s *SignatureHeaderV3) referencedId() string { return "" }
@@ -173,6 +192,7 @@ func NewPinTanSignatureHeaderSegmentV4(controlReference string, clientSystemId s
 		SecurityApplicationRange: element.NewCode("1", 3, []string{"1", "2"}),
 		SecuritySupplierRole:     element.NewCode("1", 3, []string{"1", "3", "4"}),
 		SecurityID:               element.NewRDHSecurityIdentification(element.SecurityHolderMessageSender, clientSystemId),
+		SecurityRefNumber:        element.NewNumber(0, 16),
 		SecurityDate:             element.NewSecurityDate(element.SecurityTimestamp, time.Now()),
 		HashAlgorithm:            element.NewDefaultHashAlgorithm(),
 		SignatureAlgorithm:       element.NewRDHSignatureAlgorithm(),
@@ -186,27 +206,51 @@ func NewPinTanSignatureHeaderSegmentV4(controlReference string, clientSystemId s
 	return segment
 }
 
+// NewRDHSignatureHeaderSegmentV4 creates a signature header for RDH-10,
+// using SHA-256 and RSASSA-PSS
+func NewRDHSignatureHeaderSegmentV4(controlReference string, signatureId int, clientSystemId string, keyName domain.KeyName) *SignatureHeaderSegment {
+	s := &SignatureHeaderSegmentV4{
+		SecurityProfile:          element.NewSecurityProfile("RDH", 10),
+		SecurityFunction:         element.NewCode("1", 3, []string{"1", "2", "999"}),
+		SecurityControlRef:       element.NewAlphaNumeric(controlReference, 14),
+		SecurityApplicationRange: element.NewCode("1", 3, []string{"1", "2"}),
+		SecuritySupplierRole:     element.NewCode("1", 3, []string{"1", "3", "4"}),
+		SecurityID:               element.NewRDHSecurityIdentification(element.SecurityHolderMessageSender, clientSystemId),
+		SecurityRefNumber:        element.NewNumber(signatureId, 16),
+		SecurityDate:             element.NewSecurityDate(element.SecurityTimestamp, time.Now()),
+		HashAlgorithm:            element.NewHashAlgorithm("3"),
+		SignatureAlgorithm:       element.NewRSASignatureAlgorithm("19"),
+		KeyName:                  element.NewKeyName(keyName),
+	}
+	s.ClientSegment = NewBasicSegment(2, s)
+
+	segment := &SignatureHeaderSegment{
+		signatureHeaderSegment: s,
+	}
+	return segment
+}
+
 type SignatureHeaderSegmentV4 struct {
 	ClientSegment
-	SecurityProfile *element.SecurityProfileDataElement
+	SecurityProfile *element.SecurityProfileDataElement `hbci:"required"`
 	// "1" for NRO, Non-Repudiation of Origin (RDH)
 	// "2" for AUT, Message Origin Authentication (DDV)
 	// "999" for PIN/TAN
-	SecurityFunction   *element.CodeDataElement
-	SecurityControlRef *element.AlphaNumericDataElement
+	SecurityFunction   *element.CodeDataElement         `hbci:"required"`
+	SecurityControlRef *element.AlphaNumericDataElement `hbci:"required"`
 	// "1" for SHM (SignatureHeader and HBCI-Data)
 	// "2" for SHT (SignatureHeader to SignatureEnd)
-	SecurityApplicationRange *element.CodeDataElement
+	SecurityApplicationRange *element.CodeDataElement `hbci:"required"`
 	// "1" for ISS, Herausgeber der signierten Nachricht (z.B. Erfasser oder Erstsignatur)
 	// "3" for CON, der Unterzeichnete unterstützt den Inhalt der Nachricht (z.B. bei Zweitsignatur)
 	// "4" for WIT, der Unterzeichnete ist Zeuge (z.B. Übermittler), aber für den Inhalt der Nachricht nicht verantwortlich)
-	SecuritySupplierRole *element.CodeDataElement
-	SecurityID           *element.SecurityIdentificationDataElement
-	SecurityRefNumber    *element.NumberDataElement
-	SecurityDate         *element.SecurityDateDataElement
-	HashAlgorithm        *element.HashAlgorithmDataElement
-	SignatureAlgorithm   *element.SignatureAlgorithmDataElement
-	KeyName              *element.KeyNameDataElement
+	SecuritySupplierRole *element.CodeDataElement                   `hbci:"required"`
+	SecurityID           *element.SecurityIdentificationDataElement `hbci:"required"`
+	SecurityRefNumber    *element.NumberDataElement                 `hbci:"required"`
+	SecurityDate         *element.SecurityDateDataElement           `hbci:"required"`
+	HashAlgorithm        *element.HashAlgorithmDataElement          `hbci:"required"`
+	SignatureAlgorithm   *element.SignatureAlgorithmDataElement     `hbci:"required"`
+	KeyName              *element.KeyNameDataElement                `hbci:"required"`
 	Certificate          *element.CertificateDataElement
 }
 
@@ -235,6 +279,18 @@ func (s *SignatureHeaderSegmentV4) SetSignatureID(signatureId int) {
 	s.SecurityRefNumber = element.NewNumber(signatureId, 16)
 }
 
+func (s *SignatureHeaderSegmentV4) SetSecurityMethod(method string, version int) {
+	s.SecurityProfile = element.NewSecurityProfile(method, version)
+}
+
+func (s *SignatureHeaderSegmentV4) SetHashAlgorithm(algorithm *element.HashAlgorithmDataElement) {
+	s.HashAlgorithm = algorithm
+}
+
+func (s *SignatureHeaderSegmentV4) SetSignatureAlgorithm(algorithm *element.SignatureAlgorithmDataElement) {
+	s.SignatureAlgorithm = algorithm
+}
+
 func (s *SignatureHeaderSegmentV4) Version() int         { return 4 }
 func (s *SignatureHeaderSegmentV4) ID() string           { return "HNSHK" }
 func (s *SignatureHeaderSegmentV4) referencedId() string { return "" }
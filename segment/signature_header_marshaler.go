@@ -0,0 +1,414 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (s *SignatureHeaderV3) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if s.SecurityFunction != nil {
+		marshaled, err := s.SecurityFunction.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SecurityControlRef != nil {
+		marshaled, err := s.SecurityControlRef.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SecurityApplicationRange != nil {
+		marshaled, err := s.SecurityApplicationRange.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SecuritySupplierRole != nil {
+		marshaled, err := s.SecuritySupplierRole.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SecurityID != nil {
+		marshaled, err := s.SecurityID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SecurityRefNumber != nil {
+		marshaled, err := s.SecurityRefNumber.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SecurityDate != nil {
+		marshaled, err := s.SecurityDate.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.HashAlgorithm != nil {
+		marshaled, err := s.HashAlgorithm.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SignatureAlgorithm != nil {
+		marshaled, err := s.SignatureAlgorithm.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.KeyName != nil {
+		marshaled, err := s.KeyName.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.Certificate != nil {
+		marshaled, err := s.Certificate.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (s *SignatureHeaderV3) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if s.SecurityFunction != nil {
+		buf.WriteString(s.SecurityFunction.String())
+	}
+	buf.WriteByte('+')
+	if s.SecurityControlRef != nil {
+		buf.WriteString(s.SecurityControlRef.String())
+	}
+	buf.WriteByte('+')
+	if s.SecurityApplicationRange != nil {
+		buf.WriteString(s.SecurityApplicationRange.String())
+	}
+	buf.WriteByte('+')
+	if s.SecuritySupplierRole != nil {
+		buf.WriteString(s.SecuritySupplierRole.String())
+	}
+	buf.WriteByte('+')
+	if s.SecurityID != nil {
+		buf.WriteString(s.SecurityID.String())
+	}
+	buf.WriteByte('+')
+	if s.SecurityRefNumber != nil {
+		buf.WriteString(s.SecurityRefNumber.String())
+	}
+	buf.WriteByte('+')
+	if s.SecurityDate != nil {
+		buf.WriteString(s.SecurityDate.String())
+	}
+	buf.WriteByte('+')
+	if s.HashAlgorithm != nil {
+		buf.WriteString(s.HashAlgorithm.String())
+	}
+	buf.WriteByte('+')
+	if s.SignatureAlgorithm != nil {
+		buf.WriteString(s.SignatureAlgorithm.String())
+	}
+	buf.WriteByte('+')
+	if s.KeyName != nil {
+		buf.WriteString(s.KeyName.String())
+	}
+	buf.WriteByte('+')
+	if s.Certificate != nil {
+		buf.WriteString(s.Certificate.String())
+	}
+}
+
+func (s *SignatureHeaderSegmentV4) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if s.SecurityProfile != nil {
+		marshaled, err := s.SecurityProfile.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SecurityFunction != nil {
+		marshaled, err := s.SecurityFunction.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SecurityControlRef != nil {
+		marshaled, err := s.SecurityControlRef.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SecurityApplicationRange != nil {
+		marshaled, err := s.SecurityApplicationRange.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SecuritySupplierRole != nil {
+		marshaled, err := s.SecuritySupplierRole.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SecurityID != nil {
+		marshaled, err := s.SecurityID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SecurityRefNumber != nil {
+		marshaled, err := s.SecurityRefNumber.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SecurityDate != nil {
+		marshaled, err := s.SecurityDate.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.HashAlgorithm != nil {
+		marshaled, err := s.HashAlgorithm.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SignatureAlgorithm != nil {
+		marshaled, err := s.SignatureAlgorithm.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.KeyName != nil {
+		marshaled, err := s.KeyName.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.Certificate != nil {
+		marshaled, err := s.Certificate.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (s *SignatureHeaderSegmentV4) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if s.SecurityProfile != nil {
+		buf.WriteString(s.SecurityProfile.String())
+	}
+	buf.WriteByte('+')
+	if s.SecurityFunction != nil {
+		buf.WriteString(s.SecurityFunction.String())
+	}
+	buf.WriteByte('+')
+	if s.SecurityControlRef != nil {
+		buf.WriteString(s.SecurityControlRef.String())
+	}
+	buf.WriteByte('+')
+	if s.SecurityApplicationRange != nil {
+		buf.WriteString(s.SecurityApplicationRange.String())
+	}
+	buf.WriteByte('+')
+	if s.SecuritySupplierRole != nil {
+		buf.WriteString(s.SecuritySupplierRole.String())
+	}
+	buf.WriteByte('+')
+	if s.SecurityID != nil {
+		buf.WriteString(s.SecurityID.String())
+	}
+	buf.WriteByte('+')
+	if s.SecurityRefNumber != nil {
+		buf.WriteString(s.SecurityRefNumber.String())
+	}
+	buf.WriteByte('+')
+	if s.SecurityDate != nil {
+		buf.WriteString(s.SecurityDate.String())
+	}
+	buf.WriteByte('+')
+	if s.HashAlgorithm != nil {
+		buf.WriteString(s.HashAlgorithm.String())
+	}
+	buf.WriteByte('+')
+	if s.SignatureAlgorithm != nil {
+		buf.WriteString(s.SignatureAlgorithm.String())
+	}
+	buf.WriteByte('+')
+	if s.KeyName != nil {
+		buf.WriteString(s.KeyName.String())
+	}
+	buf.WriteByte('+')
+	if s.Certificate != nil {
+		buf.WriteString(s.Certificate.String())
+	}
+}
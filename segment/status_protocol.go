@@ -9,6 +9,8 @@ import (
 	"github.com/mitch000001/go-hbci/element"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments StatusProtocolRequestSegmentV3,StatusProtocolRequestSegmentV4,StatusProtocolResponseSegmentV3,StatusProtocolResponseSegmentV4
+
 func StatusProtocolRequestBuilder(versions []int) (func(from, to time.Time, maxEntries int, continuationReference string) StatusProtocolRequest, error) {
 	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
 	for _, version := range versions {
@@ -26,6 +28,7 @@ func StatusProtocolRequestBuilder(versions []int) (func(from, to time.Time, maxE
 
 type StatusProtocolRequest interface {
 	ClientSegment
+	SetContinuationReference(continuationReference string)
 }
 
 func NewStatusProtocolRequestV3(from, to time.Time, maxEntries int, continuationReference string) StatusProtocolRequest {
@@ -63,6 +66,10 @@ func (s *StatusProtocolRequestSegmentV3) elements() []element.DataElement {
 	}
 }
 
+func (s *StatusProtocolRequestSegmentV3) SetContinuationReference(continuationReference string) {
+	s.ContinuationReference = element.NewAlphaNumeric(continuationReference, 35)
+}
+
 func NewStatusProtocolRequestV4(from, to time.Time, maxEntries int, continuationReference string) StatusProtocolRequest {
 	s := &StatusProtocolRequestSegmentV4{
 		From:       element.NewDate(from),
@@ -98,12 +105,16 @@ func (s *StatusProtocolRequestSegmentV4) elements() []element.DataElement {
 	}
 }
 
+func (s *StatusProtocolRequestSegmentV4) SetContinuationReference(continuationReference string) {
+	s.ContinuationReference = element.NewAlphaNumeric(continuationReference, 35)
+}
+
 type StatusProtocolResponse interface {
 	BankSegment
 	Status() domain.StatusAcknowledgement
 }
 
-//go:generate go run ../cmd/unmarshaler/unmarshaler_generator.go -segment StatusProtocolResponseSegment -segment_interface StatusProtocolResponse -segment_versions="StatusProtocolResponseSegmentV3:3:Segment"
+//go:generate go run ../cmd/unmarshaler/unmarshaler_generator.go -segment StatusProtocolResponseSegment -segment_interface StatusProtocolResponse -segment_versions="StatusProtocolResponseSegmentV3:3:Segment,StatusProtocolResponseSegmentV4:4:Segment"
 
 type StatusProtocolResponseSegment struct {
 	StatusProtocolResponse
@@ -0,0 +1,345 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (s *StatusProtocolRequestSegmentV3) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if s.From != nil {
+		marshaled, err := s.From.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.To != nil {
+		marshaled, err := s.To.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.MaxEntries != nil {
+		marshaled, err := s.MaxEntries.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.ContinuationReference != nil {
+		marshaled, err := s.ContinuationReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (s *StatusProtocolRequestSegmentV3) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if s.From != nil {
+		buf.WriteString(s.From.String())
+	}
+	buf.WriteByte('+')
+	if s.To != nil {
+		buf.WriteString(s.To.String())
+	}
+	buf.WriteByte('+')
+	if s.MaxEntries != nil {
+		buf.WriteString(s.MaxEntries.String())
+	}
+	buf.WriteByte('+')
+	if s.ContinuationReference != nil {
+		buf.WriteString(s.ContinuationReference.String())
+	}
+}
+
+func (s *StatusProtocolRequestSegmentV4) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if s.From != nil {
+		marshaled, err := s.From.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.To != nil {
+		marshaled, err := s.To.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.MaxEntries != nil {
+		marshaled, err := s.MaxEntries.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.ContinuationReference != nil {
+		marshaled, err := s.ContinuationReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (s *StatusProtocolRequestSegmentV4) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if s.From != nil {
+		buf.WriteString(s.From.String())
+	}
+	buf.WriteByte('+')
+	if s.To != nil {
+		buf.WriteString(s.To.String())
+	}
+	buf.WriteByte('+')
+	if s.MaxEntries != nil {
+		buf.WriteString(s.MaxEntries.String())
+	}
+	buf.WriteByte('+')
+	if s.ContinuationReference != nil {
+		buf.WriteString(s.ContinuationReference.String())
+	}
+}
+
+func (s *StatusProtocolResponseSegmentV3) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if s.ReferencingMessage != nil {
+		marshaled, err := s.ReferencingMessage.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.ReferencingSegment != nil {
+		marshaled, err := s.ReferencingSegment.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.Date != nil {
+		marshaled, err := s.Date.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.Time != nil {
+		marshaled, err := s.Time.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.Acknowledgement != nil {
+		marshaled, err := s.Acknowledgement.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (s *StatusProtocolResponseSegmentV3) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if s.ReferencingMessage != nil {
+		buf.WriteString(s.ReferencingMessage.String())
+	}
+	buf.WriteByte('+')
+	if s.ReferencingSegment != nil {
+		buf.WriteString(s.ReferencingSegment.String())
+	}
+	buf.WriteByte('+')
+	if s.Date != nil {
+		buf.WriteString(s.Date.String())
+	}
+	buf.WriteByte('+')
+	if s.Time != nil {
+		buf.WriteString(s.Time.String())
+	}
+	buf.WriteByte('+')
+	if s.Acknowledgement != nil {
+		buf.WriteString(s.Acknowledgement.String())
+	}
+}
+
+func (s *StatusProtocolResponseSegmentV4) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if s.ReferencingMessage != nil {
+		marshaled, err := s.ReferencingMessage.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.ReferencingSegment != nil {
+		marshaled, err := s.ReferencingSegment.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.Date != nil {
+		marshaled, err := s.Date.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.Time != nil {
+		marshaled, err := s.Time.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.Acknowledgement != nil {
+		marshaled, err := s.Acknowledgement.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (s *StatusProtocolResponseSegmentV4) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if s.ReferencingMessage != nil {
+		buf.WriteString(s.ReferencingMessage.String())
+	}
+	buf.WriteByte('+')
+	if s.ReferencingSegment != nil {
+		buf.WriteString(s.ReferencingSegment.String())
+	}
+	buf.WriteByte('+')
+	if s.Date != nil {
+		buf.WriteString(s.Date.String())
+	}
+	buf.WriteByte('+')
+	if s.Time != nil {
+		buf.WriteString(s.Time.String())
+	}
+	buf.WriteByte('+')
+	if s.Acknowledgement != nil {
+		buf.WriteString(s.Acknowledgement.String())
+	}
+}
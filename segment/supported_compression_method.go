@@ -2,15 +2,22 @@ package segment
 
 import "github.com/mitch000001/go-hbci/element"
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments CompressionMethodSegment
+
+// CompressionMethodID is the ID of the CompressionMethodSegment
+const CompressionMethodID = "HIKPV"
+
 //go:generate go run ../cmd/unmarshaler/unmarshaler_generator.go -segment CompressionMethodSegment
 
+// CompressionMethodSegment contains the compression functions supported by
+// the institute
 type CompressionMethodSegment struct {
 	Segment
 	SupportedCompressionMethods *element.SupportedCompressionMethodsDataElement
 }
 
 func (c *CompressionMethodSegment) Version() int         { return 1 }
-func (c *CompressionMethodSegment) ID() string           { return "HIKPV" }
+func (c *CompressionMethodSegment) ID() string           { return CompressionMethodID }
 func (c *CompressionMethodSegment) referencedId() string { return ProcessingPreparationID }
 func (c *CompressionMethodSegment) sender() string       { return senderBank }
 
@@ -19,3 +26,11 @@ func (c *CompressionMethodSegment) elements() []element.DataElement {
 		c.SupportedCompressionMethods,
 	}
 }
+
+// CompressionMethods returns the codes of the supported compression functions
+func (c *CompressionMethodSegment) CompressionMethods() []string {
+	if c.SupportedCompressionMethods == nil {
+		return nil
+	}
+	return c.SupportedCompressionMethods.Methods()
+}
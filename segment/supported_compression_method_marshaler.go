@@ -0,0 +1,32 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (c *CompressionMethodSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if c.SupportedCompressionMethods != nil {
+		marshaled, err := c.SupportedCompressionMethods.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (c *CompressionMethodSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if c.SupportedCompressionMethods != nil {
+		buf.WriteString(c.SupportedCompressionMethods.String())
+	}
+}
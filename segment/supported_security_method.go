@@ -2,6 +2,8 @@ package segment
 
 import "github.com/mitch000001/go-hbci/element"
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments SecurityMethodSegment
+
 //go:generate go run ../cmd/unmarshaler/unmarshaler_generator.go -segment SecurityMethodSegment
 
 type SecurityMethodSegment struct {
@@ -0,0 +1,49 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (s *SecurityMethodSegment) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if s.MixAllowed != nil {
+		marshaled, err := s.MixAllowed.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SupportedMethods != nil {
+		marshaled, err := s.SupportedMethods.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (s *SecurityMethodSegment) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if s.MixAllowed != nil {
+		buf.WriteString(s.MixAllowed.String())
+	}
+	buf.WriteByte('+')
+	if s.SupportedMethods != nil {
+		buf.WriteString(s.SupportedMethods.String())
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/mitch000001/go-hbci/element"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments SynchronisationRequestV2,SynchronisationRequestV3,SynchronisationResponseSegmentV3,SynchronisationResponseSegmentV4
+
 // Possible sync modes
 var (
 	SyncModeAquireClientID               = SyncMode{mode: 0}
@@ -40,7 +42,7 @@ type SynchronisationRequestV2 struct {
 	// 0 ￼ ￼| Neue Kundensystem-ID zurückmelden
 	// 1	| Letzte verarbeitete Nachrichtennummer zurückmelden ￼ ￼
 	// 2 ￼ ￼| Signatur-ID zurückmelden
-	SyncModus *element.NumberDataElement
+	SyncModus *element.NumberDataElement `hbci:"required"`
 }
 
 func (s *SynchronisationRequestV2) Version() int         { return 2 }
@@ -73,7 +75,7 @@ type SynchronisationRequestV3 struct {
 	// 0 ￼ ￼| Neue Kundensystem-ID zurückmelden
 	// 1	| Letzte verarbeitete Nachrichtennummer zurückmelden ￼ ￼
 	// 2 ￼ ￼| Signatur-ID zurückmelden
-	SyncModus *element.CodeDataElement
+	SyncModus *element.CodeDataElement `hbci:"required"`
 }
 
 func (s *SynchronisationRequestV3) Version() int         { return 3 }
@@ -129,6 +131,9 @@ func (s *SynchronisationResponseSegmentV3) LastMessageNumber() int {
 }
 
 func (s *SynchronisationResponseSegmentV3) SignatureID() int {
+	if s.SignatureIDResponse == nil {
+		return 0
+	}
 	return s.SignatureIDResponse.Val()
 }
 
@@ -161,5 +166,8 @@ func (s *SynchronisationResponseSegmentV4) LastMessageNumber() int {
 }
 
 func (s *SynchronisationResponseSegmentV4) SignatureID() int {
+	if s.SignatureIDResponse == nil {
+		return 0
+	}
 	return s.SignatureIDResponse.Val()
 }
@@ -0,0 +1,175 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (s *SynchronisationRequestV2) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if s.SyncModus != nil {
+		marshaled, err := s.SyncModus.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (s *SynchronisationRequestV2) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if s.SyncModus != nil {
+		buf.WriteString(s.SyncModus.String())
+	}
+}
+
+func (s *SynchronisationRequestV3) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if s.SyncModus != nil {
+		marshaled, err := s.SyncModus.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (s *SynchronisationRequestV3) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if s.SyncModus != nil {
+		buf.WriteString(s.SyncModus.String())
+	}
+}
+
+func (s *SynchronisationResponseSegmentV3) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if s.ClientSystemIDResponse != nil {
+		marshaled, err := s.ClientSystemIDResponse.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.MessageNumberResponse != nil {
+		marshaled, err := s.MessageNumberResponse.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SignatureIDResponse != nil {
+		marshaled, err := s.SignatureIDResponse.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (s *SynchronisationResponseSegmentV3) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if s.ClientSystemIDResponse != nil {
+		buf.WriteString(s.ClientSystemIDResponse.String())
+	}
+	buf.WriteByte('+')
+	if s.MessageNumberResponse != nil {
+		buf.WriteString(s.MessageNumberResponse.String())
+	}
+	buf.WriteByte('+')
+	if s.SignatureIDResponse != nil {
+		buf.WriteString(s.SignatureIDResponse.String())
+	}
+}
+
+func (s *SynchronisationResponseSegmentV4) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if s.ClientSystemIDResponse != nil {
+		marshaled, err := s.ClientSystemIDResponse.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.MessageNumberResponse != nil {
+		marshaled, err := s.MessageNumberResponse.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if s.SignatureIDResponse != nil {
+		marshaled, err := s.SignatureIDResponse.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (s *SynchronisationResponseSegmentV4) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if s.ClientSystemIDResponse != nil {
+		buf.WriteString(s.ClientSystemIDResponse.String())
+	}
+	buf.WriteByte('+')
+	if s.MessageNumberResponse != nil {
+		buf.WriteString(s.MessageNumberResponse.String())
+	}
+	buf.WriteByte('+')
+	if s.SignatureIDResponse != nil {
+		buf.WriteString(s.SignatureIDResponse.String())
+	}
+}
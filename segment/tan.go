@@ -7,6 +7,8 @@ import (
 	"github.com/mitch000001/go-hbci/element"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments TanRequestSegmentV1,TanRequestSegmentV6,TanResponseSegmentV6
+
 type tanProcess4Constructor func(referencingSegmentID string) *TanRequestSegment
 
 var tanProcess4RequestSegmentConstructors = map[int](tanProcess4Constructor){
@@ -57,7 +59,7 @@ func NewTanProcess4RequestSegmentV1(referencingSegmentID string) *TanRequestSegm
 
 type TanRequestSegmentV1 struct {
 	ClientSegment
-	TANProcess        *element.AlphaNumericDataElement
+	TANProcess        *element.AlphaNumericDataElement `hbci:"required"`
 	JobHash           *element.BinaryDataElement
 	JobReference      *element.AlphaNumericDataElement
 	TanListNumber     *element.AlphaNumericDataElement
@@ -96,7 +98,7 @@ func NewTanProcess4RequestSegmentV6(referencingSegmentID string) *TanRequestSegm
 
 type TanRequestSegmentV6 struct {
 	ClientSegment
-	TANProcess           *element.AlphaNumericDataElement
+	TANProcess           *element.AlphaNumericDataElement `hbci:"required"`
 	ReferencingSegmentID *element.AlphaNumericDataElement
 	JobHash              *element.BinaryDataElement
 	JobReference         *element.AlphaNumericDataElement
@@ -153,5 +155,6 @@ func (t *TanResponseSegmentV6) elements() []element.DataElement {
 		t.TANProcess,
 		t.JobHash,
 		t.JobReference,
+		t.Challenge,
 	}
 }
@@ -5,6 +5,8 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+//go:generate go run ../cmd/marshaler/marshaler_generator.go -segments TanBankParameterV6
+
 const TanBankParameterID = "HITANS"
 
 type TanBankParameter interface {
@@ -0,0 +1,83 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (t *TanBankParameterV6) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if t.MaxJobs != nil {
+		marshaled, err := t.MaxJobs.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.MinSignatures != nil {
+		marshaled, err := t.MinSignatures.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.SecurityClass != nil {
+		marshaled, err := t.SecurityClass.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.Tan2StepSubmissionParameter != nil {
+		marshaled, err := t.Tan2StepSubmissionParameter.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (t *TanBankParameterV6) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if t.MaxJobs != nil {
+		buf.WriteString(t.MaxJobs.String())
+	}
+	buf.WriteByte('+')
+	if t.MinSignatures != nil {
+		buf.WriteString(t.MinSignatures.String())
+	}
+	buf.WriteByte('+')
+	if t.SecurityClass != nil {
+		buf.WriteString(t.SecurityClass.String())
+	}
+	buf.WriteByte('+')
+	if t.Tan2StepSubmissionParameter != nil {
+		buf.WriteString(t.Tan2StepSubmissionParameter.String())
+	}
+}
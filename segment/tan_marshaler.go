@@ -0,0 +1,320 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (t *TanRequestSegmentV1) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if t.TANProcess != nil {
+		marshaled, err := t.TANProcess.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.JobHash != nil {
+		marshaled, err := t.JobHash.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.JobReference != nil {
+		marshaled, err := t.JobReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.TanListNumber != nil {
+		marshaled, err := t.TanListNumber.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.AnotherTanFollows != nil {
+		marshaled, err := t.AnotherTanFollows.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.TANInformation != nil {
+		marshaled, err := t.TANInformation.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (t *TanRequestSegmentV1) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if t.TANProcess != nil {
+		buf.WriteString(t.TANProcess.String())
+	}
+	buf.WriteByte('+')
+	if t.JobHash != nil {
+		buf.WriteString(t.JobHash.String())
+	}
+	buf.WriteByte('+')
+	if t.JobReference != nil {
+		buf.WriteString(t.JobReference.String())
+	}
+	buf.WriteByte('+')
+	if t.TanListNumber != nil {
+		buf.WriteString(t.TanListNumber.String())
+	}
+	buf.WriteByte('+')
+	if t.AnotherTanFollows != nil {
+		buf.WriteString(t.AnotherTanFollows.String())
+	}
+	buf.WriteByte('+')
+	if t.TANInformation != nil {
+		buf.WriteString(t.TANInformation.String())
+	}
+}
+
+func (t *TanRequestSegmentV6) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if t.TANProcess != nil {
+		marshaled, err := t.TANProcess.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.ReferencingSegmentID != nil {
+		marshaled, err := t.ReferencingSegmentID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.JobHash != nil {
+		marshaled, err := t.JobHash.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.JobReference != nil {
+		marshaled, err := t.JobReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.TanListNumber != nil {
+		marshaled, err := t.TanListNumber.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.AnotherTanFollows != nil {
+		marshaled, err := t.AnotherTanFollows.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.TANInformation != nil {
+		marshaled, err := t.TANInformation.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (t *TanRequestSegmentV6) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if t.TANProcess != nil {
+		buf.WriteString(t.TANProcess.String())
+	}
+	buf.WriteByte('+')
+	if t.ReferencingSegmentID != nil {
+		buf.WriteString(t.ReferencingSegmentID.String())
+	}
+	buf.WriteByte('+')
+	if t.JobHash != nil {
+		buf.WriteString(t.JobHash.String())
+	}
+	buf.WriteByte('+')
+	if t.JobReference != nil {
+		buf.WriteString(t.JobReference.String())
+	}
+	buf.WriteByte('+')
+	if t.TanListNumber != nil {
+		buf.WriteString(t.TanListNumber.String())
+	}
+	buf.WriteByte('+')
+	if t.AnotherTanFollows != nil {
+		buf.WriteString(t.AnotherTanFollows.String())
+	}
+	buf.WriteByte('+')
+	if t.TANInformation != nil {
+		buf.WriteString(t.TANInformation.String())
+	}
+}
+
+func (t *TanResponseSegmentV6) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if t.TANProcess != nil {
+		marshaled, err := t.TANProcess.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.JobHash != nil {
+		marshaled, err := t.JobHash.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.JobReference != nil {
+		marshaled, err := t.JobReference.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if t.Challenge != nil {
+		marshaled, err := t.Challenge.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (t *TanResponseSegmentV6) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if t.TANProcess != nil {
+		buf.WriteString(t.TANProcess.String())
+	}
+	buf.WriteByte('+')
+	if t.JobHash != nil {
+		buf.WriteString(t.JobHash.String())
+	}
+	buf.WriteByte('+')
+	if t.JobReference != nil {
+		buf.WriteString(t.JobReference.String())
+	}
+	buf.WriteByte('+')
+	if t.Challenge != nil {
+		buf.WriteString(t.Challenge.String())
+	}
+}
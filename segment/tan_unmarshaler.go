@@ -63,10 +63,17 @@ func (t *TanResponseSegmentV6) UnmarshalHBCI(value []byte) error {
 	}
 	if len(elements) > 3 && len(elements[3]) > 0 {
 		t.JobReference = &element.AlphaNumericDataElement{}
-		if len(elements)+1 > 3 {
-			err = t.JobReference.UnmarshalHBCI(bytes.Join(elements[3:], []byte("+")))
+		err = t.JobReference.UnmarshalHBCI(elements[3])
+		if err != nil {
+			return err
+		}
+	}
+	if len(elements) > 4 && len(elements[4]) > 0 {
+		t.Challenge = &element.AlphaNumericDataElement{}
+		if len(elements)+1 > 4 {
+			err = t.Challenge.UnmarshalHBCI(bytes.Join(elements[4:], []byte("+")))
 		} else {
-			err = t.JobReference.UnmarshalHBCI(elements[3])
+			err = t.Challenge.UnmarshalHBCI(elements[4])
 		}
 		if err != nil {
 			return err
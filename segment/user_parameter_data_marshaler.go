@@ -0,0 +1,252 @@
+// Code generated by *generator.SegmentMarshalerGenerator; DO NOT EDIT.
+
+package segment
+
+import (
+	"bytes"
+)
+
+func (c *CommonUserParameterDataV2) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if c.UserID != nil {
+		marshaled, err := c.UserID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.UPDVersion != nil {
+		marshaled, err := c.UPDVersion.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.UPDUsage != nil {
+		marshaled, err := c.UPDUsage.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (c *CommonUserParameterDataV2) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if c.UserID != nil {
+		buf.WriteString(c.UserID.String())
+	}
+	buf.WriteByte('+')
+	if c.UPDVersion != nil {
+		buf.WriteString(c.UPDVersion.String())
+	}
+	buf.WriteByte('+')
+	if c.UPDUsage != nil {
+		buf.WriteString(c.UPDUsage.String())
+	}
+}
+
+func (c *CommonUserParameterDataV3) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if c.UserID != nil {
+		marshaled, err := c.UserID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.UPDVersion != nil {
+		marshaled, err := c.UPDVersion.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.UPDUsage != nil {
+		marshaled, err := c.UPDUsage.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.UserName != nil {
+		marshaled, err := c.UserName.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.CommonExtensions != nil {
+		marshaled, err := c.CommonExtensions.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (c *CommonUserParameterDataV3) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if c.UserID != nil {
+		buf.WriteString(c.UserID.String())
+	}
+	buf.WriteByte('+')
+	if c.UPDVersion != nil {
+		buf.WriteString(c.UPDVersion.String())
+	}
+	buf.WriteByte('+')
+	if c.UPDUsage != nil {
+		buf.WriteString(c.UPDUsage.String())
+	}
+	buf.WriteByte('+')
+	if c.UserName != nil {
+		buf.WriteString(c.UserName.String())
+	}
+	buf.WriteByte('+')
+	if c.CommonExtensions != nil {
+		buf.WriteString(c.CommonExtensions.String())
+	}
+}
+
+func (c *CommonUserParameterDataV4) marshalHBCIElements(buf *bytes.Buffer) error {
+	separators := 0
+	separators++
+	if c.UserID != nil {
+		marshaled, err := c.UserID.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.UPDVersion != nil {
+		marshaled, err := c.UPDVersion.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.UPDUsage != nil {
+		marshaled, err := c.UPDUsage.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.UserName != nil {
+		marshaled, err := c.UserName.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	separators++
+	if c.CommonExtensions != nil {
+		marshaled, err := c.CommonExtensions.MarshalHBCI()
+		if err != nil {
+			return err
+		}
+		if len(marshaled) > 0 {
+			for ; separators > 0; separators-- {
+				buf.WriteByte('+')
+			}
+			buf.Write(marshaled)
+		}
+	}
+	return nil
+}
+
+func (c *CommonUserParameterDataV4) writeElementStrings(buf *bytes.Buffer) {
+	buf.WriteByte('+')
+	if c.UserID != nil {
+		buf.WriteString(c.UserID.String())
+	}
+	buf.WriteByte('+')
+	if c.UPDVersion != nil {
+		buf.WriteString(c.UPDVersion.String())
+	}
+	buf.WriteByte('+')
+	if c.UPDUsage != nil {
+		buf.WriteString(c.UPDUsage.String())
+	}
+	buf.WriteByte('+')
+	if c.UserName != nil {
+		buf.WriteString(c.UserName.String())
+	}
+	buf.WriteByte('+')
+	if c.CommonExtensions != nil {
+		buf.WriteString(c.CommonExtensions.String())
+	}
+}
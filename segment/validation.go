@@ -0,0 +1,66 @@
+package segment
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mitch000001/go-hbci/element"
+)
+
+// A ValidationError is returned when marshaling a segment whose data elements
+// violate the format rules of HBCI. It names the segment as well as the path,
+// position and violated rule of the invalid data element.
+type ValidationError struct {
+	// Segment is the ID of the segment, e.g. HKSAL
+	Segment string
+	Version int
+	*element.ValidationError
+}
+
+func (v *ValidationError) Error() string {
+	return fmt.Sprintf("segment %s version %d: %v", v.Segment, v.Version, v.ValidationError)
+}
+
+// Unwrap returns the underlying element.ValidationError
+func (v *ValidationError) Unwrap() error {
+	return v.ValidationError
+}
+
+// validate validates the header and the data elements of s. The header is
+// the first data element of every segment.
+func (s *segment) validate() error {
+	var elementErr *element.ValidationError
+	if err := element.Validate(s.header); errors.As(err, &elementErr) {
+		elementErr.Path = joinPath("Header", elementErr.Path)
+		elementErr.Position = joinPosition("1", elementErr.Position)
+	} else if err := element.ValidateGroup(s.definition(), s.segment.elements(), 1); !errors.As(err, &elementErr) {
+		return nil
+	}
+	return &ValidationError{
+		Segment:         s.ID(),
+		Version:         s.Version(),
+		ValidationError: elementErr,
+	}
+}
+
+// definition returns the struct declaring the data elements of s
+func (s *segment) definition() interface{} {
+	if defined, ok := s.segment.(definedSegment); ok {
+		return defined.SegmentDefinition
+	}
+	return s.segment
+}
+
+func joinPath(parent, path string) string {
+	if path == "" {
+		return parent
+	}
+	return parent + "." + path
+}
+
+func joinPosition(parent, position string) string {
+	if position == "" {
+		return parent
+	}
+	return parent + ":" + position
+}
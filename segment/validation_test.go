@@ -0,0 +1,110 @@
+package segment
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/element"
+)
+
+func TestSegmentMarshalHBCIValidation(t *testing.T) {
+	account := domain.AccountConnection{AccountID: strings.Repeat("1", 31), CountryCode: 280, BankID: "10000000"}
+	seg := NewAccountBalanceRequestV5(account, false)
+
+	_, err := seg.MarshalHBCI()
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError, got %T:%v", err, err)
+	}
+	if validationErr.Segment != "HKSAL" || validationErr.Version != 5 {
+		t.Logf("Expected error to name segment HKSAL version 5, got %s version %d\n", validationErr.Segment, validationErr.Version)
+		t.Fail()
+	}
+	if validationErr.Path != "AccountConnection.AccountID" || validationErr.Position != "2:1" || validationErr.Rule != element.RuleMaxLength {
+		t.Logf("Expected max length violation at 2:1 AccountConnection.AccountID, got %v\n", validationErr)
+		t.Fail()
+	}
+	expected := "segment HKSAL version 5: invalid data element 2:1 AccountConnection.AccountID: max length: length 31 exceeds the maximum of 30"
+	if err.Error() != expected {
+		t.Logf("Expected error to equal\n%q\n\tgot\n%q\n", expected, err.Error())
+		t.Fail()
+	}
+
+	account.AccountID = "12345"
+	seg = NewAccountBalanceRequestV5(account, false)
+	seg.(*AccountBalanceRequestSegmentV5).AccountConnection.BankID = nil
+
+	_, err = seg.MarshalHBCI()
+
+	if !errors.As(err, &validationErr) || validationErr.Position != "2:4" || validationErr.Rule != element.RuleRequired {
+		t.Logf("Expected missing bank ID to be reported, got %v\n", err)
+		t.Fail()
+	}
+}
+
+func TestSegmentMarshalHBCIMissingRequiredElement(t *testing.T) {
+	account := domain.AccountConnection{AccountID: "12345", CountryCode: 280, BankID: "10000000"}
+	bankID := domain.BankID{CountryCode: 280, ID: "10000000"}
+
+	tests := []struct {
+		segment  ClientSegment
+		unset    func(ClientSegment)
+		path     string
+		position string
+	}{
+		{
+			NewAccountBalanceRequestV5(account, false),
+			func(s ClientSegment) { s.(*AccountBalanceRequestSegmentV5).AccountConnection = nil },
+			"AccountConnection",
+			"2",
+		},
+		{
+			NewIdentificationSegment(bankID, "12345", "0", true),
+			func(s ClientSegment) { s.(*IdentificationSegment).ClientSystemId = nil },
+			"ClientSystemId",
+			"4",
+		},
+		{
+			NewDialogEndSegment("4711"),
+			func(s ClientSegment) { s.(*DialogEndSegment).DialogID = nil },
+			"DialogID",
+			"2",
+		},
+	}
+
+	for _, test := range tests {
+		test.unset(test.segment)
+
+		_, err := test.segment.MarshalHBCI()
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Logf("%s: Expected a ValidationError, got %T:%v\n", test.segment.Header().ID.Val(), err, err)
+			t.Fail()
+			continue
+		}
+		if validationErr.Rule != element.RuleRequired || validationErr.Path != test.path || validationErr.Position != test.position {
+			t.Logf("%s: Expected missing element at %s %s to be reported, got %v\n", test.segment.Header().ID.Val(), test.position, test.path, validationErr)
+			t.Fail()
+		}
+	}
+}
+
+func BenchmarkSegmentValidate(b *testing.B) {
+	var segments []*segment
+	for _, seg := range clientSegments(b, FINTS300) {
+		segments = append(segments, innerSegment(b, seg))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, seg := range segments {
+			if err := seg.validate(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
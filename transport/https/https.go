@@ -2,6 +2,7 @@ package transport
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"io"
 	"io/ioutil"
@@ -46,7 +47,7 @@ func (h *HTTPSBase64Transport) Do(request *transport.Request) (*transport.Respon
 	if err != nil {
 		return nil, err
 	}
-	httpResponse, err := h.httpClient.Post(request.URL, "application/vnd.hbci", &buf)
+	httpResponse, err := post(h.httpClient, request, &buf)
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +57,12 @@ func (h *HTTPSBase64Transport) Do(request *transport.Request) (*transport.Respon
 	} else {
 		reader = httpResponse.Body
 	}
-	return &transport.Response{Body: ioutil.NopCloser(reader), Request: request}, nil
+	return &transport.Response{Body: ioutil.NopCloser(reader), Request: request, StatusCode: httpResponse.StatusCode}, nil
+}
+
+// DoContext performs the request like Do, but aborts it when ctx is done.
+func (h *HTTPSBase64Transport) DoContext(ctx context.Context, request *transport.Request) (*transport.Response, error) {
+	return transport.DoContext(ctx, h, request)
 }
 
 // New returns a HTTPSTransport. It sets http.DefaultClient as http.Client to
@@ -84,9 +90,24 @@ type HTTPSTransport struct {
 // populated transport.Response with the HTTP Response Body as Body and the
 // request as Request
 func (h *HTTPSTransport) Do(request *transport.Request) (*transport.Response, error) {
-	httpResponse, err := h.HTTPClient.Post(request.URL, "application/vnd.hbci", request.Body)
+	httpResponse, err := post(h.HTTPClient, request, request.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &transport.Response{Body: httpResponse.Body, Request: request, StatusCode: httpResponse.StatusCode}, nil
+}
+
+// DoContext performs the request like Do, but aborts it when ctx is done.
+func (h *HTTPSTransport) DoContext(ctx context.Context, request *transport.Request) (*transport.Response, error) {
+	return transport.DoContext(ctx, h, request)
+}
+
+// post sends body to the URL of request, bound to the context of request.
+func post(client *http.Client, request *transport.Request, body io.Reader) (*http.Response, error) {
+	httpRequest, err := http.NewRequestWithContext(request.Context(), http.MethodPost, request.URL, body)
 	if err != nil {
 		return nil, err
 	}
-	return &transport.Response{Body: httpResponse.Body, Request: request}, nil
+	httpRequest.Header.Set("Content-Type", "application/vnd.hbci")
+	return client.Do(httpRequest)
 }
@@ -2,6 +2,7 @@ package transport
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"io/ioutil"
 	"net/http"
@@ -102,3 +103,51 @@ func TestHttpsBase64Transport(t *testing.T) {
 		t.Fail()
 	}
 }
+
+type contextKey string
+
+func TestHttpsTransportDoContext(t *testing.T) {
+	roundtripper := &MockHTTPTransport{}
+	roundtripper.SetResponsePayloads([][]byte{
+		[]byte("HNHBK:1:3+abc'"),
+	})
+	httpsTransport := &HTTPSTransport{&http.Client{Transport: roundtripper}}
+
+	ctx := context.WithValue(context.Background(), contextKey("foo"), "bar")
+	req := &transport.Request{
+		URL:  "https://localhost",
+		Body: ioutil.NopCloser(strings.NewReader("bar")),
+	}
+
+	_, err := httpsTransport.DoContext(ctx, req)
+	if err != nil {
+		t.Logf("Expected no error, got %v\n", err)
+		t.FailNow()
+	}
+
+	httpRequest := roundtripper.Request(0)
+	if httpRequest.Context().Value(contextKey("foo")) != "bar" {
+		t.Logf("Expected http request to carry the provided context\n")
+		t.Fail()
+	}
+
+	contentType := httpRequest.Header.Get("Content-Type")
+	if contentType != "application/vnd.hbci" {
+		t.Logf("Expected content type to equal %q, got %q\n", "application/vnd.hbci", contentType)
+		t.Fail()
+	}
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = httpsTransport.DoContext(cancelledCtx, req)
+	if err != context.Canceled {
+		t.Logf("Expected error to equal %v, got %v\n", context.Canceled, err)
+		t.Fail()
+	}
+
+	if roundtripper.CallCount() != 1 {
+		t.Logf("Expected cancelled request not to be sent, got %d calls\n", roundtripper.CallCount())
+		t.Fail()
+	}
+}
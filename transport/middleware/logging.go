@@ -3,11 +3,12 @@ package transport
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
-	"strings"
+	"log/slog"
 
 	"github.com/mitch000001/go-hbci/internal"
 	"github.com/mitch000001/go-hbci/message"
@@ -16,86 +17,124 @@ import (
 )
 
 // Logging creates a middleware that logs every request and response sent over
-// the transport
+// the transport to logger. PINs, TANs, IBANs and account numbers are masked.
 func Logging(logger *log.Logger, cryptoProvider message.CryptoProvider) transport.Middleware {
 	if logger == nil {
 		logger = internal.Debug
 	}
+	handler := slog.NewTextHandler(logger.Writer(), &slog.HandlerOptions{Level: slog.LevelDebug})
+	return StructuredLogging(slog.New(handler), cryptoProvider, Redaction{})
+}
+
+// StructuredLogging creates a middleware that logs every segment of the
+// requests and responses sent over the transport as debug record. The records
+// carry the dialog ID, the message number and the segment ID. The segments
+// are masked as configured by redaction before they are logged.
+func StructuredLogging(logger *slog.Logger, cryptoProvider message.CryptoProvider, redaction Redaction) transport.Middleware {
+	return StructuredLoggingFunc(logger, func() message.CryptoProvider { return cryptoProvider }, redaction)
+}
+
+// StructuredLoggingFunc is like StructuredLogging, but calls cryptoProvider
+// for every message to get the provider to decrypt it with. This allows the
+// provider to be replaced after the middleware was set up, e.g. when the PIN
+// changes.
+func StructuredLoggingFunc(logger *slog.Logger, cryptoProvider func() message.CryptoProvider, redaction Redaction) transport.Middleware {
+	if logger == nil {
+		logger = internal.Logger()
+	}
 	return func(t transport.Transport) transport.Transport {
 		return transport.Func(func(req *transport.Request) (*transport.Response, error) {
-			var buf bytes.Buffer
-			marshaledRequest, err := io.ReadAll(io.TeeReader(req.Body, &buf))
-			if err != nil {
-				logger.Printf("Error reading request body: %v", err)
-			}
-			req.Body = ioutil.NopCloser(&buf)
-			logger.Println("Decrypted Request:")
-			if err := writeMessage(cryptoProvider, logger.Writer(), marshaledRequest); err != nil {
-				logger.Printf("%s\n", marshaledRequest)
+			ctx := req.Context()
+			if logger.Enabled(ctx, slog.LevelDebug) {
+				var buf bytes.Buffer
+				marshaledRequest, err := io.ReadAll(io.TeeReader(req.Body, &buf))
+				if err != nil {
+					logger.DebugContext(ctx, "Error reading request body", slog.Any("error", err))
+				}
+				req.Body = ioutil.NopCloser(&buf)
+				logMessage(ctx, logger, "Request segment", cryptoProvider(), redaction, marshaledRequest)
 			}
 
 			res, err := t.Do(req)
 			if err != nil {
-				logger.Printf("Error executing request:\n%v", err)
+				logger.DebugContext(ctx, "Error executing request", slog.Any("error", err))
 				return nil, err
 			}
-			var responseBuf bytes.Buffer
-			marshaledResponse, err := io.ReadAll(io.TeeReader(res.Body, &responseBuf))
-			if err != nil {
-				logger.Printf("Error reading response body: %v", err)
-			}
-			res.Body = ioutil.NopCloser(&responseBuf)
-			logger.Println("Decrypted Response:")
-			if err := writeMessage(cryptoProvider, logger.Writer(), marshaledResponse); err != nil {
-				logger.Printf("%s\n", marshaledResponse)
+			if logger.Enabled(ctx, slog.LevelDebug) {
+				var responseBuf bytes.Buffer
+				marshaledResponse, err := io.ReadAll(io.TeeReader(res.Body, &responseBuf))
+				if err != nil {
+					logger.DebugContext(ctx, "Error reading response body", slog.Any("error", err))
+				}
+				res.Body = ioutil.NopCloser(&responseBuf)
+				logMessage(ctx, logger, "Response segment", cryptoProvider(), redaction, marshaledResponse)
 			}
 			return res, nil
 		})
 	}
 }
 
-func writeMessage(cryptoProvider message.CryptoProvider, w io.Writer, marshaledMessage []byte) error {
+func logMessage(ctx context.Context, logger *slog.Logger, msg string, cryptoProvider message.CryptoProvider, redaction Redaction, marshaledMessage []byte) {
+	segments, err := messageSegments(cryptoProvider, marshaledMessage)
+	if err != nil {
+		logger.DebugContext(ctx, "Error extracting segments from message", slog.Any("error", err))
+		return
+	}
+	dialogID, messageNumber := messageHeaderFields(segments)
+	for _, s := range segments {
+		redacted := redaction.RedactSegment(s)
+		logger.DebugContext(
+			ctx,
+			msg,
+			slog.String("dialog_id", dialogID),
+			slog.String("message_number", messageNumber),
+			slog.String("segment_id", segmentID(redacted)),
+			slog.String("segment", string(redacted)),
+		)
+	}
+}
+
+// messageSegments returns the segments of the decrypted message. If the
+// message cannot be decrypted, the raw segments are returned.
+func messageSegments(cryptoProvider message.CryptoProvider, marshaledMessage []byte) ([][]byte, error) {
+	rawSegments, err := message.NewSegmentExtractor(marshaledMessage).Extract()
+	if err != nil {
+		return nil, fmt.Errorf("error extracting segments from message: %w", err)
+	}
 	bankMessage, err := readMessageData(cryptoProvider, marshaledMessage)
 	if err != nil {
-		return writeRawSegments(w, marshaledMessage)
+		return rawSegments, nil
 	}
 	segmentProvider, ok := bankMessage.(marshaledSegmentsProvider)
 	if !ok {
-		return writeRawSegments(w, marshaledMessage)
+		return rawSegments, nil
 	}
-	var errs errors
-	if _, err := fmt.Fprintf(w, "\t%v\n", bankMessage.MessageHeader()); err != nil {
-		errs = append(errs, err)
+	segments := segmentProvider.MarshaledSegments()
+	if len(segments) != 0 && segmentID(segments[0]) == segment.MessageHeaderID {
+		return segments, nil
 	}
-	for _, s := range segmentProvider.MarshaledSegments() {
-		_, err := fmt.Fprintf(w, "\t%s\n", string(s))
-		if err != nil {
-			errs = append(errs, err)
-		}
-	}
-	if len(errs) != 0 {
-		return errs
-	}
-	return nil
+	// the decrypted data do not contain the message header
+	return append([][]byte{rawSegments[0]}, segments...), nil
 }
 
-func writeRawSegments(w io.Writer, marshaledMessage []byte) error {
-	extractor := message.NewSegmentExtractor(marshaledMessage)
-	segments, err := extractor.Extract()
-	if err != nil {
-		return fmt.Errorf("error extracting segments from message: %v", err)
+// messageHeaderFields returns the dialog ID and the message number from the
+// message header, if present.
+func messageHeaderFields(segments [][]byte) (dialogID string, messageNumber string) {
+	if len(segments) == 0 || segmentID(segments[0]) != segment.MessageHeaderID {
+		return "", ""
 	}
-	var errs errors
-	for _, s := range segments {
-		_, err := fmt.Fprintf(w, ">>%s\n", string(s))
-		if err != nil {
-			errs = append(errs, err)
-		}
+	elements, err := segment.ExtractElements(copyBytes(segments[0]))
+	if err != nil || len(elements) < 5 {
+		return "", ""
 	}
-	if len(errs) != 0 {
-		return errs
+	return string(elements[3]), string(elements[4])
+}
+
+func segmentID(marshaledSegment []byte) string {
+	if i := bytes.IndexByte(marshaledSegment, ':'); i != -1 {
+		return string(marshaledSegment[:i])
 	}
-	return nil
+	return ""
 }
 
 func readMessageData(cryptoProvider message.CryptoProvider, rawMessage []byte) (message.Message, error) {
@@ -137,9 +176,20 @@ func extractEncryptedMessage(response *transport.Response) (*message.EncryptedMe
 		return nil, fmt.Errorf("error while unmarshaling message header: %v", err)
 	}
 	// TODO: parse messageEnd
-	// TODO: parse encryptionHeader
 
 	encMessage := message.NewEncryptedMessage(header, nil, segment.FINTS300)
+	if encryptionHeader := response.FindSegment(segment.EncryptionHeaderSegmentID); encryptionHeader != nil {
+		compression, err := message.ExtractCompression(encryptionHeader)
+		if err != nil {
+			return nil, fmt.Errorf("error while unmarshaling encryption header: %v", err)
+		}
+		encMessage.Compression = compression
+		messageKey, err := message.ExtractMessageKey(encryptionHeader)
+		if err != nil {
+			return nil, fmt.Errorf("error while unmarshaling encryption header: %v", err)
+		}
+		encMessage.MessageKey = messageKey
+	}
 
 	encryptedData := response.FindSegment("HNVSD")
 	if encryptedData != nil {
@@ -173,16 +223,6 @@ func extractUnencryptedMessage(response *transport.Response) (message.BankMessag
 	return decryptedMessage, nil
 }
 
-type errors []error
-
-func (e errors) Error() string {
-	errs := make([]string, len(e))
-	for i, err := range e {
-		errs[i] = err.Error()
-	}
-	return strings.Join(errs, ",")
-}
-
 type marshaledSegmentsProvider interface {
 	MarshaledSegments() [][]byte
 }
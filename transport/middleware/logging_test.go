@@ -0,0 +1,96 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/transport"
+)
+
+func TestStructuredLogging(t *testing.T) {
+	request := "HNHBK:1:3+000000000092+300+4711+2'HKSAL:2:5+1234567890::280:12345678+N'HNSHA:3:2+1234567++geheim'HNHBS:4:1+2'"
+	response := "HNHBK:1:3+000000000071+300+4711+2+4711:2'HIRMG:2:2+0010::Nachricht entgegengenommen.'HNHBS:3:1+2'"
+	innerTransport := transport.Func(func(req *transport.Request) (*transport.Response, error) {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			t.Fatalf("Error reading request: %v", err)
+		}
+		if string(body) != request {
+			t.Logf("Expected the request body to be passed unchanged, got %q\n", body)
+			t.Fail()
+		}
+		return &transport.Response{Body: ioutil.NopCloser(strings.NewReader(response))}, nil
+	})
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	res, err := StructuredLogging(logger, nil, Redaction{})(innerTransport).Do(&transport.Request{
+		Body: ioutil.NopCloser(strings.NewReader(request)),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("Error reading response: %v", err)
+	}
+	if string(body) != response {
+		t.Logf("Expected the response body to be passed unchanged, got %q\n", body)
+		t.Fail()
+	}
+
+	if strings.Contains(buf.String(), "geheim") || strings.Contains(buf.String(), "1234567890") {
+		t.Logf("Expected sensitive data to be masked, got\n%s\n", buf.String())
+		t.Fail()
+	}
+	var segmentIDs []string
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var record map[string]interface{}
+		if err := decoder.Decode(&record); err != nil {
+			t.Fatalf("Error decoding log record: %v", err)
+		}
+		if record["dialog_id"] != "4711" || record["message_number"] != "2" {
+			t.Logf("Expected dialog ID and message number to be set, got %v\n", record)
+			t.Fail()
+		}
+		segmentIDs = append(segmentIDs, record["segment_id"].(string))
+	}
+	expected := "HNHBK,HKSAL,HNSHA,HNHBS,HNHBK,HIRMG,HNHBS"
+	if actual := strings.Join(segmentIDs, ","); actual != expected {
+		t.Logf("Expected logged segments %q, got %q\n", expected, actual)
+		t.Fail()
+	}
+}
+
+func TestStructuredLoggingFuncResolvesProviderPerMessage(t *testing.T) {
+	innerTransport := transport.Func(func(req *transport.Request) (*transport.Response, error) {
+		return &transport.Response{Body: ioutil.NopCloser(strings.NewReader("HNHBK:1:3+000000000071+300+4711+2+4711:2'HNHBS:2:1+2'"))}, nil
+	})
+	logger := slog.New(slog.NewJSONHandler(ioutil.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	calls := 0
+	cryptoProvider := func() message.CryptoProvider {
+		calls++
+		return nil
+	}
+	middleware := StructuredLoggingFunc(logger, cryptoProvider, Redaction{})(innerTransport)
+
+	for i := 0; i < 2; i++ {
+		_, err := middleware.Do(&transport.Request{
+			Body: ioutil.NopCloser(strings.NewReader("HNHBK:1:3+000000000092+300+4711+2'HNHBS:2:1+2'")),
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if calls != 4 {
+		t.Logf("Expected the crypto provider to be resolved for every message, got %d calls\n", calls)
+		t.Fail()
+	}
+}
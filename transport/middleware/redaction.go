@@ -0,0 +1,124 @@
+package transport
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/mitch000001/go-hbci/element"
+	"github.com/mitch000001/go-hbci/segment"
+)
+
+// Redaction configures which data are masked before segments get logged.
+// PINs and TANs are always masked, the zero value masks IBANs and account
+// numbers as well.
+type Redaction struct {
+	// KeepIBANs disables masking of IBANs.
+	KeepIBANs bool `json:"keep_ibans"`
+	// KeepAccountIDs disables masking of account numbers.
+	KeepAccountIDs bool `json:"keep_account_ids"`
+}
+
+const credentialMask = "***"
+
+// credentialElements maps the segment IDs to the positions of the data
+// elements containing PINs or TANs.
+var credentialElements = map[string][]int{
+	// Signature end: PIN and TAN
+	"HNSHA": {3},
+	// PIN change: new PIN
+	"HKPAE": {1},
+}
+
+var (
+	ibanPattern = regexp.MustCompile(`\b[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}\b`)
+	// the account field :25: of a SWIFT MT940/MT942 is of the form BLZ/Account
+	swiftAccountPattern = regexp.MustCompile(`(:25:[^/\r\n]*/)([0-9]+)`)
+)
+
+// RedactSegment returns a copy of the marshaled segment with PINs, TANs and,
+// depending on the configuration, IBANs and account numbers masked. A
+// segment which cannot be parsed is masked entirely.
+func (r Redaction) RedactSegment(marshaledSegment []byte) []byte {
+	elements, err := segment.ExtractElements(copyBytes(marshaledSegment))
+	if err != nil || len(elements) == 0 {
+		return []byte(credentialMask)
+	}
+	header, err := element.ExtractElements(copyBytes(elements[0]))
+	if err != nil || len(header) == 0 {
+		return []byte(credentialMask)
+	}
+	segmentID := string(header[0])
+	redacted := make([][]byte, len(elements))
+	redacted[0] = elements[0]
+	for i := 1; i < len(elements); i++ {
+		redacted[i] = r.redactElement(segmentID, i, elements[i])
+	}
+	return append(bytes.Join(redacted, []byte("+")), '\'')
+}
+
+func (r Redaction) redactElement(segmentID string, position int, dataElement []byte) []byte {
+	if len(dataElement) == 0 {
+		return dataElement
+	}
+	for _, p := range credentialElements[segmentID] {
+		if p == position {
+			return []byte(credentialMask)
+		}
+	}
+	if dataElement[0] == '@' {
+		// binary data: keep the length intact
+		return r.redactText(copyBytes(dataElement))
+	}
+	components, err := element.ExtractElements(copyBytes(dataElement))
+	if err != nil {
+		return []byte(credentialMask)
+	}
+	if !r.KeepAccountIDs {
+		// account connections are composed of account ID, sub account,
+		// country code and bank ID
+		for i := 2; i < len(components)-1; i++ {
+			if string(components[i]) == "280" && len(components[i+1]) != 0 {
+				components[i-2] = mask(components[i-2], 0)
+			}
+		}
+	}
+	for i, c := range components {
+		components[i] = r.redactText(c)
+	}
+	return bytes.Join(components, []byte(":"))
+}
+
+func (r Redaction) redactText(text []byte) []byte {
+	if !r.KeepIBANs {
+		text = ibanPattern.ReplaceAllFunc(text, func(iban []byte) []byte {
+			return mask(iban, 4)
+		})
+	}
+	if !r.KeepAccountIDs {
+		text = swiftAccountPattern.ReplaceAllFunc(text, func(field []byte) []byte {
+			sub := swiftAccountPattern.FindSubmatchIndex(field)
+			return append(copyBytes(field[:sub[3]]), mask(field[sub[4]:], 0)...)
+		})
+	}
+	return text
+}
+
+// mask replaces all but the first keep and the last two characters of value.
+// Short values are masked completely. The length is retained to keep binary
+// data elements valid.
+func mask(value []byte, keep int) []byte {
+	if len(value) == 0 {
+		return value
+	}
+	masked := bytes.Repeat([]byte("*"), len(value))
+	if len(value) <= keep+6 {
+		return masked
+	}
+	copy(masked, value[:keep])
+	copy(masked[len(value)-2:], value[len(value)-2:])
+	return masked
+}
+
+func copyBytes(b []byte) []byte {
+	return append([]byte(nil), b...)
+}
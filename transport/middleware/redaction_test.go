@@ -0,0 +1,70 @@
+package transport
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRedactionRedactSegment(t *testing.T) {
+	mt940 := ":20:STARTUMS\r\n:25:12345678/1234567890\r\n"
+	maskedMT940 := ":20:STARTUMS\r\n:25:12345678/********90\r\n"
+	tests := []struct {
+		redaction Redaction
+		segment   string
+		expected  string
+	}{
+		{
+			Redaction{},
+			"HNSHA:5:2+1234567++geheim:123456'",
+			"HNSHA:5:2+1234567++***'",
+		},
+		{
+			Redaction{KeepIBANs: true, KeepAccountIDs: true},
+			"HNSHA:5:2+1234567++geheim'",
+			"HNSHA:5:2+1234567++***'",
+		},
+		{
+			Redaction{},
+			"HKPAE:3:1+neuespin'",
+			"HKPAE:3:1+***'",
+		},
+		{
+			Redaction{},
+			"HKSAL:3:7+DE12500105170648489890:INGDDEFFXXX:0648489890::280:50010517+N'",
+			"HKSAL:3:7+DE12****************90:INGDDEFFXXX:********90::280:50010517+N'",
+		},
+		{
+			Redaction{KeepIBANs: true},
+			"HKSAL:3:7+DE12500105170648489890:INGDDEFFXXX:0648489890::280:50010517+N'",
+			"HKSAL:3:7+DE12500105170648489890:INGDDEFFXXX:********90::280:50010517+N'",
+		},
+		{
+			Redaction{KeepAccountIDs: true},
+			"HKSAL:3:5+1234567890::280:12345678+N'",
+			"HKSAL:3:5+1234567890::280:12345678+N'",
+		},
+		{
+			Redaction{},
+			"HKSAL:3:5+1234567890::280:12345678+N'",
+			"HKSAL:3:5+********90::280:12345678+N'",
+		},
+		{
+			Redaction{},
+			"HKIDN:3:2+280:12345678+100000000+0+1'",
+			"HKIDN:3:2+280:12345678+100000000+0+1'",
+		},
+		{
+			Redaction{},
+			fmt.Sprintf("HIKAZ:5:5:3+@%d@%s'", len(mt940), mt940),
+			fmt.Sprintf("HIKAZ:5:5:3+@%d@%s'", len(maskedMT940), maskedMT940),
+		},
+	}
+	for _, test := range tests {
+		actual := string(test.redaction.RedactSegment([]byte(test.segment)))
+
+		if actual != test.expected {
+			t.Logf("%+v: Expected segment\n%q\n\tgot\n%q\n", test.redaction, test.expected, actual)
+			t.Fail()
+		}
+	}
+}
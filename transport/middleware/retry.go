@@ -0,0 +1,162 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"io/ioutil"
+	"log/slog"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/mitch000001/go-hbci/transport"
+)
+
+// RetryPolicy configures the Retry middleware. Zero values are replaced by
+// the defaults of DefaultRetryPolicy.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the time to wait before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the time to wait between two attempts.
+	MaxBackoff time.Duration
+	// Multiplier is applied to the backoff after every attempt.
+	Multiplier float64
+	// Jitter randomizes every backoff by up to the given fraction in both
+	// directions. A Jitter of 0.2 results in backoffs between 80% and 120% of
+	// the calculated value. Negative values disable jitter.
+	Jitter float64
+	// Logger receives a debug record for every retry. It defaults to
+	// slog.Default().
+	Logger *slog.Logger
+}
+
+// DefaultRetryPolicy is used by Retry for every unset field of the provided
+// RetryPolicy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+func (r RetryPolicy) withDefaults() RetryPolicy {
+	if r.MaxAttempts <= 0 {
+		r.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	if r.InitialBackoff <= 0 {
+		r.InitialBackoff = DefaultRetryPolicy.InitialBackoff
+	}
+	if r.MaxBackoff <= 0 {
+		r.MaxBackoff = DefaultRetryPolicy.MaxBackoff
+	}
+	if r.Multiplier < 1 {
+		r.Multiplier = DefaultRetryPolicy.Multiplier
+	}
+	if r.Jitter == 0 {
+		r.Jitter = DefaultRetryPolicy.Jitter
+	}
+	if r.Logger == nil {
+		r.Logger = slog.Default()
+	}
+	return r
+}
+
+// backoff returns the time to wait before the given retry, starting with 1.
+func (r RetryPolicy) backoff(retry int) time.Duration {
+	backoff := float64(r.InitialBackoff) * math.Pow(r.Multiplier, float64(retry-1))
+	if backoff > float64(r.MaxBackoff) {
+		backoff = float64(r.MaxBackoff)
+	}
+	if r.Jitter > 0 {
+		backoff = backoff * (1 - r.Jitter + 2*r.Jitter*rand.Float64())
+	}
+	return time.Duration(backoff)
+}
+
+// Retry creates a middleware which sends a request again if it failed in a
+// way that is safe to retry. These are:
+//
+//   - connection errors which occurred before the request was sent
+//   - server errors (5xx) when initializing a dialog
+//   - server errors (5xx) for requests not marked as NonRetryable
+//
+// All other errors and responses are passed as is. Errors occurring after the
+// request was sent, e.g. read timeouts, are never retried, as the institute
+// might have processed the message and the retry would reuse its message
+// number. Requests marked as NonRetryable within their metadata are never
+// sent twice, as the institute might have executed the contained jobs
+// already.
+//
+// Between the attempts Retry waits according to the policy, but aborts when
+// the request context is done.
+func Retry(policy RetryPolicy) transport.Middleware {
+	policy = policy.withDefaults()
+	return func(t transport.Transport) transport.Transport {
+		return transport.Func(func(req *transport.Request) (*transport.Response, error) {
+			body, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			for attempt := 1; ; attempt++ {
+				req.Body = ioutil.NopCloser(bytes.NewReader(body))
+				res, err := t.Do(req)
+				if attempt >= policy.MaxAttempts || !retryable(req, res, err) {
+					return res, err
+				}
+				if err != nil {
+					policy.Logger.DebugContext(req.Context(), "Retrying request after error", slog.Int("attempt", attempt), slog.Any("error", err))
+				} else {
+					policy.Logger.DebugContext(req.Context(), "Retrying request after server error", slog.Int("attempt", attempt), slog.Int("status_code", res.StatusCode))
+					res.Body.Close()
+				}
+				if err := sleep(req.Context(), policy.backoff(attempt)); err != nil {
+					return nil, err
+				}
+			}
+		})
+	}
+}
+
+func retryable(req *transport.Request, res *transport.Response, err error) bool {
+	if err != nil {
+		if stderrors.Is(err, context.Canceled) || stderrors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return notSent(err)
+	}
+	if res.StatusCode >= http.StatusInternalServerError {
+		return req.Metadata.DialogInitialization || !req.Metadata.NonRetryable
+	}
+	return false
+}
+
+// notSent returns true if err indicates that the request never reached the
+// server, i.e. DNS lookups or establishing the connection failed.
+func notSent(err error) bool {
+	var dnsErr *net.DNSError
+	if stderrors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	if stderrors.As(err, &opErr) {
+		return opErr.Op == "dial"
+	}
+	return false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
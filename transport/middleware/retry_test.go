@@ -0,0 +1,146 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/transport"
+)
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		Jitter:         -1,
+	}
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}
+	readErr := &net.OpError{Op: "read", Net: "tcp", Err: fmt.Errorf("connection reset by peer")}
+
+	tests := []struct {
+		description   string
+		metadata      transport.RequestMetadata
+		statusCode    int
+		err           error
+		expectedCalls int
+	}{
+		{"success", transport.RequestMetadata{}, http.StatusOK, nil, 1},
+		{"server error on read only job", transport.RequestMetadata{Jobs: []string{"HKSAL"}}, http.StatusBadGateway, nil, 3},
+		{"server error on non retryable job", transport.RequestMetadata{Jobs: []string{"HKCCS"}, NonRetryable: true}, http.StatusBadGateway, nil, 1},
+		{"server error on dialog initialization", transport.RequestMetadata{DialogInitialization: true, NonRetryable: true}, http.StatusServiceUnavailable, nil, 3},
+		{"client error", transport.RequestMetadata{}, http.StatusBadRequest, nil, 1},
+		{"dial error on non retryable job", transport.RequestMetadata{NonRetryable: true}, 0, dialErr, 3},
+		{"read error on read only job", transport.RequestMetadata{}, 0, readErr, 1},
+		{"read error on dialog initialization", transport.RequestMetadata{DialogInitialization: true}, 0, readErr, 1},
+		{"dial error on dialog initialization", transport.RequestMetadata{DialogInitialization: true}, 0, dialErr, 3},
+	}
+
+	for _, test := range tests {
+		calls := 0
+		var bodies []string
+		inner := transport.Func(func(req *transport.Request) (*transport.Response, error) {
+			calls++
+			body, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			bodies = append(bodies, string(body))
+			if test.err != nil {
+				return nil, test.err
+			}
+			return &transport.Response{
+				StatusCode: test.statusCode,
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+			}, nil
+		})
+
+		request := &transport.Request{
+			URL:      "foo",
+			Body:     ioutil.NopCloser(strings.NewReader("bar")),
+			Metadata: test.metadata,
+		}
+
+		_, err := Retry(policy)(inner).Do(request)
+
+		if !stderrors.Is(err, test.err) {
+			t.Logf("%s: Expected error %v, got %v\n", test.description, test.err, err)
+			t.Fail()
+		}
+		if calls != test.expectedCalls {
+			t.Logf("%s: Expected %d calls, got %d\n", test.description, test.expectedCalls, calls)
+			t.Fail()
+		}
+		for _, body := range bodies {
+			if body != "bar" {
+				t.Logf("%s: Expected every attempt to send body %q, got %q\n", test.description, "bar", body)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestRetryContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	inner := transport.Func(func(req *transport.Request) (*transport.Response, error) {
+		calls++
+		cancel()
+		return &transport.Response{
+			StatusCode: http.StatusBadGateway,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}, nil
+	})
+
+	request := (&transport.Request{
+		URL:  "foo",
+		Body: ioutil.NopCloser(strings.NewReader("bar")),
+	}).WithContext(ctx)
+
+	_, err := Retry(RetryPolicy{InitialBackoff: time.Hour})(inner).Do(request)
+
+	if !stderrors.Is(err, context.Canceled) {
+		t.Logf("Expected error to be context.Canceled, got %v\n", err)
+		t.Fail()
+	}
+	if calls != 1 {
+		t.Logf("Expected 1 call, got %d\n", calls)
+		t.Fail()
+	}
+}
+
+func TestRetryLogsToPolicyLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	inner := transport.Func(func(req *transport.Request) (*transport.Response, error) {
+		return &transport.Response{
+			StatusCode: http.StatusBadGateway,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}, nil
+	})
+
+	request := &transport.Request{
+		URL:  "foo",
+		Body: ioutil.NopCloser(strings.NewReader("bar")),
+	}
+
+	policy := RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, Logger: logger}
+	_, err := Retry(policy)(inner).Do(request)
+
+	if err != nil {
+		t.Logf("Expected no error, got %v\n", err)
+		t.Fail()
+	}
+	if !strings.Contains(buf.String(), "status_code=502") {
+		t.Logf("Expected retry to be logged, got %q\n", buf.String())
+		t.Fail()
+	}
+}
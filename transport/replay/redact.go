@@ -0,0 +1,347 @@
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/mitch000001/go-hbci/token"
+)
+
+// minSecretLength is the minimum length of a value to be masked wherever it
+// appears. Shorter values would mask too much unrelated data.
+const minSecretLength = 3
+
+// envelopeSegments are the segments wrapping the jobs of a message. They are
+// ignored when matching requests.
+var envelopeSegments = map[string]bool{
+	"HNHBK": true,
+	"HNHBS": true,
+	"HNVSK": true,
+	"HNVSD": true,
+	"HNSHK": true,
+	"HNSHA": true,
+}
+
+// redactionRule marks a data element, or a single group data element of it,
+// to be masked. Element -1 matches every data element, Component -1 matches
+// every group data element and Version 0 matches every segment version.
+//
+// If Secret is true, the value is also masked wherever else it appears within
+// the recorded messages, e.g. an account number learned from the UPD.
+//
+// If SWIFT is true, the value is a SWIFT message and only the fields
+// identifying accounts and counterparties are masked, see maskSWIFT.
+type redactionRule struct {
+	Version   int
+	Element   int
+	Component int
+	Secret    bool
+	SWIFT     bool
+}
+
+// accountRules mask the account number of a national account connection
+// (ktv) within element 1 up to version 6 and the IBAN and account number of
+// an international account connection (kti) from version 7 on.
+var accountRules = []redactionRule{
+	{Version: 5, Element: 1, Component: 0, Secret: true},
+	{Version: 6, Element: 1, Component: 0, Secret: true},
+	{Version: 7, Element: 1, Component: 0, Secret: true},
+	{Version: 7, Element: 1, Component: 2, Secret: true},
+}
+
+var redactionRules = map[string][]redactionRule{
+	// PIN and TAN
+	"HNSHA": {{Element: 3, Component: -1}},
+	// Customer ID
+	"HKIDN": {{Element: 2, Component: -1, Secret: true}},
+	// User ID and user name
+	"HIUPA": {
+		{Element: 1, Component: -1, Secret: true},
+		{Element: 4, Component: -1, Secret: true},
+	},
+	// Account number, customer ID, IBAN and names
+	"HIUPD": {
+		{Element: 1, Component: 0, Secret: true},
+		{Element: 2, Component: -1, Secret: true},
+		{Version: 4, Element: 4, Component: -1, Secret: true},
+		{Version: 4, Element: 5, Component: -1, Secret: true},
+		{Version: 5, Element: 5, Component: -1, Secret: true},
+		{Version: 5, Element: 6, Component: -1, Secret: true},
+		{Version: 6, Element: 3, Component: -1, Secret: true},
+		{Version: 6, Element: 6, Component: -1, Secret: true},
+		{Version: 6, Element: 7, Component: -1, Secret: true},
+		{Version: 7, Element: 3, Component: -1, Secret: true},
+		{Version: 7, Element: 6, Component: -1, Secret: true},
+		{Version: 7, Element: 7, Component: -1, Secret: true},
+	},
+	// IBAN and account number of SEPA accounts
+	"HISPA": {
+		{Element: -1, Component: 1, Secret: true},
+		{Element: -1, Component: 3, Secret: true},
+	},
+	// Accounts of the jobs
+	"HKSAL": accountRules,
+	"HISAL": accountRules,
+	"HKKAZ": accountRules,
+	"HKSPA": {
+		{Version: 1, Element: -1, Component: 0, Secret: true},
+		{Element: -1, Component: -1},
+	},
+	// Booked and unbooked transactions
+	"HIKAZ": {
+		{Element: 1, Component: -1, SWIFT: true},
+		{Element: 2, Component: -1, SWIFT: true},
+	},
+}
+
+// swiftTag matches the tag of a field at the start of a line of a SWIFT
+// message, e.g. :86:
+var swiftTag = regexp.MustCompile(`(?m)^:(\d{2}[A-Z]?):`)
+
+// counterpartySubfields are the subfields of the MT940 field 86 naming the
+// counterparty: its bank, account, name and IBAN.
+var counterpartySubfields = map[string]bool{
+	"30": true,
+	"31": true,
+	"32": true,
+	"33": true,
+	"38": true,
+}
+
+// maskSWIFT masks the account identification (field 25) and the
+// counterparty within the information to the account owner (field 86) of
+// the MT940 message original in value. Unstructured information is masked
+// completely, as names can not be located within it.
+func maskSWIFT(value, original []byte) {
+	tags := swiftTag.FindAllSubmatchIndex(original, -1)
+	for i, tag := range tags {
+		start, end := tag[1], len(original)
+		if i+1 < len(tags) {
+			end = tags[i+1][0]
+		}
+		switch string(original[tag[2]:tag[3]]) {
+		case "25":
+			mask(value[start:end])
+		case "86":
+			maskCounterparty(value[start:end], original[start:end])
+		}
+	}
+}
+
+// maskCounterparty masks the counterparty subfields of the field 86 content
+// original in value. Structured content starts with a three digit business
+// transaction code followed by subfields like ?32.
+func maskCounterparty(value, original []byte) {
+	if len(original) < 4 || original[3] != '?' {
+		mask(value)
+		return
+	}
+	for i := 3; i < len(original); {
+		next := bytes.IndexByte(original[i+1:], '?')
+		end := len(original)
+		if next != -1 {
+			end = i + 1 + next
+		}
+		if i+3 <= end && counterpartySubfields[string(original[i+1:i+3])] {
+			mask(value[i+3 : end])
+		}
+		i = end
+	}
+}
+
+// position describes where a token is located within a message.
+type position struct {
+	segmentID string
+	version   int
+	element   int
+	component int
+	typ       token.Type
+	start     int
+	end       int
+}
+
+func (p position) matches(rule redactionRule) bool {
+	if p.element == 0 {
+		return false
+	}
+	return (rule.Version == 0 || rule.Version == p.version) &&
+		(rule.Element == -1 || rule.Element == p.element) &&
+		(rule.Component == -1 || rule.Component == p.component)
+}
+
+// walk calls fn for every value token of msg. Positions of binary data
+// exclude the length prefix. The data of HNVSD segments is walked as nested
+// message, with positions relative to msg.
+func walk(msg []byte, fn func(position)) error {
+	return walkAt(msg, 0, fn)
+}
+
+func walkAt(msg []byte, offset int, fn func(position)) error {
+	lexer := token.NewLexer("Replay", msg)
+	var pos position
+	for lexer.HasNext() {
+		t := lexer.Next()
+		switch t.Type() {
+		case token.ERROR:
+			return fmt.Errorf("syntax error at position %d: %q", offset+t.Pos(), t.Value())
+		case token.EOF:
+		case token.SEGMENT_END_MARKER:
+			pos = position{}
+		case token.DATA_ELEMENT_SEPARATOR:
+			pos.element++
+			pos.component = 0
+		case token.GROUP_DATA_ELEMENT_SEPARATOR:
+			pos.component++
+		default:
+			pos.typ = t.Type()
+			pos.start = offset + t.Pos()
+			pos.end = pos.start + len(t.Value())
+			if pos.element == 0 {
+				switch pos.component {
+				case 0:
+					pos.segmentID = string(t.Value())
+				case 2:
+					pos.version, _ = strconv.Atoi(string(t.Value()))
+				}
+			}
+			if t.Type() == token.BINARY_DATA {
+				// only the data itself is of interest, not its length
+				start := bytes.IndexByte(t.Value()[1:], '@') + 2
+				pos.start += start
+				if pos.segmentID == "HNVSD" && pos.element == 1 {
+					if err := walkAt(t.Value()[start:], pos.start, fn); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+			fn(pos)
+		}
+	}
+	return nil
+}
+
+// segmentIDs returns the IDs of all segments within msg which are not part of
+// the message envelope.
+func segmentIDs(msg []byte) ([]string, error) {
+	var ids []string
+	err := walk(msg, func(p position) {
+		if p.element == 0 && p.component == 0 && !envelopeSegments[p.segmentID] {
+			ids = append(ids, p.segmentID)
+		}
+	})
+	return ids, err
+}
+
+// A redactor masks confidential values within messages. It learns the values
+// to mask from the redaction rules, so that account numbers or names sent
+// by the institute are also masked within later messages.
+type redactor struct {
+	secrets map[string]bool
+}
+
+func newRedactor() *redactor {
+	return &redactor{secrets: make(map[string]bool)}
+}
+
+// add registers values to be masked wherever they appear.
+func (r *redactor) add(values ...string) {
+	for _, value := range values {
+		if len(value) >= minSecretLength {
+			r.secrets[value] = true
+		}
+	}
+}
+
+// learn registers the secret values of msg.
+func (r *redactor) learn(msg []byte) {
+	walk(msg, func(p position) {
+		for _, rule := range redactionRules[p.segmentID] {
+			if rule.Secret && p.matches(rule) {
+				value := msg[p.start:p.end]
+				r.add(string(value), string(unescape(value)))
+			}
+		}
+	})
+}
+
+// redact returns a copy of msg with all matching data elements and all known
+// secrets masked. The masked message has the same length as msg, so that the
+// message size and the length of binary data stay valid.
+func (r *redactor) redact(msg []byte) []byte {
+	redacted := make([]byte, len(msg))
+	copy(redacted, msg)
+	err := walk(msg, func(p position) {
+		value, original := redacted[p.start:p.end], msg[p.start:p.end]
+		for _, rule := range redactionRules[p.segmentID] {
+			if !p.matches(rule) {
+				continue
+			}
+			if !rule.SWIFT {
+				mask(value)
+				return
+			}
+			maskSWIFT(value, original)
+			break
+		}
+		if p.element == 0 {
+			return
+		}
+		for secret := range r.secrets {
+			if p.typ == token.NUMERIC || p.typ == token.DIGIT {
+				if string(original) == secret {
+					mask(value)
+				}
+				continue
+			}
+			maskAll(value, original, []byte(secret))
+		}
+	})
+	if err != nil {
+		// Not a HBCI message, so mask the secrets anywhere
+		for secret := range r.secrets {
+			maskAll(redacted, msg, []byte(secret))
+		}
+	}
+	return redacted
+}
+
+// maskAll masks every occurrence of secret within original in value. Both
+// must have the same length. Searching within the unmasked original ensures
+// that overlapping secrets are masked completely.
+func maskAll(value, original, secret []byte) {
+	for i := 0; i < len(original); {
+		j := bytes.Index(original[i:], secret)
+		if j == -1 {
+			return
+		}
+		mask(value[i+j : i+j+len(secret)])
+		i += j + 1
+	}
+}
+
+// mask replaces digits with 9 and letters with X in place. Syntax symbols
+// are kept, so that the message structure stays intact.
+func mask(value []byte) {
+	for i, b := range value {
+		switch {
+		case '0' <= b && b <= '9':
+			value[i] = '9'
+		case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', b >= 0x80:
+			value[i] = 'X'
+		}
+	}
+}
+
+func unescape(value []byte) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < len(value); i++ {
+		if value[i] == '?' && i+1 < len(value) {
+			i++
+		}
+		buf.WriteByte(value[i])
+	}
+	return buf.Bytes()
+}
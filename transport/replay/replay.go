@@ -0,0 +1,261 @@
+// Package transport provides a record and replay transport.Transport.
+//
+// A Recorder wraps a real transport and writes every request and response
+// into a cassette file. Confidential data like PINs, TANs, account numbers
+// and names is masked before writing. A Replayer serves the recorded
+// responses again. It matches requests by the IDs of the contained job
+// segments, as timestamps and message numbers differ between runs.
+//
+// Both work on plain and Base64 encoded messages, so they can be used as
+// Transport within a dialog.Config.
+package transport
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sync"
+
+	"github.com/mitch000001/go-hbci/charset"
+	"github.com/mitch000001/go-hbci/segment"
+	"github.com/mitch000001/go-hbci/transport"
+)
+
+// A Cassette contains recorded request/response pairs.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// An Interaction is one recorded request with its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a recorded request. Body holds the decoded message.
+type RecordedRequest struct {
+	Segments []string `json:"segments"`
+	Body     string   `json:"body"`
+}
+
+// RecordedResponse is a recorded response. Body holds the decoded message.
+// If the transport returned an error, it is stored in Error.
+type RecordedResponse struct {
+	StatusCode int    `json:"statusCode,omitempty"`
+	Body       string `json:"body,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// LoadCassette reads the cassette file at path.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cassette: %w", err)
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("error unmarshaling cassette: %w", err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to the file at path.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling cassette: %w", err)
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// NewRecorder returns a Recorder which performs all requests with t and
+// writes them into the cassette file at path.
+func NewRecorder(path string, t transport.Transport) *Recorder {
+	return &Recorder{
+		path:      path,
+		transport: t,
+		redactor:  newRedactor(),
+	}
+}
+
+// A Recorder implements transport.Transport and records all requests and
+// responses. After every request the cassette file is rewritten, with all
+// confidential data known so far masked.
+type Recorder struct {
+	path         string
+	transport    transport.Transport
+	mu           sync.Mutex
+	redactor     *redactor
+	interactions []interaction
+}
+
+type interaction struct {
+	request    []byte
+	response   []byte
+	statusCode int
+	err        error
+}
+
+// Redact registers additional values to mask wherever they appear, e.g. the
+// name of a transfer recipient.
+func (r *Recorder) Redact(values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.redactor.add(values...)
+}
+
+// Do performs the request with the wrapped transport and records it.
+func (r *Recorder) Do(request *transport.Request) (*transport.Response, error) {
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return nil, err
+	}
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	recorded := interaction{request: decode(body)}
+	response, err := r.transport.Do(request)
+	if err != nil {
+		recorded.err = err
+	} else {
+		responseBody, readErr := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		if readErr != nil {
+			return nil, readErr
+		}
+		response.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+		recorded.response = decode(responseBody)
+		recorded.statusCode = response.StatusCode
+	}
+	if saveErr := r.record(recorded); saveErr != nil {
+		return nil, saveErr
+	}
+	return response, err
+}
+
+func (r *Recorder) record(recorded interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.redactor.learn(recorded.request)
+	r.redactor.learn(recorded.response)
+	r.interactions = append(r.interactions, recorded)
+	return r.cassette().Save(r.path)
+}
+
+// Cassette returns the recorded interactions with all confidential data
+// masked.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette()
+}
+
+func (r *Recorder) cassette() *Cassette {
+	cassette := &Cassette{}
+	for _, recorded := range r.interactions {
+		segments, _ := segmentIDs(recorded.request)
+		interaction := Interaction{
+			Request: RecordedRequest{
+				Segments: segments,
+				Body:     charset.ToUTF8(r.redactor.redact(recorded.request)),
+			},
+			Response: RecordedResponse{
+				StatusCode: recorded.statusCode,
+				Body:       charset.ToUTF8(r.redactor.redact(recorded.response)),
+			},
+		}
+		if recorded.err != nil {
+			interaction.Response.Error = string(r.redactor.redact([]byte(recorded.err.Error())))
+		}
+		cassette.Interactions = append(cassette.Interactions, interaction)
+	}
+	return cassette
+}
+
+// NewReplayer returns a Replayer serving the interactions of cassette.
+func NewReplayer(cassette *Cassette) *Replayer {
+	return &Replayer{
+		cassette: cassette,
+		used:     make([]bool, len(cassette.Interactions)),
+	}
+}
+
+// A Replayer implements transport.Transport and answers requests with
+// recorded responses. A request matches the first unused interaction with
+// the same job segments. Every interaction is only used once.
+type Replayer struct {
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// Do returns the recorded response for request. It returns an error if
+// there is no matching interaction left.
+func (r *Replayer) Do(request *transport.Request) (*transport.Response, error) {
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := segmentIDs(decode(body))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing request: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !reflect.DeepEqual(segments, interaction.Request.Segments) {
+			continue
+		}
+		r.used[i] = true
+		if interaction.Response.Error != "" {
+			return nil, errors.New(interaction.Response.Error)
+		}
+		responseBody := charset.ToISO8859_1(interaction.Response.Body)
+		if isEncoded(body) && bytes.HasPrefix(responseBody, []byte(segment.MessageHeaderID)) {
+			responseBody = []byte(base64.StdEncoding.EncodeToString(responseBody))
+		}
+		return &transport.Response{
+			Request:    request,
+			Body:       ioutil.NopCloser(bytes.NewReader(responseBody)),
+			StatusCode: interaction.Response.StatusCode,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded interaction left for segments %v", segments)
+}
+
+// Remaining returns the number of interactions not yet replayed.
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	remaining := 0
+	for _, used := range r.used {
+		if !used {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// decode returns the HBCI message within body, decoding it from Base64 if
+// necessary. Bodies which are no HBCI message are returned as is.
+func decode(body []byte) []byte {
+	if !isEncoded(body) {
+		return body
+	}
+	decoded, _ := ioutil.ReadAll(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(bytes.TrimSpace(body))))
+	return decoded
+}
+
+// isEncoded reports whether body is a Base64 encoded HBCI message.
+func isEncoded(body []byte) bool {
+	header := []byte(segment.MessageHeaderID)
+	if bytes.HasPrefix(body, header) {
+		return false
+	}
+	prefix := make([]byte, len(header))
+	_, err := io.ReadFull(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(bytes.TrimSpace(body))), prefix)
+	return err == nil && bytes.Equal(prefix, header)
+}
@@ -0,0 +1,237 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mitch000001/go-hbci/charset"
+	"github.com/mitch000001/go-hbci/dialog"
+	"github.com/mitch000001/go-hbci/domain"
+	"github.com/mitch000001/go-hbci/message"
+	"github.com/mitch000001/go-hbci/segment"
+	"github.com/mitch000001/go-hbci/transport"
+)
+
+func TestRecorderAndReplayer(t *testing.T) {
+	responses := [][]byte{
+		encryptedTestMessage("dialog1",
+			"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+			"HIUPA:3:2:4+12345+4+0'",
+			"HIUPD:4:4:4+100000000::280:10000000+12345+EUR+Mustermann+Max+++HKSAL:1'",
+		),
+		encryptedTestMessage("dialog1",
+			"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+			"HISAL:3:5:3+100000000::280:10000000+Sichteinlagen+EUR+C:1000,15:EUR:20150812+C:20,:EUR:20150812+500,:EUR+1499,85:EUR'",
+		),
+		encryptedTestMessage("dialog1", "HIRMG:2:2:1+0100::Dialog beendet'"),
+	}
+	bank := transport.Func(func(req *transport.Request) (*transport.Response, error) {
+		response := responses[0]
+		responses = responses[1:]
+		return &transport.Response{
+			Request:    req,
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(strings.NewReader(base64.StdEncoding.EncodeToString(response))),
+		}, nil
+	})
+
+	cassettePath := filepath.Join(t.TempDir(), "cassette.json")
+	recorder := NewRecorder(cassettePath, bank)
+
+	_, err := sendBalanceRequest(recorder)
+	if err != nil {
+		t.Fatalf("Expected no error while recording, got %T:%v\n", err, err)
+	}
+
+	cassette, err := LoadCassette(cassettePath)
+	if err != nil {
+		t.Fatalf("Expected no error loading cassette, got %v\n", err)
+	}
+
+	{
+		var actual [][]string
+		for _, interaction := range cassette.Interactions {
+			actual = append(actual, interaction.Request.Segments)
+		}
+		expected := [][]string{
+			{"HKIDN", "HKVVB", "HKTAN"},
+			{"HKSAL"},
+			{"HKEND"},
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Logf("Expected recorded segments to equal\n%v\n\tgot\n%v\n", expected, actual)
+			t.Fail()
+		}
+	}
+
+	data, err := ioutil.ReadFile(cassettePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"abcde", "12345", "100000000", "Mustermann"} {
+		if bytes.Contains(data, []byte(secret)) {
+			t.Logf("Expected cassette not to contain %q\n", secret)
+			t.Fail()
+		}
+	}
+
+	replayer := NewReplayer(cassette)
+
+	balances, err := sendBalanceRequest(replayer)
+	if err != nil {
+		t.Fatalf("Expected no error while replaying, got %T:%v\n", err, err)
+	}
+
+	if len(balances) != 1 {
+		t.Logf("Expected one balance to be replayed, got %d\n", len(balances))
+		t.Fail()
+	}
+
+	if replayer.Remaining() != 0 {
+		t.Logf("Expected all interactions to be replayed, %d remaining\n", replayer.Remaining())
+		t.Fail()
+	}
+
+	_, err = sendBalanceRequest(replayer)
+	if err == nil {
+		t.Logf("Expected error when no interactions are left\n")
+		t.Fail()
+	}
+}
+
+func TestRecorderWithoutUserParameterData(t *testing.T) {
+	responses := [][]byte{
+		encryptedTestMessage("dialog1", "HIRMG:2:2:1+0020::Auftrag entgegengenommen'"),
+		encryptedTestMessage("dialog1",
+			"HIRMG:2:2:1+0020::Auftrag entgegengenommen'",
+			"HISAL:3:5:3+100000000::280:10000000+Sichteinlagen+EUR+C:1000,15:EUR:20150812+C:20,:EUR:20150812+500,:EUR+1499,85:EUR'",
+		),
+		encryptedTestMessage("dialog1", "HIRMG:2:2:1+0100::Dialog beendet'"),
+	}
+	bank := transport.Func(func(req *transport.Request) (*transport.Response, error) {
+		response := responses[0]
+		responses = responses[1:]
+		return &transport.Response{
+			Request:    req,
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(strings.NewReader(base64.StdEncoding.EncodeToString(response))),
+		}, nil
+	})
+	cassettePath := filepath.Join(t.TempDir(), "cassette.json")
+
+	if _, err := sendBalanceRequest(NewRecorder(cassettePath, bank)); err != nil {
+		t.Fatalf("Expected no error while recording, got %T:%v\n", err, err)
+	}
+
+	data, err := ioutil.ReadFile(cassettePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"abcde", "12345", "100000000"} {
+		if bytes.Contains(data, []byte(secret)) {
+			t.Logf("Expected cassette not to contain %q\n", secret)
+			t.Fail()
+		}
+	}
+}
+
+func TestRedactorRedact(t *testing.T) {
+	r := newRedactor()
+	r.learn([]byte("HIUPD:4:4:4+100000000::280:10000000+12345+EUR+M?+M+Max+++HKSAL:1'"))
+	r.add("Bob")
+	binary := func(data string) string { return fmt.Sprintf("@%d@%s", len(data), data) }
+
+	input := []byte("HNHBK:1:3+000000000123+220+0+1'HNSHA:2:1+1++abcde:123456'HKSAL:3:5+100000000::280:10000000+N'HIKAZ:4:5:3+" +
+		binary("\r\n:20:STARTUMSE\r\n:25:10000000/100000000\r\n:86:Miete Bob M+M Max\r\n-") + "'")
+	expected := []byte("HNHBK:1:3+000000000123+220+0+1'HNSHA:2:1+1++XXXXX:999999'HKSAL:3:5+999999999::280:10000000+N'HIKAZ:4:5:3+" +
+		binary("\r\n:20:STARTUMSE\r\n:25:99999999/999999999\r\n:86:XXXXX XXX X+X XXX\r\n-") + "'")
+
+	actual := r.redact(input)
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Logf("Expected redacted message to equal\n%s\n\tgot\n%s\n", expected, actual)
+		t.Fail()
+	}
+}
+
+func TestRedactorRedactWithoutUserParameterData(t *testing.T) {
+	mt940 := "\r\n:20:STARTUMSE\r\n:25:37040044/0532013000\r\n:28C:00000/001\r\n:60F:C190124EUR1000,00" +
+		"\r\n:61:1901250125DR50,00NMSCNONREF\r\n:86:177?00UEBERWEISUNG?20Miete Januar?30COBADEFFXXX" +
+		"\r\n?31DE02120300000000202051?32Max Mustermann?3440\r\n:62F:C190125EUR950,00\r\n-"
+	requests := []string{
+		"HKSAL:3:7+DE89370400440532013000:COBADEFFXXX:0532013000::280:37040044+N'",
+		"HKKAZ:3:6+0532013000::280:37040044+N'",
+		"HKSPA:3:1+0532013000::280:37040044'",
+	}
+	responses := []string{
+		"HISAL:4:7:3+DE89370400440532013000:COBADEFFXXX:0532013000::280:37040044+Girokonto+EUR+C:1000,:EUR:20190124'",
+		fmt.Sprintf("HIKAZ:4:6:3+@%d@%s'", len(mt940), mt940),
+	}
+	r := newRedactor()
+	var redacted []byte
+	for _, msg := range append(requests, responses...) {
+		r.learn([]byte(msg))
+	}
+	for _, msg := range append(requests, responses...) {
+		redacted = append(redacted, r.redact([]byte(msg))...)
+	}
+
+	for _, secret := range []string{"DE89370400440532013000", "0532013000", "DE02120300000000202051", "Mustermann"} {
+		if bytes.Contains(redacted, []byte(secret)) {
+			t.Logf("Expected redacted messages not to contain %q, got\n%s\n", secret, redacted)
+			t.Fail()
+		}
+	}
+	for _, kept := range []string{"37040044", "Miete Januar", "C190124EUR1000,00", ":61:1901250125DR50,00NMSCNONREF"} {
+		if !bytes.Contains(redacted, []byte(kept)) {
+			t.Logf("Expected redacted messages to keep %q, got\n%s\n", kept, redacted)
+			t.Fail()
+		}
+	}
+}
+
+func sendBalanceRequest(t transport.Transport) ([]domain.AccountBalance, error) {
+	d := dialog.NewPinTanDialog(dialog.Config{
+		BankID:      domain.BankID{CountryCode: 280, ID: "10000000"},
+		HBCIURL:     "https://localhost",
+		UserID:      "12345",
+		HBCIVersion: segment.HBCI220,
+		Transport:   t,
+	})
+	d.SetPin("abcde")
+	d.SetClientSystemID("xyz")
+	account := domain.AccountConnection{AccountID: "100000000", CountryCode: 280, BankID: "10000000"}
+	response, err := d.SendMessage(message.NewHBCIMessage(segment.HBCI220, segment.NewAccountBalanceRequestV5(account, false)))
+	if err != nil {
+		return nil, err
+	}
+	var balances []domain.AccountBalance
+	for _, marshaledSegment := range response.FindMarshaledSegments("HISAL") {
+		balanceSegment := &segment.AccountBalanceResponseSegment{}
+		if err := balanceSegment.UnmarshalHBCI(marshaledSegment); err != nil {
+			return nil, err
+		}
+		balances = append(balances, balanceSegment.AccountBalance())
+	}
+	return balances, nil
+}
+
+func encryptedTestMessage(dialogID string, encryptedData ...string) []byte {
+	encryptionHeader := "HNVSK:998:2:+998+1+1::0+1:20150713:173634+2:2:13:@8@\x00\x00\x00\x00\x00\x00\x00\x00:5:1:+280:10000000:12345:V:0:0+0+'"
+	data := charset.ToISO8859_1(strings.Join(encryptedData, ""))
+	encryptionData := fmt.Sprintf("HNVSD:999:1:+@%d@%s'", len(data), data)
+	messageEnd := fmt.Sprintf("HNHBS:%d:1:+1'", len(encryptedData)+1)
+	messageHeader := fmt.Sprintf("HNHBK:1:3+%012d+220+%s+1+'", 31+len(dialogID)+len(encryptionHeader)+len(encryptionData)+len(messageEnd), dialogID)
+	return bytes.Join([][]byte{
+		charset.ToISO8859_1(messageHeader),
+		charset.ToISO8859_1(encryptionHeader),
+		[]byte(encryptionData),
+		charset.ToISO8859_1(messageEnd),
+	}, nil)
+}
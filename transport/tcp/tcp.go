@@ -0,0 +1,195 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/mitch000001/go-hbci/segment"
+	"github.com/mitch000001/go-hbci/transport"
+)
+
+// DefaultPort is the port of the classic HBCI TCP channel. It is used if the
+// request URL does not contain a port.
+const DefaultPort = "3000"
+
+// maxMessageSize limits the size of a message announced within the HNHBK
+// segment, to prevent allocating arbitrary amounts of memory for a corrupt
+// header.
+const maxMessageSize = 64 << 20
+
+// Timeouts configures the time a TCPTransport waits for the single steps of a
+// request. A zero value means no timeout.
+type Timeouts struct {
+	// Dial limits the time to establish the connection.
+	Dial time.Duration
+	// Write limits the time to send the request message.
+	Write time.Duration
+	// Read limits the time to receive the complete response message.
+	Read time.Duration
+}
+
+// DefaultTimeouts are used by New.
+var DefaultTimeouts = Timeouts{
+	Dial:  10 * time.Second,
+	Write: 30 * time.Second,
+	Read:  2 * time.Minute,
+}
+
+// New returns a TCPTransport using DefaultTimeouts.
+func New() *TCPTransport {
+	return &TCPTransport{Timeouts: DefaultTimeouts}
+}
+
+// NewWithTimeouts returns a TCPTransport using the given timeouts.
+func NewWithTimeouts(timeouts Timeouts) *TCPTransport {
+	return &TCPTransport{Timeouts: timeouts}
+}
+
+// A TCPTransport implements transport.Transport and sends messages over the
+// classic HBCI TCP channel. The request URL is the address of the server in
+// the form host:port, optionally prefixed by tcp://.
+//
+// Every request uses its own connection. Messages are sent as is, without
+// any encoding. The response is read completely, using the size announced
+// within its message header.
+type TCPTransport struct {
+	Timeouts Timeouts
+}
+
+// Do sends the request to the HBCI server. If successful, it returns a
+// populated transport.Response with the complete response message as Body and
+// the request as Request.
+func (t *TCPTransport) Do(request *transport.Request) (*transport.Response, error) {
+	ctx := request.Context()
+	dialer := &net.Dialer{Timeout: t.Timeouts.Dial}
+	conn, err := dialer.DialContext(ctx, "tcp", address(request.URL))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	// Abort blocking reads and writes when the context is done
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.SetDeadline(time.Now())
+		case <-stop:
+		}
+	}()
+
+	if err := conn.SetWriteDeadline(deadline(ctx, t.Timeouts.Write)); err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(conn, request.Body); err != nil {
+		return nil, withContextError(ctx, fmt.Errorf("error while writing message: %w", err))
+	}
+	if err := conn.SetReadDeadline(deadline(ctx, t.Timeouts.Read)); err != nil {
+		return nil, err
+	}
+	message, err := ReadMessage(bufio.NewReader(conn))
+	if err != nil {
+		return nil, withContextError(ctx, err)
+	}
+	return &transport.Response{
+		Body:    ioutil.NopCloser(bytes.NewReader(message)),
+		Request: request,
+	}, nil
+}
+
+// DoContext performs the request like Do, but aborts it when ctx is done.
+func (t *TCPTransport) DoContext(ctx context.Context, request *transport.Request) (*transport.Response, error) {
+	return transport.DoContext(ctx, t, request)
+}
+
+// ReadMessage reads exactly one HBCI message from r. It reads the message
+// header segment HNHBK first and uses its size element to read the remainder
+// of the message.
+func ReadMessage(r *bufio.Reader) ([]byte, error) {
+	header, err := readSegment(r)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading message header: %w", err)
+	}
+	messageHeader := &segment.MessageHeaderSegment{}
+	if err := messageHeader.UnmarshalHBCI(header); err != nil {
+		return nil, fmt.Errorf("error while unmarshaling message header: %w", err)
+	}
+	if messageHeader.Size == nil {
+		return nil, fmt.Errorf("message header without size")
+	}
+	size := messageHeader.Size.Val()
+	if size < len(header) || size > maxMessageSize {
+		return nil, fmt.Errorf("invalid message size: %d", size)
+	}
+	message := make([]byte, size)
+	copy(message, header)
+	if _, err := io.ReadFull(r, message[len(header):]); err != nil {
+		return nil, fmt.Errorf("error while reading message: %w", err)
+	}
+	return message, nil
+}
+
+// readSegment reads up to and including the first unescaped segment
+// terminator.
+func readSegment(r *bufio.Reader) ([]byte, error) {
+	var buf bytes.Buffer
+	escaped := false
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteByte(b)
+		switch {
+		case escaped:
+			escaped = false
+		case b == '?':
+			escaped = true
+		case b == '\'':
+			return buf.Bytes(), nil
+		}
+		if buf.Len() > maxMessageSize {
+			return nil, fmt.Errorf("segment too long")
+		}
+	}
+}
+
+// address returns the network address of url, adding DefaultPort if it has
+// no port.
+func address(url string) string {
+	addr := strings.TrimPrefix(url, "tcp://")
+	addr = strings.TrimSuffix(addr, "/")
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return net.JoinHostPort(addr, DefaultPort)
+	}
+	return addr
+}
+
+// deadline returns the earlier of the context deadline and now plus timeout.
+// It returns the zero time if neither is set.
+func deadline(ctx context.Context, timeout time.Duration) time.Time {
+	var d time.Time
+	if timeout > 0 {
+		d = time.Now().Add(timeout)
+	}
+	if ctxDeadline, ok := ctx.Deadline(); ok && (d.IsZero() || ctxDeadline.Before(d)) {
+		d = ctxDeadline
+	}
+	return d
+}
+
+// withContextError returns the context error if ctx is done, as it caused
+// err, or err otherwise.
+func withContextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
@@ -0,0 +1,175 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mitch000001/go-hbci/transport"
+)
+
+func testMessage(dialogID string, segments string) []byte {
+	header := fmt.Sprintf("HNHBK:1:3+%012d+300+%s+1'", 0, dialogID)
+	size := len(header) + len(segments)
+	return []byte(fmt.Sprintf("HNHBK:1:3+%012d+300+%s+1'%s", size, dialogID, segments))
+}
+
+func startTestServer(t *testing.T, handle func(net.Conn)) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error while listening: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestTCPTransportDo(t *testing.T) {
+	requestMessage := testMessage("0", "HKIDN:2:2+280:10000000+user+0+0'HNHBS:3:1+1'")
+	responseMessage := testMessage("abc?'def", "HIRMG:2:2+0010::Nachricht entgegengenommen'HNHBS:3:1+1'")
+	received := make(chan []byte, 1)
+
+	addr := startTestServer(t, func(conn net.Conn) {
+		request, err := ReadMessage(bufio.NewReader(conn))
+		if err != nil {
+			t.Logf("Expected no error reading the request, got %v\n", err)
+			t.Fail()
+		}
+		received <- request
+		// send the response in several chunks to verify it is read completely
+		for i := 0; i < len(responseMessage); i += 20 {
+			end := i + 20
+			if end > len(responseMessage) {
+				end = len(responseMessage)
+			}
+			conn.Write(responseMessage[i:end])
+			time.Sleep(time.Millisecond)
+		}
+	})
+
+	request := &transport.Request{
+		URL:  "tcp://" + addr,
+		Body: ioutil.NopCloser(bytes.NewReader(requestMessage)),
+	}
+
+	response, err := New().Do(request)
+	if err != nil {
+		t.Fatalf("Expected no error, got %T:%v\n", err, err)
+	}
+
+	{
+		actual := <-received
+		if !reflect.DeepEqual(requestMessage, actual) {
+			t.Logf("Expected server to receive\n%s\n\tgot\n%s\n", requestMessage, actual)
+			t.Fail()
+		}
+	}
+	{
+		actual, err := ioutil.ReadAll(response.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(responseMessage, actual) {
+			t.Logf("Expected response body to equal\n%s\n\tgot\n%s\n", responseMessage, actual)
+			t.Fail()
+		}
+	}
+}
+
+func TestTCPTransportDoTimeout(t *testing.T) {
+	addr := startTestServer(t, func(conn net.Conn) {
+		ReadMessage(bufio.NewReader(conn))
+		// send a truncated message and keep the connection open
+		conn.Write(testMessage("abc", "HIRMG:2:2+0010::Nachricht entgegengenommen'")[:30])
+		time.Sleep(time.Second)
+	})
+
+	newRequest := func() *transport.Request {
+		return &transport.Request{
+			URL:  addr,
+			Body: ioutil.NopCloser(bytes.NewReader(testMessage("0", "HNHBS:2:1+1'"))),
+		}
+	}
+
+	tcpTransport := NewWithTimeouts(Timeouts{Read: 50 * time.Millisecond})
+	_, err := tcpTransport.Do(newRequest())
+
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Logf("Expected timeout error, got %T:%v\n", err, err)
+		t.Fail()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = New().DoContext(ctx, newRequest())
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Logf("Expected error to be %v, got %T:%v\n", context.DeadlineExceeded, err, err)
+		t.Fail()
+	}
+}
+
+func TestReadMessage(t *testing.T) {
+	message := testMessage("abc", "HIRMG:2:2+0010::Nachricht entgegengenommen'")
+
+	tests := []struct {
+		description string
+		input       []byte
+		expected    []byte
+		expectError bool
+	}{
+		{"complete message", message, message, false},
+		{"trailing data", append(append([]byte{}, message...), "HNHBK"...), message, false},
+		{"truncated message", message[:len(message)-5], nil, true},
+		{"size smaller than header", []byte("HNHBK:1:3+000000000010+300+abc+1'"), nil, true},
+		{"other segment first", []byte("HIRMG:2:2+0010::Nachricht entgegengenommen'"), nil, true},
+	}
+
+	for _, test := range tests {
+		actual, err := ReadMessage(bufio.NewReader(bytes.NewReader(test.input)))
+		if test.expectError != (err != nil) {
+			t.Logf("%s: Expected error: %t, got %v\n", test.description, test.expectError, err)
+			t.Fail()
+		}
+		if !reflect.DeepEqual(test.expected, actual) {
+			t.Logf("%s: Expected message to equal\n%q\n\tgot\n%q\n", test.description, test.expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func TestAddress(t *testing.T) {
+	tests := map[string]string{
+		"hbci.example.com":          "hbci.example.com:3000",
+		"hbci.example.com:3001":     "hbci.example.com:3001",
+		"tcp://hbci.example.com:80": "hbci.example.com:80",
+		"tcp://10.0.0.1/":           "10.0.0.1:3000",
+	}
+
+	for url, expected := range tests {
+		actual := address(url)
+		if expected != actual {
+			t.Logf("Expected address of %q to equal %q, got %q\n", url, expected, actual)
+			t.Fail()
+		}
+	}
+}
@@ -3,6 +3,7 @@ package transport
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"io"
 	"io/ioutil"
 
@@ -27,6 +28,15 @@ func (fn Func) Do(req *Request) (*Response, error) {
 	return fn(req)
 }
 
+// DoContext performs req with t after attaching ctx to it. If ctx is already
+// done, the context error is returned without calling t.
+func DoContext(ctx context.Context, t Transport, req *Request) (*Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return t.Do(req.WithContext(ctx))
+}
+
 // Middleware defines the interface for writing middleware for transports
 type Middleware func(Transport) Transport
 
@@ -40,6 +50,52 @@ type Request struct {
 	//
 	// Body has always to be non-nil
 	Body io.ReadCloser
+	// Metadata describes the content of the request. Middlewares can use it
+	// without having to parse the possibly encrypted Body.
+	Metadata RequestMetadata
+
+	// ctx is either the client or server context. It should only
+	// be modified via copying the whole Request using WithContext.
+	ctx context.Context
+}
+
+// Context returns the request's context. To change the context, use
+// WithContext.
+//
+// The returned context is always non-nil; it defaults to the
+// background context.
+func (r *Request) Context() context.Context {
+	if r.ctx != nil {
+		return r.ctx
+	}
+	return context.Background()
+}
+
+// WithContext returns a shallow copy of r with its context changed
+// to ctx. The provided ctx must be non-nil.
+//
+// Transports should abort the request when the context gets cancelled.
+func (r *Request) WithContext(ctx context.Context) *Request {
+	if ctx == nil {
+		panic("nil context")
+	}
+	r2 := new(Request)
+	*r2 = *r
+	r2.ctx = ctx
+	return r2
+}
+
+// RequestMetadata describes the HBCI message transported within a Request.
+type RequestMetadata struct {
+	// Jobs contains the IDs of the job segments within the message, e.g.
+	// HKSAL or HKCCS.
+	Jobs []string
+	// DialogInitialization is true if the message initializes a dialog.
+	DialogInitialization bool
+	// NonRetryable is true if the message contains jobs which change state
+	// at the institute, e.g. transfers. Sending such a message twice might
+	// execute the jobs twice.
+	NonRetryable bool
 }
 
 // ReadResponse reads and returns a Response from r. It populates the embedded
@@ -82,6 +138,10 @@ type Response struct {
 	MarshaledResponse []byte
 	// Body represents the response body.
 	Body io.ReadCloser
+	// StatusCode is the status code of the underlying protocol, if it has one,
+	// e.g. the HTTP status code. It is zero if the Transport does not provide
+	// it.
+	StatusCode int
 }
 
 // IsEncrypted returns whether the response contains an encrypted message.